@@ -155,7 +155,10 @@ func (a *Agent) Run(ctx context.Context) error {
 			}
 		}
 
-		next, au = a.startAggregators(aggC, next, a.Config.Aggregators)
+		next, au, err = a.startAggregators(aggC, next, a.Config.Aggregators)
+		if err != nil {
+			return err
+		}
 	}
 
 	var pu []*processorUnit
@@ -669,16 +672,37 @@ func (*Agent) runProcessors(units []*processorUnit) {
 	wg.Wait()
 }
 
-// startAggregators sets up the aggregator unit and returns the source channel.
-func (*Agent) startAggregators(aggC, outputC chan<- telegraf.Metric, aggregators []*models.RunningAggregator) (chan<- telegraf.Metric, *aggregatorUnit) {
-	src := make(chan telegraf.Metric, 100)
+// startAggregators sets up the aggregator unit, calls Start on all aggregators
+// and returns the source channel. If an error occurs any started aggregators
+// are stopped.
+func (*Agent) startAggregators(
+	aggC, outputC chan<- telegraf.Metric,
+	aggregators []*models.RunningAggregator,
+) (chan<- telegraf.Metric, *aggregatorUnit, error) {
 	unit := &aggregatorUnit{
-		src:         src,
-		aggC:        aggC,
-		outputC:     outputC,
-		aggregators: aggregators,
+		aggC:    aggC,
+		outputC: outputC,
+	}
+	for _, aggregator := range aggregators {
+		if err := aggregator.Start(); err != nil {
+			var fatalErr *internal.FatalError
+			if errors.As(err, &fatalErr) {
+				// If the model tells us to remove the plugin we do so without error
+				log.Printf("I! [agent] Failed to start %s, shutting down plugin: %s", aggregator.LogName(), err)
+				continue
+			}
+
+			for _, agg := range unit.aggregators {
+				agg.Stop()
+			}
+			return nil, nil, fmt.Errorf("starting aggregator %s: %w", aggregator.LogName(), err)
+		}
+		unit.aggregators = append(unit.aggregators, aggregator)
 	}
-	return src, unit
+
+	src := make(chan telegraf.Metric, 100)
+	unit.src = src
+	return src, unit, nil
 }
 
 // runAggregators beings aggregating metrics and runs until the source channel
@@ -691,9 +715,9 @@ func (a *Agent) runAggregators(
 
 	// Before calling Add, initialize the aggregation window.  This ensures
 	// that any metric created after start time will be aggregated.
-	for _, agg := range a.Config.Aggregators {
-		since, until := updateWindow(startTime, a.Config.Agent.RoundInterval, agg.Period())
-		agg.UpdateWindow(since, until)
+	for _, agg := range unit.aggregators {
+		_, until := updateWindow(startTime, a.Config.Agent.RoundInterval, agg.Step())
+		agg.UpdateWindow(until.Add(-agg.Period()), until)
 	}
 
 	var wg sync.WaitGroup
@@ -702,7 +726,7 @@ func (a *Agent) runAggregators(
 		defer wg.Done()
 		for metric := range unit.src {
 			var dropOriginal bool
-			for _, agg := range a.Config.Aggregators {
+			for _, agg := range unit.aggregators {
 				if ok := agg.Add(metric); ok {
 					dropOriginal = true
 				}
@@ -717,7 +741,7 @@ func (a *Agent) runAggregators(
 		cancel()
 	}()
 
-	for _, agg := range a.Config.Aggregators {
+	for _, agg := range unit.aggregators {
 		wg.Add(1)
 		go func(agg *models.RunningAggregator) {
 			defer wg.Done()
@@ -733,6 +757,10 @@ func (a *Agent) runAggregators(
 
 	wg.Wait()
 
+	for _, agg := range unit.aggregators {
+		agg.Stop()
+	}
+
 	// In the case that there are no processors, both aggC and outputC are the
 	// same channel.  If there are processors, we close the aggC and the
 	// processor chain will close the outputC when it finishes processing.
@@ -1000,15 +1028,18 @@ func (a *Agent) runTest(ctx context.Context, wait time.Duration, outputC chan<-
 	var au *aggregatorUnit
 	if len(a.Config.Aggregators) != 0 {
 		procC := next
+		var err error
 		if len(a.Config.AggProcessors) != 0 && !*a.Config.Agent.SkipProcessorsAfterAggregators {
-			var err error
 			procC, apu, err = a.startProcessors(next, a.Config.AggProcessors)
 			if err != nil {
 				return err
 			}
 		}
 
-		next, au = a.startAggregators(procC, next, a.Config.Aggregators)
+		next, au, err = a.startAggregators(procC, next, a.Config.Aggregators)
+		if err != nil {
+			return err
+		}
 	}
 
 	var pu []*processorUnit
@@ -1116,7 +1147,10 @@ func (a *Agent) runOnce(ctx context.Context, wait time.Duration) error {
 			}
 		}
 
-		next, au = a.startAggregators(procC, next, a.Config.Aggregators)
+		next, au, err = a.startAggregators(procC, next, a.Config.Aggregators)
+		if err != nil {
+			return err
+		}
 	}
 
 	var pu []*processorUnit
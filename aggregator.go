@@ -16,3 +16,15 @@ type Aggregator interface {
 	// Reset resets the aggregators caches and aggregates.
 	Reset()
 }
+
+// ServiceAggregator is an Aggregator holding resources, e.g. a process, which
+// must be acquired before aggregating and released when Telegraf stops.
+type ServiceAggregator interface {
+	Aggregator
+
+	// Start acquires the resources of the aggregator.
+	Start() error
+
+	// Stop releases the resources of the aggregator.
+	Stop()
+}
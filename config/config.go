@@ -1006,7 +1006,19 @@ func (c *Config) addAggregator(name, source string, table *ast.Table) error {
 		return err
 	}
 
-	if err := c.toml.UnmarshalTable(table, aggregator); err != nil {
+	// Ignore the window options only valid for aggregators without adding
+	// them to the general options to keep reporting them for other plugins
+	tracker := c.toml.MissingField
+	c.toml.MissingField = func(t reflect.Type, key string) error {
+		switch key {
+		case "step", "allowed_lateness", "updated_tag", "window_buffer_limit":
+			return nil
+		}
+		return tracker(t, key)
+	}
+	err = c.toml.UnmarshalTable(table, aggregator)
+	c.toml.MissingField = tracker
+	if err != nil {
 		return err
 	}
 
@@ -1164,6 +1176,62 @@ func (c *Config) addParser(parentcategory, parentname string, table *ast.Table)
 	return running, err
 }
 
+// addParserTables returns the functions creating the parsers configured in
+// the sub-tables of the given plugin by key. The sub-tables are removed from
+// the plugin table as the parser options are unknown to the plugin.
+func (c *Config) addParserTables(
+	parentcategory, parentname string,
+	table *ast.Table,
+	plugin telegraf.ParserTablePlugin,
+) (map[string]telegraf.ParserFunc, error) {
+	tableName, key := plugin.ParserTable()
+	node, found := table.Fields[tableName]
+	if !found {
+		return nil, nil
+	}
+	subtables, ok := node.([]*ast.Table)
+	if !ok {
+		return nil, fmt.Errorf("%q must be an array of tables", tableName)
+	}
+	delete(table.Fields, tableName)
+
+	fns := make(map[string]telegraf.ParserFunc, len(subtables))
+	for _, subtable := range subtables {
+		k := c.getFieldString(subtable, key)
+		if k == "" {
+			return nil, fmt.Errorf("missing %q setting in %q", key, tableName)
+		}
+		if _, exists := fns[k]; exists {
+			return nil, fmt.Errorf("duplicate %q setting %q in %q", key, k, tableName)
+		}
+
+		// Strip the key as it is not a parser option
+		parserTable := *subtable
+		parserTable.Fields = make(map[string]interface{}, len(subtable.Fields))
+		for field, value := range subtable.Fields {
+			if field != key {
+				parserTable.Fields[field] = value
+			}
+		}
+
+		// Create the parser once to report misspelled options. The options of
+		// the sub-table are not shared with the plugin, so all unknown options
+		// are reported directly instead of using the tracker of the plugin.
+		tracker := c.toml.MissingField
+		c.resetMissingTomlFieldTracker()
+		_, err := c.addParser(parentcategory, parentname, &parserTable)
+		c.toml.MissingField = tracker
+		if err != nil {
+			return nil, fmt.Errorf("creating parser for %s %q failed: %w", key, k, err)
+		}
+
+		fns[k] = func() (telegraf.Parser, error) {
+			return c.addParser(parentcategory, parentname, &parserTable)
+		}
+	}
+	return fns, nil
+}
+
 func (c *Config) probeSerializer(table *ast.Table) bool {
 	dataFormat := c.getFieldString(table, "data_format")
 	if dataFormat == "" {
@@ -1489,6 +1557,14 @@ func (c *Config) addInput(name, source string, table *ast.Table) error {
 		})
 	}
 
+	if t, ok := input.(telegraf.ParserTablePlugin); ok {
+		fns, err := c.addParserTables("inputs", name, table, t)
+		if err != nil {
+			return fmt.Errorf("adding parsers failed: %w", err)
+		}
+		t.SetParserFuncs(fns)
+	}
+
 	pluginConfig, err := c.buildInput(name, source, table)
 	if err != nil {
 		return err
@@ -1535,6 +1611,8 @@ func (c *Config) buildAggregator(name, source string, tbl *ast.Table) (*models.A
 		Delay:  time.Millisecond * 100,
 		Period: time.Second * 30,
 		Grace:  time.Second * 0,
+
+		WindowBufferLimit: 10000,
 	}
 
 	if period, found := c.getFieldDuration(tbl, "period"); found {
@@ -1546,6 +1624,15 @@ func (c *Config) buildAggregator(name, source string, tbl *ast.Table) (*models.A
 	if grace, found := c.getFieldDuration(tbl, "grace"); found {
 		conf.Grace = grace
 	}
+	if step, found := c.getFieldDuration(tbl, "step"); found {
+		conf.Step = step
+	}
+	if lateness, found := c.getFieldDuration(tbl, "allowed_lateness"); found {
+		conf.Lateness = lateness
+	}
+	if limit := c.getFieldInt(tbl, "window_buffer_limit"); limit > 0 {
+		conf.WindowBufferLimit = limit
+	}
 
 	conf.DropOriginal = c.getFieldBool(tbl, "drop_original")
 	conf.MeasurementPrefix = c.getFieldString(tbl, "name_prefix")
@@ -1553,6 +1640,8 @@ func (c *Config) buildAggregator(name, source string, tbl *ast.Table) (*models.A
 	conf.NameOverride = c.getFieldString(tbl, "name_override")
 	conf.Alias = c.getFieldString(tbl, "alias")
 	conf.LogLevel = c.getFieldString(tbl, "log_level")
+	conf.UpdatedTag = c.getFieldString(tbl, "updated_tag")
+	conf.StartupErrorBehavior = c.getFieldString(tbl, "startup_error_behavior")
 
 	conf.Tags = make(map[string]string)
 	if node, ok := tbl.Fields["tags"]; ok {
@@ -1567,6 +1656,10 @@ func (c *Config) buildAggregator(name, source string, tbl *ast.Table) (*models.A
 		return nil, c.firstErr()
 	}
 
+	if conf.Step > conf.Period {
+		return nil, fmt.Errorf("step %s of aggregator %s exceeds period %s", conf.Step, name, conf.Period)
+	}
+
 	var err error
 	conf.Filter, err = c.buildFilter("aggregators."+name, tbl)
 	if err != nil {
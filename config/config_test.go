@@ -26,6 +26,7 @@ import (
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/models"
 	"github.com/influxdata/telegraf/persister"
+	"github.com/influxdata/telegraf/plugins/aggregators"
 	"github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/outputs"
@@ -437,6 +438,18 @@ func TestConfig_FieldNotDefined(t *testing.T) {
 			expected: "line 1: configuration specified the fields [\"not_a_field\"], but they were not used; " +
 				"this is either a typo or this config option does not exist in this version",
 		},
+		{
+			name:     "in path parser of input plugin",
+			filename: "./testdata/invalid_field_in_path_parser.toml",
+			expected: "line 1: configuration specified the fields [\"json_nme_key\"], but they were not used; " +
+				"this is either a typo or this config option does not exist in this version",
+		},
+		{
+			name:     "aggregator option in input plugin",
+			filename: "./testdata/invalid_field_window_option.toml",
+			expected: "line 1: configuration specified the fields [\"step\"], but they were not used; " +
+				"this is either a typo or this config option does not exist in this version",
+		},
 		{
 			name:     "in processor plugin without parser",
 			filename: "./testdata/invalid_field_processor.toml",
@@ -478,6 +491,16 @@ func TestConfig_FieldNotDefined(t *testing.T) {
 	}
 }
 
+func TestConfig_AggregatorWindowOptions(t *testing.T) {
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfig("./testdata/aggregator_window_options.toml"))
+	require.Len(t, c.Aggregators, 1)
+	require.Equal(t, 10*time.Second, c.Aggregators[0].Config.Step)
+	require.Equal(t, 2*time.Minute, c.Aggregators[0].Config.Lateness)
+	require.Equal(t, "updated", c.Aggregators[0].Config.UpdatedTag)
+	require.Equal(t, 500, c.Aggregators[0].Config.WindowBufferLimit)
+}
+
 func TestConfig_WrongFieldType(t *testing.T) {
 	c := config.NewConfig()
 	err := c.LoadConfig("./testdata/wrong_field_type.toml")
@@ -938,6 +961,37 @@ func TestConfig_MultipleProcessorsOrder(t *testing.T) {
 	}
 }
 
+func TestConfig_ParserTables(t *testing.T) {
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfig("./testdata/parser_tables.toml"))
+	require.Len(t, c.Inputs, 1)
+
+	input, ok := c.Inputs[0].Input.(*MockupInputPluginParserTable)
+	require.True(t, ok)
+	require.Equal(t, []string{"/telegraf", "/json", "/value"}, input.Paths)
+	require.NotNil(t, input.parser)
+	require.Len(t, input.parserFuncs, 2)
+
+	parser, err := input.parserFuncs["/json"]()
+	require.NoError(t, err)
+	metrics, err := parser.Parse([]byte(`{"name": "test", "value": 42}`))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	require.Equal(t, "test", metrics[0].Name())
+
+	parser, err = input.parserFuncs["/value"]()
+	require.NoError(t, err)
+	metrics, err = parser.Parse([]byte("42"))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	require.Equal(t, map[string]interface{}{"value": int64(42)}, metrics[0].Fields())
+}
+
+func TestConfig_ParserTablesMissingKey(t *testing.T) {
+	c := config.NewConfig()
+	require.ErrorContains(t, c.LoadConfig("./testdata/parser_tables_missing_key.toml"), `missing "path" setting in "path_parser"`)
+}
+
 func TestConfig_ProcessorsWithParsers(t *testing.T) {
 	formats := []string{
 		"collectd",
@@ -1530,6 +1584,30 @@ func (m *MockupInputPluginParserFunc) SetParserFunc(pf telegraf.ParserFunc) {
 	m.parserFunc = pf
 }
 
+// Mockup INPUT plugin with ParserTable interface
+type MockupInputPluginParserTable struct {
+	Paths []string `toml:"paths"`
+
+	parser      telegraf.Parser
+	parserFuncs map[string]telegraf.ParserFunc
+}
+
+func (*MockupInputPluginParserTable) SampleConfig() string {
+	return "Mockup test input plugin"
+}
+func (*MockupInputPluginParserTable) Gather(telegraf.Accumulator) error {
+	return nil
+}
+func (m *MockupInputPluginParserTable) SetParser(p telegraf.Parser) {
+	m.parser = p
+}
+func (*MockupInputPluginParserTable) ParserTable() (table, key string) {
+	return "path_parser", "path"
+}
+func (m *MockupInputPluginParserTable) SetParserFuncs(fns map[string]telegraf.ParserFunc) {
+	m.parserFuncs = fns
+}
+
 // Mockup INPUT plugin without ParserFunc interface
 type MockupInputPluginParserOnly struct {
 	parser telegraf.Parser
@@ -1691,6 +1769,16 @@ func (*MockupOutputPluginSerializerNew) Write(_ []telegraf.Metric) error {
 	return nil
 }
 
+// Mockup AGGREGATOR plugin for testing to avoid cyclic dependencies
+type MockupAggregatorPlugin struct{}
+
+func (*MockupAggregatorPlugin) SampleConfig() string {
+	return "Mockup test aggregator plugin"
+}
+func (*MockupAggregatorPlugin) Add(telegraf.Metric)       {}
+func (*MockupAggregatorPlugin) Push(telegraf.Accumulator) {}
+func (*MockupAggregatorPlugin) Reset()                    {}
+
 // Mockup INPUT plugin with state for testing to avoid cyclic dependencies
 type MockupState struct {
 	Name     string
@@ -1763,6 +1851,9 @@ func init() {
 	inputs.Add("parser_func", func() telegraf.Input {
 		return &MockupInputPluginParserFunc{}
 	})
+	inputs.Add("parser_table", func() telegraf.Input {
+		return &MockupInputPluginParserTable{}
+	})
 	inputs.Add("exec", func() telegraf.Input {
 		return &MockupInputPlugin{Timeout: config.Duration(time.Second * 5)}
 	})
@@ -1799,6 +1890,11 @@ func init() {
 		return &MockupProcessorPlugin{}
 	})
 
+	// Register the mockup aggregator plugin for the required names
+	aggregators.Add("minmax", func() telegraf.Aggregator {
+		return &MockupAggregatorPlugin{}
+	})
+
 	// Register the mockup output plugin for the required names
 	outputs.Add("azure_monitor", func() telegraf.Output {
 		return &MockupOutputPlugin{NamespacePrefix: "Telegraf/"}
@@ -84,8 +84,10 @@ func (p *Process) Stop() {
 		p.cancel()
 	}
 	// close stdin so the app can shut down gracefully.
-	if err := p.Stdin.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
-		p.Log.Errorf("Stdin closed with message: %v", err)
+	if p.Stdin != nil {
+		if err := p.Stdin.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			p.Log.Errorf("Stdin closed with message: %v", err)
+		}
 	}
 	p.mainLoopWg.Wait()
 }
@@ -0,0 +1,397 @@
+package metric
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// CustomBucketsSchema is the schema of Prometheus native histograms with
+// custom bucket boundaries given by the custom values of the histogram
+const CustomBucketsSchema = -53
+
+// maxBucketRange is the maximum number of consecutive buckets covered by the
+// spans of a histogram. This limits the memory required to convert the
+// buckets to dense counts for histograms received from untrusted sources.
+const maxBucketRange = 1 << 16
+
+// ExponentialHistogram is a sparse histogram with exponentially growing
+// bucket boundaries as used by Prometheus native histograms and OpenTelemetry
+// exponential histograms. The boundaries are determined by the schema (or
+// scale) where the bucket with index i covers the range (base^(i-1), base^i]
+// with base = 2^(2^-schema). Histograms with the CustomBucketsSchema use the
+// upper bounds given in CustomValues instead and are only passed through, i.e.
+// Add and the bucket index helpers do not support those.
+//
+// Metrics created with NewExponentialHistogram carry the typed histogram
+// which is returned by ExponentialHistogramOf. For compatibility with plugins
+// only handling fields, e.g. when serializing to line protocol, the histogram
+// is additionally represented by the fields returned by Fields.
+type ExponentialHistogram struct {
+	Schema           int32
+	CounterResetHint uint8
+	ZeroThreshold    float64
+	ZeroCount        float64
+	Count            float64
+	Sum              float64
+
+	// Populated buckets described by spans of consecutive bucket indices
+	// with the absolute bucket counts in the order of the spans.
+	PositiveSpans   []BucketSpan
+	PositiveBuckets []float64
+	NegativeSpans   []BucketSpan
+	NegativeBuckets []float64
+
+	// Upper bounds of the buckets for the CustomBucketsSchema
+	CustomValues []float64
+}
+
+// BucketSpan defines a number of consecutive buckets starting at the given
+// offset. The offset of the first span is the absolute index of the first
+// bucket, all other offsets are relative to the end of the previous span.
+type BucketSpan struct {
+	Offset int32
+	Length uint32
+}
+
+// NewExponentialHistogram creates a metric of type histogram carrying the
+// given histogram
+func NewExponentialHistogram(name string, tags map[string]string, h *ExponentialHistogram, tm time.Time) telegraf.Metric {
+	m := New(name, tags, h.Fields(), tm, telegraf.Histogram).(*metric)
+	m.MetricHistogram = h.Copy()
+	return m
+}
+
+// ExponentialHistogramOf returns the histogram carried by the metric. The
+// typed histogram is returned for metrics created by NewExponentialHistogram
+// as long as none of the histogram fields was modified, otherwise the
+// histogram is extracted from the fields. The second return value is false if
+// the metric is not a histogram or does not contain a valid histogram.
+func ExponentialHistogramOf(m telegraf.Metric) (*ExponentialHistogram, bool) {
+	if m.Type() != telegraf.Histogram {
+		return nil, false
+	}
+	if wm, ok := m.(telegraf.UnwrappableMetric); ok {
+		m = wm.Unwrap()
+	}
+	if rm, ok := m.(*metric); ok && rm.MetricHistogram != nil {
+		return rm.MetricHistogram.Copy(), true
+	}
+	return ExponentialHistogramFromFields(m.Fields())
+}
+
+// ExponentialHistogramFromFields extracts the histogram from the fields of a
+// metric. The second return value is false if the fields do not contain a
+// valid exponential histogram.
+func ExponentialHistogramFromFields(fields map[string]interface{}) (*ExponentialHistogram, bool) {
+	count, ok := fields["count"].(float64)
+	if !ok {
+		return nil, false
+	}
+	sum, ok := fields["sum"].(float64)
+	if !ok {
+		return nil, false
+	}
+	schema, ok := fields["schema"].(int64)
+	if !ok {
+		return nil, false
+	}
+	counterResetHint, ok := fields["counter_reset_hint"].(uint64)
+	if !ok {
+		return nil, false
+	}
+	zeroThreshold, ok := fields["zero_threshold"].(float64)
+	if !ok {
+		return nil, false
+	}
+	zeroCount, ok := fields["zero_count"].(float64)
+	if !ok {
+		return nil, false
+	}
+
+	h := &ExponentialHistogram{
+		Schema:           int32(schema),
+		CounterResetHint: uint8(counterResetHint),
+		ZeroThreshold:    zeroThreshold,
+		ZeroCount:        zeroCount,
+		Count:            count,
+		Sum:              sum,
+		PositiveSpans:    spansFromFields(fields, "positive"),
+		PositiveBuckets:  bucketsFromFields(fields, "positive"),
+		NegativeSpans:    spansFromFields(fields, "negative"),
+		NegativeBuckets:  bucketsFromFields(fields, "negative"),
+		CustomValues:     customValuesFromFields(fields),
+	}
+	if err := h.Validate(); err != nil {
+		return nil, false
+	}
+	return h, true
+}
+
+// Fields returns the metric fields representing the histogram
+func (h *ExponentialHistogram) Fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"counter_reset_hint": uint64(h.CounterResetHint),
+		"schema":             int64(h.Schema),
+		"zero_threshold":     h.ZeroThreshold,
+		"zero_count":         h.ZeroCount,
+		"count":              h.Count,
+		"sum":                h.Sum,
+	}
+
+	for i, span := range h.PositiveSpans {
+		fields[fmt.Sprintf("positive_span_%d_offset", i)] = int64(span.Offset)
+		fields[fmt.Sprintf("positive_span_%d_length", i)] = uint64(span.Length)
+	}
+	for i, span := range h.NegativeSpans {
+		fields[fmt.Sprintf("negative_span_%d_offset", i)] = int64(span.Offset)
+		fields[fmt.Sprintf("negative_span_%d_length", i)] = uint64(span.Length)
+	}
+	for i, bucket := range h.PositiveBuckets {
+		fields[fmt.Sprintf("positive_bucket_%d", i)] = bucket
+	}
+	for i, bucket := range h.NegativeBuckets {
+		fields[fmt.Sprintf("negative_bucket_%d", i)] = bucket
+	}
+	for i, value := range h.CustomValues {
+		fields[fmt.Sprintf("custom_value_%d", i)] = value
+	}
+
+	return fields
+}
+
+// Copy returns a deep copy of the histogram
+func (h *ExponentialHistogram) Copy() *ExponentialHistogram {
+	c := *h
+	c.PositiveSpans = append([]BucketSpan(nil), h.PositiveSpans...)
+	c.PositiveBuckets = append([]float64(nil), h.PositiveBuckets...)
+	c.NegativeSpans = append([]BucketSpan(nil), h.NegativeSpans...)
+	c.NegativeBuckets = append([]float64(nil), h.NegativeBuckets...)
+	c.CustomValues = append([]float64(nil), h.CustomValues...)
+	return &c
+}
+
+// IsCustomBuckets returns true if the histogram uses custom bucket boundaries
+func (h *ExponentialHistogram) IsCustomBuckets() bool {
+	return h.Schema == CustomBucketsSchema
+}
+
+// Validate checks the consistency of the spans and buckets
+func (h *ExponentialHistogram) Validate() error {
+	if (h.Schema < -4 || h.Schema > 8) && !h.IsCustomBuckets() {
+		return fmt.Errorf("schema %d out of range", h.Schema)
+	}
+	if err := validateSpans(h.PositiveSpans, len(h.PositiveBuckets)); err != nil {
+		return fmt.Errorf("positive buckets: %w", err)
+	}
+	if err := validateSpans(h.NegativeSpans, len(h.NegativeBuckets)); err != nil {
+		return fmt.Errorf("negative buckets: %w", err)
+	}
+	if h.IsCustomBuckets() {
+		if len(h.NegativeBuckets) > 0 {
+			return errors.New("negative buckets not allowed for custom buckets")
+		}
+		// The last bucket is bounded by +Inf which is not contained in the
+		// custom values
+		offset, counts := h.PositiveBucketCounts()
+		if offset < 0 || int(offset)+len(counts) > len(h.CustomValues)+1 {
+			return errors.New("bucket index out of range of custom values")
+		}
+	}
+	return nil
+}
+
+// Add adds the given value with the given count to the histogram. Adding
+// values to a histogram with the counter-reset hint set is not supported.
+func (h *ExponentialHistogram) Add(value, count float64) {
+	h.Count += count
+	h.Sum += value * count
+
+	switch {
+	case math.Abs(value) <= h.ZeroThreshold:
+		h.ZeroCount += count
+	case value > 0:
+		h.PositiveSpans, h.PositiveBuckets = addToBuckets(h.PositiveSpans, h.PositiveBuckets, ExponentialBucketIndex(h.Schema, value), count)
+	default:
+		h.NegativeSpans, h.NegativeBuckets = addToBuckets(h.NegativeSpans, h.NegativeBuckets, ExponentialBucketIndex(h.Schema, -value), count)
+	}
+}
+
+// PositiveBucketCounts returns the index of the first populated positive
+// bucket and the dense counts of all consecutive buckets.
+func (h *ExponentialHistogram) PositiveBucketCounts() (int32, []float64) {
+	return denseBuckets(h.PositiveSpans, h.PositiveBuckets)
+}
+
+// NegativeBucketCounts returns the index of the first populated negative
+// bucket and the dense counts of all consecutive buckets.
+func (h *ExponentialHistogram) NegativeBucketCounts() (int32, []float64) {
+	return denseBuckets(h.NegativeSpans, h.NegativeBuckets)
+}
+
+// SetPositiveBucketCounts sets the positive buckets from dense counts
+// starting at the given bucket index. Empty buckets are omitted.
+func (h *ExponentialHistogram) SetPositiveBucketCounts(offset int32, counts []float64) {
+	h.PositiveSpans, h.PositiveBuckets = sparseBuckets(offset, counts)
+}
+
+// SetNegativeBucketCounts sets the negative buckets from dense counts
+// starting at the given bucket index. Empty buckets are omitted.
+func (h *ExponentialHistogram) SetNegativeBucketCounts(offset int32, counts []float64) {
+	h.NegativeSpans, h.NegativeBuckets = sparseBuckets(offset, counts)
+}
+
+// ExponentialBucketIndex returns the index of the bucket containing the given
+// positive value for the schema.
+func ExponentialBucketIndex(schema int32, value float64) int32 {
+	frac, exp := math.Frexp(value)
+	log2 := math.Log2(value)
+	if frac == 0.5 {
+		// Use the exact value for powers of two to avoid rounding issues
+		log2 = float64(exp - 1)
+	}
+	return int32(math.Ceil(math.Ldexp(log2, int(schema))))
+}
+
+// ExponentialBucketUpperBound returns the inclusive upper bound of the bucket
+// with the given index for the schema.
+func ExponentialBucketUpperBound(schema, index int32) float64 {
+	return math.Exp2(math.Ldexp(float64(index), -int(schema)))
+}
+
+// isHistogramField returns true if the field is part of the representation
+// of a histogram
+func isHistogramField(key string) bool {
+	switch key {
+	case "counter_reset_hint", "schema", "zero_threshold", "zero_count", "count", "sum":
+		return true
+	}
+	return strings.HasPrefix(key, "positive_") || strings.HasPrefix(key, "negative_") || strings.HasPrefix(key, "custom_value_")
+}
+
+func spansFromFields(fields map[string]interface{}, prefix string) []BucketSpan {
+	var spans []BucketSpan
+	for i := 0; ; i++ {
+		offset, ok := fields[fmt.Sprintf("%s_span_%d_offset", prefix, i)].(int64)
+		if !ok {
+			return spans
+		}
+		length, ok := fields[fmt.Sprintf("%s_span_%d_length", prefix, i)].(uint64)
+		if !ok {
+			return spans
+		}
+		spans = append(spans, BucketSpan{Offset: int32(offset), Length: uint32(length)})
+	}
+}
+
+func bucketsFromFields(fields map[string]interface{}, prefix string) []float64 {
+	var buckets []float64
+	for i := 0; ; i++ {
+		bucket, ok := fields[fmt.Sprintf("%s_bucket_%d", prefix, i)].(float64)
+		if !ok {
+			return buckets
+		}
+		buckets = append(buckets, bucket)
+	}
+}
+
+func customValuesFromFields(fields map[string]interface{}) []float64 {
+	var values []float64
+	for i := 0; ; i++ {
+		value, ok := fields[fmt.Sprintf("custom_value_%d", i)].(float64)
+		if !ok {
+			return values
+		}
+		values = append(values, value)
+	}
+}
+
+func validateSpans(spans []BucketSpan, n int) error {
+	var total, covered int
+	for i, span := range spans {
+		if i > 0 {
+			if span.Offset < 0 {
+				return fmt.Errorf("span %d has negative offset %d", i, span.Offset)
+			}
+			covered += int(span.Offset)
+		}
+		total += int(span.Length)
+		covered += int(span.Length)
+		if covered > maxBucketRange {
+			return fmt.Errorf("spans cover more than %d buckets", maxBucketRange)
+		}
+	}
+	if total != n {
+		return fmt.Errorf("spans require %d buckets but got %d", total, n)
+	}
+	return nil
+}
+
+func denseBuckets(spans []BucketSpan, buckets []float64) (int32, []float64) {
+	if len(spans) == 0 {
+		return 0, nil
+	}
+
+	var counts []float64
+	var idx int
+	for i, span := range spans {
+		if i > 0 {
+			counts = append(counts, make([]float64, span.Offset)...)
+		}
+		counts = append(counts, buckets[idx:idx+int(span.Length)]...)
+		idx += int(span.Length)
+	}
+	return spans[0].Offset, counts
+}
+
+func sparseBuckets(offset int32, counts []float64) ([]BucketSpan, []float64) {
+	var spans []BucketSpan
+	var buckets []float64
+
+	var gap int32
+	for i, c := range counts {
+		if c == 0 {
+			gap++
+			continue
+		}
+		if len(spans) == 0 {
+			spans = append(spans, BucketSpan{Offset: offset + int32(i)})
+		} else if gap > 0 {
+			spans = append(spans, BucketSpan{Offset: gap})
+		}
+		spans[len(spans)-1].Length++
+		buckets = append(buckets, c)
+		gap = 0
+	}
+	return spans, buckets
+}
+
+func addToBuckets(spans []BucketSpan, buckets []float64, index int32, count float64) ([]BucketSpan, []float64) {
+	// Convert to a map of absolute indices to simplify insertion
+	offset, dense := denseBuckets(spans, buckets)
+	values := make(map[int32]float64, len(dense)+1)
+	for i, c := range dense {
+		if c != 0 {
+			values[offset+int32(i)] = c
+		}
+	}
+	values[index] += count
+
+	indices := make([]int32, 0, len(values))
+	for i := range values {
+		indices = append(indices, i)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	first := indices[0]
+	dense = make([]float64, indices[len(indices)-1]-first+1)
+	for _, i := range indices {
+		dense[i-first] = values[i]
+	}
+	return sparseBuckets(first, dense)
+}
@@ -0,0 +1,171 @@
+package metric
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+)
+
+func TestExponentialBucketIndex(t *testing.T) {
+	tests := []struct {
+		schema   int32
+		value    float64
+		expected int32
+	}{
+		{schema: 0, value: 1, expected: 0},
+		{schema: 0, value: 1.5, expected: 1},
+		{schema: 0, value: 2, expected: 1},
+		{schema: 0, value: 3, expected: 2},
+		{schema: 0, value: 0.5, expected: -1},
+		{schema: 1, value: 2, expected: 2},
+		{schema: 1, value: 1.4, expected: 1},
+		{schema: 1, value: 1.5, expected: 2},
+		{schema: -1, value: 4, expected: 1},
+		{schema: -1, value: 5, expected: 2},
+	}
+
+	for _, tt := range tests {
+		actual := ExponentialBucketIndex(tt.schema, tt.value)
+		require.Equalf(t, tt.expected, actual, "schema %d value %v", tt.schema, tt.value)
+		require.LessOrEqual(t, tt.value, ExponentialBucketUpperBound(tt.schema, actual))
+		require.Greater(t, tt.value, ExponentialBucketUpperBound(tt.schema, actual-1))
+	}
+}
+
+func TestExponentialHistogramAdd(t *testing.T) {
+	h := &ExponentialHistogram{ZeroThreshold: 0.001}
+	for _, v := range []float64{0, 1, 2, 2, 16, -3} {
+		h.Add(v, 1)
+	}
+
+	expected := &ExponentialHistogram{
+		ZeroThreshold:   0.001,
+		ZeroCount:       1,
+		Count:           6,
+		Sum:             18,
+		PositiveSpans:   []BucketSpan{{Offset: 0, Length: 2}, {Offset: 2, Length: 1}},
+		PositiveBuckets: []float64{1, 2, 1},
+		NegativeSpans:   []BucketSpan{{Offset: 2, Length: 1}},
+		NegativeBuckets: []float64{1},
+	}
+	require.Equal(t, expected, h)
+	require.NoError(t, h.Validate())
+
+	offset, counts := h.PositiveBucketCounts()
+	require.Equal(t, int32(0), offset)
+	require.Equal(t, []float64{1, 2, 0, 0, 1}, counts)
+}
+
+func TestExponentialHistogramFieldsRoundtrip(t *testing.T) {
+	h := &ExponentialHistogram{
+		Schema:        2,
+		ZeroThreshold: 0.001,
+		ZeroCount:     3,
+		Count:         10,
+		Sum:           42.5,
+	}
+	h.SetPositiveBucketCounts(-2, []float64{1, 0, 0, 2, 3})
+	h.SetNegativeBucketCounts(4, []float64{0, 1})
+
+	fields := h.Fields()
+	require.Equal(t, int64(-2), fields["positive_span_0_offset"])
+	require.Equal(t, uint64(1), fields["positive_span_0_length"])
+	require.Equal(t, int64(2), fields["positive_span_1_offset"])
+	require.Equal(t, uint64(2), fields["positive_span_1_length"])
+	require.Equal(t, int64(5), fields["negative_span_0_offset"])
+
+	actual, ok := ExponentialHistogramFromFields(fields)
+	require.True(t, ok)
+	require.Equal(t, h, actual)
+
+	// Inconsistent spans and buckets
+	delete(fields, "positive_bucket_2")
+	_, ok = ExponentialHistogramFromFields(fields)
+	require.False(t, ok)
+
+	// Not a histogram at all
+	_, ok = ExponentialHistogramFromFields(map[string]interface{}{"value": 42.0})
+	require.False(t, ok)
+}
+
+func TestExponentialHistogramCustomBucketsRoundtrip(t *testing.T) {
+	h := &ExponentialHistogram{
+		Schema:          CustomBucketsSchema,
+		Count:           6,
+		Sum:             12,
+		PositiveSpans:   []BucketSpan{{Offset: 0, Length: 3}},
+		PositiveBuckets: []float64{1, 2, 3},
+		CustomValues:    []float64{0.5, 1},
+	}
+	require.NoError(t, h.Validate())
+	require.True(t, h.IsCustomBuckets())
+
+	actual, ok := ExponentialHistogramFromFields(h.Fields())
+	require.True(t, ok)
+	require.Equal(t, h, actual)
+
+	// More buckets than bounded by the custom values
+	h.CustomValues = []float64{0.5}
+	require.Error(t, h.Validate())
+}
+
+func TestExponentialHistogramValidateBucketRange(t *testing.T) {
+	h := &ExponentialHistogram{
+		Schema:          0,
+		Count:           2,
+		PositiveSpans:   []BucketSpan{{Offset: 0, Length: 1}, {Offset: math.MaxInt32, Length: 1}},
+		PositiveBuckets: []float64{1, 1},
+	}
+	require.ErrorContains(t, h.Validate(), "spans cover more than 65536 buckets")
+
+	_, ok := ExponentialHistogramFromFields(h.Fields())
+	require.False(t, ok)
+
+	// Large absolute offsets of the first span are fine
+	h.PositiveSpans = []BucketSpan{{Offset: math.MaxInt32 - 1, Length: 2}}
+	require.NoError(t, h.Validate())
+}
+
+func TestExponentialHistogramTyped(t *testing.T) {
+	h := &ExponentialHistogram{Schema: 1}
+	for _, v := range []float64{1, 2, 4} {
+		h.Add(v, 1)
+	}
+	m := NewExponentialHistogram("test", map[string]string{"host": "a"}, h, time.Unix(0, 0))
+	require.Equal(t, telegraf.Histogram, m.Type())
+
+	// Modifying the source must not affect the metric
+	h.Add(8, 1)
+	actual, ok := ExponentialHistogramOf(m)
+	require.True(t, ok)
+	require.InDelta(t, 3.0, actual.Count, 0)
+
+	// The typed histogram survives copying and tracking
+	actual, ok = ExponentialHistogramOf(m.Copy())
+	require.True(t, ok)
+	require.InDelta(t, 3.0, actual.Count, 0)
+	tm, _ := WithTracking(m.Copy(), func(telegraf.DeliveryInfo) {})
+	actual, ok = ExponentialHistogramOf(tm)
+	require.True(t, ok)
+	require.InDelta(t, 3.0, actual.Count, 0)
+
+	// Unrelated fields keep the typed histogram
+	m.AddField("other", 42.0)
+	require.NotNil(t, m.(*metric).MetricHistogram)
+
+	// Modifying histogram fields falls back to the fields
+	m.AddField("count", 5.0)
+	require.Nil(t, m.(*metric).MetricHistogram)
+	actual, ok = ExponentialHistogramOf(m)
+	require.True(t, ok)
+	require.InDelta(t, 5.0, actual.Count, 0)
+
+	// Histograms require the histogram type
+	m.SetType(telegraf.Untyped)
+	_, ok = ExponentialHistogramOf(m)
+	require.False(t, ok)
+}
@@ -18,6 +18,10 @@ type metric struct {
 	MetricTime   time.Time
 
 	MetricType telegraf.ValueType
+
+	// Typed histogram of metrics created by NewExponentialHistogram, reset
+	// when modifying the histogram fields or the type
+	MetricHistogram *ExponentialHistogram
 }
 
 func New(
@@ -84,6 +88,13 @@ func FromMetric(other telegraf.Metric) telegraf.Metric {
 	for i, field := range other.FieldList() {
 		m.MetricFields[i] = &telegraf.Field{Key: field.Key, Value: field.Value}
 	}
+
+	if wm, ok := other.(telegraf.UnwrappableMetric); ok {
+		other = wm.Unwrap()
+	}
+	if om, ok := other.(*metric); ok && om.MetricHistogram != nil {
+		m.MetricHistogram = om.MetricHistogram.Copy()
+	}
 	return m
 }
 
@@ -195,6 +206,9 @@ func (m *metric) RemoveTag(key string) {
 }
 
 func (m *metric) AddField(key string, value interface{}) {
+	if isHistogramField(key) {
+		m.MetricHistogram = nil
+	}
 	for i, field := range m.MetricFields {
 		if key == field.Key {
 			m.MetricFields[i] = &telegraf.Field{Key: key, Value: convertField(value)}
@@ -230,6 +244,9 @@ func (m *metric) Field(key string) interface{} {
 }
 
 func (m *metric) RemoveField(key string) {
+	if isHistogramField(key) {
+		m.MetricHistogram = nil
+	}
 	for i, field := range m.MetricFields {
 		if field.Key == key {
 			copy(m.MetricFields[i:], m.MetricFields[i+1:])
@@ -245,6 +262,9 @@ func (m *metric) SetTime(t time.Time) {
 }
 
 func (m *metric) SetType(t telegraf.ValueType) {
+	if t != telegraf.Histogram {
+		m.MetricHistogram = nil
+	}
 	m.MetricType = t
 }
 
@@ -264,6 +284,10 @@ func (m *metric) Copy() telegraf.Metric {
 	for i, field := range m.MetricFields {
 		m2.MetricFields[i] = &telegraf.Field{Key: field.Key, Value: field.Value}
 	}
+
+	if m.MetricHistogram != nil {
+		m2.MetricHistogram = m.MetricHistogram.Copy()
+	}
 	return m2
 }
 
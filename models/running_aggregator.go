@@ -1,10 +1,14 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
 	logging "github.com/influxdata/telegraf/logger"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/selfstat"
@@ -18,10 +22,23 @@ type RunningAggregator struct {
 	periodEnd   time.Time
 	log         telegraf.Logger
 
+	// Buffered metrics and the end of already pushed windows for sliding
+	// windows and late-metric handling
+	buffer   []windowedMetric
+	pushed   []time.Time
+	dirty    map[time.Time]bool
+	overflow int64
+
+	// Set if starting the aggregator failed and needs to be retried
+	pending bool
+	retries uint64
+
 	MetricsPushed   selfstat.Stat
 	MetricsFiltered selfstat.Stat
 	MetricsDropped  selfstat.Stat
 	PushTime        selfstat.Stat
+	StartupErrors   selfstat.Stat
+	StartupPending  selfstat.Stat
 }
 
 func NewRunningAggregator(aggregator telegraf.Aggregator, config *AggregatorConfig) *RunningAggregator {
@@ -67,6 +84,16 @@ func NewRunningAggregator(aggregator telegraf.Aggregator, config *AggregatorConf
 			"push_time_ns",
 			tags,
 		),
+		StartupErrors: selfstat.Register(
+			"aggregate",
+			"startup_errors",
+			tags,
+		),
+		StartupPending: selfstat.Register(
+			"aggregate",
+			"startup_pending",
+			tags,
+		),
 		log: logger,
 	}
 }
@@ -81,8 +108,15 @@ type AggregatorConfig struct {
 	Period       time.Duration
 	Delay        time.Duration
 	Grace        time.Duration
+	Step         time.Duration
+	Lateness     time.Duration
+	UpdatedTag   string
 	LogLevel     string
 
+	WindowBufferLimit int
+
+	StartupErrorBehavior string
+
 	NameOverride      string
 	MeasurementPrefix string
 	MeasurementSuffix string
@@ -95,6 +129,12 @@ func (r *RunningAggregator) LogName() string {
 }
 
 func (r *RunningAggregator) Init() error {
+	switch r.Config.StartupErrorBehavior {
+	case "", "error", "retry", "ignore":
+	default:
+		return fmt.Errorf("invalid 'startup_error_behavior' setting %q", r.Config.StartupErrorBehavior)
+	}
+
 	if p, ok := r.Aggregator.(telegraf.Initializer); ok {
 		err := p.Init()
 		if err != nil {
@@ -104,6 +144,77 @@ func (r *RunningAggregator) Init() error {
 	return nil
 }
 
+// Stop releases the resources of aggregators holding e.g. a process
+// Start starts service aggregators and handles startup errors according to
+// the configured startup-error behavior.
+func (r *RunningAggregator) Start() error {
+	r.Lock()
+	defer r.Unlock()
+
+	p, ok := r.Aggregator.(telegraf.ServiceAggregator)
+	if !ok {
+		return nil
+	}
+
+	// Try to start and exit early on success
+	err := p.Start()
+	if err == nil {
+		return nil
+	}
+	r.StartupErrors.Incr(1)
+
+	// Check if the plugin reports a retry-able error, otherwise we exit.
+	var serr *internal.StartupError
+	if !errors.As(err, &serr) || !serr.Retry {
+		return err
+	}
+
+	// Handle the retry-able error depending on the configured behavior
+	switch r.Config.StartupErrorBehavior {
+	case "", "error": // fall-trough to return the actual error
+	case "retry":
+		r.log.Infof("Start failed: %v; retrying...", err)
+		r.pending = true
+		r.StartupPending.Set(1)
+		return nil
+	case "ignore":
+		return &internal.FatalError{Err: serr}
+	default:
+		r.log.Errorf("Invalid 'startup_error_behavior' setting %q", r.Config.StartupErrorBehavior)
+	}
+
+	return err
+}
+
+// retryStart tries to start the aggregator again if a previous start failed
+func (r *RunningAggregator) retryStart() {
+	if !r.pending {
+		return
+	}
+
+	r.retries++
+	if err := r.Aggregator.(telegraf.ServiceAggregator).Start(); err != nil {
+		r.StartupErrors.Incr(1)
+		r.log.Errorf("Start failed: %v", err)
+		return
+	}
+	r.pending = false
+	r.StartupPending.Set(0)
+	r.log.Debugf("Successfully started after %d attempts", r.retries)
+}
+
+func (r *RunningAggregator) Stop() {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.pending {
+		return
+	}
+	if p, ok := r.Aggregator.(telegraf.ServiceAggregator); ok {
+		p.Stop()
+	}
+}
+
 func (r *RunningAggregator) ID() string {
 	if p, ok := r.Aggregator.(telegraf.PluginWithID); ok {
 		return p.ID()
@@ -115,6 +226,21 @@ func (r *RunningAggregator) Period() time.Duration {
 	return r.Config.Period
 }
 
+// Step returns the interval between two consecutive pushes of the aggregator
+// which equals the period for tumbling windows.
+func (r *RunningAggregator) Step() time.Duration {
+	if r.Config.Step > 0 {
+		return r.Config.Step
+	}
+	return r.Config.Period
+}
+
+// windowed returns true if the aggregator needs to buffer the metrics to
+// support sliding windows or re-emitting windows for late metrics.
+func (r *RunningAggregator) windowed() bool {
+	return r.Step() != r.Config.Period || r.Config.Lateness > 0
+}
+
 func (r *RunningAggregator) EndPeriod() time.Time {
 	return r.periodEnd
 }
@@ -164,6 +290,16 @@ func (r *RunningAggregator) Add(m telegraf.Metric) bool {
 	r.Lock()
 	defer r.Unlock()
 
+	// Pass the original metric on while the aggregator is not yet started
+	// to not lose any data
+	if r.pending {
+		return false
+	}
+
+	if r.windowed() {
+		return r.addWindowed(m)
+	}
+
 	if m.Time().Before(r.periodStart.Add(-r.Config.Grace)) || m.Time().After(r.periodEnd.Add(r.Config.Delay)) {
 		r.log.Debugf("Metric is outside aggregation window; discarding. %s: m: %s e: %s g: %s",
 			m.Time(), r.periodStart, r.periodEnd, r.Config.Grace)
@@ -175,10 +311,84 @@ func (r *RunningAggregator) Add(m telegraf.Metric) bool {
 	return r.Config.DropOriginal
 }
 
+// addWindowed buffers the metric for aggregating all windows containing the
+// metric. Windows already pushed are marked for being re-emitted if they are
+// still within the allowed lateness.
+func (r *RunningAggregator) addWindowed(m telegraf.Metric) bool {
+	ts := m.Time()
+	if ts.After(r.periodEnd.Add(r.Config.Delay)) {
+		r.log.Debugf("Metric is outside aggregation window; discarding. %s: m: %s e: %s",
+			ts, r.periodStart, r.periodEnd)
+		r.MetricsDropped.Incr(1)
+		return r.Config.DropOriginal
+	}
+
+	accepted := !ts.Before(r.periodStart)
+	for _, end := range r.pushed {
+		if !ts.Before(end.Add(-r.Config.Period)) && ts.Before(end) {
+			if r.dirty == nil {
+				r.dirty = make(map[time.Time]bool)
+			}
+			r.dirty[end] = true
+			accepted = true
+		}
+	}
+
+	// Roll metrics within the grace duration into the current window as done
+	// for tumbling windows but keep metrics of already pushed windows there
+	if !accepted && !ts.Before(r.periodStart.Add(-r.Config.Grace)) {
+		ts = r.periodStart
+		accepted = true
+	}
+	if !accepted {
+		r.log.Debugf("Metric is later than the allowed lateness; discarding. %s: m: %s l: %s",
+			ts, r.periodStart, r.Config.Lateness)
+		r.MetricsDropped.Incr(1)
+		return r.Config.DropOriginal
+	}
+
+	r.limitBuffer()
+	r.buffer = append(r.buffer, windowedMetric{metric: m, time: ts})
+	return r.Config.DropOriginal
+}
+
+// limitBuffer makes room for a new metric if the buffer reached its limit by
+// dropping the oldest windows still accepting late metrics first and the
+// oldest metrics of the current window afterwards.
+func (r *RunningAggregator) limitBuffer() {
+	limit := r.Config.WindowBufferLimit
+	if limit <= 0 {
+		return
+	}
+
+	for len(r.buffer) >= limit && len(r.pushed) > 0 {
+		delete(r.dirty, r.pushed[0])
+		r.pushed = r.pushed[1:]
+		r.overflow += int64(r.pruneBuffer())
+	}
+
+	for len(r.buffer) >= limit {
+		oldest := 0
+		for i, wm := range r.buffer {
+			if wm.time.Before(r.buffer[oldest].time) {
+				oldest = i
+			}
+		}
+		r.buffer = slices.Delete(r.buffer, oldest, oldest+1)
+		r.overflow++
+	}
+}
+
 func (r *RunningAggregator) Push(acc telegraf.Accumulator) {
 	r.Lock()
 	defer r.Unlock()
 
+	r.retryStart()
+	if r.windowed() {
+		r.pushWindowed(acc)
+		return
+	}
+
 	since := r.periodEnd
 	until := r.periodEnd.Add(r.Config.Period)
 
@@ -193,6 +403,9 @@ func (r *RunningAggregator) Push(acc telegraf.Accumulator) {
 	}
 
 	r.UpdateWindow(since, until)
+	if r.pending {
+		return
+	}
 
 	start := time.Now()
 	r.Aggregator.Push(acc)
@@ -201,6 +414,141 @@ func (r *RunningAggregator) Push(acc telegraf.Accumulator) {
 	r.Aggregator.Reset()
 }
 
+// pushWindowed re-emits all windows updated by late metrics and pushes the
+// current window before advancing the window by one step.
+func (r *RunningAggregator) pushWindowed(acc telegraf.Accumulator) {
+	if !r.pending {
+		start := time.Now()
+		for _, end := range r.pushed {
+			if r.dirty[end] {
+				r.aggregateWindow(acc, end, true)
+			}
+		}
+		r.aggregateWindow(acc, r.periodEnd, false)
+		r.PushTime.Incr(time.Since(start).Nanoseconds())
+	}
+	r.dirty = nil
+
+	if r.overflow > 0 {
+		r.log.Warnf("Window buffer limit of %d metrics exceeded; dropped %d metrics of the oldest windows",
+			r.Config.WindowBufferLimit, r.overflow)
+		r.MetricsDropped.Incr(r.overflow)
+		r.overflow = 0
+	}
+
+	// Remember the pushed window as long as late metrics are accepted for it
+	r.pushed = append(r.pushed, r.periodEnd)
+	watermark := r.periodEnd.Add(-r.Config.Lateness)
+	idx := 0
+	for _, end := range r.pushed {
+		if end.After(watermark) {
+			r.pushed[idx] = end
+			idx++
+		}
+	}
+	r.pushed = r.pushed[:idx]
+
+	// Advance the window by one step, see Push for details on the check
+	until := r.periodEnd.Add(r.Step())
+	nowWall := time.Now().Truncate(-1)
+	if nowWall.Before(r.periodEnd.Truncate(-1)) || nowWall.After(until.Truncate(-1)) {
+		until = nowWall.Truncate(r.Step()).Add(r.Step())
+	}
+	r.UpdateWindow(until.Add(-r.Config.Period), until)
+
+	r.pruneBuffer()
+}
+
+// pruneBuffer removes all metrics neither contained in the current window nor
+// in a window updatable by late metrics and returns the number of removed
+// metrics.
+func (r *RunningAggregator) pruneBuffer() int {
+	oldest := r.periodStart
+	for _, end := range r.pushed {
+		if s := end.Add(-r.Config.Period); s.Before(oldest) {
+			oldest = s
+		}
+	}
+	idx := 0
+	for _, wm := range r.buffer {
+		if !wm.time.Before(oldest) {
+			r.buffer[idx] = wm
+			idx++
+		}
+	}
+	removed := len(r.buffer) - idx
+	clear(r.buffer[idx:])
+	r.buffer = r.buffer[:idx]
+	return removed
+}
+
+// aggregateWindow feeds all buffered metrics of the window ending at the given
+// time to the aggregator and pushes the result. Re-emitted windows are marked
+// with the configured tag, if any.
+func (r *RunningAggregator) aggregateWindow(acc telegraf.Accumulator, end time.Time, updated bool) {
+	begin := end.Add(-r.Config.Period)
+	r.Aggregator.Reset()
+	for _, wm := range r.buffer {
+		if !wm.time.Before(begin) && wm.time.Before(end) {
+			r.Aggregator.Add(wm.metric)
+		}
+	}
+	if updated && r.Config.UpdatedTag != "" {
+		acc = &updatedAccumulator{Accumulator: acc, tag: r.Config.UpdatedTag}
+	}
+	r.Aggregator.Push(acc)
+	r.Aggregator.Reset()
+}
+
 func (r *RunningAggregator) Log() telegraf.Logger {
 	return r.log
 }
+
+// windowedMetric is a buffered metric together with the time used to assign
+// the metric to windows which differs from the metric time for metrics
+// accepted within the grace duration.
+type windowedMetric struct {
+	metric telegraf.Metric
+	time   time.Time
+}
+
+// updatedAccumulator marks all metrics of a re-emitted window with a tag.
+type updatedAccumulator struct {
+	telegraf.Accumulator
+	tag string
+}
+
+func (u *updatedAccumulator) AddFields(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	u.Accumulator.AddFields(measurement, fields, u.tags(tags), t...)
+}
+
+func (u *updatedAccumulator) AddGauge(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	u.Accumulator.AddGauge(measurement, fields, u.tags(tags), t...)
+}
+
+func (u *updatedAccumulator) AddCounter(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	u.Accumulator.AddCounter(measurement, fields, u.tags(tags), t...)
+}
+
+func (u *updatedAccumulator) AddSummary(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	u.Accumulator.AddSummary(measurement, fields, u.tags(tags), t...)
+}
+
+func (u *updatedAccumulator) AddHistogram(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	u.Accumulator.AddHistogram(measurement, fields, u.tags(tags), t...)
+}
+
+func (u *updatedAccumulator) AddMetric(m telegraf.Metric) {
+	m.AddTag(u.tag, "true")
+	u.Accumulator.AddMetric(m)
+}
+
+func (u *updatedAccumulator) tags(tags map[string]string) map[string]string {
+	// Copy the tags as the aggregator might reuse the map
+	t := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		t[k] = v
+	}
+	t[u.tag] = "true"
+	return t
+}
@@ -1,12 +1,14 @@
 package models
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/testutil"
 )
@@ -240,6 +242,253 @@ func TestRunningAggregatorAddDoesNotModifyMetric(t *testing.T) {
 	testutil.RequireMetricEqual(t, expected, m)
 }
 
+func TestRunningAggregatorSlidingWindow(t *testing.T) {
+	ra := NewRunningAggregator(&mockAggregator{}, &AggregatorConfig{
+		Name: "TestRunningAggregator",
+		Filter: Filter{
+			NamePass: []string{"*"},
+		},
+		Period: 3 * time.Second,
+		Step:   time.Second,
+		Delay:  time.Second,
+	})
+	require.NoError(t, ra.Config.Filter.Compile())
+	require.Equal(t, time.Second, ra.Step())
+
+	end := time.Now().Truncate(time.Second)
+	ra.UpdateWindow(end.Add(-ra.Config.Period), end)
+
+	for i, offset := range []time.Duration{-2500 * time.Millisecond, -500 * time.Millisecond, 500 * time.Millisecond} {
+		m := metric.New("RITest",
+			map[string]string{},
+			map[string]interface{}{"value": int64(1) << (2 * i)},
+			end.Add(offset),
+		)
+		require.False(t, ra.Add(m))
+	}
+
+	var acc testutil.Accumulator
+	ra.Push(&acc)
+	ra.Push(&acc)
+
+	expected := []telegraf.Metric{
+		metric.New("TestMetric", map[string]string{}, map[string]interface{}{"sum": int64(5)}, time.Unix(0, 0)),
+		metric.New("TestMetric", map[string]string{}, map[string]interface{}{"sum": int64(20)}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestRunningAggregatorAllowedLateness(t *testing.T) {
+	ra := NewRunningAggregator(&mockAggregator{}, &AggregatorConfig{
+		Name: "TestRunningAggregator",
+		Filter: Filter{
+			NamePass: []string{"*"},
+		},
+		Period:     time.Second,
+		Lateness:   2 * time.Second,
+		UpdatedTag: "updated",
+	})
+	require.NoError(t, ra.Config.Filter.Compile())
+
+	end := time.Now().Truncate(time.Second)
+	ra.UpdateWindow(end.Add(-ra.Config.Period), end)
+
+	m := metric.New("RITest", map[string]string{}, map[string]interface{}{"value": int64(1)}, end.Add(-500*time.Millisecond))
+	require.False(t, ra.Add(m))
+
+	var acc testutil.Accumulator
+	ra.Push(&acc)
+
+	// Late metric for the already pushed window
+	m = metric.New("RITest", map[string]string{}, map[string]interface{}{"value": int64(2)}, end.Add(-200*time.Millisecond))
+	require.False(t, ra.Add(m))
+
+	// Metric in the current window
+	m = metric.New("RITest", map[string]string{}, map[string]interface{}{"value": int64(10)}, end.Add(500*time.Millisecond))
+	require.False(t, ra.Add(m))
+
+	// Metric exceeding the allowed lateness
+	dropped := ra.MetricsDropped.Get()
+	m = metric.New("RITest", map[string]string{}, map[string]interface{}{"value": int64(100)}, end.Add(-5*time.Second))
+	require.False(t, ra.Add(m))
+	require.Equal(t, dropped+1, ra.MetricsDropped.Get())
+
+	ra.Push(&acc)
+
+	expected := []telegraf.Metric{
+		metric.New("TestMetric", map[string]string{}, map[string]interface{}{"sum": int64(1)}, time.Unix(0, 0)),
+		metric.New("TestMetric", map[string]string{"updated": "true"}, map[string]interface{}{"sum": int64(3)}, time.Unix(0, 0)),
+		metric.New("TestMetric", map[string]string{}, map[string]interface{}{"sum": int64(10)}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestRunningAggregatorSlidingWindowWithGrace(t *testing.T) {
+	ra := NewRunningAggregator(&mockAggregator{}, &AggregatorConfig{
+		Name: "TestRunningAggregator",
+		Filter: Filter{
+			NamePass: []string{"*"},
+		},
+		Period: 2 * time.Second,
+		Step:   time.Second,
+		Grace:  time.Second,
+	})
+	require.NoError(t, ra.Config.Filter.Compile())
+
+	end := time.Now().Truncate(time.Second)
+	ra.UpdateWindow(end.Add(-ra.Config.Period), end)
+
+	// Metric within the grace duration
+	m := metric.New("RITest", map[string]string{}, map[string]interface{}{"value": int64(1)}, end.Add(-2500*time.Millisecond))
+	require.False(t, ra.Add(m))
+
+	// Metric before the grace duration
+	dropped := ra.MetricsDropped.Get()
+	m = metric.New("RITest", map[string]string{}, map[string]interface{}{"value": int64(100)}, end.Add(-3500*time.Millisecond))
+	require.False(t, ra.Add(m))
+	require.Equal(t, dropped+1, ra.MetricsDropped.Get())
+
+	// Metric in the current window
+	m = metric.New("RITest", map[string]string{}, map[string]interface{}{"value": int64(10)}, end.Add(-500*time.Millisecond))
+	require.False(t, ra.Add(m))
+
+	var acc testutil.Accumulator
+	ra.Push(&acc)
+	ra.Push(&acc)
+
+	expected := []telegraf.Metric{
+		metric.New("TestMetric", map[string]string{}, map[string]interface{}{"sum": int64(11)}, time.Unix(0, 0)),
+		metric.New("TestMetric", map[string]string{}, map[string]interface{}{"sum": int64(10)}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestRunningAggregatorWindowBufferLimit(t *testing.T) {
+	ra := NewRunningAggregator(&mockAggregator{}, &AggregatorConfig{
+		Name: "TestRunningAggregator",
+		Filter: Filter{
+			NamePass: []string{"*"},
+		},
+		Period:            time.Second,
+		Lateness:          2 * time.Second,
+		WindowBufferLimit: 2,
+	})
+	require.NoError(t, ra.Config.Filter.Compile())
+
+	end := time.Now().Truncate(time.Second)
+	ra.UpdateWindow(end.Add(-ra.Config.Period), end)
+
+	m := metric.New("RITest", map[string]string{}, map[string]interface{}{"value": int64(1)}, end.Add(-500*time.Millisecond))
+	require.False(t, ra.Add(m))
+
+	var acc testutil.Accumulator
+	ra.Push(&acc)
+
+	// Filling the buffer drops the oldest window first and the oldest metric
+	// of the current window afterwards
+	for i, offset := range []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 600 * time.Millisecond} {
+		m := metric.New("RITest", map[string]string{}, map[string]interface{}{"value": int64(10) << i}, end.Add(offset))
+		require.False(t, ra.Add(m))
+	}
+
+	// Late metric for the dropped window
+	m = metric.New("RITest", map[string]string{}, map[string]interface{}{"value": int64(1000)}, end.Add(-200*time.Millisecond))
+	require.False(t, ra.Add(m))
+
+	dropped := ra.MetricsDropped.Get()
+	ra.Push(&acc)
+	require.Equal(t, dropped+2, ra.MetricsDropped.Get())
+
+	expected := []telegraf.Metric{
+		metric.New("TestMetric", map[string]string{}, map[string]interface{}{"sum": int64(1)}, time.Unix(0, 0)),
+		metric.New("TestMetric", map[string]string{}, map[string]interface{}{"sum": int64(60)}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestRunningAggregatorStartupBehaviorInvalid(t *testing.T) {
+	ra := NewRunningAggregator(&mockAggregator{}, &AggregatorConfig{
+		Name:                 "TestRunningAggregator",
+		StartupErrorBehavior: "foo",
+	})
+	require.ErrorContains(t, ra.Init(), "invalid 'startup_error_behavior'")
+}
+
+func TestRunningAggregatorRetryableStartupBehaviorDefault(t *testing.T) {
+	serr := &internal.StartupError{
+		Err:   errors.New("retryable err"),
+		Retry: true,
+	}
+	ra := NewRunningAggregator(
+		&mockServiceAggregator{startupErrorCount: 1, startupError: serr},
+		&AggregatorConfig{Name: "TestRunningAggregator"},
+	)
+	require.NoError(t, ra.Init())
+
+	// If Start() fails, the agent will stop
+	require.ErrorIs(t, ra.Start(), serr)
+}
+
+func TestRunningAggregatorRetryableStartupBehaviorIgnore(t *testing.T) {
+	serr := &internal.StartupError{
+		Err:   errors.New("retryable err"),
+		Retry: true,
+	}
+	ra := NewRunningAggregator(
+		&mockServiceAggregator{startupErrorCount: 1, startupError: serr},
+		&AggregatorConfig{
+			Name:                 "TestRunningAggregator",
+			StartupErrorBehavior: "ignore",
+		},
+	)
+	require.NoError(t, ra.Init())
+
+	// The agent will remove the plugin on a fatal error
+	var fatalErr *internal.FatalError
+	require.ErrorAs(t, ra.Start(), &fatalErr)
+}
+
+func TestRunningAggregatorRetryableStartupBehaviorRetry(t *testing.T) {
+	serr := &internal.StartupError{
+		Err:   errors.New("retryable err"),
+		Retry: true,
+	}
+	a := &mockServiceAggregator{startupErrorCount: 2, startupError: serr}
+	ra := NewRunningAggregator(a, &AggregatorConfig{
+		Name:                 "TestRunningAggregator",
+		StartupErrorBehavior: "retry",
+		DropOriginal:         true,
+		Period:               time.Minute,
+	})
+	require.NoError(t, ra.Init())
+
+	// For retry, Start() should succeed even though there is an error
+	require.NoError(t, ra.Start())
+	require.Equal(t, int64(1), ra.StartupPending.Get())
+
+	now := time.Now()
+	ra.UpdateWindow(now.Add(-time.Minute), now.Add(time.Minute))
+	m := metric.New("cpu", nil, map[string]interface{}{"value": int64(1)}, now)
+
+	// Metrics must be passed on while the aggregator is not started and
+	// nothing must be pushed
+	var acc testutil.Accumulator
+	require.False(t, ra.Add(m))
+	ra.Push(&acc)
+	require.Empty(t, acc.GetTelegrafMetrics())
+	require.Equal(t, int64(1), ra.StartupPending.Get())
+
+	// Starting succeeds on the second retry and pushes the empty window
+	ra.Push(&acc)
+	require.Zero(t, ra.StartupPending.Get())
+	require.True(t, ra.Add(m))
+	ra.Push(&acc)
+	require.Len(t, acc.GetTelegrafMetrics(), 2)
+
+	ra.Stop()
+	require.True(t, a.stopped)
+}
+
 type mockAggregator struct {
 	sum int64
 }
@@ -266,3 +515,23 @@ func (t *mockAggregator) Add(in telegraf.Metric) {
 		}
 	}
 }
+
+type mockServiceAggregator struct {
+	mockAggregator
+
+	startupErrorCount int
+	startupError      error
+	stopped           bool
+}
+
+func (t *mockServiceAggregator) Start() error {
+	if t.startupErrorCount > 0 {
+		t.startupErrorCount--
+		return t.startupError
+	}
+	return nil
+}
+
+func (t *mockServiceAggregator) Stop() {
+	t.stopped = true
+}
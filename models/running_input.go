@@ -30,11 +30,16 @@ type RunningInput struct {
 	gatherStart time.Time
 	gatherEnd   time.Time
 
-	MetricsGathered selfstat.Stat
-	GatherTime      selfstat.Stat
-	GatherTimeouts  selfstat.Stat
-	GatherErrors    selfstat.Stat
-	StartupErrors   selfstat.Stat
+	MetricsGathered           selfstat.Stat
+	GatherTime                selfstat.Stat
+	GatherTimeouts            selfstat.Stat
+	GatherErrors              selfstat.Stat
+	ConsecutiveGatherErrors   selfstat.Stat
+	ConsecutiveGatherTimeouts selfstat.Stat
+	StartupErrors             selfstat.Stat
+	StartupPending            selfstat.Stat
+
+	errorsLogged selfstat.Stat
 }
 
 func NewRunningInput(input telegraf.Input, config *InputConfig) *RunningInput {
@@ -80,12 +85,28 @@ func NewRunningInput(input telegraf.Input, config *InputConfig) *RunningInput {
 			"gather_errors",
 			tags,
 		),
+		ConsecutiveGatherErrors: selfstat.Register(
+			"gather",
+			"consecutive_gather_errors",
+			tags,
+		),
+		ConsecutiveGatherTimeouts: selfstat.Register(
+			"gather",
+			"consecutive_gather_timeouts",
+			tags,
+		),
 		StartupErrors: selfstat.Register(
 			"gather",
 			"startup_errors",
 			tags,
 		),
-		log: logger,
+		StartupPending: selfstat.Register(
+			"gather",
+			"startup_pending",
+			tags,
+		),
+		errorsLogged: errorLogRegister,
+		log:          logger,
 	}
 }
 
@@ -171,6 +192,7 @@ func (r *RunningInput) Start(acc telegraf.Accumulator) error {
 			return err
 		}
 		r.log.Infof("Startup failed: %v; retrying...", err)
+		r.StartupPending.Set(1)
 		return nil
 	case "ignore", "probe":
 		return &internal.FatalError{Err: serr}
@@ -262,16 +284,30 @@ func (r *RunningInput) Gather(acc telegraf.Accumulator) error {
 			r.log.Debugf("Partially connected after %d attempts", r.retries)
 		} else {
 			r.started = true
+			r.StartupPending.Set(0)
 			r.log.Debugf("Successfully connected after %d attempts", r.retries)
 		}
 	}
 
+	logged := r.errorsLogged.Get()
+	timeouts := r.ConsecutiveGatherTimeouts.Get()
+
 	r.gatherStart = time.Now()
 	err := r.Input.Gather(acc)
 	r.gatherEnd = time.Now()
 
 	r.GatherTime.Incr(r.gatherEnd.Sub(r.gatherStart).Nanoseconds())
 
+	// Collections count as failed if an error was returned or logged
+	if err != nil || r.errorsLogged.Get() != logged {
+		r.ConsecutiveGatherErrors.Incr(1)
+	} else {
+		r.ConsecutiveGatherErrors.Set(0)
+	}
+	if r.ConsecutiveGatherTimeouts.Get() == timeouts {
+		r.ConsecutiveGatherTimeouts.Set(0)
+	}
+
 	if err != nil {
 		r.GatherErrors.Incr(1)
 		GlobalGatherErrors.Incr(1)
@@ -291,4 +327,5 @@ func (r *RunningInput) Log() telegraf.Logger {
 func (r *RunningInput) IncrGatherTimeouts() {
 	GlobalGatherTimeouts.Incr(1)
 	r.GatherTimeouts.Incr(1)
+	r.ConsecutiveGatherTimeouts.Incr(1)
 }
@@ -612,6 +612,37 @@ func TestRunningInputStatisticsGatherErrorsCount(t *testing.T) {
 	require.Equal(t, int64(1), GlobalGatherErrors.Get())
 }
 
+func TestRunningInputStatisticsConsecutiveGatherErrors(t *testing.T) {
+	plugin := &mockInput{gatherReturn: errors.New("an error")}
+	model := NewRunningInput(plugin, &InputConfig{
+		Name:  "mock",
+		Alias: "TestRunningInputStatisticsConsecutiveGatherErrors",
+	})
+	require.NoError(t, model.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, model.Start(&acc))
+	defer model.Stop()
+
+	// Each failing collection should increase the count
+	require.Error(t, model.Gather(&acc))
+	require.Error(t, model.Gather(&acc))
+	require.Equal(t, int64(2), model.ConsecutiveGatherErrors.Get())
+
+	// A successful collection should reset the count
+	plugin.gatherReturn = nil
+	require.NoError(t, model.Gather(&acc))
+	require.Zero(t, model.ConsecutiveGatherErrors.Get())
+
+	// Timeouts should be counted until a collection finishes in time
+	model.IncrGatherTimeouts()
+	model.IncrGatherTimeouts()
+	require.Equal(t, int64(2), model.ConsecutiveGatherTimeouts.Get())
+	require.NoError(t, model.Gather(&acc))
+	require.Zero(t, model.ConsecutiveGatherTimeouts.Get())
+	require.Equal(t, int64(2), model.GatherTimeouts.Get())
+}
+
 type mockInput struct {
 	probeReturn  error
 	gatherReturn error
@@ -60,10 +60,12 @@ type RunningOutput struct {
 	MetricBufferLimit int
 	MetricBatchSize   int
 
-	MetricsFiltered selfstat.Stat
-	WriteTime       selfstat.Stat
-	WriteErrors     selfstat.Stat
-	StartupErrors   selfstat.Stat
+	MetricsFiltered        selfstat.Stat
+	WriteTime              selfstat.Stat
+	WriteErrors            selfstat.Stat
+	ConsecutiveWriteErrors selfstat.Stat
+	StartupErrors          selfstat.Stat
+	StartupPending         selfstat.Stat
 
 	BatchReady chan time.Time
 
@@ -136,11 +138,21 @@ func NewRunningOutput(output telegraf.Output, config *OutputConfig, batchSize, b
 			"write_errors",
 			tags,
 		),
+		ConsecutiveWriteErrors: selfstat.Register(
+			"write",
+			"consecutive_write_errors",
+			tags,
+		),
 		StartupErrors: selfstat.Register(
 			"write",
 			"startup_errors",
 			tags,
 		),
+		StartupPending: selfstat.Register(
+			"write",
+			"startup_pending",
+			tags,
+		),
 		log: logger,
 	}
 
@@ -199,6 +211,7 @@ func (r *RunningOutput) Connect() error {
 	case "", "error": // fall-trough to return the actual error
 	case "retry":
 		r.log.Infof("Connect failed: %v; retrying...", err)
+		r.StartupPending.Set(1)
 		return nil
 	case "ignore":
 		return &internal.FatalError{Err: serr}
@@ -312,6 +325,7 @@ func (r *RunningOutput) Write() error {
 			r.log.Debugf("Partially connected after %d attempts", r.retries)
 		} else {
 			r.started = true
+			r.StartupPending.Set(0)
 			r.log.Debugf("Successfully connected after %d attempts", r.retries)
 		}
 	}
@@ -355,6 +369,7 @@ func (r *RunningOutput) WriteBatch() error {
 			return internal.ErrNotConnected
 		}
 		r.started = true
+		r.StartupPending.Set(0)
 		r.log.Debugf("Successfully connected after %d attempts", r.retries)
 	}
 
@@ -378,6 +393,13 @@ func (r *RunningOutput) doTransaction() error {
 	r.updateTransaction(tx, err)
 	r.buffer.EndTransaction(tx)
 
+	// Partial writes only count as failure if no metric was accepted
+	if r.lastWriteFailed.Load() {
+		r.ConsecutiveWriteErrors.Incr(1)
+	} else {
+		r.ConsecutiveWriteErrors.Set(0)
+	}
+
 	if err != nil {
 		r.WriteErrors.Incr(1)
 		GlobalWriteErrors.Incr(1)
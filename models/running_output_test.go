@@ -797,17 +797,19 @@ func TestRunningOutputInternalMetrics(t *testing.T) {
 				"alias":  "test_alias",
 			},
 			map[string]interface{}{
-				"buffer_limit":     10,
-				"buffer_size":      0,
-				"errors":           0,
-				"metrics_added":    0,
-				"metrics_rejected": 0,
-				"metrics_dropped":  0,
-				"metrics_filtered": 0,
-				"metrics_written":  0,
-				"write_errors":     0,
-				"write_time_ns":    0,
-				"startup_errors":   0,
+				"buffer_limit":             10,
+				"buffer_size":              0,
+				"consecutive_write_errors": 0,
+				"errors":                   0,
+				"metrics_added":            0,
+				"metrics_rejected":         0,
+				"metrics_dropped":          0,
+				"metrics_filtered":         0,
+				"metrics_written":          0,
+				"write_errors":             0,
+				"write_time_ns":            0,
+				"startup_errors":           0,
+				"startup_pending":          0,
 			},
 			time.Unix(0, 0),
 		),
@@ -915,14 +917,17 @@ func TestRunningOutputRetryableStartupBehaviorRetry(t *testing.T) {
 	// should return an error on Write() until we successfully connect.
 	require.NotErrorIs(t, ro.Connect(), serr)
 	require.False(t, ro.started)
+	require.Equal(t, int64(1), ro.StartupPending.Get())
 
 	ro.AddMetric(testutil.TestMetric(1))
 	require.ErrorIs(t, ro.Write(), internal.ErrNotConnected)
 	require.False(t, ro.started)
+	require.Equal(t, int64(1), ro.StartupPending.Get())
 
 	ro.AddMetric(testutil.TestMetric(2))
 	require.NoError(t, ro.Write())
 	require.True(t, ro.started)
+	require.Zero(t, ro.StartupPending.Get())
 	require.Equal(t, 1, int(mo.writes.Load()))
 
 	ro.AddMetric(testutil.TestMetric(3))
@@ -1337,6 +1342,39 @@ func TestRunningOutputStatisticsWriteErrorsCount(t *testing.T) {
 	require.Equal(t, int64(2), GlobalWriteErrors.Get())
 }
 
+func TestRunningOutputStatisticsConsecutiveWriteErrors(t *testing.T) {
+	var fail atomic.Bool
+	plugin := &mockOutput{
+		preWriteHook: func([]telegraf.Metric) error {
+			if fail.Load() {
+				return errors.New("an error")
+			}
+			return nil
+		},
+	}
+	model, err := NewRunningOutput(plugin, &OutputConfig{
+		Name:  "mock",
+		Alias: "TestRunningOutputStatisticsConsecutiveWriteErrors",
+	}, 5, 10)
+	require.NoError(t, err)
+	require.NoError(t, model.Init())
+	require.NoError(t, model.Connect())
+	defer model.Close()
+
+	// Each failing write should increase the count
+	fail.Store(true)
+	model.AddMetric(testutil.TestMetric(1))
+	require.Error(t, model.Write())
+	require.Error(t, model.Write())
+	require.Equal(t, int64(2), model.ConsecutiveWriteErrors.Get())
+
+	// A successful write should reset the count
+	fail.Store(false)
+	require.NoError(t, model.Write())
+	require.Zero(t, model.ConsecutiveWriteErrors.Get())
+	require.Equal(t, int64(2), model.WriteErrors.Get())
+}
+
 // Benchmark adding metrics.
 func BenchmarkRunningOutputAddWrite(b *testing.B) {
 	conf := &OutputConfig{
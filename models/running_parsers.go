@@ -84,6 +84,23 @@ func (r *RunningParser) Parse(buf []byte) ([]telegraf.Metric, error) {
 	return m, err
 }
 
+// ParseTopic parses the buffer using the topic if the underlying parser
+// supports this and falls back to Parse otherwise
+func (r *RunningParser) ParseTopic(topic string, buf []byte) ([]telegraf.Metric, error) {
+	p, ok := r.Parser.(telegraf.TopicParser)
+	if !ok {
+		return r.Parse(buf)
+	}
+
+	start := time.Now()
+	m, err := p.ParseTopic(topic, buf)
+	elapsed := time.Since(start)
+	r.ParseTime.Incr(elapsed.Nanoseconds())
+	r.MetricsParsed.Incr(int64(len(m)))
+
+	return m, err
+}
+
 func (r *RunningParser) ParseLine(line string) (telegraf.Metric, error) {
 	start := time.Now()
 	m, err := r.Parser.ParseLine(line)
@@ -102,6 +102,15 @@ func (r *RunningSerializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, e
 	return buf, err
 }
 
+// Headers returns the headers required by the underlying serializer or nil
+// if the serializer does not require any
+func (r *RunningSerializer) Headers() map[string]string {
+	if s, ok := r.Serializer.(telegraf.HeaderSerializer); ok {
+		return s.Headers()
+	}
+	return nil
+}
+
 func (r *RunningSerializer) Log() telegraf.Logger {
 	return r.log
 }
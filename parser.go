@@ -22,6 +22,16 @@ type Parser interface {
 	SetDefaultTags(tags map[string]string)
 }
 
+// TopicParser is an interface for parsers requiring the topic the data was
+// received on, e.g. to decode protocols encoding information in MQTT topics.
+type TopicParser interface {
+	// ParseTopic parses the byte buffer received on the given topic into
+	// telegraf metrics.
+	//
+	// Must be thread-safe.
+	ParseTopic(topic string, buf []byte) ([]Metric, error)
+}
+
 // ParserFunc is a function to create a new instance of a parser
 type ParserFunc func() (Parser, error)
 
@@ -38,3 +48,15 @@ type ParserFuncPlugin interface {
 	// SetParserFunc returns a new parser.
 	SetParserFunc(fn ParserFunc)
 }
+
+// ParserTablePlugin is an interface for plugins using different parsers
+// selected by a key, e.g. the request path. The parsers are configured in an
+// array of sub-tables each containing the key setting and the parser options.
+type ParserTablePlugin interface {
+	// ParserTable returns the name of the sub-table array and the name of the
+	// key setting within each sub-table.
+	ParserTable() (table, key string)
+
+	// SetParserFuncs sets the functions creating the parsers by key.
+	SetParserFuncs(fns map[string]ParserFunc)
+}
@@ -0,0 +1,5 @@
+//go:build !custom || aggregators || aggregators.external
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/aggregators/external" // register plugin
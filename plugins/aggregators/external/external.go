@@ -0,0 +1,102 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package external
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/aggregators"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin/pluginv1"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type External struct {
+	grpcplugin.Config
+	Timeout config.Duration `toml:"timeout"`
+	Log     telegraf.Logger `toml:"-"`
+
+	client     *grpcplugin.Client
+	aggregator pluginv1.AggregatorClient
+}
+
+func (*External) SampleConfig() string {
+	return sampleConfig
+}
+
+func (e *External) Init() error {
+	if len(e.Command) == 0 {
+		return errors.New("no command specified")
+	}
+	return nil
+}
+
+func (e *External) Start() error {
+	client, err := e.Config.StartClient(pluginv1.PluginType_PLUGIN_TYPE_AGGREGATOR, e.Log)
+	if err != nil {
+		return err
+	}
+	e.client = client
+	e.aggregator = pluginv1.NewAggregatorClient(client.Conn)
+	return nil
+}
+
+func (e *External) Add(m telegraf.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	req := &pluginv1.AddRequest{Metrics: []*pluginv1.Metric{grpcplugin.ToProto(m)}}
+	if _, err := e.aggregator.Add(ctx, req); err != nil {
+		e.Log.Errorf("Adding metric failed: %v", err)
+	}
+}
+
+func (e *External) Push(acc telegraf.Accumulator) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	resp, err := e.aggregator.Push(ctx, &pluginv1.PushRequest{})
+	if err != nil {
+		acc.AddError(err)
+		return
+	}
+	for _, pm := range resp.GetMetrics() {
+		acc.AddMetric(grpcplugin.FromProto(pm))
+	}
+}
+
+func (e *External) Reset() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	if _, err := e.aggregator.Reset(ctx, &pluginv1.ResetRequest{}); err != nil {
+		e.Log.Errorf("Resetting plugin failed: %v", err)
+	}
+}
+
+func (e *External) Stop() {
+	if e.client == nil {
+		return
+	}
+	e.client.Stop()
+	e.client = nil
+	e.aggregator = nil
+}
+
+func init() {
+	aggregators.Add("external", func() telegraf.Aggregator {
+		return &External{
+			Config: grpcplugin.Config{
+				RestartDelay: config.Duration(10 * time.Second),
+				StartTimeout: config.Duration(10 * time.Second),
+			},
+			Timeout: config.Duration(5 * time.Second),
+		}
+	})
+}
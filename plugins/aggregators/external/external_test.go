@@ -0,0 +1,145 @@
+package external
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/shim"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+var externalPlugin = flag.Bool("external-plugin", false, "if true, act like an external plugin instead of test")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *externalPlugin {
+		runExternalPlugin()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestAggregation(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	plugin := &External{
+		Config: grpcplugin.Config{
+			Command:      []string{exe, "-external-plugin"},
+			RestartDelay: config.Duration(100 * time.Millisecond),
+			StartTimeout: config.Duration(5 * time.Second),
+		},
+		Timeout: config.Duration(5 * time.Second),
+		Log:     testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Start())
+	defer plugin.Stop()
+
+	for i := range 3 {
+		plugin.Add(metric.New("cpu", nil, map[string]interface{}{"value": i}, time.Unix(0, 0)))
+	}
+
+	var acc testutil.Accumulator
+	plugin.Push(&acc)
+	expected := []telegraf.Metric{
+		metric.New("count", nil, map[string]interface{}{"value": uint64(3)}, time.Unix(0, 0), telegraf.Counter),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTags("pid"))
+	require.Empty(t, acc.Errors)
+
+	// The state of the plugin must be cleared on reset
+	plugin.Reset()
+	acc.ClearMetrics()
+	plugin.Push(&acc)
+	expected = []telegraf.Metric{
+		metric.New("count", nil, map[string]interface{}{"value": uint64(0)}, time.Unix(0, 0), telegraf.Counter),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTags("pid"))
+}
+
+func TestStop(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	plugin := &External{
+		Config: grpcplugin.Config{
+			Command:      []string{exe, "-external-plugin"},
+			RestartDelay: config.Duration(100 * time.Millisecond),
+			StartTimeout: config.Duration(5 * time.Second),
+		},
+		Timeout: config.Duration(5 * time.Second),
+		Log:     testutil.Logger{},
+	}
+
+	// The process must only be started on start
+	require.NoError(t, plugin.Init())
+	require.Nil(t, plugin.client)
+	require.NoError(t, plugin.Start())
+
+	var acc testutil.Accumulator
+	plugin.Push(&acc)
+	require.Empty(t, acc.Errors)
+	metrics := acc.GetTelegrafMetrics()
+	require.Len(t, metrics, 1)
+	tag, found := metrics[0].GetTag("pid")
+	require.True(t, found)
+	pid, err := strconv.Atoi(tag)
+	require.NoError(t, err)
+
+	// The process must exit when stopping the plugin
+	plugin.Stop()
+	require.Eventually(t, func() bool {
+		process, err := os.FindProcess(pid)
+		if err != nil {
+			return true
+		}
+		return process.Signal(syscall.Signal(0)) != nil
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func runExternalPlugin() {
+	s := shim.New()
+	err := s.AddAggregator(&testAggregator{})
+	if err == nil {
+		err = s.Run(shim.PollIntervalDisabled)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // error code is important for this "test"
+		os.Exit(1)
+	}
+}
+
+// testAggregator counts the metrics added since the last reset
+type testAggregator struct {
+	count uint64
+}
+
+func (*testAggregator) SampleConfig() string {
+	return ""
+}
+
+func (a *testAggregator) Add(telegraf.Metric) {
+	a.count++
+}
+
+func (a *testAggregator) Push(acc telegraf.Accumulator) {
+	tags := map[string]string{"pid": strconv.Itoa(os.Getpid())}
+	acc.AddCounter("count", map[string]interface{}{"value": a.count}, tags, time.Unix(0, 0))
+}
+
+func (a *testAggregator) Reset() {
+	a.count = 0
+}
@@ -3,12 +3,14 @@ package histogram
 
 import (
 	_ "embed"
+	"fmt"
 	"sort"
 	"strconv"
 	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/aggregators"
 )
 
@@ -41,9 +43,11 @@ type Histogram struct {
 
 // bucketConfig is the config, which contains name, field of metric and histogram buckets.
 type bucketConfig struct {
-	Metric  string   `toml:"measurement_name"`
-	Fields  []string `toml:"fields"`
-	Buckets buckets  `toml:"buckets"`
+	Metric            string   `toml:"measurement_name"`
+	Fields            []string `toml:"fields"`
+	Buckets           buckets  `toml:"buckets"`
+	ExponentialSchema *int32   `toml:"exponential_schema"`
+	ZeroThreshold     float64  `toml:"zero_threshold"`
 }
 
 // bucketsByMetrics contains the buckets grouped by metric and field name
@@ -58,6 +62,7 @@ type buckets []float64
 // metricHistogramCollection aggregates the histogram data
 type metricHistogramCollection struct {
 	histogramCollection map[string]counts
+	exponential         map[string]*metric.ExponentialHistogram
 	name                string
 	tags                map[string]string
 	expireTime          time.Time
@@ -78,18 +83,38 @@ func (*Histogram) SampleConfig() string {
 	return sampleConfig
 }
 
+func (h *Histogram) Init() error {
+	for _, cfg := range h.Configs {
+		if cfg.ExponentialSchema == nil {
+			continue
+		}
+		if schema := *cfg.ExponentialSchema; schema < -4 || schema > 8 {
+			return fmt.Errorf("exponential schema %d for measurement %q out of range", schema, cfg.Metric)
+		}
+		if len(cfg.Buckets) > 0 {
+			return fmt.Errorf("buckets and exponential schema for measurement %q are mutually exclusive", cfg.Metric)
+		}
+	}
+	return nil
+}
+
 func (h *Histogram) Add(in telegraf.Metric) {
 	addTime := timeNow()
 
 	bucketsByField := make(map[string][]float64)
+	exponentialByField := make(map[string]*bucketConfig)
 	for field := range in.Fields() {
+		if cfg := h.getExponentialConfig(in.Name(), field); cfg != nil {
+			exponentialByField[field] = cfg
+			continue
+		}
 		buckets := h.getBuckets(in.Name(), field)
 		if buckets != nil {
 			bucketsByField[field] = buckets
 		}
 	}
 
-	if len(bucketsByField) == 0 {
+	if len(bucketsByField) == 0 && len(exponentialByField) == 0 {
 		return
 	}
 
@@ -100,10 +125,28 @@ func (h *Histogram) Add(in telegraf.Metric) {
 			name:                in.Name(),
 			tags:                in.Tags(),
 			histogramCollection: make(map[string]counts),
+			exponential:         make(map[string]*metric.ExponentialHistogram),
 		}
 	}
 
 	for field, value := range in.Fields() {
+		if cfg, ok := exponentialByField[field]; ok {
+			if agr.exponential[field] == nil {
+				agr.exponential[field] = &metric.ExponentialHistogram{
+					Schema:        *cfg.ExponentialSchema,
+					ZeroThreshold: cfg.ZeroThreshold,
+				}
+			}
+
+			if value, ok := convert(value); ok {
+				agr.exponential[field].Add(value, 1)
+			}
+			if h.ExpirationInterval != 0 {
+				agr.expireTime = addTime.Add(time.Duration(h.ExpirationInterval))
+			}
+			agr.updated = true
+			continue
+		}
 		if buckets, ok := bucketsByField[field]; ok {
 			if agr.histogramCollection[field] == nil {
 				agr.histogramCollection[field] = make(counts, len(buckets)+1)
@@ -139,6 +182,9 @@ func (h *Histogram) Push(acc telegraf.Accumulator) {
 		for field, counts := range aggregate.histogramCollection {
 			h.groupFieldsByBuckets(&metricsWithGroupedFields, aggregate.name, field, copyTags(aggregate.tags), counts)
 		}
+		for field, histogram := range aggregate.exponential {
+			acc.AddMetric(metric.NewExponentialHistogram(aggregate.name+"_"+field, copyTags(aggregate.tags), histogram, now))
+		}
 	}
 
 	for _, metric := range metricsWithGroupedFields {
@@ -230,6 +276,17 @@ func (h *Histogram) getBuckets(metric, field string) []float64 {
 	return h.buckets[metric][field]
 }
 
+// getExponentialConfig returns the config if the field should be aggregated
+// into an exponential histogram or nil otherwise
+func (h *Histogram) getExponentialConfig(metric, field string) *bucketConfig {
+	for i, cfg := range h.Configs {
+		if cfg.Metric == metric && cfg.ExponentialSchema != nil && isBucketExists(field, cfg) {
+			return &h.Configs[i]
+		}
+	}
+	return nil
+}
+
 // isBucketExists checks if buckets exists for the passed field
 func isBucketExists(field string, cfg bucketConfig) bool {
 	if len(cfg.Fields) == 0 {
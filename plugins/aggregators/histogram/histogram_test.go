@@ -574,3 +574,40 @@ func assertContainsTaggedField(t *testing.T, acc *testutil.Accumulator, metricNa
 
 	require.Failf(t, "Unknown measurement", "Unknown measurement %q with tags: %v, fields: %v", metricName, tags, fields)
 }
+
+func TestHistogramExponential(t *testing.T) {
+	schema := int32(0)
+	cfg := []bucketConfig{{Metric: "first_metric_name", Fields: []string{"a"}, ExponentialSchema: &schema}}
+	histogram := newTestHistogram(cfg, true, true, false)
+	require.NoError(t, histogram.(*Histogram).Init())
+
+	acc := &testutil.Accumulator{}
+	histogram.Add(firstMetric1)
+	histogram.Add(firstMetric2)
+	histogram.Push(acc)
+
+	require.Len(t, acc.Metrics, 1)
+	m := acc.GetTelegrafMetrics()[0]
+	require.Equal(t, "first_metric_name_a", m.Name())
+	require.Equal(t, telegraf.Histogram, m.Type())
+
+	h, ok := metric.ExponentialHistogramFromFields(m.Fields())
+	require.True(t, ok)
+	require.InDelta(t, 31.2, h.Sum, 1e-9)
+	require.Equal(t, &metric.ExponentialHistogram{
+		Count:           2,
+		Sum:             h.Sum,
+		PositiveSpans:   []metric.BucketSpan{{Offset: 4, Length: 1}},
+		PositiveBuckets: []float64{2},
+	}, h)
+}
+
+func TestHistogramExponentialInvalid(t *testing.T) {
+	schema := int32(9)
+	histogram := newTestHistogram([]bucketConfig{{Metric: "foo", ExponentialSchema: &schema}}, true, true, false)
+	require.ErrorContains(t, histogram.(*Histogram).Init(), "out of range")
+
+	schema = 2
+	histogram = newTestHistogram([]bucketConfig{{Metric: "foo", Buckets: []float64{1}, ExponentialSchema: &schema}}, true, true, false)
+	require.ErrorContains(t, histogram.(*Histogram).Init(), "mutually exclusive")
+}
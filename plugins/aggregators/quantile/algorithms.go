@@ -1,9 +1,11 @@
 package quantile
 
 import (
+	"bytes"
 	"math"
 	"sort"
 
+	"github.com/DataDog/sketches-go/ddsketch"
 	"github.com/caio/go-tdigest"
 )
 
@@ -12,8 +14,100 @@ type algorithm interface {
 	Quantile(q float64) float64
 }
 
+// sketch is an algorithm with a serializable state that can be merged with
+// the state of other instances of the same algorithm
+type sketch interface {
+	algorithm
+	AddWeighted(value, count float64) error
+	ForEach(fn func(value, count float64))
+	Marshal() ([]byte, error)
+	Merge(buf []byte) error
+}
+
+type tdigestSketch struct {
+	*tdigest.TDigest
+}
+
 func newTDigest(compression float64) (algorithm, error) {
-	return tdigest.New(tdigest.Compression(compression))
+	td, err := tdigest.New(tdigest.Compression(compression))
+	if err != nil {
+		return nil, err
+	}
+	return &tdigestSketch{td}, nil
+}
+
+// AddWeighted adds the value with the given count rounded to an integer
+func (t *tdigestSketch) AddWeighted(value, count float64) error {
+	return t.TDigest.AddWeighted(value, uint64(math.Round(count)))
+}
+
+// ForEach calls the function for the mean and count of each centroid
+func (t *tdigestSketch) ForEach(fn func(value, count float64)) {
+	t.ForEachCentroid(func(mean float64, count uint64) bool {
+		fn(mean, float64(count))
+		return true
+	})
+}
+
+// Marshal serializes the centroids of the digest
+func (t *tdigestSketch) Marshal() ([]byte, error) {
+	return t.AsBytes()
+}
+
+// Merge merges the serialized digest into the digest
+func (t *tdigestSketch) Merge(buf []byte) error {
+	other, err := tdigest.FromBytes(bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	return t.TDigest.Merge(other)
+}
+
+type ddSketch struct {
+	*ddsketch.DDSketch
+}
+
+func newDDSketch(relativeAccuracy float64) (algorithm, error) {
+	sk, err := ddsketch.NewDefaultDDSketch(relativeAccuracy)
+	if err != nil {
+		return nil, err
+	}
+	return &ddSketch{sk}, nil
+}
+
+// Quantile returns the quantile value for the given q.
+func (d *ddSketch) Quantile(q float64) float64 {
+	v, err := d.GetValueAtQuantile(q)
+	if err != nil {
+		return math.NaN()
+	}
+	return v
+}
+
+// AddWeighted adds the value with the given count
+func (d *ddSketch) AddWeighted(value, count float64) error {
+	return d.AddWithCount(value, count)
+}
+
+// ForEach calls the function for the representative value and count of
+// each bin
+func (d *ddSketch) ForEach(fn func(value, count float64)) {
+	d.DDSketch.ForEach(func(value, count float64) bool {
+		fn(value, count)
+		return false
+	})
+}
+
+// Marshal serializes the sketch including its index mapping
+func (d *ddSketch) Marshal() ([]byte, error) {
+	var buf []byte
+	d.Encode(&buf, false)
+	return buf, nil
+}
+
+// Merge merges the serialized sketch into the sketch
+func (d *ddSketch) Merge(buf []byte) error {
+	return d.DecodeAndMergeWith(buf)
 }
 
 type exactAlgorithmR7 struct {
@@ -3,9 +3,15 @@ package quantile
 
 import (
 	_ "embed"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/aggregators"
 )
 
@@ -13,12 +19,18 @@ import (
 var sampleConfig string
 
 type Quantile struct {
-	Quantiles     []float64       `toml:"quantiles"`
-	Compression   float64         `toml:"compression"`
-	AlgorithmType string          `toml:"algorithm"`
-	Log           telegraf.Logger `toml:"-"`
+	Quantiles             []float64       `toml:"quantiles"`
+	Compression           float64         `toml:"compression"`
+	RelativeAccuracy      float64         `toml:"relative_accuracy"`
+	AlgorithmType         string          `toml:"algorithm"`
+	EmitSketch            bool            `toml:"emit_sketch"`
+	MergeSketches         bool            `toml:"merge_sketches"`
+	SketchFormat          string          `toml:"sketch_format"`
+	NativeHistogramSchema int32           `toml:"native_histogram_schema"`
+	Log                   telegraf.Logger `toml:"-"`
 
 	newAlgorithm newAlgorithmFunc
+	sketchSuffix string
 	cache        map[uint64]aggregate
 
 	suffixes []string
@@ -28,8 +40,15 @@ type aggregate struct {
 	name   string
 	fields map[string]algorithm
 	tags   map[string]string
+
+	// Aggregate of merged native histograms named after the histogram
+	histogram bool
 }
 
+// histogramField is the field name used for aggregates of merged native
+// histograms
+const histogramField = "value"
+
 type newAlgorithmFunc func(compression float64) (algorithm, error)
 
 func (*Quantile) SampleConfig() string {
@@ -40,6 +59,15 @@ func (q *Quantile) Init() error {
 	switch q.AlgorithmType {
 	case "t-digest", "":
 		q.newAlgorithm = newTDigest
+		q.sketchSuffix = "_tdigest"
+	case "ddsketch":
+		if q.RelativeAccuracy <= 0 || q.RelativeAccuracy >= 1 {
+			return fmt.Errorf("relative accuracy %v out of range", q.RelativeAccuracy)
+		}
+		q.newAlgorithm = func(float64) (algorithm, error) {
+			return newDDSketch(q.RelativeAccuracy)
+		}
+		q.sketchSuffix = "_ddsketch"
 	case "exact R7":
 		q.newAlgorithm = newExactR7
 	case "exact R8":
@@ -50,6 +78,21 @@ func (q *Quantile) Init() error {
 	if _, err := q.newAlgorithm(q.Compression); err != nil {
 		return fmt.Errorf("cannot create %q algorithm: %w", q.AlgorithmType, err)
 	}
+	if (q.EmitSketch || q.MergeSketches) && q.sketchSuffix == "" {
+		return errors.New("emitting or merging sketches requires the t-digest or ddsketch algorithm")
+	}
+
+	switch q.SketchFormat {
+	case "":
+		q.SketchFormat = "field"
+	case "field":
+	case "native_histogram":
+		if q.NativeHistogramSchema < -4 || q.NativeHistogramSchema > 8 {
+			return fmt.Errorf("native histogram schema %d out of range", q.NativeHistogramSchema)
+		}
+	default:
+		return fmt.Errorf("unknown sketch format %q", q.SketchFormat)
+	}
 
 	if len(q.Quantiles) == 0 {
 		q.Quantiles = []float64{0.25, 0.5, 0.75}
@@ -74,15 +117,33 @@ func (q *Quantile) Init() error {
 }
 
 func (q *Quantile) Add(in telegraf.Metric) {
+	if q.MergeSketches && q.SketchFormat == "native_histogram" {
+		if h, ok := metric.ExponentialHistogramOf(in); ok {
+			q.mergeHistogram(in, h)
+			return
+		}
+	}
+
+	// Metrics containing sketches are aggregated by merging the sketches only
+	// ignoring all other fields e.g. the quantiles computed by the sender.
+	sketched := q.MergeSketches && q.SketchFormat == "field" && q.hasSketch(in)
+
 	id := in.HashID()
 	if cached, ok := q.cache[id]; ok {
-		fields := in.Fields()
-		for k, algo := range cached.fields {
-			if field, ok := fields[k]; ok {
-				if v, isconvertible := convert(field); isconvertible {
+		for _, field := range in.FieldList() {
+			if sketched {
+				if k, found := strings.CutSuffix(field.Key, q.sketchSuffix); found {
+					if algo, ok := cached.fields[k]; ok {
+						q.mergeSketch(algo, k, field.Value)
+					}
+				}
+				continue
+			}
+			if algo, ok := cached.fields[field.Key]; ok {
+				if v, isconvertible := convert(field.Value); isconvertible {
 					err := algo.Add(v)
 					if err != nil {
-						q.Log.Errorf("adding cached field %s: %v", k, err)
+						q.Log.Errorf("adding cached field %s: %v", field.Key, err)
 					}
 				}
 			}
@@ -97,13 +158,25 @@ func (q *Quantile) Add(in telegraf.Metric) {
 		fields: make(map[string]algorithm),
 	}
 	for k, field := range in.Fields() {
+		if sketched {
+			if name, found := strings.CutSuffix(k, q.sketchSuffix); found {
+				algo, err := q.newAlgorithm(q.Compression)
+				if err != nil {
+					q.Log.Errorf("generating algorithm %s: %v", name, err)
+					continue
+				}
+				q.mergeSketch(algo, name, field)
+				a.fields[name] = algo
+			}
+			continue
+		}
 		if v, isconvertible := convert(field); isconvertible {
 			algo, err := q.newAlgorithm(q.Compression)
 			if err != nil {
 				q.Log.Errorf("generating algorithm %s: %v", k, err)
+				continue
 			}
-			err = algo.Add(v)
-			if err != nil {
+			if err := algo.Add(v); err != nil {
 				q.Log.Errorf("adding field %s: %v", k, err)
 			}
 			a.fields[k] = algo
@@ -112,6 +185,91 @@ func (q *Quantile) Add(in telegraf.Metric) {
 	q.cache[id] = a
 }
 
+func (q *Quantile) hasSketch(m telegraf.Metric) bool {
+	for _, field := range m.FieldList() {
+		if strings.HasSuffix(field.Key, q.sketchSuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeSketch decodes the given serialized sketch and merges it into the
+// algorithm of the field
+func (q *Quantile) mergeSketch(algo algorithm, name string, value interface{}) {
+	encoded, ok := value.(string)
+	if !ok {
+		q.Log.Errorf("sketch for field %s has invalid type %T", name, value)
+		return
+	}
+	buf, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		q.Log.Errorf("decoding sketch for field %s failed: %v", name, err)
+		return
+	}
+	if err := algo.(sketch).Merge(buf); err != nil {
+		q.Log.Errorf("merging sketch for field %s failed: %v", name, err)
+	}
+}
+
+// mergeHistogram adds the buckets of the native histogram to the aggregate
+// of the histogram metric. Each bucket is represented by the geometric mean
+// of its boundaries.
+func (q *Quantile) mergeHistogram(in telegraf.Metric, h *metric.ExponentialHistogram) {
+	if h.Schema < -4 || h.Schema > 8 {
+		q.Log.Errorf("histogram %s has unsupported schema %d", in.Name(), h.Schema)
+		return
+	}
+
+	id := in.HashID()
+	a, found := q.cache[id]
+	if !found {
+		algo, err := q.newAlgorithm(q.Compression)
+		if err != nil {
+			q.Log.Errorf("generating algorithm %s: %v", in.Name(), err)
+			return
+		}
+		a = aggregate{
+			name:      in.Name(),
+			tags:      in.Tags(),
+			fields:    map[string]algorithm{histogramField: algo},
+			histogram: true,
+		}
+		q.cache[id] = a
+	}
+	// The series might have been aggregated from plain metrics before, e.g.
+	// if a processor dropped the histogram of some metrics
+	algo, ok := a.fields[histogramField].(sketch)
+	if !a.histogram || !ok {
+		q.Log.Errorf("histogram %s conflicts with aggregate of non-histogram metrics of the same series", in.Name())
+		return
+	}
+
+	add := func(value, count float64) {
+		if count <= 0 {
+			return
+		}
+		if err := algo.AddWeighted(value, count); err != nil {
+			q.Log.Errorf("adding histogram %s: %v", in.Name(), err)
+		}
+	}
+	add(0, h.ZeroCount)
+	offset, counts := h.PositiveBucketCounts()
+	for i, count := range counts {
+		add(bucketValue(h.Schema, offset+int32(i)), count)
+	}
+	offset, counts = h.NegativeBucketCounts()
+	for i, count := range counts {
+		add(-bucketValue(h.Schema, offset+int32(i)), count)
+	}
+}
+
+func bucketValue(schema, index int32) float64 {
+	lower := metric.ExponentialBucketUpperBound(schema, index-1)
+	upper := metric.ExponentialBucketUpperBound(schema, index)
+	return math.Sqrt(lower * upper)
+}
+
 func (q *Quantile) Push(acc telegraf.Accumulator) {
 	for _, aggregate := range q.cache {
 		fields := make(map[string]interface{}, len(aggregate.fields)*len(q.Quantiles))
@@ -119,11 +277,36 @@ func (q *Quantile) Push(acc telegraf.Accumulator) {
 			for i, qtl := range q.Quantiles {
 				fields[k+q.suffixes[i]] = algo.Quantile(qtl)
 			}
+			if !q.EmitSketch {
+				continue
+			}
+			if q.SketchFormat == "native_histogram" {
+				name := aggregate.name + "_" + k
+				if aggregate.histogram {
+					name = aggregate.name
+				}
+				acc.AddMetric(metric.NewExponentialHistogram(name, aggregate.tags, q.nativeHistogram(algo.(sketch)), time.Now()))
+				continue
+			}
+			buf, err := algo.(sketch).Marshal()
+			if err != nil {
+				q.Log.Errorf("serializing sketch for field %s failed: %v", k, err)
+				continue
+			}
+			fields[k+q.sketchSuffix] = base64.StdEncoding.EncodeToString(buf)
 		}
 		acc.AddFields(aggregate.name, fields, aggregate.tags)
 	}
 }
 
+// nativeHistogram converts the sketch into a native histogram with the
+// configured schema. The sum is approximated from the sketch.
+func (q *Quantile) nativeHistogram(s sketch) *metric.ExponentialHistogram {
+	h := &metric.ExponentialHistogram{Schema: q.NativeHistogramSchema}
+	s.ForEach(h.Add)
+	return h
+}
+
 func (q *Quantile) Reset() {
 	q.cache = make(map[uint64]aggregate)
 }
@@ -143,6 +326,10 @@ func convert(in interface{}) (float64, bool) {
 
 func init() {
 	aggregators.Add("quantile", func() telegraf.Aggregator {
-		return &Quantile{Compression: 100}
+		return &Quantile{
+			Compression:           100,
+			RelativeAccuracy:      0.01,
+			NativeHistogramSchema: 3,
+		}
 	})
 }
@@ -44,6 +44,249 @@ func TestConfigInvalidQuantiles(t *testing.T) {
 	require.Contains(t, err.Error(), "duplicate quantile")
 }
 
+func TestConfigSketchRequiresSketchAlgorithm(t *testing.T) {
+	q := Quantile{Compression: 100, AlgorithmType: "exact R7", EmitSketch: true}
+	require.ErrorContains(t, q.Init(), "requires the t-digest or ddsketch algorithm")
+
+	q = Quantile{Compression: 100, AlgorithmType: "exact R8", MergeSketches: true}
+	require.ErrorContains(t, q.Init(), "requires the t-digest or ddsketch algorithm")
+}
+
+func TestConfigInvalidSketch(t *testing.T) {
+	q := Quantile{AlgorithmType: "ddsketch", RelativeAccuracy: 1.5}
+	require.ErrorContains(t, q.Init(), "relative accuracy 1.5 out of range")
+
+	q = Quantile{Compression: 100, SketchFormat: "foo"}
+	require.ErrorContains(t, q.Init(), `unknown sketch format "foo"`)
+
+	q = Quantile{Compression: 100, SketchFormat: "native_histogram", NativeHistogramSchema: 9}
+	require.ErrorContains(t, q.Init(), "native histogram schema 9 out of range")
+}
+
+func TestMergeSketches(t *testing.T) {
+	// Emit sketches of two hosts with disjunct data
+	var hosts testutil.Accumulator
+	for _, offset := range []int{0, 50} {
+		q := Quantile{
+			Compression: 100,
+			EmitSketch:  true,
+			Log:         testutil.Logger{},
+		}
+		require.NoError(t, q.Init())
+		for i := range 50 {
+			q.Add(metric.New(
+				"test",
+				map[string]string{"foo": "bar"},
+				map[string]interface{}{"a": float64(offset + i)},
+				time.Now(),
+			))
+		}
+		q.Push(&hosts)
+	}
+
+	sketches := hosts.GetTelegrafMetrics()
+	require.Len(t, sketches, 2)
+	for _, m := range sketches {
+		require.True(t, m.HasField("a_tdigest"))
+	}
+
+	// Merge the sketches on the central instance
+	q := Quantile{
+		Compression:   100,
+		MergeSketches: true,
+		Log:           testutil.Logger{},
+	}
+	require.NoError(t, q.Init())
+	for _, m := range sketches {
+		q.Add(m)
+	}
+	var acc testutil.Accumulator
+	q.Push(&acc)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{"foo": "bar"},
+			map[string]interface{}{
+				"a_025": 24.75,
+				"a_050": 49.50,
+				"a_075": 74.25,
+			},
+			time.Now(),
+		),
+	}
+
+	epsilon := cmpopts.EquateApprox(0, 1e-2)
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), epsilon)
+}
+
+func TestMergeSketchesDDSketch(t *testing.T) {
+	// Emit sketches of two hosts with disjunct data
+	var hosts testutil.Accumulator
+	for _, offset := range []int{0, 50} {
+		q := Quantile{
+			AlgorithmType:    "ddsketch",
+			RelativeAccuracy: 0.01,
+			EmitSketch:       true,
+			Log:              testutil.Logger{},
+		}
+		require.NoError(t, q.Init())
+		for i := range 50 {
+			q.Add(metric.New(
+				"test",
+				map[string]string{"foo": "bar"},
+				map[string]interface{}{"a": float64(offset + i + 1)},
+				time.Now(),
+			))
+		}
+		q.Push(&hosts)
+	}
+
+	sketches := hosts.GetTelegrafMetrics()
+	require.Len(t, sketches, 2)
+	for _, m := range sketches {
+		require.True(t, m.HasField("a_ddsketch"))
+	}
+
+	// Merge the sketches on the central instance
+	q := Quantile{
+		AlgorithmType:    "ddsketch",
+		RelativeAccuracy: 0.01,
+		MergeSketches:    true,
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, q.Init())
+	for _, m := range sketches {
+		q.Add(m)
+	}
+	var acc testutil.Accumulator
+	q.Push(&acc)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{"foo": "bar"},
+			map[string]interface{}{
+				"a_025": 25.0,
+				"a_050": 50.0,
+				"a_075": 75.0,
+			},
+			time.Now(),
+		),
+	}
+
+	epsilon := cmpopts.EquateApprox(0.02, 0)
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), epsilon)
+}
+
+func TestMergeNativeHistograms(t *testing.T) {
+	// Emit native histograms of two hosts with disjunct data
+	var hosts testutil.Accumulator
+	for _, offset := range []int{0, 50} {
+		q := Quantile{
+			AlgorithmType:         "ddsketch",
+			RelativeAccuracy:      0.01,
+			EmitSketch:            true,
+			SketchFormat:          "native_histogram",
+			NativeHistogramSchema: 3,
+			Log:                   testutil.Logger{},
+		}
+		require.NoError(t, q.Init())
+		for i := range 50 {
+			q.Add(metric.New(
+				"test",
+				map[string]string{"foo": "bar"},
+				map[string]interface{}{"a": float64(offset + i + 1)},
+				time.Now(),
+			))
+		}
+		q.Push(&hosts)
+	}
+
+	var histograms []telegraf.Metric
+	for _, m := range hosts.GetTelegrafMetrics() {
+		if m.Type() != telegraf.Histogram {
+			continue
+		}
+		require.Equal(t, "test_a", m.Name())
+		h, ok := metric.ExponentialHistogramFromFields(m.Fields())
+		require.True(t, ok)
+		require.Equal(t, int32(3), h.Schema)
+		require.InDelta(t, 50, h.Count, 1e-9)
+		histograms = append(histograms, m)
+	}
+	require.Len(t, histograms, 2)
+
+	// Merge the histograms on the central instance
+	q := Quantile{
+		Compression:   100,
+		MergeSketches: true,
+		SketchFormat:  "native_histogram",
+		Log:           testutil.Logger{},
+	}
+	require.NoError(t, q.Init())
+	for _, m := range histograms {
+		q.Add(m)
+	}
+	var acc testutil.Accumulator
+	q.Push(&acc)
+
+	// The resolution is limited by the bucket width of about 9% for schema 3
+	expected := []telegraf.Metric{
+		metric.New(
+			"test_a",
+			map[string]string{"foo": "bar"},
+			map[string]interface{}{
+				"value_025": 25.0,
+				"value_050": 50.0,
+				"value_075": 75.0,
+			},
+			time.Now(),
+		),
+	}
+
+	epsilon := cmpopts.EquateApprox(0.1, 0)
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), epsilon)
+}
+
+func TestMergeNativeHistogramAfterPlainMetric(t *testing.T) {
+	q := Quantile{
+		Compression:   100,
+		MergeSketches: true,
+		SketchFormat:  "native_histogram",
+		Log:           testutil.Logger{},
+	}
+	require.NoError(t, q.Init())
+
+	// A plain metric and a histogram of the same series must not panic and
+	// the histogram is ignored in favor of the existing aggregate
+	tags := map[string]string{"foo": "bar"}
+	q.Add(metric.New("test", tags, map[string]interface{}{"a": 10.0}, time.Now()))
+	h := &metric.ExponentialHistogram{
+		Count:           2,
+		Sum:             3,
+		PositiveSpans:   []metric.BucketSpan{{Offset: 0, Length: 2}},
+		PositiveBuckets: []float64{1, 1},
+	}
+	q.Add(metric.NewExponentialHistogram("test", tags, h, time.Now()))
+
+	var acc testutil.Accumulator
+	q.Push(&acc)
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			tags,
+			map[string]interface{}{
+				"a_025": 10.0,
+				"a_050": 10.0,
+				"a_075": 10.0,
+			},
+			time.Now(),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
 func TestSingleMetricTDigest(t *testing.T) {
 	acc := testutil.Accumulator{}
 
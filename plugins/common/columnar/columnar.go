@@ -0,0 +1,267 @@
+package columnar
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+)
+
+// Converter converts metrics to Arrow record batches
+type Converter struct {
+	// NameColumn is the column holding the metric name, omitted if empty
+	NameColumn string
+	// TimestampColumn is the column holding the metric time as nanoseconds
+	// since the Unix epoch, omitted if empty
+	TimestampColumn string
+	// Nullable marks the tag and field columns as nullable
+	Nullable bool
+	// Strict fails for values not matching the column type instead of
+	// converting them or storing them as null
+	Strict bool
+}
+
+// GroupByName splits the metrics by name keeping the order of the first
+// occurrence of each name
+func GroupByName(metrics []telegraf.Metric) [][]telegraf.Metric {
+	var groups [][]telegraf.Metric
+	indices := make(map[string]int)
+	for _, m := range metrics {
+		i, found := indices[m.Name()]
+		if !found {
+			i = len(groups)
+			indices[m.Name()] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], m)
+	}
+	return groups
+}
+
+// Schema infers the schema of the given metrics, usually of a single
+// measurement. Tags are stored as strings and fields use the type of their
+// first occurrence. Columns are sorted by name with the name column first and
+// the timestamp column last.
+func (c *Converter) Schema(metrics []telegraf.Metric) (*arrow.Schema, error) {
+	types := make(map[string]arrow.DataType)
+	for _, m := range metrics {
+		for _, field := range m.FieldList() {
+			if _, found := types[field.Key]; !found {
+				dt, err := ArrowType(field.Value)
+				if err != nil {
+					return nil, fmt.Errorf("error converting '%s=%v' field to arrow type: %w", field.Key, field.Value, err)
+				}
+				types[field.Key] = dt
+			}
+		}
+		for _, tag := range m.TagList() {
+			if _, found := types[tag.Key]; !found {
+				types[tag.Key] = arrow.BinaryTypes.String
+			}
+		}
+	}
+	delete(types, c.NameColumn)
+	delete(types, c.TimestampColumn)
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]arrow.Field, 0, len(names)+2)
+	if c.NameColumn != "" {
+		fields = append(fields, arrow.Field{Name: c.NameColumn, Type: arrow.BinaryTypes.String})
+	}
+	for _, name := range names {
+		fields = append(fields, arrow.Field{Name: name, Type: types[name], Nullable: c.Nullable})
+	}
+	if c.TimestampColumn != "" {
+		fields = append(fields, arrow.Field{Name: c.TimestampColumn, Type: arrow.PrimitiveTypes.Int64})
+	}
+
+	return arrow.NewSchema(fields, nil), nil
+}
+
+// RecordBatch converts the metrics to a record batch of the given schema.
+// Missing values are stored as null. Values not matching the column type fail
+// in strict mode, otherwise they are converted or stored as null if not
+// convertible. The caller must release the returned record batch.
+func (c *Converter) RecordBatch(metrics []telegraf.Metric, schema *arrow.Schema) (arrow.RecordBatch, error) {
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	for index, col := range schema.Fields() {
+		fb := builder.Field(index)
+		for _, m := range metrics {
+			var value interface{}
+			var found bool
+			switch col.Name {
+			case c.NameColumn:
+				value, found = m.Name(), true
+			case c.TimestampColumn:
+				value, found = m.Time().UnixNano(), true
+			default:
+				// Try to get the value from a field first, then from a tag.
+				value, found = m.GetField(col.Name)
+				if !found {
+					value, found = m.GetTag(col.Name)
+				}
+			}
+
+			if !found {
+				fb.AppendNull()
+				continue
+			}
+			if c.Strict {
+				if err := checkType(col, value); err != nil {
+					return nil, err
+				}
+			}
+			if err := appendValue(fb, value); err != nil {
+				return nil, fmt.Errorf("column %q: %w", col.Name, err)
+			}
+		}
+	}
+
+	return builder.NewRecordBatch(), nil
+}
+
+// Validate checks the values of the metric against the column types of the
+// schema as done in strict mode
+func (c *Converter) Validate(m telegraf.Metric, schema *arrow.Schema) error {
+	for _, col := range schema.Fields() {
+		if col.Name == c.NameColumn || col.Name == c.TimestampColumn {
+			continue
+		}
+		value, found := m.GetField(col.Name)
+		if !found {
+			value, found = m.GetTag(col.Name)
+		}
+		if !found {
+			continue
+		}
+		if err := checkType(col, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkType(col arrow.Field, value interface{}) error {
+	if dt, err := ArrowType(value); err != nil || !arrow.TypeEqual(dt, col.Type) {
+		return fmt.Errorf("column %q: value of type %T does not match column type %s", col.Name, value, col.Type)
+	}
+	return nil
+}
+
+// ArrowType returns the Arrow data type corresponding to the given value
+func ArrowType(value interface{}) (arrow.DataType, error) {
+	switch value.(type) {
+	case int8:
+		return arrow.PrimitiveTypes.Int8, nil
+	case int16:
+		return arrow.PrimitiveTypes.Int16, nil
+	case int32:
+		return arrow.PrimitiveTypes.Int32, nil
+	case int64, int:
+		return arrow.PrimitiveTypes.Int64, nil
+	case uint8:
+		return arrow.PrimitiveTypes.Uint8, nil
+	case uint16:
+		return arrow.PrimitiveTypes.Uint16, nil
+	case uint32:
+		return arrow.PrimitiveTypes.Uint32, nil
+	case uint64, uint:
+		return arrow.PrimitiveTypes.Uint64, nil
+	case float32:
+		return arrow.PrimitiveTypes.Float32, nil
+	case float64:
+		return arrow.PrimitiveTypes.Float64, nil
+	case string:
+		return arrow.BinaryTypes.String, nil
+	case bool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	default:
+		return nil, fmt.Errorf("unsupported type: %T", value)
+	}
+}
+
+func appendValue(b array.Builder, value interface{}) error {
+	var err error
+	switch b := b.(type) {
+	case *array.Int8Builder:
+		var v int8
+		if v, err = internal.ToInt8(value); err == nil {
+			b.Append(v)
+		}
+	case *array.Int16Builder:
+		var v int16
+		if v, err = internal.ToInt16(value); err == nil {
+			b.Append(v)
+		}
+	case *array.Int32Builder:
+		var v int32
+		if v, err = internal.ToInt32(value); err == nil {
+			b.Append(v)
+		}
+	case *array.Int64Builder:
+		var v int64
+		if v, err = internal.ToInt64(value); err == nil {
+			b.Append(v)
+		}
+	case *array.Uint8Builder:
+		var v uint8
+		if v, err = internal.ToUint8(value); err == nil {
+			b.Append(v)
+		}
+	case *array.Uint16Builder:
+		var v uint16
+		if v, err = internal.ToUint16(value); err == nil {
+			b.Append(v)
+		}
+	case *array.Uint32Builder:
+		var v uint32
+		if v, err = internal.ToUint32(value); err == nil {
+			b.Append(v)
+		}
+	case *array.Uint64Builder:
+		var v uint64
+		if v, err = internal.ToUint64(value); err == nil {
+			b.Append(v)
+		}
+	case *array.Float32Builder:
+		var v float32
+		if v, err = internal.ToFloat32(value); err == nil {
+			b.Append(v)
+		}
+	case *array.Float64Builder:
+		var v float64
+		if v, err = internal.ToFloat64(value); err == nil {
+			b.Append(v)
+		}
+	case *array.StringBuilder:
+		var v string
+		if v, err = internal.ToString(value); err == nil {
+			b.Append(v)
+		}
+	case *array.BooleanBuilder:
+		var v bool
+		if v, err = internal.ToBool(value); err == nil {
+			b.Append(v)
+		}
+	default:
+		return fmt.Errorf("unsupported column type %T", b)
+	}
+
+	// Store values not convertible to the column type as null
+	if err != nil {
+		b.AppendNull()
+	}
+	return nil
+}
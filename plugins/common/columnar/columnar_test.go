@@ -0,0 +1,87 @@
+package columnar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+func TestConverter(t *testing.T) {
+	metrics := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{"host": "server01"},
+			map[string]interface{}{"usage": 42.5, "cores": int64(4)},
+			time.Unix(0, 1),
+		),
+		metric.New(
+			"mem",
+			map[string]string{},
+			map[string]interface{}{"used": uint64(1024), "cores": "many"},
+			time.Unix(0, 2),
+		),
+	}
+
+	converter := &Converter{NameColumn: "name", TimestampColumn: "time", Nullable: true}
+	schema, err := converter.Schema(metrics)
+	require.NoError(t, err)
+	require.True(t, schema.Field(1).Nullable)
+
+	names := make([]string, 0, schema.NumFields())
+	for _, f := range schema.Fields() {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"name", "cores", "host", "usage", "used", "time"}, names)
+	require.Equal(t, arrow.PrimitiveTypes.Int64, schema.Field(1).Type)
+
+	record, err := converter.RecordBatch(metrics, schema)
+	require.NoError(t, err)
+	defer record.Release()
+
+	require.Equal(t, int64(2), record.NumRows())
+	require.Equal(t, []string{"cpu", "mem"}, []string{
+		record.Column(0).(*array.String).Value(0),
+		record.Column(0).(*array.String).Value(1),
+	})
+
+	// Values not convertible to the column type and missing values are null
+	cores := record.Column(1).(*array.Int64)
+	require.Equal(t, int64(4), cores.Value(0))
+	require.True(t, cores.IsNull(1))
+	require.True(t, record.Column(2).IsNull(1))
+	require.Equal(t, []int64{1, 2}, record.Column(5).(*array.Int64).Int64Values())
+}
+
+func TestConverterStrict(t *testing.T) {
+	metrics := []telegraf.Metric{
+		metric.New("cpu", map[string]string{}, map[string]interface{}{"cores": int64(4)}, time.Unix(0, 1)),
+		metric.New("cpu", map[string]string{}, map[string]interface{}{"cores": "many"}, time.Unix(0, 2)),
+	}
+
+	converter := &Converter{TimestampColumn: "time", Strict: true}
+	schema, err := converter.Schema(metrics)
+	require.NoError(t, err)
+	require.False(t, schema.Field(0).Nullable)
+
+	_, err = converter.RecordBatch(metrics, schema)
+	require.ErrorContains(t, err, `column "cores": value of type string does not match column type int64`)
+}
+
+func TestGroupByName(t *testing.T) {
+	metrics := []telegraf.Metric{
+		metric.New("mem", map[string]string{}, map[string]interface{}{"value": 1}, time.Unix(0, 0)),
+		metric.New("cpu", map[string]string{}, map[string]interface{}{"value": 2}, time.Unix(0, 0)),
+		metric.New("mem", map[string]string{}, map[string]interface{}{"value": 3}, time.Unix(0, 0)),
+	}
+
+	groups := GroupByName(metrics)
+	require.Len(t, groups, 2)
+	require.Equal(t, []telegraf.Metric{metrics[0], metrics[2]}, groups[0])
+	require.Equal(t, []telegraf.Metric{metrics[1]}, groups[1])
+}
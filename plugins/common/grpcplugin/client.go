@@ -0,0 +1,254 @@
+package grpcplugin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/internal/process"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin/pluginv1"
+)
+
+// ProtocolVersion is the version of the protocol spoken by this package
+const ProtocolVersion = 1
+
+// SocketEnv is the environment variable containing the path of the unix
+// socket the external plugin should serve the protocol on
+const SocketEnv = "TELEGRAF_PLUGIN_SOCKET"
+
+// Config contains the settings for running an external plugin
+type Config struct {
+	Command      []string        `toml:"command"`
+	Environment  []string        `toml:"environment"`
+	RestartDelay config.Duration `toml:"restart_delay"`
+	StartTimeout config.Duration `toml:"start_timeout"`
+}
+
+// Client runs the external plugin process and connects to the plugin
+type Client struct {
+	// Conn is the connection to the plugin for creating service clients
+	Conn *grpc.ClientConn
+
+	pluginType pluginv1.PluginType
+	timeout    time.Duration
+	log        telegraf.Logger
+
+	process *process.Process
+	dir     string
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewClient creates a client for the external plugin of the given type. The
+// process is not started before calling Start.
+func (cfg *Config) NewClient(pluginType pluginv1.PluginType, log telegraf.Logger) (*Client, error) {
+	if len(cfg.Command) == 0 {
+		return nil, errors.New("no command specified")
+	}
+
+	timeout := time.Duration(cfg.StartTimeout)
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	// Keep the socket in a private directory to avoid other users from
+	// connecting and to keep the path short enough for unix sockets
+	dir, err := os.MkdirTemp("", "telegraf-plugin-")
+	if err != nil {
+		return nil, fmt.Errorf("creating socket directory failed: %w", err)
+	}
+	socket := filepath.Join(dir, "plugin.sock")
+
+	env := append(append([]string(nil), cfg.Environment...), SocketEnv+"="+socket)
+	proc, err := process.New(cfg.Command, env)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("error creating process %s: %w", cfg.Command, err)
+	}
+	proc.Log = log
+	if cfg.RestartDelay > 0 {
+		proc.RestartDelay = time.Duration(cfg.RestartDelay)
+	}
+	proc.ReadStdoutFn = func(r io.Reader) { logLines(r, log.Info) }
+	proc.ReadStderrFn = func(r io.Reader) { logLines(r, log.Error) }
+
+	// Reconnect quickly as the socket only appears once the process started
+	params := grpc.ConnectParams{
+		Backoff:           backoff.DefaultConfig,
+		MinConnectTimeout: time.Second,
+	}
+	params.Backoff.BaseDelay = 50 * time.Millisecond
+	params.Backoff.MaxDelay = time.Second
+	conn, err := grpc.NewClient("unix://"+socket,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(params),
+	)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("creating connection failed: %w", err)
+	}
+
+	return &Client{
+		Conn:       conn,
+		pluginType: pluginType,
+		timeout:    timeout,
+		log:        log,
+		process:    proc,
+		dir:        dir,
+	}, nil
+}
+
+// StartClient creates a new client for the external plugin of the given type
+// and starts it. As a process can only be started once, a new client must be
+// used for each attempt, so start failures are reported as retry-able.
+func (cfg *Config) StartClient(pluginType pluginv1.PluginType, log telegraf.Logger) (*Client, error) {
+	c, err := cfg.NewClient(pluginType, log)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Start(); err != nil {
+		c.Stop()
+		return nil, &internal.StartupError{Err: err, Retry: true}
+	}
+	return c, nil
+}
+
+// Start starts the process, checks the protocol version and plugin type and
+// forwards the log messages of the plugin
+func (c *Client) Start() error {
+	if err := c.process.Start(); err != nil {
+		return fmt.Errorf("failed to start process: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	client := pluginv1.NewPluginClient(c.Conn)
+	resp, err := client.Handshake(ctx, &pluginv1.HandshakeRequest{
+		ProtocolVersion: ProtocolVersion,
+		Type:            c.pluginType,
+		LogLevel:        ToProtoLogLevel(c.log.Level()),
+	}, grpc.WaitForReady(true))
+	if err != nil {
+		c.process.Stop()
+		return fmt.Errorf("handshake failed: %w", err)
+	}
+	if resp.GetProtocolVersion() != ProtocolVersion {
+		c.process.Stop()
+		return fmt.Errorf("unsupported protocol version %d, expected %d", resp.GetProtocolVersion(), ProtocolVersion)
+	}
+	if resp.GetType() != c.pluginType {
+		c.process.Stop()
+		return fmt.Errorf("plugin type %s does not match expected %s", resp.GetType(), c.pluginType)
+	}
+
+	ctx, c.cancel = context.WithCancel(context.Background())
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.forwardLogs(ctx, client)
+	}()
+
+	return nil
+}
+
+// Stop terminates the process and releases the connection
+func (c *Client) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.process.Stop()
+	c.wg.Wait()
+	if err := c.Conn.Close(); err != nil {
+		c.log.Debugf("Closing connection failed: %v", err)
+	}
+	if err := os.RemoveAll(c.dir); err != nil {
+		c.log.Debugf("Removing socket directory failed: %v", err)
+	}
+}
+
+// Retry calls the given function until it succeeds or the context is
+// cancelled, waiting for the restart delay between attempts. This allows to
+// reestablish streams after the process was restarted.
+func (c *Client) Retry(ctx context.Context, name string, fn func(context.Context) error) {
+	for {
+		err := fn(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			c.log.Debugf("Stream %s interrupted: %v", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.process.RestartDelay):
+		}
+	}
+}
+
+func (c *Client) forwardLogs(ctx context.Context, client pluginv1.PluginClient) {
+	c.Retry(ctx, "logs", func(ctx context.Context) error {
+		stream, err := client.Logs(ctx, &pluginv1.LogsRequest{}, grpc.WaitForReady(true))
+		if err != nil {
+			return err
+		}
+		for {
+			entry, err := stream.Recv()
+			if err != nil {
+				return err
+			}
+			c.logEntry(entry)
+		}
+	})
+}
+
+func (c *Client) logEntry(entry *pluginv1.LogEntry) {
+	msg := entry.GetMessage()
+	if attrs := entry.GetAttributes(); len(attrs) > 0 {
+		keys := make([]string, 0, len(attrs))
+		for k := range attrs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, k+"="+attrs[k])
+		}
+		msg += " (" + strings.Join(parts, ", ") + ")"
+	}
+
+	switch FromProtoLogLevel(entry.GetLevel()) {
+	case telegraf.Error:
+		c.log.Error(msg)
+	case telegraf.Warn:
+		c.log.Warn(msg)
+	case telegraf.Debug:
+		c.log.Debug(msg)
+	case telegraf.Trace:
+		c.log.Trace(msg)
+	default:
+		c.log.Info(msg)
+	}
+}
+
+func logLines(r io.Reader, fn func(args ...interface{})) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fn(scanner.Text())
+	}
+}
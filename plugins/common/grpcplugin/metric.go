@@ -0,0 +1,156 @@
+package grpcplugin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin/pluginv1"
+)
+
+// ToProto converts the metric to its protocol representation
+func ToProto(m telegraf.Metric) *pluginv1.Metric {
+	pm := &pluginv1.Metric{
+		Name:   m.Name(),
+		Tags:   make([]*pluginv1.Tag, 0, len(m.TagList())),
+		Fields: make([]*pluginv1.Field, 0, len(m.FieldList())),
+		Time:   m.Time().UnixNano(),
+		Type:   pluginv1.ValueType(m.Type()),
+	}
+	for _, tag := range m.TagList() {
+		pm.Tags = append(pm.Tags, &pluginv1.Tag{Key: tag.Key, Value: tag.Value})
+	}
+	for _, field := range m.FieldList() {
+		pf := &pluginv1.Field{Key: field.Key}
+		switch v := field.Value.(type) {
+		case float64:
+			pf.Value = &pluginv1.Field_DoubleValue{DoubleValue: v}
+		case int64:
+			pf.Value = &pluginv1.Field_IntValue{IntValue: v}
+		case uint64:
+			pf.Value = &pluginv1.Field_UintValue{UintValue: v}
+		case string:
+			pf.Value = &pluginv1.Field_StringValue{StringValue: v}
+		case bool:
+			pf.Value = &pluginv1.Field_BoolValue{BoolValue: v}
+		default:
+			pf.Value = &pluginv1.Field_StringValue{StringValue: fmt.Sprint(v)}
+		}
+		pm.Fields = append(pm.Fields, pf)
+	}
+	return pm
+}
+
+// ToProtoMetrics converts the metrics to their protocol representation
+func ToProtoMetrics(metrics []telegraf.Metric) []*pluginv1.Metric {
+	pms := make([]*pluginv1.Metric, 0, len(metrics))
+	for _, m := range metrics {
+		pms = append(pms, ToProto(m))
+	}
+	return pms
+}
+
+// FromProto creates a metric from its protocol representation
+func FromProto(pm *pluginv1.Metric) telegraf.Metric {
+	tags := make(map[string]string, len(pm.GetTags()))
+	for _, tag := range pm.GetTags() {
+		tags[tag.GetKey()] = tag.GetValue()
+	}
+	fields := make(map[string]interface{}, len(pm.GetFields()))
+	for _, field := range pm.GetFields() {
+		if v := fieldValue(field); v != nil {
+			fields[field.GetKey()] = v
+		}
+	}
+	return metric.New(pm.GetName(), tags, fields, time.Unix(0, pm.GetTime()), valueType(pm.GetType()))
+}
+
+// FromProtoMetrics creates metrics from their protocol representation
+func FromProtoMetrics(pms []*pluginv1.Metric) []telegraf.Metric {
+	metrics := make([]telegraf.Metric, 0, len(pms))
+	for _, pm := range pms {
+		metrics = append(metrics, FromProto(pm))
+	}
+	return metrics
+}
+
+// UpdateMetric modifies the metric in-place to match the given protocol
+// representation. This allows to keep the tracking information of the
+// metric when being modified externally.
+func UpdateMetric(m telegraf.Metric, pm *pluginv1.Metric) {
+	m.SetName(pm.GetName())
+	m.SetTime(time.Unix(0, pm.GetTime()))
+
+	tags := make(map[string]bool, len(pm.GetTags()))
+	for _, tag := range pm.GetTags() {
+		tags[tag.GetKey()] = true
+		m.AddTag(tag.GetKey(), tag.GetValue())
+	}
+	// Collect the keys first as removing modifies the underlying list
+	var remove []string
+	for _, tag := range m.TagList() {
+		if !tags[tag.Key] {
+			remove = append(remove, tag.Key)
+		}
+	}
+	for _, key := range remove {
+		m.RemoveTag(key)
+	}
+
+	fields := make(map[string]bool, len(pm.GetFields()))
+	for _, field := range pm.GetFields() {
+		if v := fieldValue(field); v != nil {
+			fields[field.GetKey()] = true
+			m.AddField(field.GetKey(), v)
+		}
+	}
+	remove = remove[:0]
+	for _, field := range m.FieldList() {
+		if !fields[field.Key] {
+			remove = append(remove, field.Key)
+		}
+	}
+	for _, key := range remove {
+		m.RemoveField(key)
+	}
+}
+
+func fieldValue(field *pluginv1.Field) interface{} {
+	switch v := field.GetValue().(type) {
+	case *pluginv1.Field_DoubleValue:
+		return v.DoubleValue
+	case *pluginv1.Field_IntValue:
+		return v.IntValue
+	case *pluginv1.Field_UintValue:
+		return v.UintValue
+	case *pluginv1.Field_StringValue:
+		return v.StringValue
+	case *pluginv1.Field_BoolValue:
+		return v.BoolValue
+	}
+	return nil
+}
+
+func valueType(t pluginv1.ValueType) telegraf.ValueType {
+	if t == pluginv1.ValueType_VALUE_TYPE_UNSPECIFIED {
+		return telegraf.Untyped
+	}
+	return telegraf.ValueType(t)
+}
+
+// ToProtoLogLevel converts the log level to its protocol representation
+func ToProtoLogLevel(level telegraf.LogLevel) pluginv1.LogLevel {
+	if level == telegraf.None {
+		return pluginv1.LogLevel_LOG_LEVEL_UNSPECIFIED
+	}
+	return pluginv1.LogLevel(level)
+}
+
+// FromProtoLogLevel converts the protocol representation of the log level
+func FromProtoLogLevel(level pluginv1.LogLevel) telegraf.LogLevel {
+	if level == pluginv1.LogLevel_LOG_LEVEL_UNSPECIFIED {
+		return telegraf.Info
+	}
+	return telegraf.LogLevel(level)
+}
@@ -0,0 +1,68 @@
+package grpcplugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestMetricRoundtrip(t *testing.T) {
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{"host": "localhost", "region": "eu"},
+			map[string]interface{}{
+				"float":  3.14,
+				"int":    int64(-42),
+				"uint":   uint64(42),
+				"string": "foo",
+				"bool":   true,
+			},
+			time.Unix(1700000000, 123456789),
+			telegraf.Counter,
+		),
+		metric.New("untyped", nil, map[string]interface{}{"value": 1.0}, time.Unix(0, 0)),
+		metric.New("histogram", nil, map[string]interface{}{"value": 1.0}, time.Unix(0, 0), telegraf.Histogram),
+	}
+
+	actual := FromProtoMetrics(ToProtoMetrics(expected))
+	testutil.RequireMetricsEqual(t, expected, actual)
+	for i, m := range actual {
+		require.Equal(t, expected[i].Type(), m.Type())
+	}
+}
+
+func TestUpdateMetric(t *testing.T) {
+	var delivered bool
+	notify := func(di telegraf.DeliveryInfo) {
+		delivered = di.Delivered()
+	}
+
+	input := metric.New(
+		"test",
+		map[string]string{"keep": "a", "remove": "b"},
+		map[string]interface{}{"keep": 1.0, "remove": 2.0},
+		time.Unix(0, 0),
+		telegraf.Gauge,
+	)
+	m, _ := metric.WithTracking(input, notify)
+
+	modified := metric.New(
+		"modified",
+		map[string]string{"keep": "changed", "added": "c"},
+		map[string]interface{}{"keep": 10.0, "added": "new"},
+		time.Unix(10, 0),
+		telegraf.Gauge,
+	)
+	UpdateMetric(m, ToProto(modified))
+	testutil.RequireMetricEqual(t, modified, m)
+
+	// The tracking information must be kept
+	m.Accept()
+	require.True(t, delivered)
+}
@@ -0,0 +1,10 @@
+package pluginv1
+
+// To run these commands, make sure that protoc-gen-go and protoc-gen-go-grpc are installed
+// > go install google.golang.org/protobuf/cmd/protoc-gen-go
+// > go install google.golang.org/grpc/cmd/protoc-gen-go-grpc
+//
+// Generated files were last generated with:
+// - protoc-gen-go: v1.36.11
+// - protoc-gen-go-grpc: v1.6.1
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative plugin.proto
@@ -22,6 +22,15 @@ type PublishProperties struct {
 	UserProperties map[string]string `toml:"user_properties"`
 }
 
+// WillMessage is published by the broker if the client disconnects
+// unexpectedly
+type WillMessage struct {
+	Topic   string
+	Payload []byte
+	QoS     int
+	Retain  bool
+}
+
 type MqttConfig struct {
 	Servers             []string           `toml:"servers"`
 	Protocol            string             `toml:"protocol"`
@@ -39,8 +48,14 @@ type MqttConfig struct {
 
 	tls.ClientConfig
 
-	AutoReconnect    bool        `toml:"-"`
-	OnConnectionLost func(error) `toml:"-"`
+	AutoReconnect    bool         `toml:"-"`
+	OnConnectionLost func(error)  `toml:"-"`
+	OnConnect        func()       `toml:"-"`
+	Will             *WillMessage `toml:"-"`
+
+	// OnReconnecting is called before each reconnect attempt of the
+	// MQTT 3.1.1 client and allows to update the will message
+	OnReconnecting func() `toml:"-"`
 }
 
 // Client is a protocol neutral MQTT client for connecting,
@@ -31,7 +31,23 @@ func NewMQTTv311Client(cfg *MqttConfig) (*mqttv311Client, error) {
 		}
 		opts.SetConnectionLostHandler(onConnectionLost)
 	}
+	if cfg.OnConnect != nil {
+		opts.SetOnConnectHandler(func(mqttv3.Client) {
+			cfg.OnConnect()
+		})
+	}
+	if cfg.OnReconnecting != nil {
+		opts.SetReconnectingHandler(func(_ mqttv3.Client, o *mqttv3.ClientOptions) {
+			cfg.OnReconnecting()
+			if cfg.Will != nil {
+				o.SetBinaryWill(cfg.Will.Topic, cfg.Will.Payload, byte(cfg.Will.QoS), cfg.Will.Retain)
+			}
+		})
+	}
 	opts.SetAutoReconnect(cfg.AutoReconnect)
+	if cfg.Will != nil {
+		opts.SetBinaryWill(cfg.Will.Topic, cfg.Will.Payload, byte(cfg.Will.QoS), cfg.Will.Retain)
+	}
 
 	if cfg.ClientID != "" {
 		opts.SetClientID(cfg.ClientID)
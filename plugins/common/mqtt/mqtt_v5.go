@@ -34,8 +34,21 @@ func NewMQTTv5Client(cfg *MqttConfig) (*mqttv5Client, error) {
 	}
 	opts.ConnectPacketBuilder = func(c *mqttv5.Connect, _ *url.URL) (*mqttv5.Connect, error) {
 		c.CleanStart = cfg.PersistentSession
+		if cfg.Will != nil {
+			c.WillMessage = &mqttv5.WillMessage{
+				Topic:   cfg.Will.Topic,
+				Payload: cfg.Will.Payload,
+				QoS:     byte(cfg.Will.QoS),
+				Retain:  cfg.Will.Retain,
+			}
+		}
 		return c, nil
 	}
+	if cfg.OnConnect != nil {
+		opts.OnConnectionUp = func(*mqttv5auto.ConnectionManager, *mqttv5.Connack) {
+			cfg.OnConnect()
+		}
+	}
 
 	if time.Duration(cfg.ConnectionTimeout) >= 1*time.Second {
 		opts.ConnectTimeout = time.Duration(cfg.ConnectionTimeout)
@@ -0,0 +1,189 @@
+package opentelemetry
+
+import (
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/influxdb-observability/common"
+	"github.com/influxdata/influxdb-observability/otel2influx"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// ExtractExponentialHistograms returns all exponential histograms contained in
+// the given metrics as Telegraf metrics following the given schema and removes
+// them from the metrics, as those are not supported by the conversion library.
+// Invalid data points are skipped with a warning.
+func ExtractExponentialHistograms(md pmetric.Metrics, schema common.MetricsSchema, log telegraf.Logger) []telegraf.Metric {
+	var metrics []telegraf.Metric
+	for _, rm := range md.ResourceMetrics().All() {
+		for _, sm := range rm.ScopeMetrics().All() {
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				if m.Type() != pmetric.MetricTypeExponentialHistogram {
+					return false
+				}
+
+				scopeTags := otel2influx.ResourceToTags(rm.Resource(), make(map[string]string))
+				scopeTags = otel2influx.InstrumentationScopeToTags(sm.Scope(), scopeTags)
+
+				delta := m.ExponentialHistogram().AggregationTemporality() == pmetric.AggregationTemporalityDelta
+				for _, dp := range m.ExponentialHistogram().DataPoints().All() {
+					ts := dp.Timestamp().AsTime()
+					if dp.Timestamp() == 0 {
+						log.Warnf("Skipping exponential histogram %q without timestamp", m.Name())
+						continue
+					}
+
+					h := exponentialHistogramFromDataPoint(dp)
+					if err := h.Validate(); err != nil {
+						log.Warnf("Skipping invalid exponential histogram %q: %v", m.Name(), err)
+						continue
+					}
+					if delta {
+						// Delta temporality corresponds to a gauge histogram
+						h.CounterResetHint = 3
+					}
+
+					tags := maps.Clone(scopeTags)
+					addAttributes(tags, dp.Attributes())
+					if schema == common.MetricsSchemaTelegrafPrometheusV2 {
+						metrics = append(metrics, bucketMetrics(m.Name(), tags, h, ts)...)
+					} else {
+						metrics = append(metrics, metric.NewExponentialHistogram(m.Name(), tags, h, ts))
+					}
+				}
+				return true
+			})
+		}
+	}
+	return metrics
+}
+
+// bucketMetrics converts the histogram to metrics with cumulative bucket
+// counts in the same way the conversion library converts explicit bucket
+// histograms for the prometheus-v2 schema.
+func bucketMetrics(name string, tags map[string]string, h *metric.ExponentialHistogram, ts time.Time) []telegraf.Metric {
+	fields := map[string]interface{}{
+		name + common.MetricHistogramCountSuffix: h.Count,
+		name + common.MetricHistogramSumSuffix:   h.Sum,
+	}
+	metrics := []telegraf.Metric{
+		metric.New(common.MeasurementPrometheus, tags, fields, ts, telegraf.Histogram),
+	}
+
+	addBucket := func(bound string, count float64) {
+		btags := maps.Clone(tags)
+		btags[common.MetricHistogramBoundKeyV2] = bound
+		fields := map[string]interface{}{name + common.MetricHistogramBucketSuffix: count}
+		metrics = append(metrics, metric.New(common.MeasurementPrometheus, btags, fields, ts, telegraf.Histogram))
+	}
+
+	// Add the buckets in ascending order of their upper bound starting with
+	// the negative bucket of the highest index
+	var count float64
+	offset, counts := h.NegativeBucketCounts()
+	for i := len(counts) - 1; i >= 0; i-- {
+		count += counts[i]
+		bound := -metric.ExponentialBucketUpperBound(h.Schema, offset+int32(i)-1)
+		addBucket(strconv.FormatFloat(bound, 'f', -1, 64), count)
+	}
+	count += h.ZeroCount
+	addBucket(strconv.FormatFloat(h.ZeroThreshold, 'f', -1, 64), count)
+	offset, counts = h.PositiveBucketCounts()
+	for i, c := range counts {
+		count += c
+		bound := metric.ExponentialBucketUpperBound(h.Schema, offset+int32(i))
+		addBucket(strconv.FormatFloat(bound, 'f', -1, 64), count)
+	}
+	addBucket(common.MetricHistogramInfFieldKey, h.Count)
+
+	return metrics
+}
+
+// AppendExponentialHistogram adds the metric as OpenTelemetry exponential
+// histogram to the scope metrics if it contains one and returns false
+// otherwise. All tags of the metric are added as data point attributes.
+func AppendExponentialHistogram(sm pmetric.ScopeMetrics, m telegraf.Metric) bool {
+	// OpenTelemetry has no equivalent for custom bucket histograms
+	h, ok := metric.ExponentialHistogramOf(m)
+	if !ok || h.IsCustomBuckets() {
+		return false
+	}
+
+	om := sm.Metrics().AppendEmpty()
+	om.SetName(m.Name())
+
+	eh := om.SetEmptyExponentialHistogram()
+	// Gauge histograms correspond to delta temporality
+	if h.CounterResetHint == 3 {
+		eh.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	} else {
+		eh.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	}
+
+	dp := eh.DataPoints().AppendEmpty()
+	for _, tag := range m.TagList() {
+		dp.Attributes().PutStr(tag.Key, tag.Value)
+	}
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(m.Time()))
+	dp.SetScale(h.Schema)
+	dp.SetCount(uint64(h.Count))
+	dp.SetSum(h.Sum)
+	dp.SetZeroCount(uint64(h.ZeroCount))
+	dp.SetZeroThreshold(h.ZeroThreshold)
+
+	// Native histogram bucket indices are one larger than the OpenTelemetry
+	// indices for the same bucket boundaries.
+	offset, counts := h.PositiveBucketCounts()
+	dp.Positive().SetOffset(offset - 1)
+	dp.Positive().BucketCounts().FromRaw(toUints(counts))
+	offset, counts = h.NegativeBucketCounts()
+	dp.Negative().SetOffset(offset - 1)
+	dp.Negative().BucketCounts().FromRaw(toUints(counts))
+
+	return true
+}
+
+func exponentialHistogramFromDataPoint(dp pmetric.ExponentialHistogramDataPoint) *metric.ExponentialHistogram {
+	h := &metric.ExponentialHistogram{
+		Schema:        dp.Scale(),
+		ZeroThreshold: dp.ZeroThreshold(),
+		ZeroCount:     float64(dp.ZeroCount()),
+		Count:         float64(dp.Count()),
+		Sum:           dp.Sum(),
+	}
+
+	// OpenTelemetry buckets cover the range (base^i, base^(i+1)] while
+	// Prometheus buckets cover (base^(i-1), base^i], so the index of the
+	// OpenTelemetry bucket is one less than the index of the native histogram.
+	h.SetPositiveBucketCounts(dp.Positive().Offset()+1, toFloats(dp.Positive().BucketCounts()))
+	h.SetNegativeBucketCounts(dp.Negative().Offset()+1, toFloats(dp.Negative().BucketCounts()))
+
+	return h
+}
+
+func toFloats(counts pcommon.UInt64Slice) []float64 {
+	values := make([]float64, 0, counts.Len())
+	for _, c := range counts.All() {
+		values = append(values, float64(c))
+	}
+	return values
+}
+
+func toUints(values []float64) []uint64 {
+	counts := make([]uint64, 0, len(values))
+	for _, v := range values {
+		counts = append(counts, uint64(v))
+	}
+	return counts
+}
+
+func addAttributes(tags map[string]string, attributes pcommon.Map) {
+	for k, v := range attributes.All() {
+		tags[k] = v.AsString()
+	}
+}
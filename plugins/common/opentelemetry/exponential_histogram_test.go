@@ -0,0 +1,166 @@
+package opentelemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-observability/common"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestExtractExponentialHistograms(t *testing.T) {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "test")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("library-name")
+
+	// Exponential histogram to be converted
+	m := sm.Metrics().AppendEmpty()
+	m.SetName("latency")
+	eh := m.SetEmptyExponentialHistogram()
+	eh.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := eh.DataPoints().AppendEmpty()
+	dp.Attributes().PutStr("method", "GET")
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1700000000, 0)))
+	dp.SetScale(1)
+	dp.SetCount(6)
+	dp.SetSum(12.5)
+	dp.SetZeroCount(1)
+	dp.Positive().SetOffset(-1)
+	dp.Positive().BucketCounts().FromRaw([]uint64{2, 0, 3})
+
+	// Other metrics must be kept
+	m = sm.Metrics().AppendEmpty()
+	m.SetName("requests")
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(42)
+
+	actual := ExtractExponentialHistograms(md, common.MetricsSchemaTelegrafPrometheusV1, testutil.Logger{})
+	require.Equal(t, 1, sm.Metrics().Len())
+	require.Equal(t, "requests", sm.Metrics().At(0).Name())
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"latency",
+			map[string]string{
+				"service.name":      "test",
+				"otel.library.name": "library-name",
+				"method":            "GET",
+			},
+			map[string]interface{}{
+				"counter_reset_hint":     uint64(0),
+				"schema":                 int64(1),
+				"zero_threshold":         float64(0),
+				"zero_count":             float64(1),
+				"count":                  float64(6),
+				"sum":                    12.5,
+				"positive_span_0_offset": int64(0),
+				"positive_span_0_length": uint64(1),
+				"positive_span_1_offset": int64(1),
+				"positive_span_1_length": uint64(1),
+				"positive_bucket_0":      float64(2),
+				"positive_bucket_1":      float64(3),
+			},
+			time.Unix(1700000000, 0),
+			telegraf.Histogram,
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestExtractExponentialHistogramsPrometheusV2(t *testing.T) {
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	m := sm.Metrics().AppendEmpty()
+	m.SetName("latency")
+	dp := m.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	dp.Attributes().PutStr("method", "GET")
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1700000000, 0)))
+	dp.SetScale(0)
+	dp.SetCount(7)
+	dp.SetSum(10.5)
+	dp.SetZeroCount(1)
+	dp.Negative().SetOffset(0)
+	dp.Negative().BucketCounts().FromRaw([]uint64{1})
+	dp.Positive().SetOffset(0)
+	dp.Positive().BucketCounts().FromRaw([]uint64{2, 3})
+
+	actual := ExtractExponentialHistograms(md, common.MetricsSchemaTelegrafPrometheusV2, testutil.Logger{})
+	require.Equal(t, 0, sm.Metrics().Len())
+
+	ts := time.Unix(1700000000, 0)
+	expected := []telegraf.Metric{
+		metric.New("prometheus",
+			map[string]string{"method": "GET"},
+			map[string]interface{}{"latency_count": float64(7), "latency_sum": 10.5},
+			ts, telegraf.Histogram,
+		),
+		metric.New("prometheus",
+			map[string]string{"method": "GET", "le": "-1"},
+			map[string]interface{}{"latency_bucket": float64(1)},
+			ts, telegraf.Histogram,
+		),
+		metric.New("prometheus",
+			map[string]string{"method": "GET", "le": "0"},
+			map[string]interface{}{"latency_bucket": float64(2)},
+			ts, telegraf.Histogram,
+		),
+		metric.New("prometheus",
+			map[string]string{"method": "GET", "le": "2"},
+			map[string]interface{}{"latency_bucket": float64(4)},
+			ts, telegraf.Histogram,
+		),
+		metric.New("prometheus",
+			map[string]string{"method": "GET", "le": "4"},
+			map[string]interface{}{"latency_bucket": float64(7)},
+			ts, telegraf.Histogram,
+		),
+		metric.New("prometheus",
+			map[string]string{"method": "GET", "le": "+Inf"},
+			map[string]interface{}{"latency_bucket": float64(7)},
+			ts, telegraf.Histogram,
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestExtractExponentialHistogramsInvalid(t *testing.T) {
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	m := sm.Metrics().AppendEmpty()
+	m.SetName("latency")
+
+	// Data point exceeding the bucket limit
+	dp := m.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1700000000, 0)))
+	dp.SetCount(2)
+	dp.Positive().BucketCounts().FromRaw(append([]uint64{1}, make([]uint64, 1<<16)...))
+	dp.Positive().BucketCounts().Append(1)
+
+	// Data point without timestamp
+	dp = m.ExponentialHistogram().DataPoints().AppendEmpty()
+	dp.SetCount(1)
+	dp.SetZeroCount(1)
+
+	// Valid data point
+	dp = m.ExponentialHistogram().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1700000000, 0)))
+	dp.SetCount(1)
+	dp.SetZeroCount(1)
+
+	var log testutil.CaptureLogger
+	actual := ExtractExponentialHistograms(md, common.MetricsSchemaTelegrafPrometheusV1, &log)
+	require.Len(t, actual, 1)
+	require.Equal(t, 0, sm.Metrics().Len())
+
+	warnings := log.Warnings()
+	require.Len(t, warnings, 2)
+	require.Contains(t, warnings[0], "spans cover more than 65536 buckets")
+	require.Contains(t, warnings[1], "without timestamp")
+}
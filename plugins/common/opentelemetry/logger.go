@@ -6,12 +6,14 @@ import (
 	"github.com/influxdata/telegraf"
 )
 
-type otelLogger struct {
+// Logger adapts the Telegraf logger to the logger interface of the
+// OpenTelemetry conversion library
+type Logger struct {
 	telegraf.Logger
 }
 
 // Debug logs a debug message, patterned after log.Print.
-func (l otelLogger) Debug(msg string, kv ...interface{}) {
+func (l Logger) Debug(msg string, kv ...interface{}) {
 	format := msg + strings.Repeat(" %s=%q", len(kv)/2)
 	l.Logger.Debugf(format, kv...)
 }
@@ -0,0 +1,58 @@
+package opentelemetry
+
+import (
+	"github.com/influxdata/influxdb-observability/common"
+	"github.com/influxdata/influxdb-observability/influx2otel"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/influxdata/telegraf"
+)
+
+// MetricsSchemata maps the metric schema names used in the configuration to
+// the schemata of the conversion library
+var MetricsSchemata = map[string]common.MetricsSchema{
+	"prometheus-v1": common.MetricsSchemaTelegrafPrometheusV1,
+	"prometheus-v2": common.MetricsSchemaTelegrafPrometheusV2,
+}
+
+// EncodeMetrics converts the given metrics to OpenTelemetry metrics using the
+// converter. Metrics that cannot be converted are skipped with a warning.
+func EncodeMetrics(converter *influx2otel.LineProtocolToOtelMetrics, metrics []telegraf.Metric, log telegraf.Logger) pmetric.Metrics {
+	batch := converter.NewBatch()
+	exponential := pmetric.NewScopeMetrics()
+	for _, metric := range metrics {
+		// Exponential histograms are not supported by the conversion library
+		// so convert them directly
+		if AppendExponentialHistogram(exponential, metric) {
+			continue
+		}
+
+		var vType common.InfluxMetricValueType
+		switch metric.Type() {
+		case telegraf.Gauge:
+			vType = common.InfluxMetricValueTypeGauge
+		case telegraf.Untyped:
+			vType = common.InfluxMetricValueTypeUntyped
+		case telegraf.Counter:
+			vType = common.InfluxMetricValueTypeSum
+		case telegraf.Histogram:
+			vType = common.InfluxMetricValueTypeHistogram
+		case telegraf.Summary:
+			vType = common.InfluxMetricValueTypeSummary
+		default:
+			log.Warnf("Unrecognized metric type %v", metric.Type())
+			continue
+		}
+		err := batch.AddPoint(metric.Name(), metric.Tags(), metric.Fields(), metric.Time(), vType)
+		if err != nil {
+			log.Warnf("Failed to add point: %v", err)
+			continue
+		}
+	}
+
+	md := batch.GetMetrics()
+	if exponential.Metrics().Len() > 0 {
+		exponential.MoveTo(md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty())
+	}
+	return md
+}
@@ -0,0 +1,231 @@
+package partition
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenFunc creates the format specific writer writing to the given file
+type OpenFunc[T io.Closer] func(w io.Writer) (T, error)
+
+// Pool manages the open files of partitions. Files are rolled over after
+// the configured interval or after exceeding the configured size. If the file
+// name of the partition contains the part placeholder a new part is started,
+// otherwise the existing file is renamed. Expired files of idle partitions are
+// closed when accessing any partition. The number of open files is bounded
+// by closing the least recently used files.
+type Pool[T io.Closer] struct {
+	// MaxOpen is the maximum number of open files, zero means unlimited
+	MaxOpen int
+	// Interval after which files are rolled over, zero disables time based
+	// rollover
+	Interval time.Duration
+	// MaxSize in bytes after which files are rolled over, zero disables size
+	// based rollover
+	MaxSize int64
+	// Append to existing files instead of rolling them over when opening
+	// partitions without part placeholder
+	Append bool
+
+	files   map[string]*file[T]
+	parts   map[string]int
+	lru     *list.List
+	expires time.Time
+}
+
+// file is an open file of a partition counting the bytes written
+type file[T io.Closer] struct {
+	partition string
+	filename  string
+	fd        *os.File
+	handle    T
+	written   int64
+	expires   time.Time
+	element   *list.Element
+}
+
+func (f *file[T]) Write(b []byte) (int, error) {
+	n, err := f.fd.Write(b)
+	f.written += int64(n)
+	return n, err
+}
+
+// Get returns the writer of the given partition, opening the file if
+// necessary using the open function
+func (p *Pool[T]) Get(partition string, open OpenFunc[T]) (T, error) {
+	var zero T
+	if p.files == nil {
+		p.files = make(map[string]*file[T])
+		p.parts = make(map[string]int)
+		p.lru = list.New()
+	}
+
+	// Roll over the expired files of other partitions not written to anymore
+	if err := p.closeExpired(partition); err != nil {
+		return zero, err
+	}
+
+	if f, found := p.files[partition]; found {
+		if !p.due(f.written, f.expires) {
+			p.lru.MoveToFront(f.element)
+			return f.handle, nil
+		}
+		if err := p.close(f); err != nil {
+			return zero, fmt.Errorf("closing file %q for rollover failed: %w", f.filename, err)
+		}
+	}
+
+	// Close the least recently used files to stay within the limit
+	for p.MaxOpen > 0 && p.lru.Len() >= p.MaxOpen {
+		f := p.lru.Back().Value.(*file[T])
+		if err := p.close(f); err != nil {
+			return zero, fmt.Errorf("closing file %q failed: %w", f.filename, err)
+		}
+	}
+
+	f, err := p.open(partition)
+	if err != nil {
+		return zero, err
+	}
+	f.handle, err = open(f)
+	if err != nil {
+		f.fd.Close() //nolint:errcheck // ignore the error as creating the writer failed anyway
+		return zero, fmt.Errorf("creating writer for file %q failed: %w", f.filename, err)
+	}
+	f.element = p.lru.PushFront(f)
+	p.files[partition] = f
+	if p.expires.IsZero() || f.expires.Before(p.expires) {
+		p.expires = f.expires
+	}
+
+	return f.handle, nil
+}
+
+// Filename returns the name of the currently open file of the partition
+func (p *Pool[T]) Filename(partition string) string {
+	if f, found := p.files[partition]; found {
+		return f.filename
+	}
+	return ""
+}
+
+// Close closes all open files
+func (p *Pool[T]) Close() error {
+	var errs []error
+	for _, f := range p.files {
+		if err := p.close(f); err != nil {
+			errs = append(errs, fmt.Errorf("closing file %q failed: %w", f.filename, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closeExpired closes the files of all partitions except the given one which
+// exceeded the rollover interval
+func (p *Pool[T]) closeExpired(partition string) error {
+	now := time.Now()
+	if p.Interval <= 0 || now.Before(p.expires) {
+		return nil
+	}
+
+	var errs []error
+	var next time.Time
+	for _, f := range p.files {
+		if f.partition != partition && now.After(f.expires) {
+			if err := p.close(f); err != nil {
+				errs = append(errs, fmt.Errorf("closing file %q for rollover failed: %w", f.filename, err))
+			}
+			continue
+		}
+		if next.IsZero() || f.expires.Before(next) {
+			next = f.expires
+		}
+	}
+	p.expires = next
+	return errors.Join(errs...)
+}
+
+func (p *Pool[T]) due(written int64, expires time.Time) bool {
+	return (p.Interval > 0 && time.Now().After(expires)) || (p.MaxSize > 0 && written >= p.MaxSize)
+}
+
+func (p *Pool[T]) open(partition string) (*file[T], error) {
+	if err := os.MkdirAll(filepath.Dir(partition), 0750); err != nil {
+		return nil, fmt.Errorf("creating directory for %q failed: %w", partition, err)
+	}
+
+	f := &file[T]{
+		partition: partition,
+		filename:  partition,
+		expires:   time.Now().Add(p.Interval),
+	}
+
+	// Start a new part using the first unused part number
+	dir, base := filepath.Split(partition)
+	if idx := strings.LastIndex(base, PartPlaceholder); idx >= 0 {
+		for n := p.parts[partition]; ; n++ {
+			name := base[:idx] + fmt.Sprintf("%05d", n) + base[idx+len(PartPlaceholder):]
+			if _, err := os.Stat(filepath.Join(dir, name)); errors.Is(err, os.ErrNotExist) {
+				f.filename = filepath.Join(dir, name)
+				p.parts[partition] = n + 1
+				break
+			}
+		}
+		fd, err := os.OpenFile(f.filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0640)
+		if err != nil {
+			return nil, fmt.Errorf("creating file %q failed: %w", f.filename, err)
+		}
+		f.fd = fd
+		return f, nil
+	}
+
+	// Continue existing files if possible or move them out of the way
+	if stat, err := os.Stat(f.filename); err == nil {
+		if p.Append && !p.due(stat.Size(), stat.ModTime().Add(p.Interval)) {
+			fd, err := os.OpenFile(f.filename, os.O_WRONLY|os.O_APPEND, 0640)
+			if err != nil {
+				return nil, fmt.Errorf("opening file %q failed: %w", f.filename, err)
+			}
+			f.fd = fd
+			f.written = stat.Size()
+			f.expires = stat.ModTime().Add(p.Interval)
+			return f, nil
+		}
+		if err := os.Rename(f.filename, rotatedName(f.filename)); err != nil {
+			return nil, fmt.Errorf("renaming file %q failed: %w", f.filename, err)
+		}
+	}
+
+	fd, err := os.OpenFile(f.filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("creating file %q failed: %w", f.filename, err)
+	}
+	f.fd = fd
+	return f, nil
+}
+
+func (p *Pool[T]) close(f *file[T]) error {
+	p.lru.Remove(f.element)
+	delete(p.files, f.partition)
+
+	err := f.handle.Close()
+	if cerr := f.fd.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// rotatedName returns the name for moving a file out of the way using the
+// same scheme as the rotating file writer
+func rotatedName(filename string) string {
+	ext := filepath.Ext(filename)
+	now := time.Now()
+	return strings.TrimSuffix(filename, ext) + "." + now.Format("2006-01-02") + "-" + strconv.FormatInt(now.Unix(), 10) + ext
+}
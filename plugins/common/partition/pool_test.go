@@ -0,0 +1,124 @@
+package partition
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type writer struct {
+	io.Writer
+	closed bool
+}
+
+func (w *writer) Close() error {
+	w.closed = true
+	return nil
+}
+
+func open(w io.Writer) (*writer, error) {
+	return &writer{Writer: w}, nil
+}
+
+func TestPoolRolloverSize(t *testing.T) {
+	dir := t.TempDir()
+	partition := filepath.Join(dir, "cpu", "part-*.out")
+
+	pool := &Pool[*writer]{MaxSize: 10}
+	for i := 0; i < 3; i++ {
+		w, err := pool.Get(partition, open)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("0123456789"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, pool.Close())
+
+	for _, name := range []string{"part-00000.out", "part-00001.out", "part-00002.out"} {
+		buf, err := os.ReadFile(filepath.Join(dir, "cpu", name))
+		require.NoError(t, err)
+		require.Equal(t, "0123456789", string(buf))
+	}
+}
+
+func TestPoolRolloverIdle(t *testing.T) {
+	dir := t.TempDir()
+
+	pool := &Pool[*writer]{Interval: 50 * time.Millisecond}
+	idle, err := pool.Get(filepath.Join(dir, "a.out"), open)
+	require.NoError(t, err)
+	_, err = idle.Write([]byte("idle"))
+	require.NoError(t, err)
+
+	// Writing to another partition must close the expired idle partition
+	time.Sleep(100 * time.Millisecond)
+	active, err := pool.Get(filepath.Join(dir, "b.out"), open)
+	require.NoError(t, err)
+	require.True(t, idle.closed)
+	require.False(t, active.closed)
+	require.Empty(t, pool.Filename(filepath.Join(dir, "a.out")))
+
+	require.NoError(t, pool.Close())
+	buf, err := os.ReadFile(filepath.Join(dir, "a.out"))
+	require.NoError(t, err)
+	require.Equal(t, "idle", string(buf))
+}
+
+func TestPoolSkipExistingParts(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "part-00000.out"), []byte("existing"), 0600))
+
+	pool := &Pool[*writer]{}
+	w, err := pool.Get(filepath.Join(dir, "part-*.out"), open)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("new"))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "part-00001.out"), pool.Filename(filepath.Join(dir, "part-*.out")))
+	require.NoError(t, pool.Close())
+
+	buf, err := os.ReadFile(filepath.Join(dir, "part-00000.out"))
+	require.NoError(t, err)
+	require.Equal(t, "existing", string(buf))
+}
+
+func TestPoolAppend(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "metrics.out")
+	require.NoError(t, os.WriteFile(filename, []byte("existing\n"), 0600))
+
+	pool := &Pool[*writer]{Append: true}
+	w, err := pool.Get(filename, open)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("new\n"))
+	require.NoError(t, err)
+	require.NoError(t, pool.Close())
+
+	buf, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "existing\nnew\n", string(buf))
+}
+
+func TestPoolMaxOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	pool := &Pool[*writer]{MaxOpen: 2}
+	writers := make([]*writer, 0, 3)
+	for _, name := range []string{"a.out", "b.out", "c.out"} {
+		w, err := pool.Get(filepath.Join(dir, name), open)
+		require.NoError(t, err)
+		writers = append(writers, w)
+	}
+
+	// The least recently used file must be closed
+	require.True(t, writers[0].closed)
+	require.False(t, writers[1].closed)
+	require.False(t, writers[2].closed)
+	require.Empty(t, pool.Filename(filepath.Join(dir, "a.out")))
+
+	require.NoError(t, pool.Close())
+	require.True(t, writers[1].closed)
+	require.True(t, writers[2].closed)
+}
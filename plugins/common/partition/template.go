@@ -0,0 +1,72 @@
+package partition
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+
+	"github.com/influxdata/telegraf"
+)
+
+// PartPlaceholder is replaced by the part number in the file name of
+// partitions to allow rolling over to new files
+const PartPlaceholder = "*"
+
+// Template generates the file path of a partition from a metric using a Go
+// template, e.g. `{{.Name}}/date={{.Time.Format "2006-01-02"}}/part-*.parquet`
+type Template struct {
+	text string
+	tmpl *template.Template
+}
+
+// NewTemplate parses the given path template
+func NewTemplate(text string) (*Template, error) {
+	if text == "" {
+		return nil, errors.New("empty path template")
+	}
+	tmpl, err := template.New("path").Funcs(sprig.TxtFuncMap()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing path template failed: %w", err)
+	}
+	return &Template{text: text, tmpl: tmpl}, nil
+}
+
+// IsTemplate returns true if the given path contains template actions
+func IsTemplate(path string) bool {
+	return strings.Contains(path, "{{")
+}
+
+// HasPart returns true if the file name of the template contains the part
+// placeholder
+func (t *Template) HasPart() bool {
+	return strings.Contains(filepath.Base(t.text), PartPlaceholder)
+}
+
+// Path returns the path of the partition the metric belongs to. The path must
+// not be empty and must not refer to parent directories to prevent escaping
+// the configured location using metric values.
+func (t *Template) Path(m telegraf.Metric) (string, error) {
+	if wm, ok := m.(telegraf.UnwrappableMetric); ok {
+		m = wm.Unwrap()
+	}
+
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, m.(telegraf.TemplateMetric)); err != nil {
+		return "", err
+	}
+
+	path := b.String()
+	if path == "" {
+		return "", errors.New("empty path")
+	}
+	for _, element := range strings.Split(filepath.ToSlash(path), "/") {
+		if element == ".." {
+			return "", fmt.Errorf("path %q refers to a parent directory", path)
+		}
+	}
+	return filepath.Clean(path), nil
+}
@@ -0,0 +1,66 @@
+package partition
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf/metric"
+)
+
+func TestTemplatePath(t *testing.T) {
+	m := metric.New(
+		"cpu",
+		map[string]string{"host": "server01"},
+		map[string]interface{}{"value": 42},
+		time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	)
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+		part     bool
+		errmsg   string
+	}{
+		{
+			name:     "hive style",
+			text:     `{{.Name}}/date={{.Time.Format "2006-01-02"}}/host={{.Tag "host"}}/part-*.parquet`,
+			expected: filepath.Join("cpu", "date=2024-03-01", "host=server01", "part-*.parquet"),
+			part:     true,
+		},
+		{
+			name:     "sprig functions",
+			text:     `{{.Name | upper}}.out`,
+			expected: "CPU.out",
+		},
+		{
+			name:   "empty",
+			text:   `{{.Tag "missing"}}`,
+			errmsg: "empty path",
+		},
+		{
+			name:   "parent directory",
+			text:   `../{{.Name}}.out`,
+			errmsg: "refers to a parent directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := NewTemplate(tt.text)
+			require.NoError(t, err)
+			require.Equal(t, tt.part, tmpl.HasPart())
+
+			actual, err := tmpl.Path(m)
+			if tt.errmsg != "" {
+				require.ErrorContains(t, err, tt.errmsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+// Package schemaregistry implements a client for Confluent-compatible schema
+// registries shared by the parsers and serializers using the Confluent wire
+// format
+package schemaregistry
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	contentType       = "application/vnd.schemaregistry.v1+json"
+	schemaByID        = "%s/schemas/ids/%d"
+	schemaBySubject   = "%s/subjects/%s/versions/%d"
+	registerBySubject = "%s/subjects/%s/versions"
+)
+
+// Reference to another schema imported by a schema
+type Reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Schema as returned by the registry
+type Schema struct {
+	Type       string      `json:"schemaType"`
+	Schema     string      `json:"schema"`
+	References []Reference `json:"references"`
+}
+
+// Client fetches and registers schemas with a Confluent-compatible schema
+// registry
+type Client struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+
+	ids map[string]int
+	mu  sync.Mutex
+}
+
+type registerRequest struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+type registerResponse struct {
+	ID        int    `json:"id"`
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// NewClient creates a client for the registry at the given address which may
+// contain username and password for basic authentication
+func NewClient(addr, caCertPath string) (*Client, error) {
+	var tlsCfg *tls.Config
+	if caCertPath != "" {
+		caCert, err := os.ReadFile(caCertPath)
+		if err != nil {
+			return nil, err
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(caCert)
+		tlsCfg = &tls.Config{
+			RootCAs: caCertPool,
+		}
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing registry URL failed: %w", err)
+	}
+
+	var username, password string
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+		u.User = nil
+	}
+
+	return &Client{
+		url:      u.String(),
+		username: username,
+		password: password,
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: tlsCfg,
+				MaxIdleConns:    10,
+				IdleConnTimeout: 90 * time.Second,
+			},
+			Timeout: 30 * time.Second,
+		},
+		ids: make(map[string]int),
+	}, nil
+}
+
+// SchemaByID returns the schema registered with the given ID
+func (c *Client) SchemaByID(id int) (*Schema, error) {
+	return c.fetch(fmt.Sprintf(schemaByID, c.url, id))
+}
+
+// SchemaBySubject returns the given version of the schema registered under
+// the subject
+func (c *Client) SchemaBySubject(subject string, version int) (*Schema, error) {
+	return c.fetch(fmt.Sprintf(schemaBySubject, c.url, url.PathEscape(subject), version))
+}
+
+// Register registers the schema of the given type, e.g. "AVRO" or "PROTOBUF",
+// under the subject and returns the schema ID. The registry returns the ID of
+// an existing schema if the schema was registered before. IDs are cached so
+// the registry is only contacted once for each subject and schema.
+func (c *Client) Register(subject, schemaType, schema string) (int, error) {
+	key := subject + "\x00" + schemaType + "\x00" + schema
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id, found := c.ids[key]; found {
+		return id, nil
+	}
+
+	body, err := json.Marshal(&registerRequest{Schema: schema, SchemaType: schemaType})
+	if err != nil {
+		return 0, err
+	}
+
+	address := fmt.Sprintf(registerBySubject, c.url, url.PathEscape(subject))
+	req, err := http.NewRequest(http.MethodPost, address, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var response registerResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&response)
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && response.Message != "" {
+			return 0, fmt.Errorf("registering schema for subject %q failed: %s (%d)", subject, response.Message, response.ErrorCode)
+		}
+		return 0, fmt.Errorf("registering schema for subject %q failed with status %q", subject, resp.Status)
+	}
+	if decodeErr != nil {
+		return 0, fmt.Errorf("decoding response failed: %w", decodeErr)
+	}
+	if response.ID <= 0 {
+		return 0, errors.New("malformed response from schema registry: no schema ID")
+	}
+
+	c.ids[key] = response.ID
+	return response.ID, nil
+}
+
+// AppendHeader appends the magic byte and the schema ID of the Confluent wire
+// format to the buffer
+func AppendHeader(buf []byte, id int) []byte {
+	buf = append(buf, 0)
+	return binary.BigEndian.AppendUint32(buf, uint32(id))
+}
+
+func (c *Client) fetch(address string) (*Schema, error) {
+	req, err := http.NewRequest(http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("schema registry returned status %q", resp.Status)
+	}
+
+	var schema Schema
+	if err := json.NewDecoder(resp.Body).Decode(&schema); err != nil {
+		return nil, err
+	}
+	if schema.Schema == "" {
+		return nil, errors.New("malformed response from schema registry: no 'schema' key")
+	}
+
+	return &schema, nil
+}
@@ -0,0 +1,130 @@
+package schemaregistry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var schema *Schema
+		switch r.URL.Path {
+		case "/schemas/ids/1":
+			schema = &Schema{
+				Type:       "PROTOBUF",
+				Schema:     `syntax = "proto3"; import "other.proto";`,
+				References: []Reference{{Name: "other.proto", Subject: "other", Version: 2}},
+			}
+		case "/subjects/other/versions/2":
+			schema = &Schema{Type: "PROTOBUF", Schema: `syntax = "proto3";`}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(schema); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("http://user:secret@"+server.Listener.Addr().String(), "")
+	require.NoError(t, err)
+
+	schema, err := client.SchemaByID(1)
+	require.NoError(t, err)
+	require.Equal(t, "PROTOBUF", schema.Type)
+	require.Len(t, schema.References, 1)
+
+	ref := schema.References[0]
+	schema, err = client.SchemaBySubject(ref.Subject, ref.Version)
+	require.NoError(t, err)
+	require.Equal(t, `syntax = "proto3";`, schema.Schema)
+
+	_, err = client.SchemaByID(2)
+	require.ErrorContains(t, err, "404 Not Found")
+}
+
+func TestRegister(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/subjects/cpu-value/versions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		id := 1
+		if body["schemaType"] == "PROTOBUF" {
+			id = 2
+		}
+		if err := json.NewEncoder(w).Encode(map[string]int{"id": id}); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("http://user:secret@"+server.Listener.Addr().String(), "")
+	require.NoError(t, err)
+
+	id, err := client.Register("cpu-value", "AVRO", `"string"`)
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+
+	// Registering the same schema again should use the cached ID
+	id, err = client.Register("cpu-value", "AVRO", `"string"`)
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+	require.Equal(t, 1, requests)
+
+	id, err = client.Register("cpu-value", "PROTOBUF", `syntax = "proto3";`)
+	require.NoError(t, err)
+	require.Equal(t, 2, id)
+	require.Equal(t, 2, requests)
+
+	_, err = client.Register("mem-value", "AVRO", `"string"`)
+	require.ErrorContains(t, err, `registering schema for subject "mem-value" failed`)
+}
+
+func TestAppendHeader(t *testing.T) {
+	require.Equal(t, []byte{0x2a, 0x00, 0x00, 0x00, 0x01, 0x02}, AppendHeader([]byte{0x2a}, 258))
+}
+
+func TestSanitizeName(t *testing.T) {
+	require.Equal(t, "cpu_usage", SanitizeName("cpu.usage"))
+	require.Equal(t, "_1m_load", SanitizeName("1m-load"))
+	require.Equal(t, "_", SanitizeName(""))
+}
+
+func TestSubject(t *testing.T) {
+	require.NoError(t, CheckSubjectStrategy("topic_record"))
+	require.ErrorContains(t, CheckSubjectStrategy("foo"), `invalid subject strategy "foo"`)
+
+	require.Equal(t, "telegraf.cpu", Subject("", "metrics", "telegraf.cpu"))
+	require.Equal(t, "telegraf.cpu", Subject("record", "metrics", "telegraf.cpu"))
+	require.Equal(t, "metrics-value", Subject("topic", "metrics", "telegraf.cpu"))
+	require.Equal(t, "metrics-telegraf.cpu", Subject("topic_record", "metrics", "telegraf.cpu"))
+
+	// Without topic the record name is used
+	require.Equal(t, "telegraf.cpu", Subject("topic", "", "telegraf.cpu"))
+}
@@ -0,0 +1,45 @@
+package schemaregistry
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// SanitizeName converts the given name into a name valid for records and
+// fields of Avro and Protobuf schemas by replacing all characters other than
+// letters, digits and underscores with underscores
+func SanitizeName(name string) string {
+	name = invalidNameChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// CheckSubjectStrategy returns an error if the subject name strategy is
+// not supported
+func CheckSubjectStrategy(strategy string) error {
+	switch strategy {
+	case "", "record", "topic", "topic_record":
+		return nil
+	}
+	return fmt.Errorf("invalid subject strategy %q", strategy)
+}
+
+// Subject returns the subject to register the schema of the given record
+// under according to the subject name strategy. Strategies depending on the
+// topic fall back to the record name if the topic is unknown.
+func Subject(strategy, topic, record string) string {
+	if topic == "" {
+		return record
+	}
+	switch strategy {
+	case "topic":
+		return topic + "-value"
+	case "topic_record":
+		return topic + "-" + record
+	}
+	return record
+}
@@ -0,0 +1,22 @@
+package shim
+
+import (
+	"fmt"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/models"
+)
+
+// AddAggregator adds the aggregator to the shim. Aggregators are only
+// supported when being run via gRPC.
+func (s *Shim) AddAggregator(aggregator telegraf.Aggregator) error {
+	models.SetLoggerOnPlugin(aggregator, s.Log())
+	if p, ok := aggregator.(telegraf.Initializer); ok {
+		if err := p.Init(); err != nil {
+			return fmt.Errorf("failed to init aggregator: %w", err)
+		}
+	}
+
+	s.Aggregator = aggregator
+	return nil
+}
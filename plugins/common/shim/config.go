@@ -9,21 +9,27 @@ import (
 	"github.com/BurntSushi/toml"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/aggregators"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/outputs"
 	"github.com/influxdata/telegraf/plugins/processors"
+	"github.com/influxdata/telegraf/plugins/secretstores"
 )
 
 type config struct {
-	Inputs     map[string][]toml.Primitive
-	Processors map[string][]toml.Primitive
-	Outputs    map[string][]toml.Primitive
+	Inputs       map[string][]toml.Primitive
+	Processors   map[string][]toml.Primitive
+	Outputs      map[string][]toml.Primitive
+	Aggregators  map[string][]toml.Primitive
+	SecretStores map[string][]toml.Primitive
 }
 
 type loadedConfig struct {
-	Input     telegraf.Input
-	Processor telegraf.StreamingProcessor
-	Output    telegraf.Output
+	Input       telegraf.Input
+	Processor   telegraf.StreamingProcessor
+	Output      telegraf.Output
+	Aggregator  telegraf.Aggregator
+	SecretStore telegraf.SecretStore
 }
 
 // LoadConfig Adds plugins to the shim
@@ -44,6 +50,14 @@ func (s *Shim) LoadConfig(filePath *string) error {
 		if err = s.AddOutput(conf.Output); err != nil {
 			return fmt.Errorf("failed to add Output: %w", err)
 		}
+	} else if conf.Aggregator != nil {
+		if err = s.AddAggregator(conf.Aggregator); err != nil {
+			return fmt.Errorf("failed to add Aggregator: %w", err)
+		}
+	} else if conf.SecretStore != nil {
+		if err = s.AddSecretStore(conf.SecretStore); err != nil {
+			return fmt.Errorf("failed to add SecretStore: %w", err)
+		}
 	}
 	return nil
 }
@@ -139,6 +153,40 @@ func createPluginsWithTomlConfig(md toml.MetaData, conf config) (loadedConfig, e
 		loadedConf.Output = plugin
 		break
 	}
+
+	for name, primitives := range conf.Aggregators {
+		creator, ok := aggregators.Aggregators[name]
+		if !ok {
+			return loadedConf, errors.New("unknown aggregator " + name)
+		}
+
+		plugin := creator()
+		if len(primitives) > 0 {
+			primitive := primitives[0]
+			if err := md.PrimitiveDecode(primitive, plugin); err != nil {
+				return loadedConf, err
+			}
+		}
+		loadedConf.Aggregator = plugin
+		break
+	}
+
+	for name, primitives := range conf.SecretStores {
+		creator, ok := secretstores.SecretStores[name]
+		if !ok {
+			return loadedConf, errors.New("unknown secret-store " + name)
+		}
+
+		plugin := creator(name)
+		if len(primitives) > 0 {
+			primitive := primitives[0]
+			if err := md.PrimitiveDecode(primitive, plugin); err != nil {
+				return loadedConf, err
+			}
+		}
+		loadedConf.SecretStore = plugin
+		break
+	}
 	return loadedConf, nil
 }
 
@@ -166,5 +214,10 @@ func DefaultImportedPlugins() config {
 		conf.Outputs[name] = make([]toml.Primitive, 0)
 		return conf
 	}
+	for name := range aggregators.Aggregators {
+		log.Println("No config found. Loading default config for plugin", name)
+		conf.Aggregators = map[string][]toml.Primitive{name: make([]toml.Primitive, 0)}
+		return conf
+	}
 	return conf
 }
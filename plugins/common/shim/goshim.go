@@ -13,6 +13,7 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/logger"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
 	"github.com/influxdata/telegraf/plugins/serializers/influx"
 )
 
@@ -35,9 +36,11 @@ const (
 // Shim allows you to wrap your inputs and run them as if they were part of Telegraf,
 // except built externally.
 type Shim struct {
-	Input     telegraf.Input
-	Processor telegraf.StreamingProcessor
-	Output    telegraf.Output
+	Input       telegraf.Input
+	Processor   telegraf.StreamingProcessor
+	Output      telegraf.Output
+	Aggregator  telegraf.Aggregator
+	SecretStore telegraf.SecretStore
 
 	BatchSize    int
 	BatchTimeout time.Duration
@@ -58,7 +61,7 @@ type Shim struct {
 
 // New creates a new shim interface
 func New() *Shim {
-	return &Shim{
+	s := &Shim{
 		BatchSize:    1,
 		BatchTimeout: 10 * time.Second,
 		metricCh:     make(chan telegraf.Metric, 1),
@@ -67,6 +70,12 @@ func New() *Shim {
 		stderr:       os.Stderr,
 		log:          logger.New("", "", ""),
 	}
+
+	// Stream the log messages to Telegraf when being run via gRPC
+	if os.Getenv(grpcplugin.SocketEnv) != "" {
+		s.log = newStreamLogger(s.stderr)
+	}
+	return s
 }
 
 func (*Shim) watchForShutdown(cancel context.CancelFunc) {
@@ -81,6 +90,14 @@ func (*Shim) watchForShutdown(cancel context.CancelFunc) {
 
 // Run the input plugins..
 func (s *Shim) Run(pollInterval time.Duration) error {
+	// Serve the plugin via gRPC if requested by Telegraf
+	if socket := os.Getenv(grpcplugin.SocketEnv); socket != "" {
+		if err := s.RunGRPC(socket); err != nil {
+			return fmt.Errorf("running gRPC plugin failed: %w", err)
+		}
+		return nil
+	}
+
 	if s.Input != nil {
 		err := s.RunInput(pollInterval)
 		if err != nil {
@@ -0,0 +1,524 @@
+package shim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin/pluginv1"
+)
+
+// RunGRPC serves the plugin via gRPC on the given unix socket until stdin is
+// closed or a termination signal is received
+func (s *Shim) RunGRPC(socket string) error {
+	logger, ok := s.log.(*streamLogger)
+	if !ok {
+		logger = newStreamLogger(s.stderr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := grpc.NewServer()
+	base := &pluginServer{log: logger, done: ctx.Done()}
+	var shutdown func()
+	switch {
+	case s.Input != nil:
+		srv := &inputServer{
+			input:     s.Input,
+			events:    make(chan *pluginv1.InputEvent, 1000),
+			delivered: make(chan telegraf.DeliveryInfo, 1000),
+			done:      ctx.Done(),
+		}
+		pluginv1.RegisterInputServer(server, srv)
+		base.pluginType = pluginv1.PluginType_PLUGIN_TYPE_INPUT
+		shutdown = srv.stop
+	case s.Processor != nil:
+		srv := &processorServer{processor: s.Processor, log: logger}
+		pluginv1.RegisterProcessorServer(server, srv)
+		base.pluginType = pluginv1.PluginType_PLUGIN_TYPE_PROCESSOR
+		shutdown = srv.stop
+	case s.Aggregator != nil:
+		pluginv1.RegisterAggregatorServer(server, &aggregatorServer{aggregator: s.Aggregator})
+		base.pluginType = pluginv1.PluginType_PLUGIN_TYPE_AGGREGATOR
+	case s.Output != nil:
+		srv := &outputServer{output: s.Output, log: logger}
+		pluginv1.RegisterOutputServer(server, srv)
+		base.pluginType = pluginv1.PluginType_PLUGIN_TYPE_OUTPUT
+		shutdown = srv.stop
+	case s.SecretStore != nil:
+		pluginv1.RegisterSecretStoreServer(server, &secretStoreServer{store: s.SecretStore})
+		base.pluginType = pluginv1.PluginType_PLUGIN_TYPE_SECRETSTORE
+	default:
+		return errors.New("nothing to run")
+	}
+	pluginv1.RegisterPluginServer(server, base)
+
+	// Remove stale sockets of previous runs
+	if err := os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing socket failed: %w", err)
+	}
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("listening on socket failed: %w", err)
+	}
+
+	// Telegraf closes stdin when stopping the plugin
+	s.watchForShutdown(cancel)
+	go func() {
+		io.Copy(io.Discard, s.stdin) //nolint:errcheck // stdin is only used to detect the shutdown
+		cancel()
+	}()
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	err = server.Serve(listener)
+	if shutdown != nil {
+		shutdown()
+	}
+	return err
+}
+
+// pluginServer implements the services common to all plugins
+type pluginServer struct {
+	pluginv1.UnimplementedPluginServer
+
+	pluginType pluginv1.PluginType
+	log        *streamLogger
+	done       <-chan struct{}
+}
+
+func (s *pluginServer) Handshake(_ context.Context, req *pluginv1.HandshakeRequest) (*pluginv1.HandshakeResponse, error) {
+	if req.GetLogLevel() != pluginv1.LogLevel_LOG_LEVEL_UNSPECIFIED {
+		s.log.SetLevel(grpcplugin.FromProtoLogLevel(req.GetLogLevel()))
+	}
+	return &pluginv1.HandshakeResponse{
+		ProtocolVersion: grpcplugin.ProtocolVersion,
+		Type:            s.pluginType,
+	}, nil
+}
+
+func (s *pluginServer) Logs(_ *pluginv1.LogsRequest, stream grpc.ServerStreamingServer[pluginv1.LogEntry]) error {
+	for {
+		select {
+		case <-s.done:
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		case entry := <-s.log.entries:
+			if err := stream.Send(entry); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// inputServer runs the input. Metrics gathered on request are returned
+// directly while metrics of service inputs are streamed.
+type inputServer struct {
+	pluginv1.UnimplementedInputServer
+
+	input     telegraf.Input
+	events    chan *pluginv1.InputEvent
+	delivered chan telegraf.DeliveryInfo
+	done      <-chan struct{}
+
+	trackingID atomic.Uint64
+	started    bool
+	sync.Mutex
+}
+
+func (s *inputServer) Start(context.Context, *pluginv1.StartRequest) (*pluginv1.StartResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	serviceInput, ok := s.input.(telegraf.ServiceInput)
+	if !ok || s.started {
+		return &pluginv1.StartResponse{}, nil
+	}
+
+	acc := &grpcAccumulator{
+		emit: func(metrics []telegraf.Metric, trackingID uint64) {
+			s.send(&pluginv1.InputEvent{Event: &pluginv1.InputEvent_Group{Group: &pluginv1.MetricGroup{
+				Metrics:    grpcplugin.ToProtoMetrics(metrics),
+				TrackingId: trackingID,
+			}}})
+		},
+		emitError: func(err error) {
+			s.send(&pluginv1.InputEvent{Event: &pluginv1.InputEvent_Error{Error: err.Error()}})
+		},
+		trackingID: &s.trackingID,
+		delivered:  s.delivered,
+	}
+	if err := serviceInput.Start(acc); err != nil {
+		return nil, err
+	}
+	s.started = true
+
+	return &pluginv1.StartResponse{}, nil
+}
+
+// send queues the event for streaming and blocks if the queue is full to
+// propagate back-pressure to the plugin
+func (s *inputServer) send(event *pluginv1.InputEvent) {
+	select {
+	case s.events <- event:
+	case <-s.done:
+	}
+}
+
+func (s *inputServer) Gather(context.Context, *pluginv1.GatherRequest) (*pluginv1.GatherResponse, error) {
+	var mu sync.Mutex
+	resp := &pluginv1.GatherResponse{}
+	acc := &grpcAccumulator{
+		emit: func(metrics []telegraf.Metric, trackingID uint64) {
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Groups = append(resp.Groups, &pluginv1.MetricGroup{
+				Metrics:    grpcplugin.ToProtoMetrics(metrics),
+				TrackingId: trackingID,
+			})
+		},
+		emitError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Errors = append(resp.Errors, err.Error())
+		},
+		trackingID: &s.trackingID,
+		delivered:  s.delivered,
+	}
+	if err := s.input.Gather(acc); err != nil {
+		acc.AddError(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return resp, nil
+}
+
+func (s *inputServer) Subscribe(_ *pluginv1.SubscribeRequest, stream grpc.ServerStreamingServer[pluginv1.InputEvent]) error {
+	for {
+		select {
+		case <-s.done:
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		case event := <-s.events:
+			if err := stream.Send(event); err != nil {
+				// Keep the event for the next subscriber
+				s.send(event)
+				return err
+			}
+		}
+	}
+}
+
+func (s *inputServer) Acknowledge(ctx context.Context, req *pluginv1.DeliveryReport) (*pluginv1.AcknowledgeResponse, error) {
+	info := &deliveryInfo{id: telegraf.TrackingID(req.GetTrackingId()), delivered: req.GetDelivered()}
+	select {
+	case s.delivered <- info:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &pluginv1.AcknowledgeResponse{}, nil
+}
+
+func (s *inputServer) Stop(context.Context, *pluginv1.StopRequest) (*pluginv1.StopResponse, error) {
+	s.stop()
+	return &pluginv1.StopResponse{}, nil
+}
+
+func (s *inputServer) stop() {
+	s.Lock()
+	defer s.Unlock()
+
+	if serviceInput, ok := s.input.(telegraf.ServiceInput); ok && s.started {
+		serviceInput.Stop()
+	}
+	s.started = false
+}
+
+// processorServer applies the processor to the received metrics. Metrics
+// emitted asynchronously by streaming processors are returned with the
+// response to the next request.
+type processorServer struct {
+	pluginv1.UnimplementedProcessorServer
+
+	processor telegraf.StreamingProcessor
+	log       telegraf.Logger
+	acc       *grpcAccumulator
+	pending   []telegraf.Metric
+	started   bool
+
+	// Serialize the processing of requests
+	apply sync.Mutex
+	sync.Mutex
+}
+
+func (s *processorServer) Start(context.Context, *pluginv1.StartRequest) (*pluginv1.StartResponse, error) {
+	s.apply.Lock()
+	defer s.apply.Unlock()
+
+	if s.started {
+		return &pluginv1.StartResponse{}, nil
+	}
+
+	s.acc = &grpcAccumulator{
+		emit: func(metrics []telegraf.Metric, _ uint64) {
+			s.Lock()
+			defer s.Unlock()
+			s.pending = append(s.pending, metrics...)
+		},
+		emitError: func(err error) {
+			s.log.Errorf("Failure during processing metric by processor: %v", err)
+		},
+		trackingID: &atomic.Uint64{},
+	}
+	if err := s.processor.Start(s.acc); err != nil {
+		return nil, err
+	}
+	s.started = true
+
+	return &pluginv1.StartResponse{}, nil
+}
+
+func (s *processorServer) Apply(_ context.Context, req *pluginv1.ApplyRequest) (*pluginv1.ApplyResponse, error) {
+	s.apply.Lock()
+	defer s.apply.Unlock()
+
+	if !s.started {
+		return nil, errors.New("processor not started")
+	}
+
+	// Remember the source of the metrics to allow Telegraf keeping track of
+	// the original metrics
+	source := make(map[telegraf.Metric]uint32, len(req.GetMetrics()))
+	for i, pm := range req.GetMetrics() {
+		m := grpcplugin.FromProto(pm)
+		source[m] = uint32(i)
+		if err := s.processor.Add(m, s.acc); err != nil {
+			s.acc.AddError(err)
+		}
+	}
+
+	s.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.Unlock()
+
+	resp := &pluginv1.ApplyResponse{Metrics: make([]*pluginv1.ProcessedMetric, 0, len(pending))}
+	for _, m := range pending {
+		pm := &pluginv1.ProcessedMetric{Metric: grpcplugin.ToProto(m)}
+		if idx, found := source[m]; found {
+			pm.Source = &idx
+		}
+		resp.Metrics = append(resp.Metrics, pm)
+	}
+	return resp, nil
+}
+
+func (s *processorServer) Stop(context.Context, *pluginv1.StopRequest) (*pluginv1.StopResponse, error) {
+	s.stop()
+	return &pluginv1.StopResponse{}, nil
+}
+
+func (s *processorServer) stop() {
+	s.apply.Lock()
+	defer s.apply.Unlock()
+
+	if s.started {
+		s.processor.Stop()
+	}
+	s.started = false
+}
+
+// aggregatorServer forwards the calls to the aggregator
+type aggregatorServer struct {
+	pluginv1.UnimplementedAggregatorServer
+
+	aggregator telegraf.Aggregator
+	sync.Mutex
+}
+
+func (s *aggregatorServer) Add(_ context.Context, req *pluginv1.AddRequest) (*pluginv1.AddResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, pm := range req.GetMetrics() {
+		s.aggregator.Add(grpcplugin.FromProto(pm))
+	}
+	return &pluginv1.AddResponse{}, nil
+}
+
+func (s *aggregatorServer) Push(context.Context, *pluginv1.PushRequest) (*pluginv1.PushResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var metrics []telegraf.Metric
+	var errs []error
+	acc := &grpcAccumulator{
+		emit: func(group []telegraf.Metric, _ uint64) {
+			metrics = append(metrics, group...)
+		},
+		emitError: func(err error) {
+			errs = append(errs, err)
+		},
+		trackingID: &atomic.Uint64{},
+	}
+	s.aggregator.Push(acc)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &pluginv1.PushResponse{Metrics: grpcplugin.ToProtoMetrics(metrics)}, nil
+}
+
+func (s *aggregatorServer) Reset(context.Context, *pluginv1.ResetRequest) (*pluginv1.ResetResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.aggregator.Reset()
+	return &pluginv1.ResetResponse{}, nil
+}
+
+// outputServer forwards the calls to the output reporting partial writes
+type outputServer struct {
+	pluginv1.UnimplementedOutputServer
+
+	output    telegraf.Output
+	log       telegraf.Logger
+	connected bool
+	sync.Mutex
+}
+
+func (s *outputServer) Connect(context.Context, *pluginv1.ConnectRequest) (*pluginv1.ConnectResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return &pluginv1.ConnectResponse{}, nil
+}
+
+func (s *outputServer) connect() error {
+	if s.connected {
+		return nil
+	}
+	if err := s.output.Connect(); err != nil {
+		return err
+	}
+	s.connected = true
+	return nil
+}
+
+func (s *outputServer) Write(_ context.Context, req *pluginv1.WriteRequest) (*pluginv1.WriteResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	// Connect the output in case the process was restarted
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+
+	err := s.output.Write(grpcplugin.FromProtoMetrics(req.GetMetrics()))
+	if err == nil {
+		return &pluginv1.WriteResponse{}, nil
+	}
+
+	var writeErr *internal.PartialWriteError
+	if !errors.As(err, &writeErr) {
+		return nil, err
+	}
+	resp := &pluginv1.WriteResponse{
+		Error:    err.Error(),
+		Accepted: make([]uint32, 0, len(writeErr.MetricsAccept)),
+		Rejected: make([]uint32, 0, len(writeErr.MetricsReject)),
+	}
+	for _, idx := range writeErr.MetricsAccept {
+		resp.Accepted = append(resp.Accepted, uint32(idx))
+	}
+	for _, idx := range writeErr.MetricsReject {
+		resp.Rejected = append(resp.Rejected, uint32(idx))
+	}
+	return resp, nil
+}
+
+func (s *outputServer) Close(context.Context, *pluginv1.CloseRequest) (*pluginv1.CloseResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.connected = false
+	if err := s.output.Close(); err != nil {
+		return nil, err
+	}
+	return &pluginv1.CloseResponse{}, nil
+}
+
+// stop closes the output if Telegraf terminated without closing it
+func (s *outputServer) stop() {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.connected {
+		return
+	}
+	s.connected = false
+	if err := s.output.Close(); err != nil {
+		s.log.Errorf("Closing output failed: %v", err)
+	}
+}
+
+// secretStoreServer forwards the calls to the secret-store
+type secretStoreServer struct {
+	pluginv1.UnimplementedSecretStoreServer
+
+	store telegraf.SecretStore
+}
+
+func (s *secretStoreServer) Get(_ context.Context, req *pluginv1.GetRequest) (*pluginv1.GetResponse, error) {
+	value, err := s.store.Get(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	return &pluginv1.GetResponse{Value: value}, nil
+}
+
+func (s *secretStoreServer) Set(_ context.Context, req *pluginv1.SetRequest) (*pluginv1.SetResponse, error) {
+	if err := s.store.Set(req.GetKey(), req.GetValue()); err != nil {
+		return nil, err
+	}
+	return &pluginv1.SetResponse{}, nil
+}
+
+func (s *secretStoreServer) List(context.Context, *pluginv1.ListRequest) (*pluginv1.ListResponse, error) {
+	keys, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+	return &pluginv1.ListResponse{Keys: keys}, nil
+}
+
+func (s *secretStoreServer) Resolve(_ context.Context, req *pluginv1.ResolveRequest) (*pluginv1.ResolveResponse, error) {
+	resolver, err := s.store.GetResolver(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	value, dynamic, err := resolver()
+	if err != nil {
+		return nil, err
+	}
+	return &pluginv1.ResolveResponse{Value: value, Dynamic: dynamic}, nil
+}
@@ -0,0 +1,101 @@
+package shim
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// grpcAccumulator passes the metrics added by the plugin to the given
+// functions for sending them to Telegraf. Tracking IDs are assigned by the
+// accumulator and delivery reports are received from Telegraf.
+type grpcAccumulator struct {
+	emit      func(metrics []telegraf.Metric, trackingID uint64)
+	emitError func(err error)
+
+	trackingID *atomic.Uint64
+	delivered  chan telegraf.DeliveryInfo
+}
+
+func (a *grpcAccumulator) AddFields(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	a.add(measurement, fields, tags, telegraf.Untyped, t...)
+}
+
+func (a *grpcAccumulator) AddGauge(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	a.add(measurement, fields, tags, telegraf.Gauge, t...)
+}
+
+func (a *grpcAccumulator) AddCounter(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	a.add(measurement, fields, tags, telegraf.Counter, t...)
+}
+
+func (a *grpcAccumulator) AddSummary(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	a.add(measurement, fields, tags, telegraf.Summary, t...)
+}
+
+func (a *grpcAccumulator) AddHistogram(measurement string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	a.add(measurement, fields, tags, telegraf.Histogram, t...)
+}
+
+func (a *grpcAccumulator) add(
+	measurement string,
+	fields map[string]interface{},
+	tags map[string]string,
+	tp telegraf.ValueType,
+	t ...time.Time,
+) {
+	ts := time.Now()
+	if len(t) > 0 {
+		ts = t[0]
+	}
+	a.emit([]telegraf.Metric{metric.New(measurement, tags, fields, ts, tp)}, 0)
+}
+
+func (a *grpcAccumulator) AddMetric(m telegraf.Metric) {
+	a.emit([]telegraf.Metric{m}, 0)
+}
+
+// SetPrecision is a no-op as the precision is applied by Telegraf
+func (*grpcAccumulator) SetPrecision(time.Duration) {}
+
+func (a *grpcAccumulator) AddError(err error) {
+	if err != nil {
+		a.emitError(err)
+	}
+}
+
+// WithTracking returns the accumulator itself as the number of undelivered
+// metrics is limited by Telegraf
+func (a *grpcAccumulator) WithTracking(int) telegraf.TrackingAccumulator {
+	return a
+}
+
+func (a *grpcAccumulator) AddTrackingMetric(m telegraf.Metric) telegraf.TrackingID {
+	return a.AddTrackingMetricGroup([]telegraf.Metric{m})
+}
+
+func (a *grpcAccumulator) AddTrackingMetricGroup(group []telegraf.Metric) telegraf.TrackingID {
+	id := a.trackingID.Add(1)
+	a.emit(group, id)
+	return telegraf.TrackingID(id)
+}
+
+func (a *grpcAccumulator) Delivered() <-chan telegraf.DeliveryInfo {
+	return a.delivered
+}
+
+// deliveryInfo is the delivery report received from Telegraf
+type deliveryInfo struct {
+	id        telegraf.TrackingID
+	delivered bool
+}
+
+func (d *deliveryInfo) ID() telegraf.TrackingID {
+	return d.id
+}
+
+func (d *deliveryInfo) Delivered() bool {
+	return d.delivered
+}
@@ -0,0 +1,114 @@
+package shim
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin/pluginv1"
+)
+
+// streamLogger queues the log messages of the plugin for streaming them to
+// Telegraf. Messages are written to stderr if the queue is full.
+type streamLogger struct {
+	level   atomic.Int32
+	entries chan *pluginv1.LogEntry
+	stderr  io.Writer
+
+	attributes map[string]string
+	sync.Mutex
+}
+
+func newStreamLogger(stderr io.Writer) *streamLogger {
+	l := &streamLogger{
+		entries:    make(chan *pluginv1.LogEntry, 1000),
+		stderr:     stderr,
+		attributes: make(map[string]string),
+	}
+	l.level.Store(int32(telegraf.Info))
+	return l
+}
+
+func (l *streamLogger) SetLevel(level telegraf.LogLevel) {
+	l.level.Store(int32(level))
+}
+
+func (l *streamLogger) Level() telegraf.LogLevel {
+	return telegraf.LogLevel(l.level.Load())
+}
+
+func (l *streamLogger) AddAttribute(key string, value interface{}) {
+	l.Lock()
+	defer l.Unlock()
+	l.attributes[key] = fmt.Sprint(value)
+}
+
+func (l *streamLogger) Errorf(format string, args ...interface{}) {
+	l.print(telegraf.Error, fmt.Sprintf(format, args...))
+}
+
+func (l *streamLogger) Error(args ...interface{}) {
+	l.print(telegraf.Error, fmt.Sprint(args...))
+}
+
+func (l *streamLogger) Warnf(format string, args ...interface{}) {
+	l.print(telegraf.Warn, fmt.Sprintf(format, args...))
+}
+
+func (l *streamLogger) Warn(args ...interface{}) {
+	l.print(telegraf.Warn, fmt.Sprint(args...))
+}
+
+func (l *streamLogger) Infof(format string, args ...interface{}) {
+	l.print(telegraf.Info, fmt.Sprintf(format, args...))
+}
+
+func (l *streamLogger) Info(args ...interface{}) {
+	l.print(telegraf.Info, fmt.Sprint(args...))
+}
+
+func (l *streamLogger) Debugf(format string, args ...interface{}) {
+	l.print(telegraf.Debug, fmt.Sprintf(format, args...))
+}
+
+func (l *streamLogger) Debug(args ...interface{}) {
+	l.print(telegraf.Debug, fmt.Sprint(args...))
+}
+
+func (l *streamLogger) Tracef(format string, args ...interface{}) {
+	l.print(telegraf.Trace, fmt.Sprintf(format, args...))
+}
+
+func (l *streamLogger) Trace(args ...interface{}) {
+	l.print(telegraf.Trace, fmt.Sprint(args...))
+}
+
+func (l *streamLogger) print(level telegraf.LogLevel, msg string) {
+	if !l.Level().Includes(level) {
+		return
+	}
+
+	l.Lock()
+	var attributes map[string]string
+	if len(l.attributes) > 0 {
+		attributes = make(map[string]string, len(l.attributes))
+		for k, v := range l.attributes {
+			attributes[k] = v
+		}
+	}
+	l.Unlock()
+
+	entry := &pluginv1.LogEntry{
+		Level:      grpcplugin.ToProtoLogLevel(level),
+		Message:    msg,
+		Attributes: attributes,
+	}
+	select {
+	case l.entries <- entry:
+	default:
+		fmt.Fprintln(l.stderr, level.Indicator(), msg)
+	}
+}
@@ -2,13 +2,17 @@ package shim
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/plugins/common/shim/protocol"
 	"github.com/influxdata/telegraf/plugins/parsers/influx"
 )
 
@@ -87,10 +91,45 @@ func (s *Shim) RunOutput() error {
 	}
 
 	// Start the processing loop
+	var current *frame
 	scanner := bufio.NewScanner(s.stdin)
 	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Handle batches framed for acknowledgement. Those are written as a
+		// whole when the end marker is received independent of the batch
+		// settings as Telegraf determines the batch.
+		if id, ok := protocol.ParseBatchMarker(line, protocol.BatchStartMarker); ok {
+			current = &frame{id: id}
+			continue
+		}
+		if current != nil {
+			if id, ok := protocol.ParseBatchMarker(line, protocol.BatchEndMarker); ok && id == current.id {
+				mu.Lock()
+				ack := current.write(s.Output)
+				mu.Unlock()
+				fmt.Fprintln(s.stdout, ack.String())
+				current = nil
+				continue
+			}
+			if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			idx := current.count
+			current.count++
+			m, err := parser.ParseLine(line)
+			if err != nil {
+				fmt.Fprintf(s.stderr, "Failed to parse metric: %s\n", err)
+				current.rejected = append(current.rejected, idx)
+				continue
+			}
+			current.metrics = append(current.metrics, m)
+			current.indices = append(current.indices, idx)
+			continue
+		}
+
 		// Read metrics from stdin
-		m, err := parser.ParseLine(scanner.Text())
+		m, err := parser.ParseLine(line)
 		if err != nil {
 			fmt.Fprintf(s.stderr, "Failed to parse metric: %s\n", err)
 			continue
@@ -121,3 +160,46 @@ func (s *Shim) RunOutput() error {
 
 	return nil
 }
+
+// frame is a batch of metrics sent by Telegraf for acknowledgement
+type frame struct {
+	id       uint64
+	count    int
+	metrics  []telegraf.Metric
+	indices  []int
+	rejected []int
+}
+
+// write outputs the metrics of the frame and returns the acknowledgement with
+// the indices referring to the metrics as sent by Telegraf
+func (f *frame) write(output telegraf.Output) *protocol.Acknowledgement {
+	var err error
+	if len(f.metrics) > 0 {
+		err = output.Write(f.metrics)
+	}
+
+	var writeErr *internal.PartialWriteError
+	switch {
+	case err == nil:
+	case errors.As(err, &writeErr):
+		// Metrics neither accepted nor rejected should be retried so the
+		// whole batch is not acknowledged.
+		if len(writeErr.MetricsAccept)+len(writeErr.MetricsReject) < len(f.metrics) {
+			return &protocol.Acknowledgement{ID: f.id, Status: protocol.StatusNack, Message: err.Error()}
+		}
+		for _, idx := range writeErr.MetricsReject {
+			f.rejected = append(f.rejected, f.indices[idx])
+		}
+	default:
+		return &protocol.Acknowledgement{ID: f.id, Status: protocol.StatusNack, Message: err.Error()}
+	}
+
+	if len(f.rejected) > 0 {
+		ack := &protocol.Acknowledgement{ID: f.id, Status: protocol.StatusReject, Rejected: f.rejected}
+		if err != nil {
+			ack.Message = err.Error()
+		}
+		return ack
+	}
+	return &protocol.Acknowledgement{ID: f.id, Status: protocol.StatusAck}
+}
@@ -1,6 +1,7 @@
 package shim
 
 import (
+	"bufio"
 	"io"
 	"sync"
 	"sync/atomic"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/shim/protocol"
 	"github.com/influxdata/telegraf/plugins/serializers/influx"
 	"github.com/influxdata/telegraf/testutil"
 )
@@ -173,6 +175,70 @@ func TestOutputShimWithFlushTimeout(t *testing.T) {
 	testutil.RequireMetricsEqual(t, expected, o.MetricsWritten)
 }
 
+func TestOutputShimAcknowledgement(t *testing.T) {
+	o := &testOutput{}
+
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+
+	// Use a large batch size to make sure framed batches are written
+	// independent of the batch settings
+	s := New()
+	s.stdin = stdinReader
+	s.stdout = stdoutWriter
+	s.BatchSize = 100
+	s.BatchTimeout = 0
+	require.NoError(t, s.AddOutput(o))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		if err := s.RunOutput(); err != nil {
+			t.Error(err)
+		}
+		wg.Done()
+	}()
+
+	serializer := &influx.Serializer{}
+	require.NoError(t, serializer.Init())
+	m := metric.New("thing",
+		map[string]string{
+			"a": "b",
+		},
+		map[string]interface{}{
+			"v": 1,
+		},
+		time.Now(),
+	)
+	payload, err := serializer.Serialize(m)
+	require.NoError(t, err)
+
+	// Send a batch containing an invalid metric
+	go func() {
+		var batch []byte
+		batch = append(batch, protocol.BatchStartMarker+" 7\n"...)
+		batch = append(batch, payload...)
+		batch = append(batch, "invalid metric\n"...)
+		batch = append(batch, payload...)
+		batch = append(batch, protocol.BatchEndMarker+" 7\n"...)
+		if _, err := stdinWriter.Write(batch); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	// Read the acknowledgement rejecting the invalid metric
+	scanner := bufio.NewScanner(stdoutReader)
+	require.True(t, scanner.Scan())
+	ack, ok, err := protocol.ParseAcknowledgement(scanner.Text())
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, &protocol.Acknowledgement{ID: 7, Status: protocol.StatusReject, Rejected: []int{1}}, ack)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{m, m}, o.MetricsWritten)
+
+	require.NoError(t, stdinWriter.Close())
+	wg.Wait()
+}
+
 type testOutput struct {
 	MetricsWritten []telegraf.Metric
 	Count          atomic.Uint32
@@ -0,0 +1,107 @@
+// Package protocol contains the acknowledgement protocol spoken between the
+// execd output and external outputs run by the shim
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Markers framing a batch of metrics sent to an external output using the
+// acknowledgement protocol. The markers are written on separate lines
+// followed by the batch ID and are comments in influx line-protocol.
+const (
+	BatchStartMarker = "#telegraf:batch"
+	BatchEndMarker   = "#telegraf:end"
+)
+
+// Acknowledgement status replied by the external output for a batch
+const (
+	StatusAck    = "ack"
+	StatusNack   = "nack"
+	StatusReject = "reject"
+)
+
+// Acknowledgement is the reply of an external output for a batch. For
+// partially rejected batches the rejected metrics are given as indices into
+// the batch while all other metrics are accepted.
+type Acknowledgement struct {
+	ID       uint64
+	Status   string
+	Rejected []int
+	Message  string
+}
+
+// String returns the line representation of the acknowledgement, i.e.
+//
+//	ack <id>
+//	nack <id> [message]
+//	reject <id> <index>[,<index>...] [message]
+func (a *Acknowledgement) String() string {
+	parts := []string{a.Status, strconv.FormatUint(a.ID, 10)}
+	if a.Status == StatusReject {
+		indices := make([]string, 0, len(a.Rejected))
+		for _, idx := range a.Rejected {
+			indices = append(indices, strconv.Itoa(idx))
+		}
+		parts = append(parts, strings.Join(indices, ","))
+	}
+	if a.Message != "" && a.Status != StatusAck {
+		// The message must not break the line-based protocol
+		parts = append(parts, strings.Join(strings.Fields(a.Message), " "))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseAcknowledgement parses an acknowledgement line. The returned flag is
+// false if the line is not an acknowledgement.
+func ParseAcknowledgement(line string) (*Acknowledgement, bool, error) {
+	status, remainder, _ := strings.Cut(strings.TrimSpace(line), " ")
+	switch status {
+	case StatusAck, StatusNack, StatusReject:
+	default:
+		return nil, false, nil
+	}
+
+	rawID, remainder, _ := strings.Cut(remainder, " ")
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid batch ID %q: %w", rawID, err)
+	}
+	ack := &Acknowledgement{ID: id, Status: status}
+
+	if status == StatusReject {
+		var rawIndices string
+		rawIndices, remainder, _ = strings.Cut(remainder, " ")
+		if rawIndices == "" {
+			return nil, true, errors.New("missing indices of rejected metrics")
+		}
+		for _, raw := range strings.Split(rawIndices, ",") {
+			idx, err := strconv.Atoi(raw)
+			if err != nil || idx < 0 {
+				return nil, true, fmt.Errorf("invalid metric index %q", raw)
+			}
+			ack.Rejected = append(ack.Rejected, idx)
+		}
+	}
+	if status != StatusAck {
+		ack.Message = strings.TrimSpace(remainder)
+	}
+
+	return ack, true, nil
+}
+
+// ParseBatchMarker returns the batch ID if the line is the given marker
+func ParseBatchMarker(line, marker string) (uint64, bool) {
+	remainder, found := strings.CutPrefix(strings.TrimSpace(line), marker+" ")
+	if !found {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(strings.TrimSpace(remainder), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
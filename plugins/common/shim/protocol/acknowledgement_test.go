@@ -0,0 +1,66 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcknowledgementRoundtrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		ack      *Acknowledgement
+		expected string
+	}{
+		{
+			name:     "ack",
+			ack:      &Acknowledgement{ID: 42, Status: StatusAck},
+			expected: "ack 42",
+		},
+		{
+			name:     "nack",
+			ack:      &Acknowledgement{ID: 42, Status: StatusNack, Message: "connection refused"},
+			expected: "nack 42 connection refused",
+		},
+		{
+			name:     "reject",
+			ack:      &Acknowledgement{ID: 42, Status: StatusReject, Rejected: []int{0, 3}, Message: "invalid"},
+			expected: "reject 42 0,3 invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := tt.ack.String()
+			require.Equal(t, tt.expected, line)
+
+			actual, ok, err := ParseAcknowledgement(line)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, tt.ack, actual)
+		})
+	}
+}
+
+func TestAcknowledgementMultilineMessage(t *testing.T) {
+	ack := &Acknowledgement{ID: 1, Status: StatusNack, Message: "first\nsecond"}
+	require.Equal(t, "nack 1 first second", ack.String())
+}
+
+func TestParseAcknowledgementInvalid(t *testing.T) {
+	_, ok, err := ParseAcknowledgement("some log message")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, ok, err = ParseAcknowledgement("ack foo")
+	require.ErrorContains(t, err, `invalid batch ID "foo"`)
+	require.True(t, ok)
+
+	_, ok, err = ParseAcknowledgement("reject 1")
+	require.ErrorContains(t, err, "missing indices of rejected metrics")
+	require.True(t, ok)
+
+	_, ok, err = ParseAcknowledgement("reject 1 0,x")
+	require.ErrorContains(t, err, `invalid metric index "x"`)
+	require.True(t, ok)
+}
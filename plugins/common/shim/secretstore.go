@@ -0,0 +1,20 @@
+package shim
+
+import (
+	"fmt"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/models"
+)
+
+// AddSecretStore adds the secret-store to the shim. Secret-stores are only
+// supported when being run via gRPC.
+func (s *Shim) AddSecretStore(store telegraf.SecretStore) error {
+	models.SetLoggerOnPlugin(store, s.Log())
+	if err := store.Init(); err != nil {
+		return fmt.Errorf("failed to init secret-store: %w", err)
+	}
+
+	s.SecretStore = store
+	return nil
+}
@@ -0,0 +1,355 @@
+package sparkplug
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// DataType is the Sparkplug B data type of a metric value
+type DataType uint32
+
+// Data types defined by the Sparkplug B specification
+const (
+	Unknown  DataType = 0
+	Int8     DataType = 1
+	Int16    DataType = 2
+	Int32    DataType = 3
+	Int64    DataType = 4
+	UInt8    DataType = 5
+	UInt16   DataType = 6
+	UInt32   DataType = 7
+	UInt64   DataType = 8
+	Float    DataType = 9
+	Double   DataType = 10
+	Boolean  DataType = 11
+	String   DataType = 12
+	DateTime DataType = 13
+	Text     DataType = 14
+	UUID     DataType = 15
+	DataSet  DataType = 16
+	Bytes    DataType = 17
+	File     DataType = 18
+	Template DataType = 19
+)
+
+// Field numbers of the org.eclipse.tahu.protobuf.Payload message
+const (
+	payloadTimestamp protowire.Number = 1
+	payloadMetrics   protowire.Number = 2
+	payloadSeq       protowire.Number = 3
+	payloadUUID      protowire.Number = 4
+	payloadBody      protowire.Number = 5
+)
+
+// Field numbers of the org.eclipse.tahu.protobuf.Payload.Metric message
+const (
+	metricName         protowire.Number = 1
+	metricAlias        protowire.Number = 2
+	metricTimestamp    protowire.Number = 3
+	metricDataType     protowire.Number = 4
+	metricIsHistorical protowire.Number = 5
+	metricIsTransient  protowire.Number = 6
+	metricIsNull       protowire.Number = 7
+	metricIntValue     protowire.Number = 10
+	metricLongValue    protowire.Number = 11
+	metricFloatValue   protowire.Number = 12
+	metricDoubleValue  protowire.Number = 13
+	metricBooleanValue protowire.Number = 14
+	metricStringValue  protowire.Number = 15
+	metricBytesValue   protowire.Number = 16
+)
+
+// Payload is a Sparkplug B message payload. Data sets, templates, properties
+// and metadata of metrics are not supported and skipped during decoding.
+type Payload struct {
+	Timestamp uint64
+	Metrics   []*Metric
+	Seq       *uint64
+	UUID      string
+	Body      []byte
+}
+
+// Metric is a single metric of a Sparkplug B payload. The value is kept in
+// its wire representation, i.e. one of uint32, uint64, float32, float64,
+// bool, string or []byte, use Convert to get the value for the data type.
+type Metric struct {
+	Name         string
+	Alias        uint64
+	HasAlias     bool
+	Timestamp    uint64
+	DataType     DataType
+	IsHistorical bool
+	IsTransient  bool
+	IsNull       bool
+	Value        interface{}
+}
+
+// Unmarshal decodes the protobuf encoded payload
+func (p *Payload) Unmarshal(buf []byte) error {
+	*p = Payload{}
+	for len(buf) > 0 {
+		num, typ, n := protowire.ConsumeTag(buf)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		buf = buf[n:]
+
+		switch {
+		case num == payloadTimestamp && typ == protowire.VarintType:
+			p.Timestamp, n = protowire.ConsumeVarint(buf)
+		case num == payloadMetrics && typ == protowire.BytesType:
+			var v []byte
+			if v, n = protowire.ConsumeBytes(buf); n >= 0 {
+				m := &Metric{}
+				if err := m.unmarshal(v); err != nil {
+					return fmt.Errorf("decoding metric %d failed: %w", len(p.Metrics), err)
+				}
+				p.Metrics = append(p.Metrics, m)
+			}
+		case num == payloadSeq && typ == protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(buf)
+			p.Seq = &v
+		case num == payloadUUID && typ == protowire.BytesType:
+			var v []byte
+			v, n = protowire.ConsumeBytes(buf)
+			p.UUID = string(v)
+		case num == payloadBody && typ == protowire.BytesType:
+			var v []byte
+			v, n = protowire.ConsumeBytes(buf)
+			p.Body = append([]byte(nil), v...)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, buf)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		buf = buf[n:]
+	}
+	return nil
+}
+
+func (m *Metric) unmarshal(buf []byte) error {
+	for len(buf) > 0 {
+		num, typ, n := protowire.ConsumeTag(buf)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		buf = buf[n:]
+
+		var v uint64
+		var b []byte
+		switch {
+		case num == metricName && typ == protowire.BytesType:
+			b, n = protowire.ConsumeBytes(buf)
+			m.Name = string(b)
+		case num == metricAlias && typ == protowire.VarintType:
+			m.Alias, n = protowire.ConsumeVarint(buf)
+			m.HasAlias = true
+		case num == metricTimestamp && typ == protowire.VarintType:
+			m.Timestamp, n = protowire.ConsumeVarint(buf)
+		case num == metricDataType && typ == protowire.VarintType:
+			v, n = protowire.ConsumeVarint(buf)
+			m.DataType = DataType(v)
+		case num == metricIsHistorical && typ == protowire.VarintType:
+			v, n = protowire.ConsumeVarint(buf)
+			m.IsHistorical = v != 0
+		case num == metricIsTransient && typ == protowire.VarintType:
+			v, n = protowire.ConsumeVarint(buf)
+			m.IsTransient = v != 0
+		case num == metricIsNull && typ == protowire.VarintType:
+			v, n = protowire.ConsumeVarint(buf)
+			m.IsNull = v != 0
+		case num == metricIntValue && typ == protowire.VarintType:
+			v, n = protowire.ConsumeVarint(buf)
+			m.Value = uint32(v)
+		case num == metricLongValue && typ == protowire.VarintType:
+			m.Value, n = protowire.ConsumeVarint(buf)
+		case num == metricFloatValue && typ == protowire.Fixed32Type:
+			var f uint32
+			f, n = protowire.ConsumeFixed32(buf)
+			m.Value = math.Float32frombits(f)
+		case num == metricDoubleValue && typ == protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(buf)
+			m.Value = math.Float64frombits(v)
+		case num == metricBooleanValue && typ == protowire.VarintType:
+			v, n = protowire.ConsumeVarint(buf)
+			m.Value = v != 0
+		case num == metricStringValue && typ == protowire.BytesType:
+			b, n = protowire.ConsumeBytes(buf)
+			m.Value = string(b)
+		case num == metricBytesValue && typ == protowire.BytesType:
+			b, n = protowire.ConsumeBytes(buf)
+			m.Value = append([]byte(nil), b...)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, buf)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		buf = buf[n:]
+	}
+	return nil
+}
+
+// Marshal returns the protobuf encoding of the payload
+func (p *Payload) Marshal() ([]byte, error) {
+	var buf []byte
+	buf = protowire.AppendTag(buf, payloadTimestamp, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, p.Timestamp)
+	for i, m := range p.Metrics {
+		mbuf, err := m.marshal()
+		if err != nil {
+			return nil, fmt.Errorf("encoding metric %d failed: %w", i, err)
+		}
+		buf = protowire.AppendTag(buf, payloadMetrics, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, mbuf)
+	}
+	if p.Seq != nil {
+		buf = protowire.AppendTag(buf, payloadSeq, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, *p.Seq)
+	}
+	if p.UUID != "" {
+		buf = protowire.AppendTag(buf, payloadUUID, protowire.BytesType)
+		buf = protowire.AppendString(buf, p.UUID)
+	}
+	if len(p.Body) > 0 {
+		buf = protowire.AppendTag(buf, payloadBody, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, p.Body)
+	}
+	return buf, nil
+}
+
+func (m *Metric) marshal() ([]byte, error) {
+	var buf []byte
+	if m.Name != "" {
+		buf = protowire.AppendTag(buf, metricName, protowire.BytesType)
+		buf = protowire.AppendString(buf, m.Name)
+	}
+	if m.HasAlias {
+		buf = protowire.AppendTag(buf, metricAlias, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, m.Alias)
+	}
+	if m.Timestamp != 0 {
+		buf = protowire.AppendTag(buf, metricTimestamp, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, m.Timestamp)
+	}
+	if m.DataType != Unknown {
+		buf = protowire.AppendTag(buf, metricDataType, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(m.DataType))
+	}
+	if m.IsHistorical {
+		buf = protowire.AppendTag(buf, metricIsHistorical, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, 1)
+	}
+	if m.IsTransient {
+		buf = protowire.AppendTag(buf, metricIsTransient, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, 1)
+	}
+	if m.IsNull {
+		buf = protowire.AppendTag(buf, metricIsNull, protowire.VarintType)
+		return protowire.AppendVarint(buf, 1), nil
+	}
+
+	switch v := m.Value.(type) {
+	case uint32:
+		buf = protowire.AppendTag(buf, metricIntValue, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(v))
+	case uint64:
+		buf = protowire.AppendTag(buf, metricLongValue, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, v)
+	case float32:
+		buf = protowire.AppendTag(buf, metricFloatValue, protowire.Fixed32Type)
+		buf = protowire.AppendFixed32(buf, math.Float32bits(v))
+	case float64:
+		buf = protowire.AppendTag(buf, metricDoubleValue, protowire.Fixed64Type)
+		buf = protowire.AppendFixed64(buf, math.Float64bits(v))
+	case bool:
+		buf = protowire.AppendTag(buf, metricBooleanValue, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, protowire.EncodeBool(v))
+	case string:
+		buf = protowire.AppendTag(buf, metricStringValue, protowire.BytesType)
+		buf = protowire.AppendString(buf, v)
+	case []byte:
+		buf = protowire.AppendTag(buf, metricBytesValue, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, v)
+	case nil:
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", m.Value)
+	}
+	return buf, nil
+}
+
+// Convert returns the value of the metric converted according to the given
+// data type. Signed integers are sign-extended to int64, unsigned integers
+// are returned as uint64, floats as float64 and date-times as milliseconds
+// since epoch in int64.
+func (m *Metric) Convert(dt DataType) (interface{}, error) {
+	switch v := m.Value.(type) {
+	case uint32:
+		switch dt {
+		case Int8:
+			return int64(int8(v)), nil
+		case Int16:
+			return int64(int16(v)), nil
+		case Int32:
+			return int64(int32(v)), nil
+		case UInt8, UInt16, UInt32:
+			return uint64(v), nil
+		}
+	case uint64:
+		switch dt {
+		case Int64, DateTime:
+			return int64(v), nil
+		case UInt8, UInt16, UInt32, UInt64:
+			return v, nil
+		}
+	case float32:
+		if dt == Float {
+			return float64(v), nil
+		}
+	case float64:
+		if dt == Double {
+			return v, nil
+		}
+	case bool:
+		if dt == Boolean {
+			return v, nil
+		}
+	case string:
+		switch dt {
+		case String, Text, UUID:
+			return v, nil
+		}
+	case []byte:
+		if dt == Bytes || dt == File {
+			return v, nil
+		}
+	case nil:
+		return nil, errors.New("missing value")
+	}
+	return nil, fmt.Errorf("value of type %T does not match data type %d", m.Value, dt)
+}
+
+// SetValue sets the wire value and data type of the metric from the given
+// Go value
+func (m *Metric) SetValue(value interface{}) error {
+	switch v := value.(type) {
+	case int64:
+		m.DataType, m.Value = Int64, uint64(v)
+	case uint64:
+		m.DataType, m.Value = UInt64, v
+	case float64:
+		m.DataType, m.Value = Double, v
+	case bool:
+		m.DataType, m.Value = Boolean, v
+	case string:
+		m.DataType, m.Value = String, v
+	default:
+		return fmt.Errorf("unsupported value type %T", value)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package sparkplug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadRoundtrip(t *testing.T) {
+	seq := uint64(0)
+	expected := &Payload{
+		Timestamp: 1700000000000,
+		Seq:       &seq,
+		Metrics: []*Metric{
+			{Name: "bdSeq", DataType: UInt64, Value: uint64(7)},
+			{Name: "a", Alias: 0, HasAlias: true, DataType: Int8, Value: uint32(0x80)},
+			{Name: "b", Timestamp: 1699999999000, DataType: Float, Value: float32(1.25), IsHistorical: true},
+			{Alias: 3, HasAlias: true, Value: 2.5},
+			{Name: "c", DataType: Boolean, Value: true},
+			{Name: "d", DataType: String, Value: "foo"},
+			{Name: "e", DataType: Bytes, Value: []byte{0x01, 0x02}},
+			{Name: "f", DataType: Double, IsNull: true},
+		},
+	}
+
+	buf, err := expected.Marshal()
+	require.NoError(t, err)
+
+	var actual Payload
+	require.NoError(t, actual.Unmarshal(buf))
+	require.Equal(t, expected, &actual)
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		metric   *Metric
+		dt       DataType
+		expected interface{}
+	}{
+		{name: "int8", metric: &Metric{Value: uint32(0xff)}, dt: Int8, expected: int64(-1)},
+		{name: "int16", metric: &Metric{Value: uint32(0x8000)}, dt: Int16, expected: int64(-32768)},
+		{name: "int32", metric: &Metric{Value: uint32(0xfffffffe)}, dt: Int32, expected: int64(-2)},
+		{name: "int64", metric: &Metric{Value: ^uint64(0)}, dt: Int64, expected: int64(-1)},
+		{name: "uint16", metric: &Metric{Value: uint32(65535)}, dt: UInt16, expected: uint64(65535)},
+		{name: "uint64", metric: &Metric{Value: uint64(42)}, dt: UInt64, expected: uint64(42)},
+		{name: "float", metric: &Metric{Value: float32(0.5)}, dt: Float, expected: float64(0.5)},
+		{name: "text", metric: &Metric{Value: "foo"}, dt: Text, expected: "foo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.metric.Convert(tt.dt)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+
+	_, err := (&Metric{Value: "foo"}).Convert(Int32)
+	require.ErrorContains(t, err, "does not match data type 3")
+}
+
+func TestParseTopic(t *testing.T) {
+	for _, topic := range []string{
+		"spBv1.0/plant/NBIRTH/edge1",
+		"spBv1.0/plant/DDATA/edge1/tank",
+		"spBv1.0/STATE/scada",
+	} {
+		parsed, err := ParseTopic(topic)
+		require.NoError(t, err)
+		require.Equal(t, topic, parsed.String())
+	}
+
+	for _, topic := range []string{
+		"spAv1.0/plant/NBIRTH/edge1",
+		"spBv1.0/plant/NBIRTH/edge1/tank",
+		"spBv1.0/plant/FOO/edge1",
+	} {
+		_, err := ParseTopic(topic)
+		require.Error(t, err, topic)
+	}
+}
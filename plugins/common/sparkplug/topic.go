@@ -0,0 +1,76 @@
+package sparkplug
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Namespace is the topic namespace of Sparkplug B
+const Namespace = "spBv1.0"
+
+// Message types of Sparkplug B
+const (
+	NodeBirth     = "NBIRTH"
+	NodeDeath     = "NDEATH"
+	NodeData      = "NDATA"
+	NodeCommand   = "NCMD"
+	DeviceBirth   = "DBIRTH"
+	DeviceDeath   = "DDEATH"
+	DeviceData    = "DDATA"
+	DeviceCommand = "DCMD"
+	State         = "STATE"
+)
+
+// Topic is a Sparkplug B topic of the form
+// spBv1.0/<group_id>/<message_type>/<edge_node_id>[/<device_id>]
+type Topic struct {
+	GroupID     string
+	MessageType string
+	EdgeNodeID  string
+	DeviceID    string
+}
+
+// ParseTopic splits the given Sparkplug B topic into its components. For
+// STATE messages only the host ID is set as edge node ID.
+func ParseTopic(topic string) (*Topic, error) {
+	parts := strings.Split(topic, "/")
+	if len(parts) < 3 || parts[0] != Namespace {
+		return nil, fmt.Errorf("invalid topic %q", topic)
+	}
+
+	if parts[1] == State {
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid state topic %q", topic)
+		}
+		return &Topic{MessageType: State, EdgeNodeID: parts[2]}, nil
+	}
+
+	t := &Topic{GroupID: parts[1], MessageType: parts[2]}
+	switch t.MessageType {
+	case NodeBirth, NodeDeath, NodeData, NodeCommand:
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("invalid node topic %q", topic)
+		}
+		t.EdgeNodeID = parts[3]
+	case DeviceBirth, DeviceDeath, DeviceData, DeviceCommand:
+		if len(parts) != 5 {
+			return nil, fmt.Errorf("invalid device topic %q", topic)
+		}
+		t.EdgeNodeID, t.DeviceID = parts[3], parts[4]
+	default:
+		return nil, fmt.Errorf("invalid message type %q in topic %q", t.MessageType, topic)
+	}
+	return t, nil
+}
+
+// String returns the topic string
+func (t *Topic) String() string {
+	if t.MessageType == State {
+		return Namespace + "/" + State + "/" + t.EdgeNodeID
+	}
+	topic := Namespace + "/" + t.GroupID + "/" + t.MessageType + "/" + t.EdgeNodeID
+	if t.DeviceID != "" {
+		topic += "/" + t.DeviceID
+	}
+	return topic
+}
@@ -0,0 +1,210 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package external
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin/pluginv1"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type External struct {
+	grpcplugin.Config
+	Timeout                config.Duration `toml:"timeout"`
+	MaxUndeliveredMessages int             `toml:"max_undelivered_messages"`
+	Log                    telegraf.Logger `toml:"-"`
+
+	client *grpcplugin.Client
+	input  pluginv1.InputClient
+	acc    telegraf.TrackingAccumulator
+	sem    chan struct{}
+
+	// Mapping of the tracking IDs of Telegraf to the ones of the plugin
+	tracking map[telegraf.TrackingID]uint64
+	mu       sync.Mutex
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (*External) SampleConfig() string {
+	return sampleConfig
+}
+
+func (e *External) Init() error {
+	if e.MaxUndeliveredMessages < 1 {
+		return errors.New("max_undelivered_messages must be positive")
+	}
+	if len(e.Command) == 0 {
+		return errors.New("no command specified")
+	}
+	e.tracking = make(map[telegraf.TrackingID]uint64)
+
+	return nil
+}
+
+func (e *External) Start(acc telegraf.Accumulator) error {
+	e.acc = acc.WithTracking(e.MaxUndeliveredMessages)
+	e.sem = make(chan struct{}, e.MaxUndeliveredMessages)
+
+	client, err := e.Config.StartClient(pluginv1.PluginType_PLUGIN_TYPE_INPUT, e.Log)
+	if err != nil {
+		return err
+	}
+	input := pluginv1.NewInputClient(client.Conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+	if _, err := input.Start(ctx, &pluginv1.StartRequest{}); err != nil {
+		client.Stop()
+		return &internal.StartupError{Err: err, Retry: true}
+	}
+	e.client = client
+	e.input = input
+
+	ctx, e.cancel = context.WithCancel(context.Background())
+	e.wg.Add(2)
+	go func() {
+		defer e.wg.Done()
+		e.client.Retry(ctx, "metrics", e.subscribe)
+	}()
+	go func() {
+		defer e.wg.Done()
+		e.acknowledge(ctx)
+	}()
+
+	return nil
+}
+
+func (e *External) Gather(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	resp, err := e.input.Gather(ctx, &pluginv1.GatherRequest{})
+	if err != nil {
+		return err
+	}
+	for _, group := range resp.GetGroups() {
+		e.add(context.Background(), acc, group)
+	}
+	for _, msg := range resp.GetErrors() {
+		acc.AddError(errors.New(msg))
+	}
+	return nil
+}
+
+func (e *External) Stop() {
+	if e.client == nil {
+		return
+	}
+	if e.cancel != nil {
+		e.cancel()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+	if _, err := e.input.Stop(ctx, &pluginv1.StopRequest{}); err != nil {
+		e.Log.Errorf("Stopping plugin failed: %v", err)
+	}
+
+	e.client.Stop()
+	e.wg.Wait()
+}
+
+// subscribe receives the metrics and errors of service inputs. The plugin is
+// started again after being restarted.
+func (e *External) subscribe(ctx context.Context) error {
+	if _, err := e.input.Start(ctx, &pluginv1.StartRequest{}, grpc.WaitForReady(true)); err != nil {
+		return err
+	}
+	stream, err := e.input.Subscribe(ctx, &pluginv1.SubscribeRequest{}, grpc.WaitForReady(true))
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		switch ev := event.GetEvent().(type) {
+		case *pluginv1.InputEvent_Group:
+			e.add(ctx, e.acc, ev.Group)
+		case *pluginv1.InputEvent_Error:
+			e.acc.AddError(errors.New(ev.Error))
+		}
+	}
+}
+
+// add adds the metrics of the group to the accumulator, tracking the group
+// if requested by the plugin
+func (e *External) add(ctx context.Context, acc telegraf.Accumulator, group *pluginv1.MetricGroup) {
+	metrics := grpcplugin.FromProtoMetrics(group.GetMetrics())
+	if group.GetTrackingId() == 0 {
+		for _, m := range metrics {
+			acc.AddMetric(m)
+		}
+		return
+	}
+
+	// Limit the number of undelivered groups
+	select {
+	case e.sem <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	id := e.acc.AddTrackingMetricGroup(metrics)
+	e.tracking[id] = group.GetTrackingId()
+}
+
+// acknowledge reports the delivery of tracked groups to the plugin
+func (e *External) acknowledge(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case info := <-e.acc.Delivered():
+			e.mu.Lock()
+			id, found := e.tracking[info.ID()]
+			delete(e.tracking, info.ID())
+			e.mu.Unlock()
+			<-e.sem
+
+			if !found {
+				continue
+			}
+			report := &pluginv1.DeliveryReport{TrackingId: id, Delivered: info.Delivered()}
+			if _, err := e.input.Acknowledge(ctx, report); err != nil {
+				e.Log.Errorf("Reporting delivery of group %d failed: %v", id, err)
+			}
+		}
+	}
+}
+
+func init() {
+	inputs.Add("external", func() telegraf.Input {
+		return &External{
+			Config: grpcplugin.Config{
+				RestartDelay: config.Duration(10 * time.Second),
+				StartTimeout: config.Duration(10 * time.Second),
+			},
+			Timeout:                config.Duration(5 * time.Second),
+			MaxUndeliveredMessages: 1000,
+		}
+	})
+}
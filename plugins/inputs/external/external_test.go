@@ -0,0 +1,179 @@
+package external
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/shim"
+	"github.com/influxdata/telegraf/plugins/outputs/discard"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+var externalPlugin = flag.Bool("external-plugin", false, "if true, act like an external plugin instead of test")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *externalPlugin {
+		runExternalPlugin()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestGather(t *testing.T) {
+	plugin := newTestPlugin(t)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	require.NoError(t, plugin.Gather(&acc))
+	expected := []telegraf.Metric{
+		metric.New(
+			"requests",
+			map[string]string{"source": "external"},
+			map[string]interface{}{"count": int64(42), "ratio": 0.5, "status": "ok", "up": true, "bytes": uint64(23)},
+			time.Unix(1700000000, 0),
+			telegraf.Counter,
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.SortMetrics())
+	require.Len(t, acc.Errors, 1)
+	require.ErrorContains(t, acc.Errors[0], "partial failure")
+}
+
+func TestTracking(t *testing.T) {
+	plugin := newTestPlugin(t)
+	plugin.Environment = []string{"TEST_SERVICE=true"}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	// The plugin emits a tracked metric on start
+	require.Eventually(t, func() bool {
+		return acc.NMetrics() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	tracked := acc.GetTelegrafMetrics()[0]
+	require.Equal(t, "tracked", tracked.Name())
+
+	// Accepting the metric must be reported to the plugin
+	tracked.Accept()
+	require.Eventually(t, func() bool {
+		return acc.NMetrics() > 1
+	}, 5*time.Second, 10*time.Millisecond)
+	delivery := acc.GetTelegrafMetrics()[1]
+	require.Equal(t, "delivery", delivery.Name())
+	delivered, found := delivery.GetField("delivered")
+	require.True(t, found)
+	require.Equal(t, true, delivered)
+}
+
+func TestPluginTypeMismatch(t *testing.T) {
+	plugin := newTestPlugin(t)
+	plugin.Environment = []string{"TEST_OUTPUT=true"}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	err := plugin.Start(&acc)
+	require.ErrorContains(t, err, "does not match expected PLUGIN_TYPE_INPUT")
+	var serr *internal.StartupError
+	require.ErrorAs(t, err, &serr)
+	require.True(t, serr.Retry)
+
+	// Starting again must be possible after a failed attempt
+	plugin.Environment = nil
+	require.NoError(t, plugin.Start(&acc))
+	plugin.Stop()
+}
+
+func newTestPlugin(t *testing.T) *External {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	return &External{
+		Config: grpcplugin.Config{
+			Command:      []string{exe, "-external-plugin"},
+			RestartDelay: config.Duration(100 * time.Millisecond),
+			StartTimeout: config.Duration(5 * time.Second),
+		},
+		Timeout:                config.Duration(5 * time.Second),
+		MaxUndeliveredMessages: 10,
+		Log:                    testutil.Logger{},
+	}
+}
+
+func runExternalPlugin() {
+	s := shim.New()
+
+	var err error
+	switch {
+	case os.Getenv("TEST_OUTPUT") != "":
+		err = s.AddOutput(&discard.Discard{})
+	case os.Getenv("TEST_SERVICE") != "":
+		err = s.AddInput(&testServiceInput{})
+	default:
+		err = s.AddInput(&testInput{})
+	}
+	if err == nil {
+		err = s.Run(shim.PollIntervalDisabled)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // error code is important for this "test"
+		os.Exit(1)
+	}
+}
+
+type testInput struct{}
+
+func (*testInput) SampleConfig() string {
+	return ""
+}
+
+func (*testInput) Gather(acc telegraf.Accumulator) error {
+	acc.AddCounter(
+		"requests",
+		map[string]interface{}{"count": 42, "ratio": 0.5, "status": "ok", "up": true, "bytes": uint64(23)},
+		map[string]string{"source": "external"},
+		time.Unix(1700000000, 0),
+	)
+	acc.AddError(errors.New("partial failure"))
+	return nil
+}
+
+type testServiceInput struct {
+	testInput
+	done chan struct{}
+}
+
+func (s *testServiceInput) Start(acc telegraf.Accumulator) error {
+	s.done = make(chan struct{})
+	tacc := acc.WithTracking(1)
+	tacc.AddTrackingMetric(metric.New("tracked", nil, map[string]interface{}{"value": 1}, time.Unix(0, 0)))
+	go func() {
+		select {
+		case info := <-tacc.Delivered():
+			acc.AddFields("delivery", map[string]interface{}{"delivered": info.Delivered()}, nil, time.Unix(0, 0))
+		case <-s.done:
+		}
+	}()
+	return nil
+}
+
+func (s *testServiceInput) Stop() {
+	close(s.done)
+}
@@ -0,0 +1,299 @@
+package http_listener_v2
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+)
+
+// Only accept asymmetric signing methods to prevent tokens signed with the
+// public key as HMAC secret
+var jwtSigningMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// Minimum interval between refreshing the key set due to unknown key IDs
+const jwksMinRefreshInterval = 30 * time.Second
+
+type apiKey struct {
+	Key    config.Secret `toml:"key"`
+	Tenant string        `toml:"tenant"`
+}
+
+// principal describes the authenticated client of a request
+type principal struct {
+	tenant  string
+	subject string
+}
+
+// jwtValidator validates bearer tokens using the keys of a local file or a
+// key-set fetched from a remote location
+type jwtValidator struct {
+	url             string
+	issuer          string
+	audience        string
+	refreshInterval time.Duration
+	client          *http.Client
+	log             telegraf.Logger
+
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+	trigger     chan struct{}
+	sync.RWMutex
+}
+
+func newJWTValidator(keyFile, url, issuer, audience string, refresh time.Duration, log telegraf.Logger) (*jwtValidator, error) {
+	v := &jwtValidator{
+		url:             url,
+		issuer:          issuer,
+		audience:        audience,
+		refreshInterval: refresh,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		log:             log,
+		trigger:         make(chan struct{}, 1),
+	}
+
+	if keyFile != "" {
+		buf, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading key file failed: %w", err)
+		}
+		keys, err := parseKeys(buf)
+		if err != nil {
+			return nil, fmt.Errorf("parsing key file %q failed: %w", keyFile, err)
+		}
+		v.keys = keys
+	}
+
+	return v, nil
+}
+
+// parseKeys parses a PEM encoded public key or a JSON Web Key Set
+func parseKeys(buf []byte) (map[string]crypto.PublicKey, error) {
+	if block, _ := pem.Decode(buf); block != nil {
+		var key crypto.PublicKey
+		var err error
+		switch block.Type {
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+				key = cert.PublicKey
+			}
+		default:
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return map[string]crypto.PublicKey{"": key}, nil
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(buf, &set); err != nil {
+		return nil, fmt.Errorf("neither PEM nor JSON web key set: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if !k.IsPublic() || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		keys[k.KeyID] = k.Key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no public signing keys found")
+	}
+	return keys, nil
+}
+
+func (v *jwtValidator) start(ctx context.Context, wg *sync.WaitGroup) error {
+	if v.url == "" {
+		return nil
+	}
+	if err := v.refresh(ctx); err != nil {
+		return err
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(v.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-v.trigger:
+			}
+			if err := v.refresh(ctx); err != nil {
+				v.log.Errorf("Refreshing key set failed: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+func (v *jwtValidator) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching key set failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching key set failed with status %q", resp.Status)
+	}
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return fmt.Errorf("reading key set failed: %w", err)
+	}
+	keys, err := parseKeys(buf)
+	if err != nil {
+		return fmt.Errorf("parsing key set failed: %w", err)
+	}
+
+	v.Lock()
+	v.keys = keys
+	v.lastRefresh = time.Now()
+	v.Unlock()
+	return nil
+}
+
+func (v *jwtValidator) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	v.RLock()
+	key, found := v.keys[kid]
+	if !found && len(v.keys) == 1 {
+		// Use the only key for tokens or keys without ID
+		for id, k := range v.keys {
+			if kid == "" || id == "" {
+				key, found = k, true
+			}
+		}
+	}
+	v.RUnlock()
+	if found {
+		return key, nil
+	}
+
+	// The key might have been rotated, so refresh the key set in the background
+	// to not block the request, but limit the refresh rate as the key ID is
+	// controlled by the client
+	if v.url != "" && v.claimRefresh() {
+		select {
+		case v.trigger <- struct{}{}:
+		default:
+		}
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (v *jwtValidator) claimRefresh() bool {
+	v.Lock()
+	defer v.Unlock()
+
+	if time.Since(v.lastRefresh) < jwksMinRefreshInterval {
+		return false
+	}
+	v.lastRefresh = time.Now()
+	return true
+}
+
+// validate checks the bearer token of the request and returns the subject
+func (v *jwtValidator) validate(req *http.Request) (string, error) {
+	bearer, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return "", errors.New("missing bearer token")
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(jwtSigningMethods), jwt.WithExpirationRequired()}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+	token, err := jwt.Parse(bearer, v.key, options...)
+	if err != nil {
+		return "", err
+	}
+	return token.Claims.GetSubject()
+}
+
+// authenticate checks the request against the configured authentication
+// methods. Requests are accepted if any of the methods succeeds.
+func (h *HTTPListenerV2) authenticate(req *http.Request) (*principal, bool) {
+	basicAuth := h.BasicUsername != "" && h.BasicPassword != ""
+	if !basicAuth && h.jwt == nil && len(h.APIKeys) == 0 {
+		return &principal{}, true
+	}
+
+	if basicAuth {
+		reqUsername, reqPassword, ok := req.BasicAuth()
+		if ok &&
+			subtle.ConstantTimeCompare([]byte(reqUsername), []byte(h.BasicUsername)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(reqPassword), []byte(h.BasicPassword)) == 1 {
+			return &principal{subject: reqUsername}, true
+		}
+	}
+
+	if len(h.APIKeys) > 0 {
+		if key := req.Header.Get(h.APIKeyHeader); key != "" {
+			if tenant, found := h.lookupAPIKey(key); found {
+				return &principal{tenant: tenant}, true
+			}
+		}
+	}
+
+	if h.jwt != nil && strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") {
+		subject, err := h.jwt.validate(req)
+		if err == nil {
+			return &principal{subject: subject}, true
+		}
+		h.Log.Debugf("Invalid token from %s: %v", req.RemoteAddr, err)
+	}
+
+	return nil, false
+}
+
+func (h *HTTPListenerV2) lookupAPIKey(key string) (string, bool) {
+	var tenant string
+	var found bool
+	for _, k := range h.APIKeys {
+		secret, err := k.Key.Get()
+		if err != nil {
+			h.Log.Errorf("Getting API key for tenant %q failed: %v", k.Tenant, err)
+			continue
+		}
+		// Check all keys to not leak information via timing
+		if subtle.ConstantTimeCompare([]byte(key), secret.Bytes()) == 1 && !found {
+			tenant, found = k.Tenant, true
+		}
+		secret.Destroy()
+	}
+	return tenant, found
+}
@@ -3,12 +3,13 @@ package http_listener_v2
 
 import (
 	"compress/gzip"
-	"crypto/subtle"
+	"context"
 	"crypto/tls"
 	_ "embed"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"net/url"
@@ -62,20 +63,37 @@ type HTTPListenerV2 struct {
 	BasicPassword  string            `toml:"basic_password"`
 	HTTPHeaderTags map[string]string `toml:"http_header_tags"`
 
+	JWTKeyFile         string          `toml:"jwt_key_file"`
+	JWTJWKSURL         string          `toml:"jwt_jwks_url"`
+	JWTRefreshInterval config.Duration `toml:"jwt_jwks_refresh_interval"`
+	JWTIssuer          string          `toml:"jwt_issuer"`
+	JWTAudience        string          `toml:"jwt_audience"`
+	APIKeyHeader       string          `toml:"api_key_header"`
+	APIKeyTag          string          `toml:"api_key_tag"`
+	APIKeys            []apiKey        `toml:"api_key"`
+	RateLimit          float64         `toml:"rate_limit"`
+	RateLimitBurst     int             `toml:"rate_limit_burst"`
+
 	common_tls.ServerConfig
 	tlsConf *tls.Config
 
 	timeFunc
 	Log telegraf.Logger
 
-	wg    sync.WaitGroup
-	close chan struct{}
+	wg     sync.WaitGroup
+	close  chan struct{}
+	cancel context.CancelFunc
 
 	listener net.Listener
 	url      *url.URL
 
 	telegraf.Parser
-	acc telegraf.Accumulator
+	parserFuncs map[string]telegraf.ParserFunc
+	pathParsers map[string]telegraf.Parser
+	acc         telegraf.Accumulator
+
+	jwt     *jwtValidator
+	limiter *clientRateLimiter
 }
 
 // timeFunc provides a timestamp for the metrics
@@ -108,6 +126,57 @@ func (h *HTTPListenerV2) Init() error {
 		h.SuccessCode = http.StatusNoContent
 	}
 
+	if h.JWTKeyFile != "" && h.JWTJWKSURL != "" {
+		return errors.New("only one of 'jwt_key_file' and 'jwt_jwks_url' can be set")
+	}
+	if h.JWTKeyFile != "" || h.JWTJWKSURL != "" {
+		if h.JWTRefreshInterval <= 0 {
+			h.JWTRefreshInterval = config.Duration(time.Hour)
+		}
+		v, err := newJWTValidator(h.JWTKeyFile, h.JWTJWKSURL, h.JWTIssuer, h.JWTAudience, time.Duration(h.JWTRefreshInterval), h.Log)
+		if err != nil {
+			return err
+		}
+		h.jwt = v
+	}
+
+	if len(h.APIKeys) > 0 {
+		if h.APIKeyHeader == "" {
+			h.APIKeyHeader = "X-API-Key"
+		}
+		for i, k := range h.APIKeys {
+			if k.Key.Empty() {
+				return fmt.Errorf("empty key for API key %d", i+1)
+			}
+		}
+	}
+
+	if h.RateLimit < 0 {
+		return errors.New("'rate_limit' must not be negative")
+	}
+	if h.RateLimit > 0 {
+		if h.RateLimitBurst < 0 {
+			return errors.New("'rate_limit_burst' must not be negative")
+		}
+		if h.RateLimitBurst == 0 {
+			h.RateLimitBurst = int(math.Ceil(h.RateLimit))
+		}
+		h.limiter = newClientRateLimiter(h.RateLimit, h.RateLimitBurst)
+	}
+
+	// Create the parsers for individual paths and serve those paths
+	h.pathParsers = make(map[string]telegraf.Parser, len(h.parserFuncs))
+	for path, fn := range h.parserFuncs {
+		parser, err := fn()
+		if err != nil {
+			return fmt.Errorf("creating parser for path %q failed: %w", path, err)
+		}
+		h.pathParsers[path] = parser
+		if !choice.Contains(path, h.Paths) {
+			h.Paths = append(h.Paths, path)
+		}
+	}
+
 	return nil
 }
 
@@ -115,7 +184,24 @@ func (h *HTTPListenerV2) SetParser(parser telegraf.Parser) {
 	h.Parser = parser
 }
 
+func (*HTTPListenerV2) ParserTable() (table, key string) {
+	return "path_parser", "path"
+}
+
+func (h *HTTPListenerV2) SetParserFuncs(fns map[string]telegraf.ParserFunc) {
+	h.parserFuncs = fns
+}
+
 func (h *HTTPListenerV2) Start(acc telegraf.Accumulator) error {
+	var ctx context.Context
+	ctx, h.cancel = context.WithCancel(context.Background())
+	if h.jwt != nil {
+		if err := h.jwt.start(ctx, &h.wg); err != nil {
+			h.cancel()
+			return err
+		}
+	}
+
 	u := h.url
 	address := u.Host
 	switch u.Scheme {
@@ -195,6 +281,9 @@ func (*HTTPListenerV2) Gather(telegraf.Accumulator) error {
 }
 
 func (h *HTTPListenerV2) Stop() {
+	if h.cancel != nil {
+		h.cancel()
+	}
 	if h.listener != nil {
 		h.listener.Close()
 	}
@@ -203,17 +292,43 @@ func (h *HTTPListenerV2) Stop() {
 
 // ServeHTTP implements [http.Handler]
 func (h *HTTPListenerV2) ServeHTTP(res http.ResponseWriter, req *http.Request) {
-	handler := h.serveWrite
+	for key, value := range h.HTTPHeaders {
+		res.Header().Set(key, value)
+	}
 
-	if !choice.Contains(req.URL.Path, h.Paths) {
-		handler = http.NotFound
+	// Limit the rate before authenticating the request to protect against
+	// clients flooding the listener with invalid credentials
+	if h.limiter != nil {
+		if delay := h.limiter.allow(clientAddress(req), time.Now()); delay > 0 {
+			res.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			if err := tooManyRequests(res); err != nil {
+				h.Log.Debugf("error in too-many-requests: %v", err)
+			}
+			return
+		}
 	}
 
-	for key, value := range h.HTTPHeaders {
-		res.Header().Set(key, value)
+	client, ok := h.authenticate(req)
+	if !ok {
+		http.Error(res, "Unauthorized.", http.StatusUnauthorized)
+		return
+	}
+
+	if !choice.Contains(req.URL.Path, h.Paths) {
+		http.NotFound(res, req)
+		return
 	}
 
-	h.authenticateIfSet(handler, res, req)
+	h.serveWrite(res, req, client)
+}
+
+// clientAddress returns the address of the client used for rate limiting
+func clientAddress(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
 }
 
 func (h *HTTPListenerV2) createHTTPServer() *http.Server {
@@ -226,7 +341,7 @@ func (h *HTTPListenerV2) createHTTPServer() *http.Server {
 	}
 }
 
-func (h *HTTPListenerV2) serveWrite(res http.ResponseWriter, req *http.Request) {
+func (h *HTTPListenerV2) serveWrite(res http.ResponseWriter, req *http.Request, client *principal) {
 	select {
 	case <-h.close:
 		res.WriteHeader(http.StatusGone)
@@ -257,8 +372,17 @@ func (h *HTTPListenerV2) serveWrite(res http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	// Reject Prometheus remote write requests with unsupported protobuf
+	// messages so clients can fall back to a supported protocol version
+	rwVersion, ok := remoteWriteVersion(req.Header.Get("Content-Type"))
+	if !ok {
+		if err := unsupportedMediaType(res); err != nil {
+			h.Log.Debugf("error in unsupported-media-type: %v", err)
+		}
+		return
+	}
+
 	var bytes []byte
-	var ok bool
 
 	switch strings.ToLower(h.DataSource) {
 	case query:
@@ -271,7 +395,12 @@ func (h *HTTPListenerV2) serveWrite(res http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	metrics, err := h.Parse(bytes)
+	parser := h.Parser
+	if p, found := h.pathParsers[req.URL.Path]; found {
+		parser = p
+	}
+
+	metrics, err := parser.Parse(bytes)
 	if err != nil {
 		h.Log.Debugf("Parse error: %s", err.Error())
 		if err := badRequest(res); err != nil {
@@ -298,9 +427,22 @@ func (h *HTTPListenerV2) serveWrite(res http.ResponseWriter, req *http.Request)
 			m.AddTag(pathTag, req.URL.Path)
 		}
 
+		// Always set or remove the tenant tag to prevent clients from
+		// spoofing the tenant in the data
+		if h.APIKeyTag != "" {
+			if client.tenant != "" {
+				m.AddTag(h.APIKeyTag, client.tenant)
+			} else {
+				m.RemoveTag(h.APIKeyTag)
+			}
+		}
+
 		h.acc.AddMetric(m)
 	}
 
+	if rwVersion == "v2" {
+		setRemoteWriteStats(res, bytes)
+	}
 	res.WriteHeader(h.SuccessCode)
 }
 
@@ -390,6 +532,13 @@ func methodNotAllowed(res http.ResponseWriter) error {
 	return err
 }
 
+func tooManyRequests(res http.ResponseWriter) error {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusTooManyRequests)
+	_, err := res.Write([]byte(`{"error":"http: too many requests"}`))
+	return err
+}
+
 func badRequest(res http.ResponseWriter) error {
 	res.Header().Set("Content-Type", "application/json")
 	res.WriteHeader(http.StatusBadRequest)
@@ -397,21 +546,6 @@ func badRequest(res http.ResponseWriter) error {
 	return err
 }
 
-func (h *HTTPListenerV2) authenticateIfSet(handler http.HandlerFunc, res http.ResponseWriter, req *http.Request) {
-	if h.BasicUsername != "" && h.BasicPassword != "" {
-		reqUsername, reqPassword, ok := req.BasicAuth()
-		if !ok ||
-			subtle.ConstantTimeCompare([]byte(reqUsername), []byte(h.BasicUsername)) != 1 ||
-			subtle.ConstantTimeCompare([]byte(reqPassword), []byte(h.BasicPassword)) != 1 {
-			http.Error(res, "Unauthorized.", http.StatusUnauthorized)
-			return
-		}
-		handler(res, req)
-	} else {
-		handler(res, req)
-	}
-}
-
 func init() {
 	inputs.Add("http_listener_v2", func() telegraf.Input {
 		return &HTTPListenerV2{
@@ -3,13 +3,22 @@ package http_listener_v2
 import (
 	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	gojson "encoding/json"
+	"encoding/pem"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -17,12 +26,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang/snappy"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/stretchr/testify/require"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/plugins/parsers/form_urlencoded"
 	"github.com/influxdata/telegraf/plugins/parsers/influx"
+	"github.com/influxdata/telegraf/plugins/parsers/json"
+	"github.com/influxdata/telegraf/plugins/parsers/prometheusremotewrite"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -449,6 +464,62 @@ func TestWriteHTTPGzippedData(t *testing.T) {
 	}
 }
 
+func TestWriteRemoteWriteV2(t *testing.T) {
+	parser := &prometheusremotewrite.Parser{}
+	require.NoError(t, parser.Init())
+
+	listener, err := newTestHTTPListenerV2()
+	require.NoError(t, err)
+	listener.Parser = parser
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, listener.Init())
+	require.NoError(t, listener.Start(acc))
+	defer listener.Stop()
+
+	symbols := writev2.NewSymbolTable()
+	request := &writev2.Request{
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: []uint32{symbols.Symbolize("__name__"), symbols.Symbolize("cpu_load_short")},
+				Samples: []writev2.Sample{
+					{Value: 12, Timestamp: 1422568543702},
+					{Value: 13, Timestamp: 1422568544702},
+				},
+			},
+		},
+	}
+	request.Symbols = symbols.Symbols()
+	data, err := request.Marshal()
+	require.NoError(t, err)
+	encoded := snappy.Encode(nil, data)
+
+	// Requests with unknown protobuf messages must be rejected
+	req, err := http.NewRequest("POST", createURL(listener, "http", "/write", ""), bytes.NewBuffer(encoded))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf;proto=io.prometheus.write.v3.Request")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	req, err = http.NewRequest("POST", createURL(listener, "http", "/write", ""), bytes.NewBuffer(encoded))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf;proto=io.prometheus.write.v2.Request")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.Equal(t, "2", resp.Header.Get("X-Prometheus-Remote-Write-Samples-Written"))
+	require.Equal(t, "0", resp.Header.Get("X-Prometheus-Remote-Write-Histograms-Written"))
+	require.Equal(t, "0", resp.Header.Get("X-Prometheus-Remote-Write-Exemplars-Written"))
+
+	acc.Wait(2)
+	require.Len(t, acc.GetTelegrafMetrics(), 2)
+}
+
 // test that writing snappy data works
 func TestWriteHTTPSnappyData(t *testing.T) {
 	listener, err := newTestHTTPListenerV2()
@@ -776,3 +847,323 @@ func mustReadHugeMetric() []byte {
 
 // The term 'master_repl' used here is archaic language from redis
 var hugeMetric = mustReadHugeMetric()
+
+func TestInvalidAuthConfig(t *testing.T) {
+	listener, err := newTestHTTPListenerV2()
+	require.NoError(t, err)
+	listener.JWTKeyFile = "testdata/jwt.pem"
+	listener.JWTJWKSURL = "http://localhost/jwks.json"
+	require.ErrorContains(t, listener.Init(), "only one of 'jwt_key_file' and 'jwt_jwks_url' can be set")
+
+	listener, err = newTestHTTPListenerV2()
+	require.NoError(t, err)
+	listener.APIKeys = []apiKey{{Tenant: "team-a"}}
+	require.ErrorContains(t, listener.Init(), "empty key for API key 1")
+
+	listener, err = newTestHTTPListenerV2()
+	require.NoError(t, err)
+	listener.RateLimit = -1
+	require.ErrorContains(t, listener.Init(), "'rate_limit' must not be negative")
+}
+
+func newSignedToken(t *testing.T, key crypto.Signer, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	var method jwt.SigningMethod
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PrivateKey:
+		method = jwt.SigningMethodES256
+	}
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func postWithHeader(t *testing.T, listener *HTTPListenerV2, path, header, value, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest("POST", createURL(listener, "http", path, ""), bytes.NewBufferString(body))
+	require.NoError(t, err)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+func TestWriteHTTPJWTKeyFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	keyfile := filepath.Join(t.TempDir(), "jwt.pem")
+	require.NoError(t, os.WriteFile(keyfile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+	listener, err := newTestHTTPListenerV2()
+	require.NoError(t, err)
+	listener.JWTKeyFile = keyfile
+	listener.JWTIssuer = "issuer"
+	listener.JWTAudience = "telegraf"
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, listener.Init())
+	require.NoError(t, listener.Start(acc))
+	defer listener.Stop()
+
+	valid := jwt.MapClaims{
+		"iss": "issuer",
+		"aud": "telegraf",
+		"sub": "sensor-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	token := newSignedToken(t, key, "", valid)
+	resp := postWithHeader(t, listener, "/write", "Authorization", "Bearer "+token, testMsg)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Requests without token
+	resp = postWithHeader(t, listener, "/write", "", "", testMsg)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Expired token
+	expired := jwt.MapClaims{"iss": "issuer", "aud": "telegraf", "exp": time.Now().Add(-time.Hour).Unix()}
+	token = newSignedToken(t, key, "", expired)
+	resp = postWithHeader(t, listener, "/write", "Authorization", "Bearer "+token, testMsg)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Wrong audience
+	audience := jwt.MapClaims{"iss": "issuer", "aud": "other", "exp": time.Now().Add(time.Hour).Unix()}
+	token = newSignedToken(t, key, "", audience)
+	resp = postWithHeader(t, listener, "/write", "Authorization", "Bearer "+token, testMsg)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Token signed with a different key
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	token = newSignedToken(t, other, "", valid)
+	resp = postWithHeader(t, listener, "/write", "Authorization", "Bearer "+token, testMsg)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Token using the public key as HMAC secret
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, valid).SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	resp = postWithHeader(t, listener, "/write", "Authorization", "Bearer "+hmacToken, testMsg)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	acc.Wait(1)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+}
+
+func TestWriteHTTPJWKS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	keys := jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{Key: key.Public(), KeyID: "key-1", Algorithm: "ES256", Use: "sig"}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := gojson.NewEncoder(w).Encode(keys); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	listener, err := newTestHTTPListenerV2()
+	require.NoError(t, err)
+	listener.JWTJWKSURL = server.URL
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, listener.Init())
+	require.NoError(t, listener.Start(acc))
+	defer listener.Stop()
+
+	claims := jwt.MapClaims{"sub": "sensor-1", "exp": time.Now().Add(time.Hour).Unix()}
+	token := newSignedToken(t, key, "key-1", claims)
+	resp := postWithHeader(t, listener, "/write", "Authorization", "Bearer "+token, testMsg)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Rotated keys are fetched on demand
+	rotated, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	mu.Lock()
+	keys.Keys = append(keys.Keys, jose.JSONWebKey{Key: rotated.Public(), KeyID: "key-2", Algorithm: "ES256", Use: "sig"})
+	mu.Unlock()
+	listener.jwt.Lock()
+	listener.jwt.lastRefresh = time.Time{}
+	listener.jwt.Unlock()
+
+	// The first request triggers a refresh in the background but is rejected
+	token = newSignedToken(t, rotated, "key-2", claims)
+	resp = postWithHeader(t, listener, "/write", "Authorization", "Bearer "+token, testMsg)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Eventually(t, func() bool {
+		resp := postWithHeader(t, listener, "/write", "Authorization", "Bearer "+token, testMsg)
+		return resp.StatusCode == http.StatusNoContent
+	}, 3*time.Second, 100*time.Millisecond)
+
+	// Unknown keys are rejected
+	token = newSignedToken(t, rotated, "key-3", claims)
+	resp = postWithHeader(t, listener, "/write", "Authorization", "Bearer "+token, testMsg)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestWriteHTTPAPIKey(t *testing.T) {
+	listener, err := newTestHTTPListenerV2()
+	require.NoError(t, err)
+	listener.APIKeyTag = "tenant"
+	listener.APIKeys = []apiKey{
+		{Key: config.NewSecret([]byte("key-a")), Tenant: "team-a"},
+		{Key: config.NewSecret([]byte("key-b")), Tenant: "team-b"},
+	}
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, listener.Init())
+	require.NoError(t, listener.Start(acc))
+	defer listener.Stop()
+
+	resp := postWithHeader(t, listener, "/write", "X-API-Key", "key-b", testMsg)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = postWithHeader(t, listener, "/write", "X-API-Key", "key-c", testMsg)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	acc.Wait(1)
+	acc.AssertContainsTaggedFields(t, "cpu_load_short",
+		map[string]interface{}{"value": float64(12)},
+		map[string]string{"host": "server01", "tenant": "team-b"},
+	)
+}
+
+func TestWriteHTTPAPIKeySpoofedTag(t *testing.T) {
+	listener, err := newTestHTTPListenerV2()
+	require.NoError(t, err)
+	listener.BasicUsername = "test"
+	listener.BasicPassword = "secret"
+	listener.APIKeyTag = "tenant"
+	listener.APIKeys = []apiKey{
+		{Key: config.NewSecret([]byte("key-b")), Tenant: "team-b"},
+	}
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, listener.Init())
+	require.NoError(t, listener.Start(acc))
+	defer listener.Stop()
+
+	// The tenant of the key must overwrite the tag sent by the client
+	msg := "cpu_load_short,host=server01,tenant=team-a value=12.0 1422568543702900257\n"
+	resp := postWithHeader(t, listener, "/write", "X-API-Key", "key-b", msg)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Clients authenticated without tenant must not be able to set the tag
+	req, err := http.NewRequest(http.MethodPost, createURL(listener, "http", "/write", ""), bytes.NewBufferString(msg))
+	require.NoError(t, err)
+	req.SetBasicAuth("test", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	acc.Wait(2)
+	acc.AssertContainsTaggedFields(t, "cpu_load_short",
+		map[string]interface{}{"value": float64(12)},
+		map[string]string{"host": "server01", "tenant": "team-b"},
+	)
+	acc.AssertContainsTaggedFields(t, "cpu_load_short",
+		map[string]interface{}{"value": float64(12)},
+		map[string]string{"host": "server01"},
+	)
+	acc.AssertDoesNotContainsTaggedFields(t, "cpu_load_short",
+		map[string]interface{}{"value": float64(12)},
+		map[string]string{"host": "server01", "tenant": "team-a"},
+	)
+}
+
+func TestWriteHTTPRateLimit(t *testing.T) {
+	listener, err := newTestHTTPListenerV2()
+	require.NoError(t, err)
+	listener.RateLimit = 0.001
+	listener.RateLimitBurst = 2
+	listener.APIKeys = []apiKey{
+		{Key: config.NewSecret([]byte("key-a")), Tenant: "team-a"},
+		{Key: config.NewSecret([]byte("key-b")), Tenant: "team-b"},
+	}
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, listener.Init())
+	require.NoError(t, listener.Start(acc))
+	defer listener.Stop()
+
+	for range 2 {
+		resp := postWithHeader(t, listener, "/write", "X-API-Key", "key-a", testMsg)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+	resp := postWithHeader(t, listener, "/write", "X-API-Key", "key-a", testMsg)
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.NotEmpty(t, resp.Header.Get("Retry-After"))
+
+	// The limit applies before authentication
+	resp = postWithHeader(t, listener, "/write", "X-API-Key", "key-c", testMsg)
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	// Other clients are limited independently
+	req := httptest.NewRequest(http.MethodPost, "/write", bytes.NewBufferString(testMsg))
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("X-API-Key", "key-b")
+	rec := httptest.NewRecorder()
+	listener.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestWriteHTTPPathParsers(t *testing.T) {
+	listener, err := newTestHTTPListenerV2()
+	require.NoError(t, err)
+	listener.PathTag = true
+	listener.SetParserFuncs(map[string]telegraf.ParserFunc{
+		"/json": func() (telegraf.Parser, error) {
+			parser := &json.Parser{MetricName: "json_metric"}
+			err := parser.Init()
+			return parser, err
+		},
+	})
+
+	acc := &testutil.Accumulator{}
+	require.NoError(t, listener.Init())
+	require.NoError(t, listener.Start(acc))
+	defer listener.Stop()
+
+	resp, err := http.Post(createURL(listener, "http", "/write", ""), "", bytes.NewBufferString(testMsg))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, err = http.Post(createURL(listener, "http", "/json", ""), "", bytes.NewBufferString(`{"value": 42}`))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Line-protocol is rejected by the JSON parser
+	resp, err = http.Post(createURL(listener, "http", "/json", ""), "", bytes.NewBufferString(testMsg))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	acc.Wait(2)
+	acc.AssertContainsTaggedFields(t, "cpu_load_short",
+		map[string]interface{}{"value": float64(12)},
+		map[string]string{"host": "server01", "http_listener_v2_path": "/write"},
+	)
+	acc.AssertContainsTaggedFields(t, "json_metric",
+		map[string]interface{}{"value": float64(42)},
+		map[string]string{"http_listener_v2_path": "/json"},
+	)
+}
@@ -0,0 +1,65 @@
+package http_listener_v2
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiters of clients not seen for this duration are removed
+const clientLimiterExpiry = 10 * time.Minute
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// clientRateLimiter implements a token-bucket rate limit per client
+type clientRateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	clients   map[string]*clientLimiter
+	lastPurge time.Time
+	sync.Mutex
+}
+
+func newClientRateLimiter(limit float64, burst int) *clientRateLimiter {
+	return &clientRateLimiter{
+		limit:     rate.Limit(limit),
+		burst:     burst,
+		clients:   make(map[string]*clientLimiter),
+		lastPurge: time.Now(),
+	}
+}
+
+// allow returns zero if a request of the client is allowed or the duration
+// to wait for the next request to be accepted otherwise
+func (r *clientRateLimiter) allow(client string, now time.Time) time.Duration {
+	r.Lock()
+	defer r.Unlock()
+
+	if now.Sub(r.lastPurge) > clientLimiterExpiry {
+		for k, c := range r.clients {
+			if now.Sub(c.lastSeen) > clientLimiterExpiry {
+				delete(r.clients, k)
+			}
+		}
+		r.lastPurge = now
+	}
+
+	c, found := r.clients[client]
+	if !found {
+		c = &clientLimiter{limiter: rate.NewLimiter(r.limit, r.burst)}
+		r.clients[client] = c
+	}
+	c.lastSeen = now
+
+	reservation := c.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package http_listener_v2
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	remoteWriteProtoV1 = "prometheus.WriteRequest"
+	remoteWriteProtoV2 = "io.prometheus.write.v2.Request"
+)
+
+// remoteWriteVersion determines the Prometheus remote write protocol version
+// from the content type of the request. An empty version is returned for
+// non-protobuf content and false if the requested protobuf message is not
+// supported.
+func remoteWriteVersion(contentType string) (string, bool) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "application/x-protobuf" {
+		return "", true
+	}
+
+	switch params["proto"] {
+	case "", remoteWriteProtoV1:
+		return "v1", true
+	case remoteWriteProtoV2:
+		return "v2", true
+	}
+	return "", false
+}
+
+// setRemoteWriteStats sets the response headers containing the number of
+// samples, histograms and exemplars written as required by remote write 2.0
+func setRemoteWriteStats(res http.ResponseWriter, buf []byte) {
+	var samples, histograms, exemplars int
+
+	// Iterate over the time series (field 5) of the request and count the
+	// samples (field 2), histograms (field 3) and exemplars (field 4)
+	for len(buf) > 0 {
+		num, typ, n := protowire.ConsumeTag(buf)
+		if n < 0 {
+			break
+		}
+		buf = buf[n:]
+		if num != 5 || typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, buf); n < 0 {
+				break
+			}
+			buf = buf[n:]
+			continue
+		}
+		series, n := protowire.ConsumeBytes(buf)
+		if n < 0 {
+			break
+		}
+		buf = buf[n:]
+
+		for len(series) > 0 {
+			num, typ, n := protowire.ConsumeTag(series)
+			if n < 0 {
+				break
+			}
+			series = series[n:]
+			switch num {
+			case 2:
+				samples++
+			case 3:
+				histograms++
+			case 4:
+				exemplars++
+			}
+			if n = protowire.ConsumeFieldValue(num, typ, series); n < 0 {
+				break
+			}
+			series = series[n:]
+		}
+	}
+
+	res.Header().Set("X-Prometheus-Remote-Write-Samples-Written", strconv.Itoa(samples))
+	res.Header().Set("X-Prometheus-Remote-Write-Histograms-Written", strconv.Itoa(histograms))
+	res.Header().Set("X-Prometheus-Remote-Write-Exemplars-Written", strconv.Itoa(exemplars))
+}
+
+func unsupportedMediaType(res http.ResponseWriter) error {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusUnsupportedMediaType)
+	_, err := res.Write([]byte(`{"error":"http: unsupported media type"}`))
+	return err
+}
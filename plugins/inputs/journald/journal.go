@@ -0,0 +1,375 @@
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Implementation of a reader for the journal file format as documented in
+// https://systemd.io/JOURNAL_FILE_FORMAT/
+
+const (
+	headerSignature = "LPKSHHRH"
+
+	// Incompatible header flags
+	incompatibleCompressedXZ   = 1 << 0
+	incompatibleCompressedLZ4  = 1 << 1
+	incompatibleKeyedHash      = 1 << 2
+	incompatibleCompressedZSTD = 1 << 3
+	incompatibleCompact        = 1 << 4
+	incompatibleSupported      = incompatibleCompressedXZ | incompatibleCompressedLZ4 | incompatibleKeyedHash |
+		incompatibleCompressedZSTD | incompatibleCompact
+
+	// Object types
+	objectData       = 1
+	objectEntry      = 3
+	objectEntryArray = 6
+
+	// Object compression flags
+	objectCompressedXZ   = 1 << 0
+	objectCompressedLZ4  = 1 << 1
+	objectCompressedZSTD = 1 << 2
+
+	objectHeaderSize = 16
+	headerMinSize    = 208
+
+	// Limit the size of objects to protect against corrupted files
+	maxObjectSize = 64 * 1024 * 1024
+)
+
+var zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+
+type id128 [16]byte
+
+func (id id128) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+type journalFile struct {
+	path             string
+	file             *os.File
+	compact          bool
+	fileID           id128
+	seqnumID         id128
+	entryArrayOffset uint64
+
+	// Number of entries and header of the last entry as recorded in the
+	// file header
+	entries uint64
+	tail    entryHeader
+}
+
+type entryHeader struct {
+	seqnum    uint64
+	realtime  uint64
+	monotonic uint64
+	bootID    id128
+	xorHash   uint64
+}
+
+type entry struct {
+	entryHeader
+	seqnumID id128
+	fields   map[string]string
+}
+
+func openJournalFile(path string) (*journalFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, headerMinSize)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("reading header failed: %w", err)
+	}
+	if string(header[0:8]) != headerSignature {
+		f.Close()
+		return nil, errors.New("invalid file signature")
+	}
+	incompatible := binary.LittleEndian.Uint32(header[12:16])
+	if incompatible&^incompatibleSupported != 0 {
+		f.Close()
+		return nil, fmt.Errorf("unsupported incompatible flags 0x%x", incompatible)
+	}
+
+	j := &journalFile{
+		path:             path,
+		file:             f,
+		compact:          incompatible&incompatibleCompact != 0,
+		entryArrayOffset: binary.LittleEndian.Uint64(header[176:184]),
+		entries:          binary.LittleEndian.Uint64(header[152:160]),
+		tail: entryHeader{
+			seqnum:   binary.LittleEndian.Uint64(header[160:168]),
+			realtime: binary.LittleEndian.Uint64(header[192:200]),
+		},
+	}
+	copy(j.fileID[:], header[24:40])
+	copy(j.seqnumID[:], header[72:88])
+	return j, nil
+}
+
+func (j *journalFile) Close() error {
+	return j.file.Close()
+}
+
+// readObject reads the object at the given offset and returns its payload
+// after checking the object type
+func (j *journalFile) readObject(offset uint64, objectType uint8) (uint8, []byte, error) {
+	if offset == 0 || offset%8 != 0 {
+		return 0, nil, fmt.Errorf("invalid object offset %d", offset)
+	}
+
+	var header [objectHeaderSize]byte
+	if _, err := j.file.ReadAt(header[:], int64(offset)); err != nil {
+		return 0, nil, fmt.Errorf("reading object header at %d failed: %w", offset, err)
+	}
+	if header[0] != objectType {
+		return 0, nil, fmt.Errorf("unexpected object type %d at %d, expected %d", header[0], offset, objectType)
+	}
+	size := binary.LittleEndian.Uint64(header[8:16])
+	if size < objectHeaderSize || size > maxObjectSize {
+		return 0, nil, fmt.Errorf("invalid object size %d at %d", size, offset)
+	}
+
+	payload := make([]byte, size-objectHeaderSize)
+	if _, err := j.file.ReadAt(payload, int64(offset)+objectHeaderSize); err != nil {
+		return 0, nil, fmt.Errorf("reading object at %d failed: %w", offset, err)
+	}
+	return header[1], payload, nil
+}
+
+// entryOffsets returns the offsets of all entries in the file in sequence
+// by walking the chain of entry arrays
+func (j *journalFile) entryOffsets() ([]uint64, error) {
+	itemSize := 8
+	if j.compact {
+		itemSize = 4
+	}
+
+	var offsets []uint64
+	visited := make(map[uint64]bool)
+	for next := j.entryArrayOffset; next != 0; {
+		if visited[next] {
+			return offsets, fmt.Errorf("loop in entry array chain at %d", next)
+		}
+		visited[next] = true
+
+		_, payload, err := j.readObject(next, objectEntryArray)
+		if err != nil {
+			return offsets, err
+		}
+		if len(payload) < 8 {
+			return offsets, fmt.Errorf("entry array at %d too short", next)
+		}
+		next = binary.LittleEndian.Uint64(payload[0:8])
+		for items := payload[8:]; len(items) >= itemSize; items = items[itemSize:] {
+			var offset uint64
+			if j.compact {
+				offset = uint64(binary.LittleEndian.Uint32(items))
+			} else {
+				offset = binary.LittleEndian.Uint64(items)
+			}
+			// Unused items at the end of the last array are zero
+			if offset == 0 {
+				return offsets, nil
+			}
+			offsets = append(offsets, offset)
+		}
+	}
+	return offsets, nil
+}
+
+func (j *journalFile) readEntryHeader(offset uint64) (*entryHeader, []byte, error) {
+	_, payload, err := j.readObject(offset, objectEntry)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(payload) < 48 {
+		return nil, nil, fmt.Errorf("entry at %d too short", offset)
+	}
+	h := &entryHeader{
+		seqnum:    binary.LittleEndian.Uint64(payload[0:8]),
+		realtime:  binary.LittleEndian.Uint64(payload[8:16]),
+		monotonic: binary.LittleEndian.Uint64(payload[16:24]),
+		xorHash:   binary.LittleEndian.Uint64(payload[40:48]),
+	}
+	copy(h.bootID[:], payload[24:40])
+	return h, payload[48:], nil
+}
+
+// readEntry reads the entry at the given offset including all data fields.
+// Fields occurring multiple times in the entry keep their first value.
+func (j *journalFile) readEntry(offset uint64) (*entry, error) {
+	h, items, err := j.readEntryHeader(offset)
+	if err != nil {
+		return nil, err
+	}
+
+	itemSize := 16
+	if j.compact {
+		itemSize = 4
+	}
+
+	e := &entry{
+		entryHeader: *h,
+		seqnumID:    j.seqnumID,
+		fields:      make(map[string]string, len(items)/itemSize),
+	}
+	for ; len(items) >= itemSize; items = items[itemSize:] {
+		var dataOffset uint64
+		if j.compact {
+			dataOffset = uint64(binary.LittleEndian.Uint32(items))
+		} else {
+			dataOffset = binary.LittleEndian.Uint64(items)
+		}
+		data, err := j.readData(dataOffset)
+		if err != nil {
+			return nil, fmt.Errorf("reading data of entry %d failed: %w", h.seqnum, err)
+		}
+		name, value, found := bytes.Cut(data, []byte("="))
+		if !found {
+			return nil, fmt.Errorf("invalid data at %d", dataOffset)
+		}
+		if _, exists := e.fields[string(name)]; !exists {
+			e.fields[string(name)] = string(value)
+		}
+	}
+	return e, nil
+}
+
+func (j *journalFile) readData(offset uint64) ([]byte, error) {
+	flags, payload, err := j.readObject(offset, objectData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip the hash-table and entry references
+	start := 48
+	if j.compact {
+		start = 56
+	}
+	if len(payload) < start {
+		return nil, fmt.Errorf("data at %d too short", offset)
+	}
+	data := payload[start:]
+
+	switch {
+	case flags&objectCompressedZSTD != 0:
+		return zstdDecoder.DecodeAll(data, nil)
+	case flags&objectCompressedLZ4 != 0:
+		// The compressed block is prefixed by the uncompressed size
+		if len(data) < 8 {
+			return nil, fmt.Errorf("compressed data at %d too short", offset)
+		}
+		size := binary.LittleEndian.Uint64(data[0:8])
+		if size > maxObjectSize {
+			return nil, fmt.Errorf("invalid uncompressed size %d at %d", size, offset)
+		}
+		buf := make([]byte, size)
+		n, err := lz4.UncompressBlock(data[8:], buf)
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case flags&objectCompressedXZ != 0:
+		return nil, errors.New("XZ compression is not supported")
+	}
+	return data, nil
+}
+
+// cursor identifies an entry in the journal in the same format as used by
+// journalctl, e.g. for the "--after-cursor" option
+type cursor struct {
+	seqnumID  id128
+	seqnum    uint64
+	bootID    id128
+	monotonic uint64
+	realtime  uint64
+	xorHash   uint64
+}
+
+func newCursor(e *entry) *cursor {
+	return &cursor{
+		seqnumID:  e.seqnumID,
+		seqnum:    e.seqnum,
+		bootID:    e.bootID,
+		monotonic: e.monotonic,
+		realtime:  e.realtime,
+		xorHash:   e.xorHash,
+	}
+}
+
+func parseCursor(s string) (*cursor, error) {
+	var c cursor
+	var found int
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid cursor part %q", part)
+		}
+		var err error
+		switch key {
+		case "s":
+			err = parseID128(value, &c.seqnumID)
+		case "i":
+			c.seqnum, err = strconv.ParseUint(value, 16, 64)
+		case "b":
+			err = parseID128(value, &c.bootID)
+		case "m":
+			c.monotonic, err = strconv.ParseUint(value, 16, 64)
+		case "t":
+			c.realtime, err = strconv.ParseUint(value, 16, 64)
+		case "x":
+			c.xorHash, err = strconv.ParseUint(value, 16, 64)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor value for %q: %w", key, err)
+		}
+		found++
+	}
+	if found != 6 {
+		return nil, fmt.Errorf("incomplete cursor %q", s)
+	}
+	return &c, nil
+}
+
+func parseID128(s string, id *id128) error {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(buf) != len(id) {
+		return io.ErrUnexpectedEOF
+	}
+	copy(id[:], buf)
+	return nil
+}
+
+func (c *cursor) String() string {
+	return fmt.Sprintf("s=%s;i=%x;b=%s;m=%x;t=%x;x=%x", c.seqnumID, c.seqnum, c.bootID, c.monotonic, c.realtime, c.xorHash)
+}
+
+// after returns true if the entry with the given header and sequence-number
+// ID is located after the cursor position. Sequence numbers are only
+// comparable for the same sequence-number ID so the realtime is used
+// otherwise.
+func (c *cursor) after(seqnumID id128, h *entryHeader) bool {
+	if c.seqnumID == seqnumID {
+		return h.seqnum > c.seqnum
+	}
+	return h.realtime > c.realtime
+}
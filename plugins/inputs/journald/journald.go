@@ -0,0 +1,492 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package journald
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+var priorities = map[string]int{
+	"emerg":   0,
+	"alert":   1,
+	"crit":    2,
+	"err":     3,
+	"warning": 4,
+	"notice":  5,
+	"info":    6,
+	"debug":   7,
+}
+
+type Journald struct {
+	Directory         string          `toml:"directory"`
+	InitialReadOffset string          `toml:"initial_read_offset"`
+	Units             []string        `toml:"units"`
+	Priority          string          `toml:"priority"`
+	Matches           []string        `toml:"matches"`
+	Tags              []string        `toml:"tags"`
+	Fields            []string        `toml:"fields"`
+	BatchSize         int             `toml:"batch_size"`
+	Log               telegraf.Logger `toml:"-"`
+
+	unitFilter  filter.Filter
+	maxPriority int
+	matches     map[string][]string
+	tagFilter   filter.Filter
+	fieldFilter filter.Filter
+
+	acc    telegraf.TrackingAccumulator
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// Cursor of the last entry read and cursor of the last entry delivered
+	// which is the persisted state of the plugin
+	cursor     *cursor
+	delivered  *cursor
+	positioned bool
+
+	// Batch of entries waiting for delivery and the cursor of its last entry
+	pending       telegraf.TrackingID
+	pendingCursor *cursor
+
+	// Entry offsets of the journal files to avoid walking the entry arrays
+	// of unchanged files on every gather cycle
+	offsets map[string]*fileOffsets
+	sync.Mutex
+}
+
+// fileOffsets holds the entry offsets of a journal file. As journal files
+// are written via memory-mapping and preallocated, neither size nor
+// modification time reliably reflect new entries, so the file ID and the
+// number of entries recorded in the file header are used to detect changes.
+type fileOffsets struct {
+	fileID  id128
+	entries uint64
+	offsets []uint64
+}
+
+func (*Journald) SampleConfig() string {
+	return sampleConfig
+}
+
+func (j *Journald) Init() error {
+	if j.Directory == "" {
+		j.Directory = "/var/log/journal"
+	}
+
+	switch j.InitialReadOffset {
+	case "":
+		j.InitialReadOffset = "saved-or-end"
+	case "beginning", "end", "saved-or-beginning", "saved-or-end":
+	default:
+		return fmt.Errorf("invalid 'initial_read_offset' setting %q", j.InitialReadOffset)
+	}
+
+	j.maxPriority = -1
+	if j.Priority != "" {
+		p, found := priorities[j.Priority]
+		if !found {
+			v, err := strconv.Atoi(j.Priority)
+			if err != nil || v < 0 || v > 7 {
+				return fmt.Errorf("invalid priority %q", j.Priority)
+			}
+			p = v
+		}
+		j.maxPriority = p
+	}
+
+	j.matches = make(map[string][]string, len(j.Matches))
+	for _, m := range j.Matches {
+		field, value, found := strings.Cut(m, "=")
+		if !found || field == "" {
+			return fmt.Errorf("invalid match %q, expected 'FIELD=value'", m)
+		}
+		j.matches[field] = append(j.matches[field], value)
+	}
+
+	if j.BatchSize <= 0 {
+		return fmt.Errorf("invalid batch size %d", j.BatchSize)
+	}
+
+	j.offsets = make(map[string]*fileOffsets)
+
+	var err error
+	if len(j.Units) > 0 {
+		if j.unitFilter, err = filter.Compile(j.Units); err != nil {
+			return fmt.Errorf("creating unit filter failed: %w", err)
+		}
+	}
+	if j.tagFilter, err = filter.Compile(j.Tags); err != nil {
+		return fmt.Errorf("creating tag filter failed: %w", err)
+	}
+	if j.fieldFilter, err = filter.Compile(j.Fields); err != nil {
+		return fmt.Errorf("creating field filter failed: %w", err)
+	}
+
+	return nil
+}
+
+func (j *Journald) GetState() interface{} {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.delivered == nil {
+		return ""
+	}
+	return j.delivered.String()
+}
+
+func (j *Journald) SetState(state interface{}) error {
+	s, ok := state.(string)
+	if !ok {
+		return fmt.Errorf("state has wrong type %T", state)
+	}
+	if s == "" {
+		return nil
+	}
+
+	c, err := parseCursor(s)
+	if err != nil {
+		return err
+	}
+
+	j.Lock()
+	defer j.Unlock()
+	if j.InitialReadOffset == "saved-or-end" || j.InitialReadOffset == "saved-or-beginning" {
+		j.cursor = c
+		j.delivered = c
+		j.positioned = true
+	}
+	return nil
+}
+
+func (j *Journald) Start(acc telegraf.Accumulator) error {
+	// Only a single batch is undelivered at any time
+	j.acc = acc.WithTracking(1)
+
+	var ctx context.Context
+	ctx, j.cancel = context.WithCancel(context.Background())
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case info := <-j.acc.Delivered():
+				j.onDelivery(info)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (j *Journald) Stop() {
+	if j.cancel != nil {
+		j.cancel()
+		j.wg.Wait()
+	}
+}
+
+// onDelivery advances the persisted cursor to the delivered batch. Batches
+// not delivered are read again starting at the persisted cursor.
+func (j *Journald) onDelivery(info telegraf.DeliveryInfo) {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.pendingCursor == nil || info.ID() != j.pending {
+		return
+	}
+	if info.Delivered() {
+		j.delivered = j.pendingCursor
+	} else {
+		j.Log.Debug("Batch not delivered, reading entries again")
+		j.cursor = j.delivered
+	}
+	j.pendingCursor = nil
+}
+
+func (j *Journald) Gather(acc telegraf.Accumulator) error {
+	j.Lock()
+	defer j.Unlock()
+
+	// Wait for the previous batch to be delivered
+	if j.pendingCursor != nil {
+		return nil
+	}
+
+	files, err := j.journalFiles()
+	if err != nil {
+		return err
+	}
+
+	// Position the cursor at the end of the journal on first run
+	if !j.positioned {
+		j.positioned = true
+		if j.InitialReadOffset == "end" || j.InitialReadOffset == "saved-or-end" {
+			for _, fn := range files {
+				e, err := lastEntry(fn)
+				if err != nil {
+					acc.AddError(fmt.Errorf("reading journal file %q failed: %w", fn, err))
+					continue
+				}
+				if e != nil && (j.cursor == nil || j.cursor.realtime < e.realtime) {
+					j.cursor = newCursor(e)
+				}
+			}
+			j.delivered = j.cursor
+			return nil
+		}
+	}
+
+	// Read the next entries after the cursor from all files and sort them to
+	// interleave the entries of the different files. As the entries are
+	// ordered within each file, reading a batch from each file is sufficient.
+	// Forget the offsets of files no longer existing, e.g. due to vacuuming
+	for fn := range j.offsets {
+		if !slices.Contains(files, fn) {
+			delete(j.offsets, fn)
+		}
+	}
+
+	var entries []*entry
+	for _, fn := range files {
+		fileEntries, err := j.readFile(fn, j.BatchSize)
+		if err != nil {
+			acc.AddError(fmt.Errorf("reading journal file %q failed: %w", fn, err))
+		}
+		entries = append(entries, fileEntries...)
+	}
+	sort.SliceStable(entries, func(a, b int) bool {
+		if entries[a].realtime != entries[b].realtime {
+			return entries[a].realtime < entries[b].realtime
+		}
+		return entries[a].seqnum < entries[b].seqnum
+	})
+	if len(entries) > j.BatchSize {
+		entries = entries[:j.BatchSize]
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	metrics := make([]telegraf.Metric, 0, len(entries))
+	for _, e := range entries {
+		// Unreadable entries have no fields and only advance the cursor
+		if e.fields == nil || !j.match(e) {
+			continue
+		}
+		if m := j.createMetric(e); m != nil {
+			metrics = append(metrics, m)
+		}
+	}
+	j.cursor = newCursor(entries[len(entries)-1])
+
+	// Batches without metrics are considered delivered
+	if len(metrics) == 0 {
+		j.delivered = j.cursor
+		return nil
+	}
+	j.pendingCursor = j.cursor
+	j.pending = j.acc.AddTrackingMetricGroup(metrics)
+
+	return nil
+}
+
+// journalFiles returns the journal files in the directory and its direct
+// sub-directories, e.g. the machine-ID directories of the default location
+func (j *Journald) journalFiles() ([]string, error) {
+	var files []string
+	for _, pattern := range []string{"*.journal", "*.journal~", "*/*.journal", "*/*.journal~"} {
+		matches, err := filepath.Glob(filepath.Join(j.Directory, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		if _, err := os.Stat(j.Directory); err != nil {
+			return nil, fmt.Errorf("accessing journal directory failed: %w", err)
+		}
+	}
+	return files, nil
+}
+
+// readFile returns up to limit entries of the given file after the cursor
+func (j *Journald) readFile(fn string, limit int) ([]*entry, error) {
+	f, err := openJournalFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Skip files without entries after the cursor, e.g. archived files,
+	// without reading the entry arrays
+	if f.entries == 0 || (j.cursor != nil && !j.cursor.after(f.seqnumID, &f.tail)) {
+		return nil, nil
+	}
+
+	offsets, err := j.entryOffsets(f)
+	if err != nil && len(offsets) == 0 {
+		return nil, err
+	}
+
+	// Entries are ordered within a file, so search for the first entry
+	// after the cursor
+	var start int
+	if j.cursor != nil {
+		var searchErr error
+		start = sort.Search(len(offsets), func(i int) bool {
+			h, _, err := f.readEntryHeader(offsets[i])
+			if err != nil {
+				searchErr = err
+				return true
+			}
+			return j.cursor.after(f.seqnumID, h)
+		})
+		if searchErr != nil {
+			return nil, searchErr
+		}
+	}
+
+	end := min(start+limit, len(offsets))
+	entries := make([]*entry, 0, end-start)
+	for _, offset := range offsets[start:end] {
+		e, err := f.readEntry(offset)
+		if err != nil {
+			// Skip unreadable entries, e.g. using unsupported compression,
+			// but keep their header to move the cursor past them
+			h, _, herr := f.readEntryHeader(offset)
+			if herr != nil {
+				j.Log.Errorf("Skipping unreadable entry at %d in %q: %v", offset, fn, err)
+				continue
+			}
+			j.Log.Errorf("Skipping unreadable entry %d in %q: %v", h.seqnum, fn, err)
+			e = &entry{entryHeader: *h, seqnumID: f.seqnumID}
+		}
+		entries = append(entries, e)
+	}
+	return entries, err
+}
+
+// entryOffsets returns the offsets of all entries of the file reusing the
+// offsets of previous reads if the file did not change
+func (j *Journald) entryOffsets(f *journalFile) ([]uint64, error) {
+	if cached, found := j.offsets[f.path]; found && cached.fileID == f.fileID && cached.entries == f.entries {
+		return cached.offsets, nil
+	}
+
+	offsets, err := f.entryOffsets()
+	if err != nil {
+		delete(j.offsets, f.path)
+		return offsets, err
+	}
+	j.offsets[f.path] = &fileOffsets{
+		fileID:  f.fileID,
+		entries: f.entries,
+		offsets: offsets,
+	}
+	return offsets, nil
+}
+
+// lastEntry returns the last entry of the given file or nil if the file
+// does not contain any entries
+func lastEntry(fn string) (*entry, error) {
+	f, err := openJournalFile(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	offsets, err := f.entryOffsets()
+	if len(offsets) == 0 {
+		return nil, err
+	}
+	h, _, err := f.readEntryHeader(offsets[len(offsets)-1])
+	if err != nil {
+		return nil, err
+	}
+	return &entry{entryHeader: *h, seqnumID: f.seqnumID}, nil
+}
+
+func (j *Journald) match(e *entry) bool {
+	if j.unitFilter != nil && !j.unitFilter.Match(e.fields["_SYSTEMD_UNIT"]) && !j.unitFilter.Match(e.fields["UNIT"]) {
+		return false
+	}
+
+	if j.maxPriority >= 0 {
+		p, err := strconv.Atoi(e.fields["PRIORITY"])
+		if err != nil || p > j.maxPriority {
+			return false
+		}
+	}
+
+	// Matches of the same field are alternatives while matches of
+	// different fields all have to be satisfied
+	for field, values := range j.matches {
+		value, found := e.fields[field]
+		if !found {
+			return false
+		}
+		var matched bool
+		for _, v := range values {
+			if v == value {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (j *Journald) createMetric(e *entry) telegraf.Metric {
+	tags := make(map[string]string)
+	fields := make(map[string]interface{})
+	for name, value := range e.fields {
+		if name == "MESSAGE" {
+			fields["message"] = value
+			continue
+		}
+		if j.tagFilter != nil && j.tagFilter.Match(name) {
+			tags[name] = value
+		} else if j.fieldFilter != nil && j.fieldFilter.Match(name) {
+			fields[name] = value
+		}
+	}
+	if len(fields) == 0 {
+		j.Log.Debugf("Skipping entry %d without fields", e.seqnum)
+		return nil
+	}
+
+	return metric.New("journald", tags, fields, time.UnixMicro(int64(e.realtime)))
+}
+
+func init() {
+	inputs.Add("journald", func() telegraf.Input {
+		return &Journald{
+			Tags:      []string{"_HOSTNAME", "_SYSTEMD_UNIT", "SYSLOG_IDENTIFIER", "PRIORITY"},
+			BatchSize: 1000,
+		}
+	})
+}
@@ -0,0 +1,405 @@
+package journald
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+// The test files were written by systemd-journald v252 with and without
+// compact mode. All fields of the entries are compressed using zstd if
+// exceeding the compression threshold.
+var expectedCompact = []telegraf.Metric{
+	metric.New(
+		"journald",
+		map[string]string{"_HOSTNAME": "vm", "SYSLOG_IDENTIFIER": "systemd-logind", "PRIORITY": "6"},
+		map[string]interface{}{"message": "Started Session 1 of user root."},
+		time.UnixMicro(1792365492324850),
+	),
+	metric.New(
+		"journald",
+		map[string]string{"_HOSTNAME": "vm", "SYSLOG_IDENTIFIER": "sshd", "PRIORITY": "6"},
+		map[string]interface{}{"message": "Accepted publickey for root from 192.0.2.10 port 52114"},
+		time.UnixMicro(1792365492380921),
+	),
+	metric.New(
+		"journald",
+		map[string]string{"_HOSTNAME": "vm", "SYSLOG_IDENTIFIER": "sshd", "PRIORITY": "4"},
+		map[string]interface{}{"message": "Failed password for invalid user admin from 192.0.2.20 port 40022"},
+		time.UnixMicro(1792365492431188),
+	),
+	metric.New(
+		"journald",
+		map[string]string{"_HOSTNAME": "vm", "SYSLOG_IDENTIFIER": "kernel", "PRIORITY": "2"},
+		map[string]interface{}{"message": "Out of memory: Killed process 4242 (stress)"},
+		time.UnixMicro(1792365492989384),
+	),
+	metric.New(
+		"journald",
+		map[string]string{"_HOSTNAME": "vm", "SYSLOG_IDENTIFIER": "myapp", "PRIORITY": "7"},
+		map[string]interface{}{"message": "multi-line\nmessage", "REQUEST_ID": "abc123"},
+		time.UnixMicro(1792365493040576),
+	),
+	metric.New(
+		"journald",
+		map[string]string{"_HOSTNAME": "vm", "SYSLOG_IDENTIFIER": "myapp", "PRIORITY": "5"},
+		map[string]interface{}{"message": "payload " + strings.Repeat("x", 2000)},
+		time.UnixMicro(1792365493091258),
+	),
+}
+
+func TestInitInvalid(t *testing.T) {
+	plugin := &Journald{InitialReadOffset: "middle", BatchSize: 1000}
+	require.ErrorContains(t, plugin.Init(), "invalid 'initial_read_offset' setting")
+
+	plugin = &Journald{Priority: "severe", BatchSize: 1000}
+	require.ErrorContains(t, plugin.Init(), `invalid priority "severe"`)
+
+	plugin = &Journald{Matches: []string{"_SYSTEMD_UNIT"}, BatchSize: 1000}
+	require.ErrorContains(t, plugin.Init(), `invalid match "_SYSTEMD_UNIT"`)
+
+	plugin = &Journald{}
+	require.ErrorContains(t, plugin.Init(), "invalid batch size 0")
+}
+
+func TestGather(t *testing.T) {
+	tests := []struct {
+		name      string
+		directory string
+		cursor    string
+	}{
+		{
+			name:      "compact",
+			directory: "testdata/compact",
+			cursor:    "s=ca0c5d724fe64bda86a7c34955af9e25;i=9;b=002b929fa1bc48cfa1ec538d8bbecdcb;m=edf8d4a5;t=65e259c85fbba;x=c685673c91adafec",
+		},
+		{
+			name:      "regular",
+			directory: "testdata/regular",
+			cursor:    "s=25f1af1870e34a04b9ee525380d49263;i=9;b=002b929fa1bc48cfa1ec538d8bbecdcb;m=ee25bfe6;t=65e259cb2e6fb;x=2d226e1ff89ee993",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Journald{
+				Directory:         tt.directory,
+				InitialReadOffset: "beginning",
+				Matches:           []string{"_TRANSPORT=journal"},
+				Tags:              []string{"_HOSTNAME", "SYSLOG_IDENTIFIER", "PRIORITY"},
+				Fields:            []string{"REQUEST_ID"},
+				BatchSize:         1000,
+				Log:               testutil.Logger{},
+			}
+			require.NoError(t, plugin.Init())
+
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Start(&acc))
+			defer plugin.Stop()
+			require.NoError(t, plugin.Gather(&acc))
+			require.Empty(t, acc.Errors)
+
+			actual := acc.GetTelegrafMetrics()
+			require.Len(t, actual, len(expectedCompact))
+			if tt.name == "compact" {
+				testutil.RequireMetricsEqual(t, expectedCompact, actual)
+			}
+			for i, m := range actual {
+				require.Equal(t, expectedCompact[i].Fields(), m.Fields())
+			}
+
+			// The cursor should match the one reported by journalctl
+			deliver(t, plugin, &acc, true)
+			require.Equal(t, tt.cursor, plugin.GetState())
+
+			// No new entries should be reported
+			acc.ClearMetrics()
+			require.NoError(t, plugin.Gather(&acc))
+			require.Empty(t, acc.GetTelegrafMetrics())
+		})
+	}
+}
+
+func TestGatherFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		units    []string
+		priority string
+		matches  []string
+		expected []telegraf.Metric
+	}{
+		{
+			name:     "units",
+			units:    []string{"ssh.*"},
+			expected: expectedCompact[1:3],
+		},
+		{
+			name:     "priority",
+			priority: "warning",
+			expected: []telegraf.Metric{expectedCompact[2], expectedCompact[3]},
+		},
+		{
+			name:     "numerical priority",
+			priority: "2",
+			expected: expectedCompact[3:4],
+		},
+		{
+			name:     "matches",
+			matches:  []string{"SYSLOG_IDENTIFIER=sshd", "SYSLOG_IDENTIFIER=kernel", "PRIORITY=4"},
+			expected: expectedCompact[2:3],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Journald{
+				Directory:         "testdata/compact",
+				InitialReadOffset: "beginning",
+				Units:             tt.units,
+				Priority:          tt.priority,
+				Matches:           tt.matches,
+				Tags:              []string{"_HOSTNAME", "SYSLOG_IDENTIFIER", "PRIORITY"},
+				BatchSize:         1000,
+				Log:               testutil.Logger{},
+			}
+			require.NoError(t, plugin.Init())
+
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Start(&acc))
+			defer plugin.Stop()
+			require.NoError(t, plugin.Gather(&acc))
+			require.Empty(t, acc.Errors)
+			testutil.RequireMetricsEqual(t, tt.expected, acc.GetTelegrafMetrics())
+		})
+	}
+}
+
+func TestGatherInitialReadOffsetEnd(t *testing.T) {
+	plugin := &Journald{
+		Directory:         "testdata/compact",
+		InitialReadOffset: "saved-or-end",
+		BatchSize:         1000,
+		Log:               testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+	require.NoError(t, plugin.Gather(&acc))
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Empty(t, acc.GetTelegrafMetrics())
+	require.Equal(t, "s=ca0c5d724fe64bda86a7c34955af9e25;i=9;b=002b929fa1bc48cfa1ec538d8bbecdcb;m=edf8d4a5;t=65e259c85fbba;x=c685673c91adafec",
+		plugin.GetState())
+}
+
+func TestGatherRestoredState(t *testing.T) {
+	// Cursor of the entry with sequence number 5 in the compact file
+	state := "s=ca0c5d724fe64bda86a7c34955af9e25;i=5;b=002b929fa1bc48cfa1ec538d8bbecdcb;m=edeec23f;t=65e259c7be954;x=4da8329a046837ed"
+
+	// Read the files of both sub-directories with different sequence-number
+	// IDs. The entries of the regular files are written after the cursor.
+	plugin := &Journald{
+		Directory:         "testdata",
+		InitialReadOffset: "saved-or-end",
+		Matches:           []string{"_TRANSPORT=journal"},
+		Tags:              []string{"_HOSTNAME", "SYSLOG_IDENTIFIER", "PRIORITY"},
+		Fields:            []string{"REQUEST_ID"},
+		BatchSize:         1000,
+		Log:               testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.SetState(state))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	actual := acc.GetTelegrafMetrics()
+	require.Len(t, actual, 3+len(expectedCompact))
+	testutil.RequireMetricsEqual(t, expectedCompact[3:], actual[:3])
+	for i, m := range actual[3:] {
+		require.Equal(t, expectedCompact[i].Fields(), m.Fields())
+		require.True(t, m.Time().After(actual[2].Time()))
+	}
+}
+
+func TestGatherSkipFiles(t *testing.T) {
+	// Cursor of the last entry of the first compact file
+	state := "s=ca0c5d724fe64bda86a7c34955af9e25;i=5;b=002b929fa1bc48cfa1ec538d8bbecdcb;m=edeec23f;t=65e259c7be954;x=4da8329a046837ed"
+	first := filepath.Join("testdata", "compact", "system@ca0c5d724fe64bda86a7c34955af9e25-0000000000000001-00065e259c6b206e.journal")
+	second := filepath.Join("testdata", "compact", "system@ca0c5d724fe64bda86a7c34955af9e25-0000000000000006-00065e259c7cc036.journal")
+
+	plugin := &Journald{
+		Directory:         "testdata/compact",
+		InitialReadOffset: "saved-or-end",
+		BatchSize:         1000,
+		Log:               testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.SetState(state))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	// The entry arrays of the first file must not be read
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.NotEmpty(t, acc.GetTelegrafMetrics())
+	require.NotContains(t, plugin.offsets, first)
+	require.Contains(t, plugin.offsets, second)
+	cached := plugin.offsets[second]
+
+	expected := acc.GetTelegrafMetrics()
+
+	// The offsets of unchanged files are reused when reading again
+	deliver(t, plugin, &acc, false)
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+	require.Same(t, cached, plugin.offsets[second])
+}
+
+func TestGatherDelivery(t *testing.T) {
+	plugin := &Journald{
+		Directory:         "testdata/compact",
+		InitialReadOffset: "beginning",
+		Matches:           []string{"_TRANSPORT=journal"},
+		Tags:              []string{"_HOSTNAME", "SYSLOG_IDENTIFIER", "PRIORITY"},
+		Fields:            []string{"REQUEST_ID"},
+		BatchSize:         4,
+		Log:               testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	// The first batch of four entries contains two matching entries
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	testutil.RequireMetricsEqual(t, expectedCompact[:2], acc.GetTelegrafMetrics())
+
+	// No new batch is read until the previous one is delivered
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.GetTelegrafMetrics(), 2)
+
+	// Rejected batches are read again without advancing the cursor
+	deliver(t, plugin, &acc, false)
+	acc.ClearMetrics()
+	require.Empty(t, plugin.GetState())
+	require.NoError(t, plugin.Gather(&acc))
+	testutil.RequireMetricsEqual(t, expectedCompact[:2], acc.GetTelegrafMetrics())
+
+	// Delivered batches advance the cursor
+	deliver(t, plugin, &acc, true)
+	require.NotEmpty(t, plugin.GetState())
+
+	// The remaining entries are read batch by batch
+	actual := acc.GetTelegrafMetrics()
+	for range 3 {
+		acc.ClearMetrics()
+		require.NoError(t, plugin.Gather(&acc))
+		actual = append(actual, acc.GetTelegrafMetrics()...)
+		deliver(t, plugin, &acc, true)
+	}
+	require.Empty(t, acc.Errors)
+	testutil.RequireMetricsEqual(t, expectedCompact, actual)
+	require.Equal(t, "s=ca0c5d724fe64bda86a7c34955af9e25;i=9;b=002b929fa1bc48cfa1ec538d8bbecdcb;m=edf8d4a5;t=65e259c85fbba;x=c685673c91adafec",
+		plugin.GetState())
+}
+
+func TestGatherUnreadableEntry(t *testing.T) {
+	// Mark the data object of the second message as XZ compressed which is
+	// not supported to make the entry unreadable
+	dir := t.TempDir()
+	files, err := filepath.Glob(filepath.Join("testdata", "compact", "*.journal"))
+	require.NoError(t, err)
+	for _, fn := range files {
+		buf, err := os.ReadFile(fn)
+		require.NoError(t, err)
+		if idx := bytes.Index(buf, []byte("MESSAGE=Accepted publickey")); idx >= 0 {
+			// Compact data objects have a 16 byte object header and 56 bytes
+			// of hash-table and entry references before the payload
+			start := idx - objectHeaderSize - 56
+			require.Equal(t, byte(objectData), buf[start])
+			buf[start+1] |= objectCompressedXZ
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.Base(fn)), buf, 0600))
+	}
+
+	plugin := &Journald{
+		Directory:         dir,
+		InitialReadOffset: "beginning",
+		Matches:           []string{"_TRANSPORT=journal"},
+		Tags:              []string{"_HOSTNAME", "SYSLOG_IDENTIFIER", "PRIORITY"},
+		Fields:            []string{"REQUEST_ID"},
+		BatchSize:         1000,
+		Log:               testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	// The unreadable entry is skipped and the remaining entries are read
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	expected := append([]telegraf.Metric{expectedCompact[0]}, expectedCompact[2:]...)
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+
+	// The cursor moved past the unreadable entry
+	deliver(t, plugin, &acc, true)
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
+
+func TestCursor(t *testing.T) {
+	s := "s=ca0c5d724fe64bda86a7c34955af9e25;i=5;b=002b929fa1bc48cfa1ec538d8bbecdcb;m=edeec23f;t=65e259c7be954;x=4da8329a046837ed"
+	c, err := parseCursor(s)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), c.seqnum)
+	require.Equal(t, uint64(0x65e259c7be954), c.realtime)
+	require.Equal(t, s, c.String())
+
+	_, err = parseCursor("s=ca0c5d724fe64bda86a7c34955af9e25;i=5")
+	require.ErrorContains(t, err, "incomplete cursor")
+	_, err = parseCursor("s=xyz;i=5;b=002b929fa1bc48cfa1ec538d8bbecdcb;m=edeec23f;t=65e259c7be954;x=4da8329a046837ed")
+	require.ErrorContains(t, err, `invalid cursor value for "s"`)
+}
+
+// deliver accepts or rejects all metrics of the accumulator and waits for the
+// plugin to process the delivery
+func deliver(t *testing.T, plugin *Journald, acc *testutil.Accumulator, accept bool) {
+	t.Helper()
+
+	for _, m := range acc.GetTelegrafMetrics() {
+		if accept {
+			m.Accept()
+		} else {
+			m.Reject()
+		}
+	}
+	require.Eventually(t, func() bool {
+		plugin.Lock()
+		defer plugin.Unlock()
+		return plugin.pendingCursor == nil
+	}, time.Second, 10*time.Millisecond)
+}
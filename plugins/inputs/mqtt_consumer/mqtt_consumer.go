@@ -242,7 +242,13 @@ func (m *MQTTConsumer) onMessage(_ mqtt.Client, msg mqtt.Message) {
 	m.payloadSize.Incr(int64(payloadBytes))
 	m.messagesRecv.Incr(1)
 
-	metrics, err := m.parser.Parse(msg.Payload())
+	var metrics []telegraf.Metric
+	var err error
+	if p, ok := m.parser.(telegraf.TopicParser); ok {
+		metrics, err = p.ParseTopic(msg.Topic(), msg.Payload())
+	} else {
+		metrics, err = m.parser.Parse(msg.Payload())
+	}
 	if err != nil || len(metrics) == 0 {
 		if len(metrics) == 0 {
 			once.Do(func() {
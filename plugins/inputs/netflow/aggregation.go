@@ -0,0 +1,138 @@
+package netflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// Fields used as byte and packet counts of flow records in order of
+// precedence. Total counts are used if the exporter does not send deltas.
+var (
+	aggregationBytesFields   = []string{"in_bytes", "in_total_bytes"}
+	aggregationPacketsFields = []string{"in_packets", "in_total_packets"}
+)
+
+type flowGroup struct {
+	tags    map[string]string
+	bytes   uint64
+	packets uint64
+	flows   uint64
+}
+
+// flowAggregator sums the bytes and packets of flow records sharing the same
+// source and values of the key fields
+type flowAggregator struct {
+	keys []string
+	topN int
+
+	groups map[string]*flowGroup
+	sync.Mutex
+}
+
+func newFlowAggregator(keys []string, topN int) *flowAggregator {
+	return &flowAggregator{
+		keys:   keys,
+		topN:   topN,
+		groups: make(map[string]*flowGroup),
+	}
+}
+
+// add adds the given flow record to its group and returns true or returns
+// false if the metric is not a flow record.
+func (a *flowAggregator) add(m telegraf.Metric) bool {
+	if m.Name() != "netflow" {
+		return false
+	}
+
+	tags := make(map[string]string, len(a.keys)+2)
+	for k, v := range m.Tags() {
+		tags[k] = v
+	}
+	for _, key := range a.keys {
+		v, found := m.GetField(key)
+		if !found {
+			continue
+		}
+		s, err := internal.ToString(v)
+		if err != nil {
+			s = fmt.Sprint(v)
+		}
+		tags[key] = s
+	}
+
+	// Compute the group identifier from the sorted tags
+	names := make([]string, 0, len(tags))
+	for k := range tags {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var id strings.Builder
+	for _, k := range names {
+		id.WriteString(k)
+		id.WriteByte(0)
+		id.WriteString(tags[k])
+		id.WriteByte(0)
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	g, found := a.groups[id.String()]
+	if !found {
+		g = &flowGroup{tags: tags}
+		a.groups[id.String()] = g
+	}
+	g.bytes += firstUintField(m, aggregationBytesFields)
+	g.packets += firstUintField(m, aggregationPacketsFields)
+	g.flows++
+
+	return true
+}
+
+// flush returns the aggregated flows of the groups, limited to the groups with
+// the most bytes if configured, and resets the aggregation
+func (a *flowAggregator) flush(t time.Time) []telegraf.Metric {
+	a.Lock()
+	groups := make([]*flowGroup, 0, len(a.groups))
+	for _, g := range a.groups {
+		groups = append(groups, g)
+	}
+	a.groups = make(map[string]*flowGroup, len(groups))
+	a.Unlock()
+
+	if a.topN > 0 && len(groups) > a.topN {
+		sort.Slice(groups, func(i, j int) bool {
+			return groups[i].bytes > groups[j].bytes
+		})
+		groups = groups[:a.topN]
+	}
+
+	metrics := make([]telegraf.Metric, 0, len(groups))
+	for _, g := range groups {
+		fields := map[string]interface{}{
+			"in_bytes":   g.bytes,
+			"in_packets": g.packets,
+			"flows":      g.flows,
+		}
+		metrics = append(metrics, metric.New("netflow_aggregate", g.tags, fields, t))
+	}
+	return metrics
+}
+
+func firstUintField(m telegraf.Metric, names []string) uint64 {
+	for _, name := range names {
+		if v, found := m.GetField(name); found {
+			if u, err := internal.ToUint64(v); err == nil {
+				return u
+			}
+		}
+	}
+	return 0
+}
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
@@ -25,11 +26,15 @@ type NetFlow struct {
 	Protocol       string          `toml:"protocol"`
 	DumpPackets    bool            `toml:"dump_packets" deprecated:"1.35.0;use 'log_level' 'trace' instead"`
 	PENFiles       []string        `toml:"private_enterprise_number_files"`
+	Aggregation    config.Duration `toml:"aggregation_interval"`
+	AggregationKey []string        `toml:"aggregation_keys"`
+	AggregationTop int             `toml:"aggregation_top_n"`
 	Log            telegraf.Logger `toml:"-"`
 
-	conn    *net.UDPConn
-	decoder protocolDecoder
-	wg      sync.WaitGroup
+	conn       *net.UDPConn
+	decoder    protocolDecoder
+	aggregator *flowAggregator
+	wg         sync.WaitGroup
 }
 
 type protocolDecoder interface {
@@ -79,9 +84,48 @@ func (n *NetFlow) Init() error {
 		return fmt.Errorf("invalid protocol %q, only supports 'sflow', 'netflow v5', 'netflow v9' and 'ipfix'", n.Protocol)
 	}
 
+	if n.Aggregation < 0 {
+		return errors.New("'aggregation_interval' must not be negative")
+	}
+	if n.AggregationTop < 0 {
+		return errors.New("'aggregation_top_n' must not be negative")
+	}
+	if n.Aggregation > 0 {
+		if len(n.AggregationKey) == 0 {
+			n.AggregationKey = []string{"src", "dst", "protocol", "src_port", "dst_port"}
+		}
+		n.aggregator = newFlowAggregator(n.AggregationKey, n.AggregationTop)
+	}
+
 	return n.decoder.init()
 }
 
+func (n *NetFlow) GetState() interface{} {
+	d, ok := n.decoder.(*netflowDecoder)
+	if !ok {
+		return make(map[string][]persistedTemplate)
+	}
+	state, err := d.getTemplates()
+	if err != nil {
+		n.Log.Errorf("Getting templates failed: %v", err)
+		return make(map[string][]persistedTemplate)
+	}
+	return state
+}
+
+func (n *NetFlow) SetState(state interface{}) error {
+	templates, ok := state.(map[string][]persistedTemplate)
+	if !ok {
+		return fmt.Errorf("invalid state type %T", state)
+	}
+
+	d, ok := n.decoder.(*netflowDecoder)
+	if !ok {
+		return nil
+	}
+	return d.setTemplates(templates)
+}
+
 func (n *NetFlow) Start(acc telegraf.Accumulator) error {
 	u, err := url.Parse(n.ServiceAddress)
 	if err != nil {
@@ -105,12 +149,23 @@ func (n *NetFlow) Start(acc telegraf.Accumulator) error {
 	}
 	n.Log.Infof("Listening on %s://%s", n.conn.LocalAddr().Network(), n.conn.LocalAddr().String())
 
+	// Emit the remaining aggregated flows after reading stopped
+	done := make(chan struct{})
 	n.wg.Add(1)
 	go func() {
 		defer n.wg.Done()
+		defer close(done)
 		n.read(acc)
 	}()
 
+	if n.aggregator != nil {
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			n.flush(acc, done)
+		}()
+	}
+
 	return nil
 }
 
@@ -149,11 +204,35 @@ func (n *NetFlow) read(acc telegraf.Accumulator) {
 			continue
 		}
 		for _, m := range metrics {
+			if n.aggregator != nil && n.aggregator.add(m) {
+				continue
+			}
 			acc.AddMetric(m)
 		}
 	}
 }
 
+// flush periodically emits the aggregated flows and emits the remaining
+// flows on shutdown
+func (n *NetFlow) flush(acc telegraf.Accumulator, done <-chan struct{}) {
+	ticker := time.NewTicker(time.Duration(n.Aggregation))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			for _, m := range n.aggregator.flush(time.Now()) {
+				acc.AddMetric(m)
+			}
+			return
+		case t := <-ticker.C:
+			for _, m := range n.aggregator.flush(t) {
+				acc.AddMetric(m)
+			}
+		}
+	}
+}
+
 // Register the plugin
 func init() {
 	inputs.Add("netflow", func() telegraf.Input {
@@ -2,6 +2,7 @@ package netflow
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net"
 	"os"
@@ -294,3 +295,94 @@ func createClient(endpoint string, addr net.Addr) (net.Conn, error) {
 	protocol := parts[0]
 	return net.Dial(protocol, addr.String())
 }
+
+func TestTemplatePersistence(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []string
+		data      []string
+		expected  int
+	}{
+		{
+			name:      "data templates",
+			templates: []string{"ipfix_0.bin", "ipfix_1.bin", "ipfix_2.bin", "ipfix_3.bin"},
+			data:      []string{"ipfix_4.bin", "ipfix_5.bin", "ipfix_6.bin"},
+			expected:  14,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			send := func(plugin *NetFlow, files []string) {
+				client, err := createClient(plugin.ServiceAddress, plugin.conn.LocalAddr())
+				require.NoError(t, err)
+				defer client.Close()
+				for _, fn := range files {
+					msg, err := os.ReadFile(filepath.Join("testcases", "ipfix_example", fn))
+					require.NoError(t, err)
+					_, err = client.Write(msg)
+					require.NoError(t, err)
+				}
+			}
+
+			// Learn the templates
+			plugin := &NetFlow{
+				ServiceAddress: "udp://127.0.0.1:0",
+				Protocol:       "ipfix",
+				Log:            testutil.Logger{},
+			}
+			require.NoError(t, plugin.Init())
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Start(&acc))
+			send(plugin, tt.templates)
+			require.Eventually(t, func() bool {
+				state, ok := plugin.GetState().(map[string][]persistedTemplate)
+				return ok && len(state["127.0.0.1"]) == tt.expected
+			}, 3*time.Second, 100*time.Millisecond)
+			plugin.Stop()
+
+			// Serialize the state like the persister
+			buf, err := json.Marshal(plugin.GetState())
+			require.NoError(t, err)
+			var state map[string][]persistedTemplate
+			require.NoError(t, json.Unmarshal(buf, &state))
+
+			// The restarted plugin should decode the data without the templates
+			var logger testutil.CaptureLogger
+			restarted := &NetFlow{
+				ServiceAddress: "udp://127.0.0.1:0",
+				Protocol:       "ipfix",
+				Log:            &logger,
+			}
+			require.NoError(t, restarted.Init())
+			require.NoError(t, restarted.SetState(state))
+			require.NoError(t, restarted.Start(&acc))
+			defer restarted.Stop()
+			send(restarted, tt.data)
+
+			parser := &influx.Parser{}
+			require.NoError(t, parser.Init())
+			expected, err := testutil.ParseMetricsFromFile(filepath.Join("testcases", "ipfix_example", "expected.out"), parser)
+			require.NoError(t, err)
+			require.Eventually(t, func() bool {
+				return acc.NMetrics() >= uint64(len(expected))
+			}, 3*time.Second, 100*time.Millisecond)
+			testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), testutil.SortMetrics())
+			require.Empty(t, logger.Warnings())
+		})
+	}
+}
+
+func TestSetStateInvalid(t *testing.T) {
+	plugin := &NetFlow{
+		ServiceAddress: "udp://127.0.0.1:0",
+		Log:            testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.ErrorContains(t, plugin.SetState("foo"), "invalid state type")
+
+	state := map[string][]persistedTemplate{
+		"127.0.0.1": {{Version: 10, ID: 256, Type: "foo", Record: []byte("{}")}},
+	}
+	require.ErrorContains(t, plugin.SetState(state), `unknown type "foo"`)
+}
@@ -0,0 +1,100 @@
+package netflow
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/netsampler/goflow2/v2/decoders/netflow"
+)
+
+// persistedTemplate is the serializable form of a template learned from an
+// exporter to be able to decode data without waiting for the exporter to
+// resend the template after a restart
+type persistedTemplate struct {
+	Version           uint16          `json:"version"`
+	ObservationDomain uint32          `json:"observation_domain"`
+	ID                uint16          `json:"id"`
+	Type              string          `json:"type"`
+	Record            json.RawMessage `json:"record"`
+}
+
+// getTemplates returns the templates of all exporters keyed by the exporter
+// address
+func (d *netflowDecoder) getTemplates() (map[string][]persistedTemplate, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	state := make(map[string][]persistedTemplate, len(d.templates))
+	for src, system := range d.templates {
+		var templates []persistedTemplate
+		for key, template := range system.GetTemplates() {
+			var ttype string
+			switch template.(type) {
+			case netflow.TemplateRecord:
+				ttype = "data"
+			case netflow.NFv9OptionsTemplateRecord:
+				ttype = "nfv9_options"
+			case netflow.IPFIXOptionsTemplateRecord:
+				ttype = "ipfix_options"
+			default:
+				continue
+			}
+			record, err := json.Marshal(template)
+			if err != nil {
+				return nil, fmt.Errorf("serializing template %d of %q failed: %w", uint16(key), src, err)
+			}
+			templates = append(templates, persistedTemplate{
+				Version:           uint16(key >> 48),
+				ObservationDomain: uint32(key >> 16),
+				ID:                uint16(key),
+				Type:              ttype,
+				Record:            record,
+			})
+		}
+		if len(templates) > 0 {
+			state[src] = templates
+		}
+	}
+	return state, nil
+}
+
+// setTemplates adds the given templates keyed by the exporter address
+func (d *netflowDecoder) setTemplates(state map[string][]persistedTemplate) error {
+	d.Lock()
+	defer d.Unlock()
+
+	for src, templates := range state {
+		system, found := d.templates[src]
+		if !found {
+			system = netflow.CreateTemplateSystem()
+			d.templates[src] = system
+		}
+		for _, t := range templates {
+			var template interface{}
+			var err error
+			switch t.Type {
+			case "data":
+				var record netflow.TemplateRecord
+				err = json.Unmarshal(t.Record, &record)
+				template = record
+			case "nfv9_options":
+				var record netflow.NFv9OptionsTemplateRecord
+				err = json.Unmarshal(t.Record, &record)
+				template = record
+			case "ipfix_options":
+				var record netflow.IPFIXOptionsTemplateRecord
+				err = json.Unmarshal(t.Record, &record)
+				template = record
+			default:
+				return fmt.Errorf("unknown type %q of template %d of %q", t.Type, t.ID, src)
+			}
+			if err != nil {
+				return fmt.Errorf("decoding template %d of %q failed: %w", t.ID, src, err)
+			}
+			if err := system.AddTemplate(t.Version, t.ObservationDomain, t.ID, template); err != nil {
+				return fmt.Errorf("adding template %d of %q failed: %w", t.ID, src, err)
+			}
+		}
+	}
+	return nil
+}
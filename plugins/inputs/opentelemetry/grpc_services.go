@@ -9,6 +9,9 @@ import (
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	"github.com/influxdata/telegraf"
+	common_otel "github.com/influxdata/telegraf/plugins/common/opentelemetry"
 )
 
 type traceService struct {
@@ -41,17 +44,15 @@ func (s *traceService) Export(ctx context.Context, req ptraceotlp.ExportRequest)
 type metricsService struct {
 	pmetricotlp.UnimplementedGRPCServer
 	exporter *otel2influx.OtelMetricsToLineProtocol
+	writer   *writeToAccumulator
+	schema   common.MetricsSchema
+	log      telegraf.Logger
 }
 
 var _ pmetricotlp.GRPCServer = (*metricsService)(nil)
 
-var metricsSchemata = map[string]common.MetricsSchema{
-	"prometheus-v1": common.MetricsSchemaTelegrafPrometheusV1,
-	"prometheus-v2": common.MetricsSchemaTelegrafPrometheusV2,
-}
-
-func newMetricsService(logger common.Logger, writer *writeToAccumulator, schema string) (*metricsService, error) {
-	ms, found := metricsSchemata[schema]
+func newMetricsService(logger *common_otel.Logger, writer *writeToAccumulator, schema string) (*metricsService, error) {
+	ms, found := common_otel.MetricsSchemata[schema]
 	if !found {
 		return nil, fmt.Errorf("schema %q not recognized", schema)
 	}
@@ -66,11 +67,18 @@ func newMetricsService(logger common.Logger, writer *writeToAccumulator, schema
 	}
 	return &metricsService{
 		exporter: exp,
+		writer:   writer,
+		schema:   ms,
+		log:      logger.Logger,
 	}, nil
 }
 
 // Export processes and exports the metrics data received in the request.
 func (s *metricsService) Export(ctx context.Context, req pmetricotlp.ExportRequest) (pmetricotlp.ExportResponse, error) {
+	// Exponential histograms are not supported by the conversion library
+	for _, m := range common_otel.ExtractExponentialHistograms(req.Metrics(), s.schema, s.log) {
+		s.writer.accumulator.AddMetric(m)
+	}
 	err := s.exporter.WriteMetrics(ctx, req.Metrics())
 	return pmetricotlp.NewExportResponse(), err
 }
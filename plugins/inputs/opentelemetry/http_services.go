@@ -0,0 +1,242 @@
+package opentelemetry
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	pprofileotlp "go.opentelemetry.io/proto/otlp/collector/profiles/v1development"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/internal"
+)
+
+const (
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+)
+
+// errUnsupportedMediaType is returned for requests with a content-type
+// other than protobuf or JSON
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+// otlpRequest is implemented by the request types of the pdata packages
+type otlpRequest interface {
+	UnmarshalProto(data []byte) error
+	UnmarshalJSON(data []byte) error
+}
+
+// otlpResponse is implemented by the response types of the pdata packages
+type otlpResponse interface {
+	MarshalProto() ([]byte, error)
+	MarshalJSON() ([]byte, error)
+}
+
+// httpHandler serves the OTLP/HTTP endpoints using the same services as the
+// gRPC server for processing the requests
+type httpHandler struct {
+	traces   *traceService
+	metrics  *metricsService
+	logs     *logsService
+	profiles *profileService
+
+	maxSize int64
+	origins filter.Filter
+	log     telegraf.Logger
+}
+
+func (h *httpHandler) mux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/traces", h.cors(h.handleTraces))
+	mux.HandleFunc("/v1/metrics", h.cors(h.handleMetrics))
+	mux.HandleFunc("/v1/logs", h.cors(h.handleLogs))
+	mux.HandleFunc("/v1development/profiles", h.cors(h.handleProfiles))
+	return mux
+}
+
+func (h *httpHandler) cors(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowed := origin != "" && h.origins != nil && h.origins.Match(origin)
+		if allowed {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "OPTIONS, POST")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Encoding, Content-Length, Accept")
+			w.Header().Add("Vary", "Origin")
+		}
+
+		switch r.Method {
+		case http.MethodOptions:
+			// Deny preflight requests of origins not allowed
+			if origin != "" && !allowed {
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodPost:
+			next(w, r)
+		default:
+			w.Header().Set("Allow", "OPTIONS, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func (h *httpHandler) handleTraces(w http.ResponseWriter, r *http.Request) {
+	req := ptraceotlp.NewExportRequest()
+	h.serve(w, r, req, func() (otlpResponse, error) {
+		return h.traces.Export(r.Context(), req)
+	})
+}
+
+func (h *httpHandler) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	req := pmetricotlp.NewExportRequest()
+	h.serve(w, r, req, func() (otlpResponse, error) {
+		return h.metrics.Export(r.Context(), req)
+	})
+}
+
+func (h *httpHandler) handleLogs(w http.ResponseWriter, r *http.Request) {
+	req := plogotlp.NewExportRequest()
+	h.serve(w, r, req, func() (otlpResponse, error) {
+		return h.logs.Export(r.Context(), req)
+	})
+}
+
+func (h *httpHandler) handleProfiles(w http.ResponseWriter, r *http.Request) {
+	req := &profileRequest{}
+	h.serve(w, r, req, func() (otlpResponse, error) {
+		resp, err := h.profiles.Export(r.Context(), &req.ExportProfilesServiceRequest)
+		return &profileResponse{resp}, err
+	})
+}
+
+func (h *httpHandler) serve(w http.ResponseWriter, r *http.Request, req otlpRequest, export func() (otlpResponse, error)) {
+	contentType, body, err := h.readBody(r)
+	if err != nil {
+		h.log.Debugf("Reading request to %q failed: %v", r.URL.Path, err)
+		switch {
+		case errors.Is(err, errUnsupportedMediaType):
+			http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		case errors.As(err, new(*http.MaxBytesError)):
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	if contentType == contentTypeJSON {
+		err = req.UnmarshalJSON(body)
+	} else {
+		err = req.UnmarshalProto(body)
+	}
+	if err != nil {
+		h.log.Debugf("Decoding request to %q failed: %v", r.URL.Path, err)
+		http.Error(w, fmt.Sprintf("decoding request failed: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Errors are caused by the data not being convertible, so retrying the
+	// request will not help and we respond with a permanent error
+	resp, err := export()
+	if err != nil {
+		h.log.Errorf("Processing request to %q failed: %v", r.URL.Path, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf []byte
+	if contentType == contentTypeJSON {
+		buf, err = resp.MarshalJSON()
+	} else {
+		buf, err = resp.MarshalProto()
+	}
+	if err != nil {
+		h.log.Errorf("Encoding response to %q failed: %v", r.URL.Path, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf); err != nil {
+		h.log.Debugf("Writing response to %q failed: %v", r.URL.Path, err)
+	}
+}
+
+// readBody returns the content-type and the decompressed body of the request
+func (h *httpHandler) readBody(r *http.Request) (string, []byte, error) {
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", errUnsupportedMediaType, err)
+	}
+	if contentType != contentTypeProtobuf && contentType != contentTypeJSON {
+		return "", nil, fmt.Errorf("%w %q", errUnsupportedMediaType, contentType)
+	}
+
+	var body io.Reader = r.Body
+	if h.maxSize > 0 {
+		body = http.MaxBytesReader(nil, r.Body, h.maxSize)
+	}
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Guessing the encoding is not allowed by the protocol
+	encoding := strings.ToLower(r.Header.Get("Content-Encoding"))
+	if encoding == "auto" {
+		return "", nil, fmt.Errorf("unsupported content-encoding %q", encoding)
+	}
+	var options []internal.DecodingOption
+	if h.maxSize > 0 {
+		options = append(options, internal.WithMaxDecompressionSize(h.maxSize))
+	}
+	decoder, err := internal.NewContentDecoder(encoding, options...)
+	if err != nil {
+		return "", nil, fmt.Errorf("unsupported content-encoding %q", encoding)
+	}
+	buf, err = decoder.Decode(buf)
+	if err != nil {
+		return "", nil, fmt.Errorf("decompressing request failed: %w", err)
+	}
+
+	return contentType, buf, nil
+}
+
+// profileRequest wraps the profile request to implement otlpRequest as the
+// profiles signal is not yet available in the pdata packages
+type profileRequest struct {
+	pprofileotlp.ExportProfilesServiceRequest
+}
+
+func (p *profileRequest) UnmarshalProto(data []byte) error {
+	return proto.Unmarshal(data, &p.ExportProfilesServiceRequest)
+}
+
+func (p *profileRequest) UnmarshalJSON(data []byte) error {
+	return protojson.Unmarshal(data, &p.ExportProfilesServiceRequest)
+}
+
+// profileResponse wraps the profile response to implement otlpResponse
+type profileResponse struct {
+	*pprofileotlp.ExportProfilesServiceResponse
+}
+
+func (p *profileResponse) MarshalProto() ([]byte, error) {
+	return proto.Marshal(p.ExportProfilesServiceResponse)
+}
+
+func (p *profileResponse) MarshalJSON() ([]byte, error) {
+	return protojson.Marshal(p.ExportProfilesServiceResponse)
+}
@@ -2,9 +2,12 @@
 package opentelemetry
 
 import (
+	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"sync"
 	"time"
 
@@ -18,6 +21,8 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/filter"
+	common_otel "github.com/influxdata/telegraf/plugins/common/opentelemetry"
 	"github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
@@ -27,17 +32,22 @@ var sampleConfig string
 
 type OpenTelemetry struct {
 	ServiceAddress      string          `toml:"service_address"`
+	HTTPServiceAddress  string          `toml:"http_service_address"`
+	CORSAllowedOrigins  []string        `toml:"cors_allowed_origins"`
 	SpanDimensions      []string        `toml:"span_dimensions"`
 	LogRecordDimensions []string        `toml:"log_record_dimensions"`
 	ProfileDimensions   []string        `toml:"profile_dimensions"`
 	MetricsSchema       string          `toml:"metrics_schema"`
 	MaxMsgSize          config.Size     `toml:"max_msg_size"`
 	Timeout             config.Duration `toml:"timeout"`
+	ShutdownTimeout     config.Duration `toml:"shutdown_timeout"`
 	Log                 telegraf.Logger `toml:"-"`
 	tls.ServerConfig
 
-	listener   net.Listener // overridden in tests
-	grpcServer *grpc.Server
+	listener     net.Listener // overridden in tests
+	grpcServer   *grpc.Server
+	httpListener net.Listener
+	httpServer   *http.Server
 
 	wg sync.WaitGroup
 }
@@ -58,14 +68,25 @@ func (o *OpenTelemetry) Init() error {
 		return fmt.Errorf("invalid metric schema %q", o.MetricsSchema)
 	}
 
+	if _, err := filter.Compile(o.CORSAllowedOrigins); err != nil {
+		return fmt.Errorf("invalid CORS allowed origins: %w", err)
+	}
+
+	if o.ShutdownTimeout <= 0 {
+		o.ShutdownTimeout = config.Duration(5 * time.Second)
+	}
+
 	return nil
 }
 
 func (o *OpenTelemetry) Start(acc telegraf.Accumulator) error {
-	var grpcOptions []grpc.ServerOption
-	if tlsConfig, err := o.ServerConfig.TLSConfig(); err != nil {
+	tlsConfig, err := o.ServerConfig.TLSConfig()
+	if err != nil {
 		return err
-	} else if tlsConfig != nil {
+	}
+
+	var grpcOptions []grpc.ServerOption
+	if tlsConfig != nil {
 		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
 	}
 	if o.Timeout > 0 {
@@ -75,7 +96,7 @@ func (o *OpenTelemetry) Start(acc telegraf.Accumulator) error {
 		grpcOptions = append(grpcOptions, grpc.MaxRecvMsgSize(int(o.MaxMsgSize)))
 	}
 
-	logger := &otelLogger{o.Log}
+	logger := &common_otel.Logger{Logger: o.Log}
 	influxWriter := &writeToAccumulator{acc}
 	o.grpcServer = grpc.NewServer(grpcOptions...)
 
@@ -116,6 +137,50 @@ func (o *OpenTelemetry) Start(acc telegraf.Accumulator) error {
 		}
 	}()
 
+	if o.HTTPServiceAddress == "" {
+		return nil
+	}
+
+	// Serve OTLP/HTTP using the same services as the gRPC server
+	origins, err := filter.Compile(o.CORSAllowedOrigins)
+	if err != nil {
+		return fmt.Errorf("invalid CORS allowed origins: %w", err)
+	}
+	handler := &httpHandler{
+		traces:   traceSvc,
+		metrics:  metricsSvc,
+		logs:     logsSvc,
+		profiles: profileSvc,
+		maxSize:  int64(o.MaxMsgSize),
+		origins:  origins,
+		log:      o.Log,
+	}
+	o.httpServer = &http.Server{
+		Handler:     handler.mux(),
+		TLSConfig:   tlsConfig,
+		ReadTimeout: time.Duration(o.Timeout),
+	}
+
+	o.httpListener, err = net.Listen("tcp", o.HTTPServiceAddress)
+	if err != nil {
+		o.grpcServer.Stop()
+		return err
+	}
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		var err error
+		if tlsConfig != nil {
+			err = o.httpServer.ServeTLS(o.httpListener, "", "")
+		} else {
+			err = o.httpServer.Serve(o.httpListener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			acc.AddError(fmt.Errorf("failed to stop OpenTelemetry HTTP service: %w", err))
+		}
+	}()
+
 	return nil
 }
 
@@ -129,6 +194,15 @@ func (o *OpenTelemetry) Stop() {
 	}
 	o.listener = nil
 
+	if o.httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(o.ShutdownTimeout))
+		defer cancel()
+		if err := o.httpServer.Shutdown(ctx); err != nil {
+			o.Log.Errorf("Shutting down HTTP service failed: %v", err)
+		}
+	}
+	o.httpListener = nil
+
 	o.wg.Wait()
 }
 
@@ -138,6 +212,7 @@ func init() {
 			SpanDimensions:      otel2influx.DefaultOtelTracesToLineProtocolConfig().SpanDimensions,
 			LogRecordDimensions: otel2influx.DefaultOtelLogsToLineProtocolConfig().LogRecordDimensions,
 			Timeout:             config.Duration(5 * time.Second),
+			ShutdownTimeout:     config.Duration(5 * time.Second),
 		}
 	})
 }
@@ -1,8 +1,11 @@
 package opentelemetry
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -14,6 +17,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb-observability/otel2influx"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
@@ -27,6 +33,7 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/parsers/influx"
@@ -255,3 +262,120 @@ func TestCases(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTP(t *testing.T) {
+	// Setup and start the plugin
+	plugin := &OpenTelemetry{
+		ServiceAddress:     "127.0.0.1:0",
+		HTTPServiceAddress: "127.0.0.1:0",
+		CORSAllowedOrigins: []string{"https://*.example.com"},
+		MetricsSchema:      "prometheus-v1",
+		Timeout:            config.Duration(time.Second),
+		Log:                testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+	addr := "http://" + plugin.httpListener.Addr().String()
+
+	// Create the request
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("requests")
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1700000000, 0)))
+	dp.SetIntValue(42)
+	req := pmetricotlp.NewExportRequestFromMetrics(md)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"requests",
+			map[string]string{},
+			map[string]interface{}{"gauge": int64(42)},
+			time.Unix(1700000000, 0),
+			telegraf.Gauge,
+		),
+	}
+
+	// Send protobuf compressed with gzip
+	buf, err := req.MarshalProto()
+	require.NoError(t, err)
+	encoder, err := internal.NewContentEncoder("gzip")
+	require.NoError(t, err)
+	buf, err = encoder.Encode(buf)
+	require.NoError(t, err)
+
+	httpReq, err := http.NewRequest(http.MethodPost, addr+"/v1/metrics", bytes.NewReader(buf))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("Content-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/x-protobuf", resp.Header.Get("Content-Type"))
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+	acc.ClearMetrics()
+
+	// Send JSON from a browser
+	buf, err = req.MarshalJSON()
+	require.NoError(t, err)
+	httpReq, err = http.NewRequest(http.MethodPost, addr+"/v1/metrics", bytes.NewReader(buf))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Origin", "https://app.example.com")
+	resp, err = http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	require.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	require.JSONEq(t, `{"partialSuccess":{}}`, string(body))
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+
+	// Preflight requests from unknown origins must not be allowed
+	httpReq, err = http.NewRequest(http.MethodOptions, addr+"/v1/logs", nil)
+	require.NoError(t, err)
+	httpReq.Header.Set("Origin", "https://evil.com")
+	resp, err = http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	require.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+	// Preflight requests from allowed origins
+	httpReq, err = http.NewRequest(http.MethodOptions, addr+"/v1/logs", nil)
+	require.NoError(t, err)
+	httpReq.Header.Set("Origin", "https://app.example.com")
+	resp, err = http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+
+	// Guessing the content encoding is not allowed
+	httpReq, err = http.NewRequest(http.MethodPost, addr+"/v1/metrics", bytes.NewReader(buf))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Content-Encoding", "auto")
+	resp, err = http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Unsupported content types
+	resp, err = http.Post(addr+"/v1/traces", "text/plain", strings.NewReader("foo"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	// Invalid request bodies
+	resp, err = http.Post(addr+"/v1/traces", "application/x-protobuf", strings.NewReader("foo"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
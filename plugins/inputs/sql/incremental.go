@@ -0,0 +1,147 @@
+package sql
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// highWaterMark keeps track of the last delivered value of the incremental
+// column of a query
+type highWaterMark struct {
+	// value is the last delivered value bound as query parameter
+	value interface{}
+
+	// candidate is the largest value of the undelivered query result
+	candidate interface{}
+	id        telegraf.TrackingID
+	pending   bool
+}
+
+// persistedMark is the serializable representation of a high-water mark
+// preserving the type of the value
+type persistedMark struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func newPersistedMark(v interface{}) persistedMark {
+	switch v := v.(type) {
+	case int64:
+		return persistedMark{Type: "int", Value: strconv.FormatInt(v, 10)}
+	case uint64:
+		return persistedMark{Type: "uint", Value: strconv.FormatUint(v, 10)}
+	case float64:
+		return persistedMark{Type: "float", Value: strconv.FormatFloat(v, 'g', -1, 64)}
+	case time.Time:
+		return persistedMark{Type: "time", Value: v.Format(time.RFC3339Nano)}
+	case string:
+		return persistedMark{Type: "string", Value: v}
+	}
+	return persistedMark{Type: "string", Value: fmt.Sprintf("%v", v)}
+}
+
+func (pm persistedMark) value() (interface{}, error) {
+	switch pm.Type {
+	case "int":
+		return strconv.ParseInt(pm.Value, 10, 64)
+	case "uint":
+		return strconv.ParseUint(pm.Value, 10, 64)
+	case "float":
+		return strconv.ParseFloat(pm.Value, 64)
+	case "time":
+		return time.Parse(time.RFC3339Nano, pm.Value)
+	case "string":
+		return pm.Value, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", pm.Type)
+}
+
+// parseInitialValue determines the type of the configured initial value by
+// trying integers, floats and RFC3339 timestamps before falling back to
+// strings
+func parseInitialValue(s string) interface{} {
+	s = strings.TrimSpace(s)
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return v
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v
+	}
+	if v, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return v
+	}
+	return s
+}
+
+// incrementalValue normalizes the value of the incremental column returned
+// by the driver to make values comparable
+func incrementalValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return uint64(v), nil
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case time.Time:
+		return v, nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case nil:
+		return nil, errors.New("unexpected NULL value")
+	}
+	return nil, fmt.Errorf("unsupported type %T", v)
+}
+
+// compareValues returns -1, 0 or 1 if a is smaller, equal or larger than b
+func compareValues(a, b interface{}) (int, error) {
+	switch av := a.(type) {
+	case int64:
+		if bv, ok := b.(int64); ok {
+			return cmp.Compare(av, bv), nil
+		}
+	case uint64:
+		if bv, ok := b.(uint64); ok {
+			return cmp.Compare(av, bv), nil
+		}
+	case float64:
+		if bv, ok := b.(float64); ok {
+			return cmp.Compare(av, bv), nil
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), nil
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			return av.Compare(bv), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %T with %T", a, b)
+}
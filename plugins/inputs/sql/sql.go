@@ -18,6 +18,7 @@ import (
 	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/internal/choice"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
@@ -43,6 +44,11 @@ type SQL struct {
 	driverName      string
 	db              *dbsql.DB
 	serverConnected bool
+
+	acc         telegraf.TrackingAccumulator
+	incremental sync.Mutex
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
 }
 
 type query struct {
@@ -62,7 +68,11 @@ type query struct {
 	FieldColumnsBool    []string `toml:"field_columns_bool"`
 	FieldColumnsString  []string `toml:"field_columns_string"`
 
+	IncrementalColumn       string `toml:"incremental_column"`
+	IncrementalInitialValue string `toml:"incremental_initial_value"`
+
 	statement         *dbsql.Stmt
+	mark              *highWaterMark
 	tagFilter         filter.Filter
 	fieldFilter       filter.Filter
 	fieldFilterFloat  filter.Filter
@@ -166,6 +176,19 @@ func (s *SQL) Init() error {
 		if q.Measurement == "" {
 			s.Queries[i].Measurement = "sql"
 		}
+
+		// Setup the high-water mark for incremental queries
+		if q.IncrementalColumn != "" {
+			if q.IncrementalInitialValue == "" {
+				return fmt.Errorf("'incremental_initial_value' required for incremental query %q", s.Queries[i].Query)
+			}
+			for _, other := range s.Queries[:i] {
+				if other.mark != nil && other.Query == s.Queries[i].Query {
+					return fmt.Errorf("duplicate incremental query %q", s.Queries[i].Query)
+				}
+			}
+			s.Queries[i].mark = &highWaterMark{value: parseInitialValue(q.IncrementalInitialValue)}
+		}
 	}
 
 	// Derive the sql-framework driver name from our config name. This abstracts the actual driver
@@ -216,7 +239,7 @@ func (s *SQL) Init() error {
 	return nil
 }
 
-func (s *SQL) Start(telegraf.Accumulator) error {
+func (s *SQL) Start(acc telegraf.Accumulator) error {
 	if err := s.setupConnection(); err != nil {
 		return err
 	}
@@ -231,6 +254,35 @@ func (s *SQL) Start(telegraf.Accumulator) error {
 		s.prepareStatements()
 	}
 
+	// Track the delivery of incremental query results to only advance the
+	// high-water mark for rows actually written. There is at most one
+	// undelivered result per query. Tracking is started last to not leak the
+	// goroutine if starting fails.
+	var incremental int
+	for _, q := range s.Queries {
+		if q.mark != nil {
+			incremental++
+		}
+	}
+	if incremental > 0 {
+		s.acc = acc.WithTracking(incremental)
+
+		var ctx context.Context
+		ctx, s.cancel = context.WithCancel(context.Background())
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case info := <-s.acc.Delivered():
+					s.onDelivery(info)
+				}
+			}
+		}()
+	}
+
 	return nil
 }
 
@@ -265,6 +317,12 @@ func (s *SQL) Gather(acc telegraf.Accumulator) error {
 }
 
 func (s *SQL) Stop() {
+	// Stop the delivery tracking
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+
 	// Free the statements
 	for _, q := range s.Queries {
 		if q.statement != nil {
@@ -282,6 +340,66 @@ func (s *SQL) Stop() {
 	}
 }
 
+func (s *SQL) GetState() interface{} {
+	s.incremental.Lock()
+	defer s.incremental.Unlock()
+
+	state := make(map[string]persistedMark)
+	for _, q := range s.Queries {
+		if q.mark != nil {
+			state[q.Query] = newPersistedMark(q.mark.value)
+		}
+	}
+	return state
+}
+
+func (s *SQL) SetState(state interface{}) error {
+	marks, ok := state.(map[string]persistedMark)
+	if !ok {
+		return fmt.Errorf("state has wrong type %T", state)
+	}
+
+	s.incremental.Lock()
+	defer s.incremental.Unlock()
+
+	for _, q := range s.Queries {
+		if q.mark == nil {
+			continue
+		}
+		pm, found := marks[q.Query]
+		if !found {
+			continue
+		}
+		value, err := pm.value()
+		if err != nil {
+			return fmt.Errorf("restoring high-water mark of query %q failed: %w", q.Query, err)
+		}
+		q.mark.value = value
+	}
+	return nil
+}
+
+func (s *SQL) onDelivery(info telegraf.DeliveryInfo) {
+	s.incremental.Lock()
+	defer s.incremental.Unlock()
+
+	for _, q := range s.Queries {
+		if q.mark == nil || !q.mark.pending || q.mark.id != info.ID() {
+			continue
+		}
+		// Only advance the mark if the rows were delivered, otherwise the
+		// rows are queried again in the next gather cycle
+		if info.Delivered() {
+			q.mark.value = q.mark.candidate
+		} else {
+			s.Log.Warnf("Results of query %q not delivered, retrying from %v", q.Query, q.mark.value)
+		}
+		q.mark.pending = false
+		q.mark.candidate = nil
+		return
+	}
+}
+
 func (s *SQL) setupConnection() error {
 	// Connect to the database server
 	dsnSecret, err := s.Dsn.Get()
@@ -340,21 +458,59 @@ func (s *SQL) prepareStatements() {
 }
 
 func (s *SQL) executeQuery(ctx context.Context, acc telegraf.Accumulator, q query, tquery time.Time) error {
+	// Bind the high-water mark as parameter for incremental queries and skip
+	// the query if the results of the previous run are not yet delivered to
+	// avoid duplicates.
+	var args []interface{}
+	if q.mark != nil {
+		s.incremental.Lock()
+		if q.mark.pending {
+			s.incremental.Unlock()
+			s.Log.Debugf("Skipping query %q as previous results are not yet delivered", q.Query)
+			return nil
+		}
+		q.mark.pending = true
+		args = append(args, q.mark.value)
+		s.incremental.Unlock()
+	}
+
+	metrics, mark, err := s.runQuery(ctx, q, tquery, args...)
+	if q.mark == nil {
+		for _, m := range metrics {
+			acc.AddMetric(m)
+		}
+		return err
+	}
+
+	// Do not emit partial results for incremental queries as we cannot
+	// advance the high-water mark for those
+	s.incremental.Lock()
+	defer s.incremental.Unlock()
+	if err != nil || len(metrics) == 0 {
+		q.mark.pending = false
+		return err
+	}
+	q.mark.candidate = mark
+	q.mark.id = s.acc.AddTrackingMetricGroup(metrics)
+	return nil
+}
+
+func (s *SQL) runQuery(ctx context.Context, q query, tquery time.Time, args ...interface{}) ([]telegraf.Metric, interface{}, error) {
 	// Execute the query either prepared or unprepared
 	var rows *dbsql.Rows
 	if q.statement != nil {
 		// Use the previously prepared query
 		var err error
-		rows, err = q.statement.QueryContext(ctx)
+		rows, err = q.statement.QueryContext(ctx, args...)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 	} else {
 		// Fallback to unprepared query
 		var err error
-		rows, err = s.db.Query(q.Query)
+		rows, err = s.db.Query(q.Query, args...)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 	}
 	defer rows.Close()
@@ -362,12 +518,12 @@ func (s *SQL) executeQuery(ctx context.Context, acc telegraf.Accumulator, q quer
 	// Handle the rows
 	columnNames, err := rows.Columns()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	rowCount, err := q.parse(acc, rows, tquery, s.Log)
-	s.Log.Debugf("Received %d rows and %d columns for query %q", rowCount, len(columnNames), q.Query)
+	metrics, mark, err := q.parse(rows, tquery, s.Log)
+	s.Log.Debugf("Received %d rows and %d columns for query %q", len(metrics), len(columnNames), q.Query)
 
-	return err
+	return metrics, mark, err
 }
 
 func (s *SQL) checkDSN() error {
@@ -377,10 +533,10 @@ func (s *SQL) checkDSN() error {
 	return nil
 }
 
-func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, logger telegraf.Logger) (int, error) {
+func (q *query) parse(rows *dbsql.Rows, t time.Time, logger telegraf.Logger) ([]telegraf.Metric, interface{}, error) {
 	columnNames, err := rows.Columns()
 	if err != nil {
-		return 0, err
+		return nil, nil, err
 	}
 
 	// Prepare the list of datapoints according to the received row
@@ -391,7 +547,8 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 		columnDataPtr[i] = &columnData[i]
 	}
 
-	rowCount := 0
+	var metrics []telegraf.Metric
+	var mark interface{}
 	for rows.Next() {
 		measurement := q.Measurement
 		timestamp := t
@@ -400,10 +557,24 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 
 		// Do the parsing with (hopefully) automatic type conversion
 		if err := rows.Scan(columnDataPtr...); err != nil {
-			return 0, err
+			return metrics, nil, err
 		}
 
 		for i, name := range columnNames {
+			if q.IncrementalColumn != "" && name == q.IncrementalColumn {
+				v, err := incrementalValue(columnData[i])
+				if err != nil {
+					return metrics, nil, fmt.Errorf("incremental column %q: %w", name, err)
+				}
+				if mark == nil {
+					mark = v
+				} else if c, err := compareValues(v, mark); err != nil {
+					return metrics, nil, fmt.Errorf("incremental column %q: %w", name, err)
+				} else if c > 0 {
+					mark = v
+				}
+			}
+
 			if q.MeasurementColumn != "" && name == q.MeasurementColumn {
 				switch raw := columnData[i].(type) {
 				case string:
@@ -411,7 +582,7 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 				case []byte:
 					measurement = string(raw)
 				default:
-					return 0, fmt.Errorf("measurement column type \"%T\" unsupported", columnData[i])
+					return metrics, nil, fmt.Errorf("measurement column type \"%T\" unsupported", columnData[i])
 				}
 			}
 
@@ -430,11 +601,11 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 				case fmt.Stringer:
 					fieldvalue = v.String()
 				default:
-					return 0, fmt.Errorf("time column %q of type \"%T\" unsupported", name, columnData[i])
+					return metrics, nil, fmt.Errorf("time column %q of type \"%T\" unsupported", name, columnData[i])
 				}
 				if !skipParsing {
 					if timestamp, err = internal.ParseTimestamp(q.TimeFormat, fieldvalue, nil); err != nil {
-						return 0, fmt.Errorf("parsing time failed: %w", err)
+						return metrics, nil, fmt.Errorf("parsing time failed: %w", err)
 					}
 				}
 			}
@@ -442,7 +613,7 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 			if q.tagFilter.Match(name) {
 				tagvalue, err := internal.ToString(columnData[i])
 				if err != nil {
-					return 0, fmt.Errorf("converting tag column %q failed: %w", name, err)
+					return metrics, nil, fmt.Errorf("converting tag column %q failed: %w", name, err)
 				}
 				if v := strings.TrimSpace(tagvalue); v != "" {
 					tags[name] = v
@@ -453,7 +624,7 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 			if q.fieldFilterFloat.Match(name) {
 				v, err := internal.ToFloat64(columnData[i])
 				if err != nil {
-					return 0, fmt.Errorf("converting field column %q to float failed: %w", name, err)
+					return metrics, nil, fmt.Errorf("converting field column %q to float failed: %w", name, err)
 				}
 				fields[name] = v
 				continue
@@ -463,7 +634,7 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 				v, err := internal.ToInt64(columnData[i])
 				if err != nil {
 					if !errors.Is(err, internal.ErrOutOfRange) {
-						return 0, fmt.Errorf("converting field column %q to int failed: %w", name, err)
+						return metrics, nil, fmt.Errorf("converting field column %q to int failed: %w", name, err)
 					}
 					logger.Warnf("field column %q: %v", name, err)
 				}
@@ -475,7 +646,7 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 				v, err := internal.ToUint64(columnData[i])
 				if err != nil {
 					if !errors.Is(err, internal.ErrOutOfRange) {
-						return 0, fmt.Errorf("converting field column %q to uint failed: %w", name, err)
+						return metrics, nil, fmt.Errorf("converting field column %q to uint failed: %w", name, err)
 					}
 					logger.Warnf("field column %q: %v", name, err)
 				}
@@ -486,7 +657,7 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 			if q.fieldFilterBool.Match(name) {
 				v, err := internal.ToBool(columnData[i])
 				if err != nil {
-					return 0, fmt.Errorf("converting field column %q to bool failed: %w", name, err)
+					return metrics, nil, fmt.Errorf("converting field column %q to bool failed: %w", name, err)
 				}
 				fields[name] = v
 				continue
@@ -495,7 +666,7 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 			if q.fieldFilterString.Match(name) {
 				v, err := internal.ToString(columnData[i])
 				if err != nil {
-					return 0, fmt.Errorf("converting field column %q to string failed: %w", name, err)
+					return metrics, nil, fmt.Errorf("converting field column %q to string failed: %w", name, err)
 				}
 				fields[name] = v
 				continue
@@ -516,22 +687,25 @@ func (q *query) parse(acc telegraf.Accumulator, rows *dbsql.Rows, t time.Time, l
 				case fmt.Stringer:
 					fieldvalue = v.String()
 				default:
-					return 0, fmt.Errorf("field column %q of type \"%T\" unsupported", name, columnData[i])
+					return metrics, nil, fmt.Errorf("field column %q of type \"%T\" unsupported", name, columnData[i])
 				}
 				if fieldvalue != nil {
 					fields[name] = fieldvalue
 				}
 			}
 		}
-		acc.AddFields(measurement, fields, tags, timestamp)
-		rowCount++
+		metrics = append(metrics, metric.New(measurement, tags, fields, timestamp))
 	}
 
 	if err := rows.Err(); err != nil {
-		return rowCount, err
+		return metrics, nil, err
+	}
+
+	if q.IncrementalColumn != "" && len(metrics) > 0 && mark == nil {
+		return metrics, nil, fmt.Errorf("incremental column %q not found in query result", q.IncrementalColumn)
 	}
 
-	return rowCount, nil
+	return metrics, mark, nil
 }
 
 func init() {
@@ -0,0 +1,158 @@
+//go:build !mips && !mipsle && !mips64 && !ppc64 && !riscv64 && !loong64 && !mips64le && !(windows && (386 || arm)) && !(freebsd && (386 || arm))
+
+package sql
+
+import (
+	dbsql "database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestSqliteIncremental(t *testing.T) {
+	dbfile := filepath.Join(t.TempDir(), "db")
+	db, err := dbsql.Open("sqlite", dbfile)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE events (id INTEGER, value REAL)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO events VALUES (1, 1.5), (2, 2.5)")
+	require.NoError(t, err)
+
+	newPlugin := func() *SQL {
+		return &SQL{
+			Driver: "sqlite",
+			Dsn:    config.NewSecret([]byte(dbfile)),
+			Queries: []query{
+				{
+					Query:                   "SELECT id, value FROM events WHERE id > ? ORDER BY id",
+					IncrementalColumn:       "id",
+					IncrementalInitialValue: "0",
+				},
+			},
+			Log: testutil.Logger{},
+		}
+	}
+
+	plugin := newPlugin()
+	require.NoError(t, plugin.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	// Rows are not queried again while undelivered
+	require.NoError(t, plugin.Gather(&acc))
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	expected := []telegraf.Metric{
+		metric.New("sql", map[string]string{}, map[string]interface{}{"id": int64(1), "value": 1.5}, time.Unix(0, 0)),
+		metric.New("sql", map[string]string{}, map[string]interface{}{"id": int64(2), "value": 2.5}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+
+	// Rejected rows are queried again
+	for _, m := range acc.GetTelegrafMetrics() {
+		m.Reject()
+	}
+	require.Eventually(t, func() bool {
+		plugin.incremental.Lock()
+		defer plugin.incremental.Unlock()
+		return !plugin.Queries[0].mark.pending
+	}, time.Second, 10*time.Millisecond)
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+
+	// Delivered rows advance the high-water mark
+	for _, m := range acc.GetTelegrafMetrics() {
+		m.Accept()
+	}
+	require.Eventually(t, func() bool {
+		plugin.incremental.Lock()
+		defer plugin.incremental.Unlock()
+		return !plugin.Queries[0].mark.pending
+	}, time.Second, 10*time.Millisecond)
+	state, ok := plugin.GetState().(map[string]persistedMark)
+	require.True(t, ok)
+	require.Equal(t, persistedMark{Type: "int", Value: "2"}, state[plugin.Queries[0].Query])
+
+	_, err = db.Exec("INSERT INTO events VALUES (3, 3.5)")
+	require.NoError(t, err)
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	expected = []telegraf.Metric{
+		metric.New("sql", map[string]string{}, map[string]interface{}{"id": int64(3), "value": 3.5}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+
+	// A restarted plugin continues from the persisted high-water mark
+	restarted := newPlugin()
+	require.NoError(t, restarted.Init())
+	require.NoError(t, restarted.SetState(state))
+	var racc testutil.Accumulator
+	require.NoError(t, restarted.Start(&racc))
+	defer restarted.Stop()
+	require.NoError(t, restarted.Gather(&racc))
+	require.Empty(t, racc.Errors)
+	testutil.RequireMetricsEqual(t, expected, racc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestIncrementalInitInvalid(t *testing.T) {
+	plugin := &SQL{
+		Driver: "sqlite",
+		Dsn:    config.NewSecret([]byte("file::memory:")),
+		Queries: []query{
+			{Query: "SELECT * FROM events WHERE id > ?", IncrementalColumn: "id"},
+		},
+		Log: testutil.Logger{},
+	}
+	require.ErrorContains(t, plugin.Init(), "'incremental_initial_value' required")
+
+	plugin.Queries[0].IncrementalInitialValue = "0"
+	plugin.Queries = append(plugin.Queries, plugin.Queries[0])
+	require.ErrorContains(t, plugin.Init(), "duplicate incremental query")
+}
+
+func TestPersistedMark(t *testing.T) {
+	for _, v := range []interface{}{
+		int64(-42),
+		uint64(42),
+		3.25,
+		"2024-01-01",
+		time.Date(2024, 5, 17, 22, 4, 45, 123, time.UTC),
+	} {
+		actual, err := newPersistedMark(v).value()
+		require.NoError(t, err)
+		require.Equal(t, v, actual)
+	}
+}
+
+func TestIncrementalStartFail(t *testing.T) {
+	plugin := &SQL{
+		Driver:                      "sqlite",
+		Dsn:                         config.NewSecret([]byte(filepath.Join(t.TempDir(), "missing", "db"))),
+		DisconnectedServersBehavior: "error",
+		Queries: []query{
+			{
+				Query:                   "SELECT id FROM events WHERE id > ?",
+				IncrementalColumn:       "id",
+				IncrementalInitialValue: "0",
+			},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	// No delivery tracking must be started if the connection fails
+	var acc testutil.Accumulator
+	require.Error(t, plugin.Start(&acc))
+	require.Nil(t, plugin.cancel)
+	require.Nil(t, plugin.acc)
+}
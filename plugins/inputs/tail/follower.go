@@ -0,0 +1,273 @@
+//go:build !solaris
+
+package tail
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/tail"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/influxdata/telegraf/plugins/common/encoding"
+)
+
+var errFingerprintIncomplete = errors.New("file too small for fingerprint")
+
+// Interval for checking files for new data, truncation and rotation
+var followerPollInterval = 250 * time.Millisecond
+
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// follower reads the lines of a file identified by a fingerprint of its
+// content and the file ID, i.e. device and inode. In contrast to the tail
+// library, the follower keeps reading a renamed or deleted file until its end
+// and reads compressed files once.
+type follower struct {
+	path        string
+	fingerprint string
+	identity    string
+	info        os.FileInfo
+	compressed  bool
+	decoder     *encoding.Decoder
+
+	file   *os.File
+	closer io.Closer
+	reader *bufio.Reader
+
+	// offset of the last line received in uncompressed bytes
+	offset   atomic.Int64
+	finished atomic.Bool
+
+	lines  chan *tail.Line
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// newFollower opens the file and computes the fingerprint of the first bytes
+// of the (uncompressed) content as well as the identity consisting of the
+// fingerprint and the file ID. If the file does not contain enough data
+// to compute the fingerprint, errFingerprintIncomplete is returned.
+func newFollower(path string, fingerprintSize int, decoder *encoding.Decoder) (*follower, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	f := &follower{
+		path:    path,
+		info:    info,
+		decoder: decoder,
+		file:    file,
+		lines:   make(chan *tail.Line),
+		done:    make(chan struct{}),
+	}
+
+	var rd io.Reader = file
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		r, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("creating gzip reader failed: %w", err)
+		}
+		rd, f.closer, f.compressed = r, r, true
+	case strings.HasSuffix(path, ".zst"):
+		r, err := zstd.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("creating zstd reader failed: %w", err)
+		}
+		rd, f.closer, f.compressed = r, r.IOReadCloser(), true
+	}
+	f.reader = bufio.NewReaderSize(rd, max(fingerprintSize, 64*1024))
+
+	buf, err := f.reader.Peek(fingerprintSize)
+	if err != nil {
+		f.close()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errFingerprintIncomplete
+		}
+		return nil, fmt.Errorf("reading fingerprint failed: %w", err)
+	}
+	sum := sha256.Sum256(buf)
+	f.fingerprint = hex.EncodeToString(sum[:])
+
+	// Files with the same content at the beginning, e.g. due to a common
+	// header, are distinguished by the file ID
+	id, err := fileID(file, info)
+	if err != nil {
+		f.close()
+		return nil, fmt.Errorf("determining file ID failed: %w", err)
+	}
+	f.identity = f.fingerprint + ":" + id
+
+	f.ctx, f.cancel = context.WithCancel(context.Background())
+	return f, nil
+}
+
+// identify returns the file ID of the file at the given path
+func identify(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	return fileID(file, info)
+}
+
+// seek positions the follower at the given offset in uncompressed bytes or
+// at the end of the file for negative offsets. Compressed files are read up
+// to the offset.
+func (f *follower) seek(offset int64) error {
+	if f.compressed {
+		var n int64
+		var err error
+		if offset < 0 {
+			n, err = io.Copy(io.Discard, f.reader)
+		} else {
+			n, err = io.CopyN(io.Discard, f.reader, offset)
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+		}
+		f.offset.Store(n)
+		return err
+	}
+
+	whence := io.SeekStart
+	if offset < 0 {
+		offset, whence = 0, io.SeekEnd
+	}
+	pos, err := f.file.Seek(offset, whence)
+	if err != nil {
+		return err
+	}
+	f.reader.Reset(f.file)
+	f.offset.Store(pos)
+	return nil
+}
+
+// run reads the lines of the file until the follower is stopped, the file
+// is truncated or the end of a renamed, deleted or compressed file is
+// reached. The lines channel is closed on return.
+func (f *follower) run() {
+	defer close(f.done)
+	defer close(f.lines)
+	defer f.close()
+
+	// Skip the byte-order mark at the beginning of the file
+	if f.offset.Load() == 0 {
+		if buf, err := f.reader.Peek(len(utf8BOM)); err == nil && bytes.Equal(buf, utf8BOM) {
+			if _, err := f.reader.Discard(len(utf8BOM)); err == nil {
+				f.offset.Store(int64(len(utf8BOM)))
+			}
+		}
+	}
+
+	ticker := time.NewTicker(followerPollInterval)
+	defer ticker.Stop()
+
+	var partial []byte
+	var detached bool
+	for {
+		data, err := f.reader.ReadBytes('\n')
+		partial = append(partial, data...)
+		if err == nil {
+			if !f.send(partial) {
+				return
+			}
+			partial = nil
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			f.sendError(err)
+			return
+		}
+
+		// Emit an incomplete last line of files not written anymore
+		if f.compressed || detached {
+			if len(partial) > 0 && !f.send(partial) {
+				return
+			}
+			f.finished.Store(true)
+			return
+		}
+
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Stop following truncated files, e.g. due to 'copytruncate' log
+		// rotation, as the new content is identified as a new file.
+		if info, err := f.file.Stat(); err == nil && info.Size() < f.offset.Load()+int64(len(partial)) {
+			return
+		}
+
+		// Read the remaining data of files renamed or deleted, e.g. due to
+		// log rotation, and stop at the end of the data
+		if info, err := os.Stat(f.path); err != nil || !os.SameFile(f.info, info) {
+			detached = true
+		}
+	}
+}
+
+func (f *follower) send(data []byte) bool {
+	text := string(bytes.TrimSuffix(data, []byte("\n")))
+	if f.decoder != nil {
+		if decoded, err := f.decoder.String(text); err == nil {
+			text = decoded
+		}
+	}
+
+	select {
+	case <-f.ctx.Done():
+		return false
+	case f.lines <- &tail.Line{Text: text, Time: time.Now()}:
+		f.offset.Add(int64(len(data)))
+		return true
+	}
+}
+
+func (f *follower) sendError(err error) {
+	select {
+	case <-f.ctx.Done():
+	case f.lines <- &tail.Line{Time: time.Now(), Err: err}:
+	}
+}
+
+func (f *follower) stop() {
+	f.cancel()
+	<-f.done
+}
+
+func (f *follower) close() {
+	if f.closer != nil {
+		f.closer.Close()
+	}
+	f.file.Close()
+}
@@ -0,0 +1,18 @@
+//go:build !solaris && !windows
+
+package tail
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// fileID returns the device and inode of the file
+func fileID(_ *os.File, info os.FileInfo) (string, error) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", fmt.Errorf("unexpected file information type %T", info.Sys())
+	}
+	return fmt.Sprintf("%x:%x", uint64(stat.Dev), uint64(stat.Ino)), nil //nolint:unconvert // Types differ between platforms
+}
@@ -46,6 +46,9 @@ type Tail struct {
 	MaxUndeliveredLines int      `toml:"max_undelivered_lines"`
 	CharacterEncoding   string   `toml:"character_encoding"`
 	PathTag             string   `toml:"path_tag"`
+	FileIdentity        string   `toml:"file_identity"`
+	FingerprintSize     int      `toml:"fingerprint_size"`
+	IngestCompressed    bool     `toml:"ingest_compressed"`
 
 	Filters      []string `toml:"filters"`
 	filterColors bool
@@ -68,6 +71,14 @@ type Tail struct {
 	decoder *encoding.Decoder
 
 	nomatch map[string]bool
+
+	// Followers of files identified by fingerprint and file ID, offsets are
+	// keyed by the identity of the followers in this mode
+	followers      map[*follower]bool
+	followersMutex sync.Mutex
+	completed      map[string]os.FileInfo
+	seen           map[string]string
+	initialized    bool
 }
 
 type empty struct{}
@@ -100,6 +111,34 @@ func (t *Tail) Init() error {
 		return fmt.Errorf("invalid 'initial_read_offset' setting %q", t.InitialReadOffset)
 	}
 
+	switch t.FileIdentity {
+	case "":
+		t.FileIdentity = "path"
+	case "path":
+	case "fingerprint":
+		if t.Pipe {
+			return errors.New("'fingerprint' file identity cannot be used with pipes")
+		}
+		// Offsets are tracked in bytes of the file, so only encodings not
+		// changing the line separators are supported
+		switch strings.ToLower(t.CharacterEncoding) {
+		case "", "utf-8", "utf8":
+		default:
+			return fmt.Errorf("'fingerprint' file identity cannot be used with character encoding %q", t.CharacterEncoding)
+		}
+		if t.FingerprintSize == 0 {
+			t.FingerprintSize = 256
+		}
+		if t.FingerprintSize < 0 {
+			return errors.New("'fingerprint_size' must be positive")
+		}
+		t.followers = make(map[*follower]bool)
+		t.completed = make(map[string]os.FileInfo)
+		t.seen = make(map[string]string)
+	default:
+		return fmt.Errorf("invalid 'file_identity' setting %q", t.FileIdentity)
+	}
+
 	if t.MaxUndeliveredLines == 0 {
 		return errors.New("max_undelivered_lines must be positive")
 	}
@@ -158,6 +197,7 @@ func (t *Tail) Start(acc telegraf.Accumulator) error {
 	if err != nil {
 		return err
 	}
+	t.initialized = true
 
 	// assumption that once Start is called, all parallel plugins have already been initialized
 	offsetsMutex.Lock()
@@ -216,6 +256,17 @@ func (t *Tail) Gather(_ telegraf.Accumulator) error {
 }
 
 func (t *Tail) Stop() {
+	// Stop the followers first, they record their offsets on exit
+	t.followersMutex.Lock()
+	followers := make([]*follower, 0, len(t.followers))
+	for f := range t.followers {
+		followers = append(followers, f)
+	}
+	t.followersMutex.Unlock()
+	for _, f := range followers {
+		f.stop()
+	}
+
 	t.tailersMutex.Lock()
 	defer t.tailersMutex.Unlock()
 
@@ -242,6 +293,16 @@ func (t *Tail) Stop() {
 	t.cancel()
 	t.wg.Wait()
 
+	// Only keep the offsets of files seen during this run to not
+	// accumulate fingerprints of rotated files removed in the meantime
+	if t.FileIdentity == "fingerprint" {
+		for k := range t.offsets {
+			if _, found := t.seen[k]; !found {
+				delete(t.offsets, k)
+			}
+		}
+	}
+
 	// persist offsets
 	offsetsMutex.Lock()
 	for k, v := range t.offsets {
@@ -305,6 +366,13 @@ func (t *Tail) tailNewFiles() error {
 			// Mark this file as currently being processed
 			currentFiles[file] = true
 
+			if t.FileIdentity == "fingerprint" {
+				if err := t.follow(file); err != nil {
+					return err
+				}
+				continue
+			}
+
 			// Check if we're already tailing this file
 			t.tailersMutex.RLock()
 			_, alreadyTailing := t.tailers[file]
@@ -358,7 +426,7 @@ func (t *Tail) tailNewFiles() error {
 
 			go func(tl *tail.Tail) {
 				defer t.wg.Done()
-				t.receiver(parser, tl)
+				t.receiver(parser, tl.Filename, tl.Lines, tl.Dying())
 
 				t.Log.Debugf("Tail removed for %q", tl.Filename)
 
@@ -377,9 +445,198 @@ func (t *Tail) tailNewFiles() error {
 	}
 
 	// Clean up tailers for files that are no longer being monitored
+	t.cleanupUnusedFollowers(currentFiles)
 	return t.cleanupUnusedTailers(currentFiles)
 }
 
+// follow starts a follower for the given file if the file is not followed
+// yet, e.g. under a previous name before rotation
+func (t *Tail) follow(file string) error {
+	info, err := os.Stat(file)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+
+	t.followersMutex.Lock()
+	for f := range t.followers {
+		if os.SameFile(f.info, info) {
+			t.followersMutex.Unlock()
+			return nil
+		}
+	}
+	done, completed := t.completed[file]
+	t.followersMutex.Unlock()
+
+	compressed := strings.HasSuffix(file, ".gz") || strings.HasSuffix(file, ".zst")
+	if compressed {
+		if !t.IngestCompressed {
+			return nil
+		}
+		// Compressed files are only read once
+		if completed && os.SameFile(done, info) && done.Size() == info.Size() && done.ModTime().Equal(info.ModTime()) {
+			return nil
+		}
+	}
+
+	f, err := newFollower(file, t.FingerprintSize, t.decoder)
+	if err != nil {
+		if errors.Is(err, errFingerprintIncomplete) {
+			t.Log.Tracef("Waiting for %q to reach fingerprint size", file)
+		} else {
+			t.Log.Debugf("Failed to open file (%s): %v", file, err)
+		}
+		return nil
+	}
+
+	offset, err := t.getFingerprintOffset(f)
+	if err != nil {
+		f.close()
+		return err
+	}
+	if err := f.seek(offset); err != nil {
+		f.close()
+		t.Log.Errorf("Seeking in %q failed: %v", file, err)
+		return nil
+	}
+
+	parser, err := t.parserFunc()
+	if err != nil {
+		f.close()
+		t.Log.Errorf("Creating parser: %v", err)
+		return nil
+	}
+
+	t.Log.Debugf("Following %q with fingerprint %s at offset %d", file, f.identity, f.offset.Load())
+
+	t.followersMutex.Lock()
+	t.followers[f] = true
+	t.seen[f.identity] = f.path
+	t.followersMutex.Unlock()
+
+	t.wg.Add(1)
+	go f.run()
+	go func() {
+		defer t.wg.Done()
+		t.receiver(parser, f.path, f.lines, f.ctx.Done())
+		f.stop()
+
+		t.Log.Debugf("Stopped following %q at offset %d", f.path, f.offset.Load())
+
+		t.followersMutex.Lock()
+		defer t.followersMutex.Unlock()
+		t.offsets[f.identity] = f.offset.Load()
+		if f.compressed && f.finished.Load() {
+			t.completed[f.path] = f.info
+		}
+		delete(t.followers, f)
+	}()
+
+	return nil
+}
+
+// getFingerprintOffset returns the offset to start reading the file at or a
+// negative value for the end of the file. Files appearing after startup,
+// e.g. due to rotation, are read from the beginning unless persisted.
+func (t *Tail) getFingerprintOffset(f *follower) (int64, error) {
+	t.followersMutex.Lock()
+	offset, found := t.offsets[f.identity]
+	candidates := make(map[string]int64)
+	if !found {
+		prefix := f.fingerprint + ":"
+		for k, v := range t.offsets {
+			if strings.HasPrefix(k, prefix) {
+				candidates[k] = v
+			}
+		}
+	}
+	t.followersMutex.Unlock()
+
+	// Files with the same fingerprint but a different file ID are either
+	// copies of a file, i.e. compressed rotated files or copies of truncated
+	// files, or different files with a common header. Only continue at the
+	// offset of the original file for copies and use the smallest offset if
+	// multiple files match to not lose any lines.
+	for k, v := range candidates {
+		if (f.compressed || t.truncated(k)) && (!found || v < offset) {
+			offset, found = v, true
+		}
+	}
+
+	if found && !f.compressed && offset > f.info.Size() {
+		t.Log.Warnf("Persisted offset %d exceeds size of %q, reading from the beginning", offset, f.path)
+		offset = 0
+	}
+
+	switch t.InitialReadOffset {
+	case "beginning":
+		return 0, nil
+	case "end":
+		if t.initialized {
+			return 0, nil
+		}
+		return -1, nil
+	case "saved-or-beginning":
+		if found {
+			return offset, nil
+		}
+		return 0, nil
+	case "saved-or-end":
+		if found {
+			return offset, nil
+		}
+		if t.initialized {
+			return 0, nil
+		}
+		return -1, nil
+	default:
+		return 0, errors.New("invalid 'initial_read_offset' setting")
+	}
+}
+
+// truncated returns true if the file with the given identity seen during
+// this run still exists but its content changed, e.g. due to truncation
+func (t *Tail) truncated(identity string) bool {
+	t.followersMutex.Lock()
+	path, found := t.seen[identity]
+	t.followersMutex.Unlock()
+	if !found {
+		return false
+	}
+
+	id, err := identify(path)
+	if err != nil || !strings.HasSuffix(identity, ":"+id) {
+		return false
+	}
+	f, err := newFollower(path, t.FingerprintSize, t.decoder)
+	if err != nil {
+		return errors.Is(err, errFingerprintIncomplete)
+	}
+	f.close()
+	return f.identity != identity
+}
+
+// cleanupUnusedFollowers stops the followers of files no longer matching the
+// patterns. Followers of renamed or deleted files are kept until reaching the
+// end of the file.
+func (t *Tail) cleanupUnusedFollowers(currentFiles map[string]bool) {
+	t.followersMutex.Lock()
+	var unused []*follower
+	for f := range t.followers {
+		if currentFiles[f.path] {
+			continue
+		}
+		if info, err := os.Stat(f.path); err == nil && os.SameFile(f.info, info) {
+			t.Log.Debugf("Removing follower for %q as it's no longer in the glob pattern", f.path)
+			unused = append(unused, f)
+		}
+	}
+	t.followersMutex.Unlock()
+
+	for _, f := range unused {
+		f.stop()
+	}
+}
+
 // cleanupUnusedTailers stops and removes tailers for files that are no longer being monitored.
 // It uses defer to ensure the mutex is always unlocked, even if errors occur.
 func (t *Tail) cleanupUnusedTailers(currentFiles map[string]bool) error {
@@ -431,7 +688,7 @@ func parseLine(parser telegraf.Parser, line string) ([]telegraf.Metric, error) {
 
 // receiver is launched as a goroutine to continuously watch a tailed logfile
 // for changes, parse any incoming messages, and add to the accumulator.
-func (t *Tail) receiver(parser telegraf.Parser, tailer *tail.Tail) {
+func (t *Tail) receiver(parser telegraf.Parser, filename string, lines <-chan *tail.Line, dying <-chan struct{}) {
 	// holds the individual lines of multi-line log entries.
 	var buffer bytes.Buffer
 
@@ -459,7 +716,7 @@ func (t *Tail) receiver(parser telegraf.Parser, tailer *tail.Tail) {
 		select {
 		case <-t.ctx.Done():
 			channelOpen = false
-		case line, tailerOpen = <-tailer.Lines:
+		case line, tailerOpen = <-lines:
 			if !tailerOpen {
 				channelOpen = false
 			}
@@ -489,7 +746,7 @@ func (t *Tail) receiver(parser telegraf.Parser, tailer *tail.Tail) {
 		}
 
 		if line != nil && line.Err != nil {
-			t.Log.Errorf("Tailing %q: %v", tailer.Filename, line.Err)
+			t.Log.Errorf("Tailing %q: %v", filename, line.Err)
 			continue
 		}
 
@@ -504,7 +761,7 @@ func (t *Tail) receiver(parser telegraf.Parser, tailer *tail.Tail) {
 		metrics, err := parseLine(parser, text)
 		if err != nil {
 			t.Log.Errorf("Malformed log line in %q: [%q]: %v",
-				tailer.Filename, text, err)
+				filename, text, err)
 			continue
 		}
 		if len(metrics) == 0 {
@@ -514,7 +771,7 @@ func (t *Tail) receiver(parser telegraf.Parser, tailer *tail.Tail) {
 		}
 		if t.PathTag != "" {
 			for _, metric := range metrics {
-				metric.AddTag(t.PathTag, tailer.Filename)
+				metric.AddTag(t.PathTag, filename)
 			}
 		}
 
@@ -537,7 +794,7 @@ func (t *Tail) receiver(parser telegraf.Parser, tailer *tail.Tail) {
 		// Tail is trying to close so drain the sem to allow the receiver
 		// to exit. This condition is hit when the tailer may have hit the
 		// maximum undelivered lines and is trying to close.
-		case <-tailer.Dying():
+		case <-dying:
 			<-t.sem
 		case t.sem <- empty{}:
 			t.acc.AddTrackingMetricGroup(metrics)
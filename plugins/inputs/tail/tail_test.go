@@ -1,6 +1,8 @@
 package tail
 
 import (
+	"bytes"
+	"compress/gzip"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/tail"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/require"
 
 	"github.com/influxdata/telegraf"
@@ -1341,3 +1344,218 @@ func TestTailCleanupGlobPatternChange(t *testing.T) {
 	tt2.tailersMutex.RUnlock()
 	require.True(t, hasFile2, "Expected to have tailer for .txt file")
 }
+
+func TestFingerprintInitInvalid(t *testing.T) {
+	plugin := newTestTail()
+	plugin.FileIdentity = "inode"
+	require.ErrorContains(t, plugin.Init(), "invalid 'file_identity' setting")
+
+	plugin = newTestTail()
+	plugin.FileIdentity = "fingerprint"
+	plugin.Pipe = true
+	require.ErrorContains(t, plugin.Init(), "cannot be used with pipes")
+
+	plugin = newTestTail()
+	plugin.FileIdentity = "fingerprint"
+	plugin.CharacterEncoding = "utf-16le"
+	require.ErrorContains(t, plugin.Init(), `cannot be used with character encoding "utf-16le"`)
+}
+
+func newFingerprintTail(t *testing.T, pattern string) *Tail {
+	t.Helper()
+
+	interval := followerPollInterval
+	followerPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { followerPollInterval = interval })
+
+	plugin := &Tail{
+		Files:               []string{pattern},
+		InitialReadOffset:   "saved-or-beginning",
+		FileIdentity:        "fingerprint",
+		FingerprintSize:     16,
+		IngestCompressed:    true,
+		MaxUndeliveredLines: 1000,
+		offsets:             make(map[string]int64),
+		Log:                 testutil.Logger{},
+	}
+	plugin.SetParserFunc(newInfluxParser)
+	require.NoError(t, plugin.Init())
+	return plugin
+}
+
+func requireFields(t *testing.T, acc *testutil.Accumulator, expected ...int64) {
+	t.Helper()
+
+	require.Eventuallyf(t, func() bool {
+		return acc.NMetrics() >= uint64(len(expected))
+	}, 3*time.Second, 10*time.Millisecond, "expected %d metrics but got %d", len(expected), acc.NMetrics())
+
+	// Wait some time to catch duplicates
+	time.Sleep(100 * time.Millisecond)
+	actual := make([]int64, 0, len(expected))
+	for _, m := range acc.GetTelegrafMetrics() {
+		v, found := m.GetField("value")
+		require.True(t, found)
+		actual = append(actual, v.(int64))
+	}
+	require.ElementsMatch(t, expected, actual)
+}
+
+func TestFingerprintRenamedFile(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "app.log")
+	require.NoError(t, os.WriteFile(fn, []byte("test value=1i\ntest value=2i\n"), 0600))
+
+	plugin := newFingerprintTail(t, filepath.Join(dir, "app.log*"))
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+	requireFields(t, &acc, 1, 2)
+
+	// Rotate the file and keep writing to the renamed file, as the
+	// application is not yet notified, before creating a new file
+	f, err := os.OpenFile(fn, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(fn, fn+".1"))
+	_, err = f.WriteString("test value=3i\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.NoError(t, os.WriteFile(fn, []byte("test value=4i\ntest value=5i\n"), 0600))
+
+	// Discover the new file as well as the renamed file several times
+	for range 3 {
+		require.NoError(t, plugin.Gather(&acc))
+		time.Sleep(50 * time.Millisecond)
+	}
+	requireFields(t, &acc, 1, 2, 3, 4, 5)
+}
+
+func TestFingerprintIdenticalHeaders(t *testing.T) {
+	dir := t.TempDir()
+	fnA := filepath.Join(dir, "a.log")
+	fnB := filepath.Join(dir, "b.log")
+
+	// Both files start with the same banner exceeding the fingerprint size
+	banner := "test value=0i,banner=1i\n"
+	require.NoError(t, os.WriteFile(fnA, []byte(banner+"test value=1i\ntest value=2i\n"), 0600))
+	require.NoError(t, os.WriteFile(fnB, []byte(banner+"test value=3i\n"), 0600))
+
+	plugin := newFingerprintTail(t, filepath.Join(dir, "*.log"))
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	requireFields(t, &acc, 0, 1, 2, 0, 3)
+	plugin.Stop()
+
+	// Each file must have its own offset
+	state, ok := plugin.GetState().(map[string]int64)
+	require.True(t, ok)
+	require.Len(t, state, 2)
+
+	// Append to both files and continue after a restart without duplicates
+	for fn, line := range map[string]string{fnA: "test value=4i\n", fnB: "test value=5i\n"} {
+		f, err := os.OpenFile(fn, os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = f.WriteString(line)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	plugin = newFingerprintTail(t, filepath.Join(dir, "*.log"))
+	require.NoError(t, plugin.SetState(state))
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+	requireFields(t, &acc, 4, 5)
+}
+
+func TestFingerprintCopyTruncate(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "app.log")
+	content := []byte("test value=1i\ntest value=2i\n")
+	require.NoError(t, os.WriteFile(fn, content, 0600))
+
+	plugin := newFingerprintTail(t, filepath.Join(dir, "app.log*"))
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+	requireFields(t, &acc, 1, 2)
+
+	// Copy the file and truncate the original one afterwards
+	require.NoError(t, os.WriteFile(fn+".1", content, 0600))
+	require.NoError(t, os.Truncate(fn, 0))
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, os.WriteFile(fn, []byte("test value=3i\ntest value=4i\n"), 0600))
+
+	for range 3 {
+		require.NoError(t, plugin.Gather(&acc))
+		time.Sleep(50 * time.Millisecond)
+	}
+	requireFields(t, &acc, 1, 2, 3, 4)
+}
+
+func TestFingerprintCompressedAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "app.log")
+	content := "test value=1i\ntest value=2i\n"
+	require.NoError(t, os.WriteFile(fn, []byte(content), 0600))
+
+	// Read the file and keep the state
+	plugin := newFingerprintTail(t, filepath.Join(dir, "app.log*"))
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	requireFields(t, &acc, 1, 2)
+	plugin.Stop()
+	state := plugin.GetState()
+	require.Len(t, state, 1)
+
+	// Append to the file, rotate and compress it while Telegraf is stopped
+	content += "test value=3i\n"
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, os.WriteFile(fn+".1.gz", buf.Bytes(), 0600))
+	require.NoError(t, os.Remove(fn))
+	require.NoError(t, os.WriteFile(fn, []byte("test value=4i\ntest value=5i\n"), 0600))
+
+	// Continue reading after the restart without duplicating lines
+	plugin = newFingerprintTail(t, filepath.Join(dir, "app.log*"))
+	require.NoError(t, plugin.SetState(state))
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+	for range 3 {
+		require.NoError(t, plugin.Gather(&acc))
+		time.Sleep(50 * time.Millisecond)
+	}
+	requireFields(t, &acc, 3, 4, 5)
+}
+
+func TestFingerprintCompressedOnce(t *testing.T) {
+	dir := t.TempDir()
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	content := encoder.EncodeAll([]byte("test value=1i\ntest value=2i\ntest value=3i"), nil)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.log.1.zst"), content, 0600))
+
+	plugin := newFingerprintTail(t, filepath.Join(dir, "app.log*"))
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+	for range 3 {
+		require.NoError(t, plugin.Gather(&acc))
+		time.Sleep(50 * time.Millisecond)
+	}
+	requireFields(t, &acc, 1, 2, 3)
+
+	// Compressed files are ignored if not enabled
+	plugin = newFingerprintTail(t, filepath.Join(dir, "app.log*"))
+	plugin.IngestCompressed = false
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+	require.NoError(t, plugin.Gather(&acc))
+	time.Sleep(100 * time.Millisecond)
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
@@ -0,0 +1,172 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/olivere/elastic"
+
+	"github.com/influxdata/telegraf/internal"
+)
+
+// document is a metric prepared for indexing
+type document struct {
+	index string
+	body  map[string]interface{}
+}
+
+// isRetryable returns true for statuses of bulk items indicating a temporary
+// failure where the document should be sent again
+func isRetryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// handleBulkFailures evaluates the result of each document of a bulk request.
+// Successfully indexed documents are accepted, documents failing temporarily
+// are kept for retrying them in the next write and all other documents,
+// e.g. failing due to mapping conflicts, are rejected. Rejected documents are
+// written to the reject index if configured. When creating documents, version
+// conflicts indicate that the document already exists, e.g. due to a retried
+// write, so those documents are accepted.
+func (a *Elasticsearch) handleBulkFailures(ctx context.Context, docs []document, res *elastic.BulkResponse) error {
+	writeErr := &internal.PartialWriteError{
+		MetricsAccept: make([]int, 0, len(docs)),
+	}
+
+	var retry int
+	var rejected []int
+	var failures []*elastic.BulkResponseItem
+	for i := range docs {
+		// Each response item is a map with the action as the single key
+		var item *elastic.BulkResponseItem
+		if i < len(res.Items) {
+			for _, v := range res.Items[i] {
+				item = v
+			}
+		}
+
+		switch {
+		case item == nil:
+			retry++
+		case item.Error == nil && item.Status < http.StatusMultipleChoices:
+			writeErr.MetricsAccept = append(writeErr.MetricsAccept, i)
+		case item.Status == http.StatusConflict && (a.UseOpTypeCreate || a.DataStream):
+			a.Log.Debugf("Document already exists in %q: %s", docs[i].index, errorReason(item))
+			writeErr.MetricsAccept = append(writeErr.MetricsAccept, i)
+		case isRetryable(item.Status):
+			a.Log.Debugf("Indexing document into %q failed temporarily with status %d: %s", docs[i].index, item.Status, errorReason(item))
+			retry++
+		default:
+			a.Log.Debugf("Indexing document into %q was rejected with status %d: %s", docs[i].index, item.Status, errorReason(item))
+			rejected = append(rejected, i)
+			failures = append(failures, item)
+		}
+	}
+
+	// Try to store the rejected documents in the reject index and accept them
+	// if this succeeded. If the reject index is not available, keep the
+	// documents to avoid losing them.
+	var delivered []bool
+	if a.RejectIndex != "" && len(rejected) > 0 {
+		var err error
+		delivered, err = a.writeRejects(ctx, docs, rejected, failures)
+		if err != nil {
+			a.Log.Errorf("Writing rejected documents to %q failed: %v", a.RejectIndex, err)
+			retry += len(rejected)
+			rejected, failures = nil, nil
+		}
+	}
+
+	var nrejected int
+	for j, i := range rejected {
+		if delivered != nil && delivered[j] {
+			writeErr.MetricsAccept = append(writeErr.MetricsAccept, i)
+			continue
+		}
+		nrejected++
+		writeErr.MetricsReject = append(writeErr.MetricsReject, i)
+		writeErr.MetricsRejectErrors = append(writeErr.MetricsRejectErrors, errors.New(errorReason(failures[j])))
+	}
+
+	if nrejected > 0 {
+		item := failures[len(failures)-1]
+		a.Log.Errorf("Elasticsearch rejected %d documents, last error status: %d, error: %s", nrejected, item.Status, errorReason(item))
+	}
+
+	if retry == 0 && nrejected == 0 {
+		return nil
+	}
+	writeErr.Err = fmt.Errorf("elasticsearch failed to index %d metrics, %d rejected and %d kept for retry", retry+nrejected, nrejected, retry)
+	return writeErr
+}
+
+// writeRejects writes the rejected documents to the reject index and returns
+// which of the documents were stored successfully
+func (a *Elasticsearch) writeRejects(ctx context.Context, docs []document, rejected []int, failures []*elastic.BulkResponseItem) ([]bool, error) {
+	bulkRequest := a.Client.Bulk()
+	for j, i := range rejected {
+		original, err := json.Marshal(docs[i].body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding document failed: %w", err)
+		}
+		failure := map[string]interface{}{
+			"status": failures[j].Status,
+			"reason": errorReason(failures[j]),
+		}
+		if failures[j].Error != nil {
+			failure["type"] = failures[j].Error.Type
+		}
+		doc := map[string]interface{}{
+			"@timestamp":       docs[i].body["@timestamp"],
+			"measurement_name": docs[i].body["measurement_name"],
+			"index":            docs[i].index,
+			"error":            failure,
+			"document":         string(original),
+		}
+
+		br := elastic.NewBulkIndexRequest().Index(a.RejectIndex).Doc(doc)
+		if a.UseOpTypeCreate || a.DataStream {
+			br.OpType("create")
+		}
+		if a.majorReleaseNumber <= 6 {
+			br.Type("metrics")
+		}
+		bulkRequest.Add(br)
+	}
+
+	res, err := bulkRequest.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	delivered := make([]bool, len(rejected))
+	for j := range rejected {
+		if !res.Errors {
+			delivered[j] = true
+			continue
+		}
+		if j >= len(res.Items) {
+			break
+		}
+		for _, item := range res.Items[j] {
+			delivered[j] = item.Error == nil && item.Status < http.StatusMultipleChoices
+			if !delivered[j] {
+				a.Log.Debugf("Writing rejected document to %q failed with status %d: %s", a.RejectIndex, item.Status, errorReason(item))
+			}
+		}
+	}
+	return delivered, nil
+}
+
+func errorReason(item *elastic.BulkResponseItem) string {
+	if item.Error == nil {
+		return http.StatusText(item.Status)
+	}
+	if reason, ok := item.Error.CausedBy["reason"]; ok {
+		return fmt.Sprintf("%s: %s (caused by %v)", item.Error.Type, item.Error.Reason, reason)
+	}
+	return fmt.Sprintf("%s: %s", item.Error.Type, item.Error.Reason)
+}
@@ -44,6 +44,8 @@ type Elasticsearch struct {
 	ManageTemplate      bool                   `toml:"manage_template"`
 	OverwriteTemplate   bool                   `toml:"overwrite_template"`
 	UseOpTypeCreate     bool                   `toml:"use_optype_create"`
+	DataStream          bool                   `toml:"data_stream"`
+	RejectIndex         string                 `toml:"reject_index"`
 	Username            config.Secret          `toml:"username"`
 	Password            config.Secret          `toml:"password"`
 	TemplateName        string                 `toml:"template_name"`
@@ -63,7 +65,11 @@ type Elasticsearch struct {
 
 const telegrafTemplate = `
 {
-	{{ if (lt .Version 6) }}
+	{{ if .DataStream }}
+	"index_patterns" : [ "{{.TemplatePattern}}" ],
+	"data_stream": {},
+	"template": {
+	{{ else if (lt .Version 6) }}
 	"template": "{{.TemplatePattern}}",
 	{{ else }}
 	"index_patterns" : [ "{{.TemplatePattern}}" ],
@@ -124,6 +130,9 @@ const telegrafTemplate = `
 		}
 		{{ end }}
 	}
+	{{ if .DataStream }}
+	}
+	{{ end }}
 }`
 
 const defaultTemplateIndexSettings = `
@@ -138,6 +147,7 @@ type templatePart struct {
 	TemplatePattern string
 	Version         int
 	IndexTemplate   string
+	DataStream      bool
 }
 
 func (*Elasticsearch) SampleConfig() string {
@@ -230,6 +240,11 @@ func (a *Elasticsearch) Connect() error {
 
 	a.Log.Infof("Elasticsearch version: %q", esVersion)
 
+	// Data streams and composable index templates require v7.9 or later
+	if a.DataStream && majorReleaseNumber < 7 {
+		return fmt.Errorf("data streams are not supported by elasticsearch version %s", esVersion)
+	}
+
 	a.Client = client
 	a.majorReleaseNumber = majorReleaseNumber
 
@@ -301,6 +316,7 @@ func (a *Elasticsearch) Write(metrics []telegraf.Metric) error {
 
 	bulkRequest := a.Client.Bulk()
 
+	docs := make([]document, 0, len(metrics))
 	for _, metric := range metrics {
 		var name = metric.Name()
 
@@ -335,8 +351,10 @@ func (a *Elasticsearch) Write(metrics []telegraf.Metric) error {
 		m[name] = fields
 
 		br := elastic.NewBulkIndexRequest().Index(indexName).Doc(m)
+		docs = append(docs, document{index: indexName, body: m})
 
-		if a.UseOpTypeCreate {
+		// Data streams only accept the "create" operation
+		if a.UseOpTypeCreate || a.DataStream {
 			br.OpType("create")
 		}
 
@@ -368,18 +386,7 @@ func (a *Elasticsearch) Write(metrics []telegraf.Metric) error {
 	}
 
 	if res.Errors {
-		for id, err := range res.Failed() {
-			a.Log.Errorf(
-				"Elasticsearch indexing failure, id: %d, status: %d, error: %s, caused by: %s, %s",
-				id,
-				err.Status,
-				err.Error.Reason,
-				err.Error.CausedBy["reason"],
-				err.Error.CausedBy["type"],
-			)
-			break
-		}
-		return fmt.Errorf("elasticsearch failed to index %d metrics", len(res.Failed()))
+		return a.handleBulkFailures(ctx, docs, res)
 	}
 
 	return nil
@@ -390,8 +397,7 @@ func (a *Elasticsearch) manageTemplate(ctx context.Context) error {
 		return errors.New("elasticsearch template_name configuration not defined")
 	}
 
-	templateExists, errExists := a.Client.IndexTemplateExists(a.TemplateName).Do(ctx)
-
+	templateExists, errExists := a.templateExists(ctx)
 	if errExists != nil {
 		return fmt.Errorf("elasticsearch template check failed, template name: %s, error: %w", a.TemplateName, errExists)
 	}
@@ -416,7 +422,16 @@ func (a *Elasticsearch) manageTemplate(ctx context.Context) error {
 			return err
 		}
 
-		_, errCreateTemplate := a.Client.IndexPutTemplate(a.TemplateName).BodyString(data.String()).Do(ctx)
+		var errCreateTemplate error
+		if a.DataStream {
+			_, errCreateTemplate = a.Client.PerformRequest(ctx, elastic.PerformRequestOptions{
+				Method: http.MethodPut,
+				Path:   "/_index_template/" + url.PathEscape(a.TemplateName),
+				Body:   data.String(),
+			})
+		} else {
+			_, errCreateTemplate = a.Client.IndexPutTemplate(a.TemplateName).BodyString(data.String()).Do(ctx)
+		}
 		if errCreateTemplate != nil {
 			return fmt.Errorf("elasticsearch failed to create index template %s: %w", a.TemplateName, errCreateTemplate)
 		}
@@ -428,6 +443,24 @@ func (a *Elasticsearch) manageTemplate(ctx context.Context) error {
 	return nil
 }
 
+// templateExists checks for a legacy index template or for a composable index
+// template when writing to data streams
+func (a *Elasticsearch) templateExists(ctx context.Context) (bool, error) {
+	if !a.DataStream {
+		return a.Client.IndexTemplateExists(a.TemplateName).Do(ctx)
+	}
+
+	res, err := a.Client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method:       http.MethodHead,
+		Path:         "/_index_template/" + url.PathEscape(a.TemplateName),
+		IgnoreErrors: []int{http.StatusNotFound},
+	})
+	if err != nil {
+		return false, err
+	}
+	return res.StatusCode == http.StatusOK, nil
+}
+
 func (a *Elasticsearch) createNewTemplate(templatePattern string) (*bytes.Buffer, error) {
 	var indexTemplate string
 	if a.IndexTemplate != nil {
@@ -444,6 +477,7 @@ func (a *Elasticsearch) createNewTemplate(templatePattern string) (*bytes.Buffer
 		TemplatePattern: templatePattern + "*",
 		Version:         a.majorReleaseNumber,
 		IndexTemplate:   indexTemplate,
+		DataStream:      a.DataStream,
 	}
 
 	t := template.Must(template.New("template").Parse(telegrafTemplate))
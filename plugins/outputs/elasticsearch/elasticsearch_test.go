@@ -3,11 +3,14 @@ package elasticsearch
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +19,7 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -840,6 +844,195 @@ func TestDifferentIndexSettings(t *testing.T) {
 	require.Equal(t, "best_compression", index["codec"])
 }
 
+func TestDataStreamTemplate(t *testing.T) {
+	e := &Elasticsearch{
+		TemplateName:       "test",
+		IndexName:          "telegraf",
+		DataStream:         true,
+		majorReleaseNumber: 8,
+		Log:                testutil.Logger{},
+	}
+	buf, err := e.createNewTemplate("telegraf")
+	require.NoError(t, err)
+
+	var actual struct {
+		IndexPatterns []string               `json:"index_patterns"`
+		DataStream    map[string]interface{} `json:"data_stream"`
+		Template      struct {
+			esTemplate
+			Mappings map[string]interface{} `json:"mappings"`
+		} `json:"template"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+	require.Equal(t, []string{"telegraf*"}, actual.IndexPatterns)
+	require.NotNil(t, actual.DataStream)
+	require.Equal(t, "10s", actual.Template.Settings.Index["refresh_interval"])
+	require.Contains(t, actual.Template.Mappings, "dynamic_templates")
+}
+
+func TestBulkFailures(t *testing.T) {
+	// The response contains a successful, a throttled, a rejected and a
+	// failed document in this order
+	response := `{"took": 3, "errors": true, "items": [
+		{"index": {"_index": "test", "status": 201}},
+		{"index": {"_index": "test", "status": 429, "error": {"type": "es_rejected_execution_exception", "reason": "queue full"}}},
+		{"index": {"_index": "test", "status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed to parse field [value]"}}},
+		{"index": {"_index": "test", "status": 503, "error": {"type": "unavailable_shards_exception", "reason": "primary shard is not active"}}}
+	]}`
+
+	tests := []struct {
+		name           string
+		rejectIndex    string
+		rejectResponse string
+		expectedAccept []int
+		expectedReject []int
+	}{
+		{
+			name:           "without reject index",
+			expectedAccept: []int{0},
+			expectedReject: []int{2},
+		},
+		{
+			name:           "with reject index",
+			rejectIndex:    "rejects",
+			rejectResponse: `{"took": 1, "errors": false, "items": [{"index": {"_index": "rejects", "status": 201}}]}`,
+			expectedAccept: []int{0, 2},
+		},
+		{
+			name:           "reject index failure",
+			rejectIndex:    "rejects",
+			rejectResponse: `{"took": 1, "errors": true, "items": [{"index": {"_index": "rejects", "status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed"}}}]}`,
+			expectedAccept: []int{0},
+			expectedReject: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rejected []string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/_bulk" {
+					if _, err := w.Write([]byte(`{"version": {"number": "7.10"}}`)); err != nil {
+						t.Error(err)
+					}
+					return
+				}
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					t.Error(err)
+					return
+				}
+				resp := response
+				if strings.Contains(string(body), `"_index":"rejects"`) {
+					rejected = append(rejected, string(body))
+					resp = tt.rejectResponse
+				}
+				if _, err := w.Write([]byte(resp)); err != nil {
+					t.Error(err)
+				}
+			}))
+			defer ts.Close()
+
+			e := &Elasticsearch{
+				URLs:        []string{"http://" + ts.Listener.Addr().String()},
+				IndexName:   "test",
+				RejectIndex: tt.rejectIndex,
+				Timeout:     config.Duration(time.Second * 5),
+				Log:         testutil.Logger{},
+			}
+			require.NoError(t, e.Connect())
+
+			metrics := []telegraf.Metric{
+				testutil.TestMetric(1.0),
+				testutil.TestMetric(2.0),
+				testutil.TestMetric("foo"),
+				testutil.TestMetric(4.0),
+			}
+			err := e.Write(metrics)
+
+			var writeErr *internal.PartialWriteError
+			require.True(t, errors.As(err, &writeErr))
+			require.Equal(t, tt.expectedAccept, writeErr.MetricsAccept)
+			require.Equal(t, tt.expectedReject, writeErr.MetricsReject)
+			require.Len(t, writeErr.MetricsRejectErrors, len(tt.expectedReject))
+
+			if tt.rejectIndex == "" {
+				require.Empty(t, rejected)
+				return
+			}
+			require.Len(t, rejected, 1)
+			require.Contains(t, rejected[0], `"index":"test"`)
+			require.Contains(t, rejected[0], `"reason":"mapper_parsing_exception: failed to parse field [value]"`)
+			require.Contains(t, rejected[0], `"document":"{`)
+		})
+	}
+}
+
+func TestBulkConflictCreate(t *testing.T) {
+	// The second document already exists in the index
+	response := `{"took": 3, "errors": true, "items": [
+		{"create": {"_index": "test", "status": 201}},
+		{"create": {"_index": "test", "status": 409, "error": {"type": "version_conflict_engine_exception", "reason": "document already exists"}}}
+	]}`
+
+	tests := []struct {
+		name           string
+		opTypeCreate   bool
+		expectedAccept []int
+		expectedReject []int
+	}{
+		{
+			name:           "index operation",
+			expectedAccept: []int{0},
+			expectedReject: []int{1},
+		},
+		{
+			name:         "create operation",
+			opTypeCreate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				resp := `{"version": {"number": "7.10"}}`
+				if r.URL.Path == "/_bulk" {
+					resp = response
+				}
+				if _, err := w.Write([]byte(resp)); err != nil {
+					t.Error(err)
+				}
+			}))
+			defer ts.Close()
+
+			e := &Elasticsearch{
+				URLs:            []string{"http://" + ts.Listener.Addr().String()},
+				IndexName:       "test",
+				UseOpTypeCreate: tt.opTypeCreate,
+				Timeout:         config.Duration(time.Second * 5),
+				Log:             testutil.Logger{},
+			}
+			require.NoError(t, e.Connect())
+
+			metrics := []telegraf.Metric{
+				testutil.TestMetric(1.0),
+				testutil.TestMetric(2.0),
+			}
+			err := e.Write(metrics)
+			if len(tt.expectedReject) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var writeErr *internal.PartialWriteError
+			require.True(t, errors.As(err, &writeErr))
+			require.Equal(t, tt.expectedAccept, writeErr.MetricsAccept)
+			require.Equal(t, tt.expectedReject, writeErr.MetricsReject)
+		})
+	}
+}
+
 func TestProcessHeaders(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -3,16 +3,22 @@ package execd
 
 import (
 	"bufio"
+	"bytes"
 	_ "embed"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/internal/process"
+	"github.com/influxdata/telegraf/plugins/common/shim/protocol"
 	"github.com/influxdata/telegraf/plugins/outputs"
 )
 
@@ -25,10 +31,17 @@ type Execd struct {
 	RestartDelay             config.Duration `toml:"restart_delay"`
 	IgnoreSerializationError bool            `toml:"ignore_serialization_error"`
 	UseBatchFormat           bool            `toml:"use_batch_format"`
+	UseAcknowledgements      bool            `toml:"use_acknowledgements"`
+	AcknowledgementTimeout   config.Duration `toml:"acknowledgement_timeout"`
 	Log                      telegraf.Logger
 
 	process    *process.Process
 	serializer telegraf.Serializer
+
+	// State of the batches waiting for acknowledgement
+	batchID atomic.Uint64
+	pending map[uint64]chan *protocol.Acknowledgement
+	sync.Mutex
 }
 
 func (*Execd) SampleConfig() string {
@@ -55,6 +68,11 @@ func (e *Execd) Init() error {
 	e.process.ReadStdoutFn = e.cmdReadOut
 	e.process.ReadStderrFn = e.cmdReadErr
 
+	if e.AcknowledgementTimeout <= 0 {
+		e.AcknowledgementTimeout = config.Duration(30 * time.Second)
+	}
+	e.pending = make(map[uint64]chan *protocol.Acknowledgement)
+
 	return nil
 }
 
@@ -79,6 +97,10 @@ func (e *Execd) Close() error {
 }
 
 func (e *Execd) Write(metrics []telegraf.Metric) error {
+	if e.UseAcknowledgements {
+		return e.writeAcknowledged(metrics)
+	}
+
 	if e.UseBatchFormat {
 		b, err := e.serializer.SerializeBatch(metrics)
 		if err != nil {
@@ -107,6 +129,107 @@ func (e *Execd) Write(metrics []telegraf.Metric) error {
 	return nil
 }
 
+// writeAcknowledged sends the metrics framed as a batch and waits for the
+// process to acknowledge the batch
+func (e *Execd) writeAcknowledged(metrics []telegraf.Metric) error {
+	// Serialize the metrics remembering the index in the batch of each
+	// metric sent to the process
+	var payload []byte
+	sent := make([]int, 0, len(metrics))
+	var skipped []int
+	if e.UseBatchFormat {
+		b, err := e.serializer.SerializeBatch(metrics)
+		if err != nil {
+			return fmt.Errorf("error serializing metrics: %w", err)
+		}
+		payload = b
+		for i := range metrics {
+			sent = append(sent, i)
+		}
+	} else {
+		for i, m := range metrics {
+			b, err := e.serializer.Serialize(m)
+			if err != nil {
+				if !e.IgnoreSerializationError {
+					return fmt.Errorf("error serializing metrics: %w", err)
+				}
+				e.Log.Errorf("Skipping metric due to a serialization error: %v", err)
+				skipped = append(skipped, i)
+				continue
+			}
+			payload = append(payload, b...)
+			sent = append(sent, i)
+		}
+	}
+
+	id := e.batchID.Add(1)
+	ch := make(chan *protocol.Acknowledgement, 1)
+	e.Lock()
+	e.pending[id] = ch
+	e.Unlock()
+	defer func() {
+		e.Lock()
+		delete(e.pending, id)
+		e.Unlock()
+	}()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %d\n", protocol.BatchStartMarker, id)
+	buf.Write(payload)
+	if len(payload) > 0 && payload[len(payload)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	fmt.Fprintf(&buf, "%s %d\n", protocol.BatchEndMarker, id)
+	if _, err := e.process.Stdin.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("error writing metrics: %w", err)
+	}
+
+	timeout := time.Duration(e.AcknowledgementTimeout)
+	select {
+	case ack := <-ch:
+		return e.evaluate(ack, sent, skipped)
+	case <-time.After(timeout):
+		return fmt.Errorf("batch %d not acknowledged within %s", id, timeout)
+	}
+}
+
+// evaluate converts the acknowledgement of the process into the result of
+// the write
+func (e *Execd) evaluate(ack *protocol.Acknowledgement, sent, skipped []int) error {
+	var err error
+	rejected := skipped
+	switch ack.Status {
+	case protocol.StatusAck:
+		if len(rejected) == 0 {
+			return nil
+		}
+		err = errors.New("metrics skipped due to serialization errors")
+	case protocol.StatusNack:
+		return fmt.Errorf("batch %d not acknowledged: %s", ack.ID, ack.Message)
+	case protocol.StatusReject:
+		for _, idx := range ack.Rejected {
+			if idx >= len(sent) {
+				e.Log.Warnf("Ignoring rejection of unknown metric %d in batch %d", idx, ack.ID)
+				continue
+			}
+			rejected = append(rejected, sent[idx])
+		}
+		err = fmt.Errorf("%d metrics rejected in batch %d: %s", len(rejected), ack.ID, ack.Message)
+	}
+
+	accepted := make([]int, 0, len(sent))
+	for _, idx := range sent {
+		if !slices.Contains(rejected, idx) {
+			accepted = append(accepted, idx)
+		}
+	}
+	return &internal.PartialWriteError{
+		Err:           err,
+		MetricsAccept: accepted,
+		MetricsReject: rejected,
+	}
+}
+
 func (e *Execd) cmdReadErr(out io.Reader) {
 	scanner := bufio.NewScanner(out)
 
@@ -123,8 +246,33 @@ func (e *Execd) cmdReadOut(out io.Reader) {
 	scanner := bufio.NewScanner(out)
 
 	for scanner.Scan() {
-		e.Log.Info(scanner.Text())
+		line := scanner.Text()
+		if e.UseAcknowledgements {
+			ack, ok, err := protocol.ParseAcknowledgement(line)
+			if err != nil {
+				e.Log.Errorf("Invalid acknowledgement %q: %v", line, err)
+				continue
+			}
+			if ok {
+				e.acknowledge(ack)
+				continue
+			}
+		}
+		e.Log.Info(line)
+	}
+}
+
+func (e *Execd) acknowledge(ack *protocol.Acknowledgement) {
+	e.Lock()
+	ch, found := e.pending[ack.ID]
+	delete(e.pending, ack.ID)
+	e.Unlock()
+
+	if !found {
+		e.Log.Debugf("Ignoring acknowledgement for unknown batch %d", ack.ID)
+		return
 	}
+	ch <- ack
 }
 
 func init() {
@@ -17,7 +17,9 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/shim"
 	"github.com/influxdata/telegraf/plugins/parsers/influx"
 	serializers_influx "github.com/influxdata/telegraf/plugins/serializers/influx"
 	"github.com/influxdata/telegraf/testutil"
@@ -201,6 +203,99 @@ func TestPartiallyUnserializableCanBeSkipped(t *testing.T) {
 	require.NoError(t, e.Close())
 }
 
+func TestAcknowledgements(t *testing.T) {
+	serializer := &serializers_influx.Serializer{}
+	require.NoError(t, serializer.Init())
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	e := &Execd{
+		Command:                  []string{exe, "-testoutput"},
+		Environment:              []string{"PLUGINS_OUTPUTS_EXECD_MODE=shim"},
+		RestartDelay:             config.Duration(5 * time.Second),
+		IgnoreSerializationError: true,
+		UseAcknowledgements:      true,
+		AcknowledgementTimeout:   config.Duration(5 * time.Second),
+		serializer:               serializer,
+		Log:                      testutil.Logger{},
+	}
+	require.NoError(t, e.Init())
+	require.NoError(t, e.Connect())
+	defer e.Close()
+
+	accepted := metric.New(
+		"cpu",
+		map[string]string{"name": "cpu1"},
+		map[string]interface{}{"idle": 50, "sys": 30},
+		now,
+	)
+	rejected := metric.New(
+		"cpu",
+		map[string]string{"name": "cpu2", "reject": "true"},
+		map[string]interface{}{"idle": 50, "sys": 30},
+		now,
+	)
+	unserializable := metric.New(
+		"cpu",
+		map[string]string{"name": "cpu3"},
+		map[string]interface{}{},
+		now,
+	)
+
+	// A fully accepted batch is acknowledged
+	require.NoError(t, e.Write([]telegraf.Metric{accepted, accepted}))
+
+	// Rejected metrics are reported using the index in the original batch
+	// including the skipped unserializable metrics
+	err = e.Write([]telegraf.Metric{accepted, unserializable, accepted, rejected})
+	var writeErr *internal.PartialWriteError
+	require.ErrorAs(t, err, &writeErr)
+	require.Equal(t, []int{0, 2}, writeErr.MetricsAccept)
+	require.ElementsMatch(t, []int{1, 3}, writeErr.MetricsReject)
+
+	// Failing writes are not acknowledged and should be retried
+	failing := metric.New(
+		"cpu",
+		map[string]string{"name": "cpu1", "fail": "true"},
+		map[string]interface{}{"idle": 50, "sys": 30},
+		now,
+	)
+	err = e.Write([]telegraf.Metric{accepted, failing})
+	require.ErrorContains(t, err, "not acknowledged: write failed")
+	require.NotErrorAs(t, err, &writeErr)
+}
+
+func TestAcknowledgementTimeout(t *testing.T) {
+	serializer := &serializers_influx.Serializer{}
+	require.NoError(t, serializer.Init())
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	// The consumer program does not speak the acknowledgement protocol
+	e := &Execd{
+		Command:                []string{exe, "-testoutput"},
+		Environment:            []string{"PLUGINS_OUTPUTS_EXECD_MODE=application", "METRIC_NAME=cpu", "METRIC_NUM=1"},
+		RestartDelay:           config.Duration(5 * time.Second),
+		UseAcknowledgements:    true,
+		AcknowledgementTimeout: config.Duration(100 * time.Millisecond),
+		serializer:             serializer,
+		Log:                    testutil.Logger{},
+	}
+	require.NoError(t, e.Init())
+	require.NoError(t, e.Connect())
+	defer e.Close()
+
+	m := metric.New(
+		"cpu",
+		map[string]string{"name": "cpu1"},
+		map[string]interface{}{"idle": 50, "sys": 30},
+		now,
+	)
+	require.ErrorContains(t, e.Write([]telegraf.Metric{m}), "not acknowledged within 100ms")
+}
+
 var testoutput = flag.Bool("testoutput", false,
 	"if true, act like line input program instead of test")
 
@@ -211,6 +306,10 @@ func TestMain(m *testing.M) {
 		runOutputConsumerProgram()
 		os.Exit(0)
 	}
+	if *testoutput && runMode == "shim" {
+		runShimProgram()
+		os.Exit(0)
+	}
 	code := m.Run()
 	os.Exit(code)
 }
@@ -262,3 +361,55 @@ func runOutputConsumerProgram() {
 		os.Exit(1)
 	}
 }
+
+func runShimProgram() {
+	s := shim.New()
+	if err := s.AddOutput(&rejectingOutput{}); err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // error code is important for this "test"
+		os.Exit(1)
+	}
+	if err := s.RunOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // error code is important for this "test"
+		os.Exit(1)
+	}
+}
+
+// rejectingOutput rejects metrics with a "reject" tag and fails for metrics
+// with a "fail" tag
+type rejectingOutput struct{}
+
+func (*rejectingOutput) SampleConfig() string {
+	return ""
+}
+
+func (*rejectingOutput) Connect() error {
+	return nil
+}
+
+func (*rejectingOutput) Close() error {
+	return nil
+}
+
+func (*rejectingOutput) Write(metrics []telegraf.Metric) error {
+	var accept, reject []int
+	for i, m := range metrics {
+		if m.HasTag("fail") {
+			return errors.New("write failed")
+		}
+		if m.HasTag("reject") {
+			reject = append(reject, i)
+		} else {
+			accept = append(accept, i)
+		}
+	}
+	if len(reject) == 0 {
+		return nil
+	}
+	return &internal.PartialWriteError{
+		Err:           errors.New("rejected"),
+		MetricsAccept: accept,
+		MetricsReject: reject,
+	}
+}
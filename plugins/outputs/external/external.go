@@ -0,0 +1,118 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package external
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin/pluginv1"
+	"github.com/influxdata/telegraf/plugins/outputs"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type External struct {
+	grpcplugin.Config
+	Timeout config.Duration `toml:"timeout"`
+	Log     telegraf.Logger `toml:"-"`
+
+	client *grpcplugin.Client
+	output pluginv1.OutputClient
+}
+
+func (*External) SampleConfig() string {
+	return sampleConfig
+}
+
+func (e *External) Init() error {
+	if len(e.Command) == 0 {
+		return errors.New("no command specified")
+	}
+	return nil
+}
+
+func (e *External) Connect() error {
+	client, err := e.Config.StartClient(pluginv1.PluginType_PLUGIN_TYPE_OUTPUT, e.Log)
+	if err != nil {
+		return err
+	}
+	output := pluginv1.NewOutputClient(client.Conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+	if _, err := output.Connect(ctx, &pluginv1.ConnectRequest{}); err != nil {
+		client.Stop()
+		return &internal.StartupError{Err: err, Retry: true}
+	}
+	e.client = client
+	e.output = output
+	return nil
+}
+
+func (e *External) Write(metrics []telegraf.Metric) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	req := &pluginv1.WriteRequest{Metrics: grpcplugin.ToProtoMetrics(metrics)}
+	resp, err := e.output.Write(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.GetError() == "" {
+		return nil
+	}
+
+	// Partial write with the metrics not mentioned being kept for later
+	writeErr := &internal.PartialWriteError{
+		Err:           errors.New(resp.GetError()),
+		MetricsAccept: make([]int, 0, len(resp.GetAccepted())),
+		MetricsReject: make([]int, 0, len(resp.GetRejected())),
+	}
+	for _, idx := range resp.GetAccepted() {
+		if int(idx) >= len(metrics) {
+			e.Log.Warnf("Ignoring acceptance of unknown metric %d", idx)
+			continue
+		}
+		writeErr.MetricsAccept = append(writeErr.MetricsAccept, int(idx))
+	}
+	for _, idx := range resp.GetRejected() {
+		if int(idx) >= len(metrics) {
+			e.Log.Warnf("Ignoring rejection of unknown metric %d", idx)
+			continue
+		}
+		writeErr.MetricsReject = append(writeErr.MetricsReject, int(idx))
+	}
+	return writeErr
+}
+
+func (e *External) Close() error {
+	if e.client == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	_, err := e.output.Close(ctx, &pluginv1.CloseRequest{})
+	e.client.Stop()
+	return err
+}
+
+func init() {
+	outputs.Add("external", func() telegraf.Output {
+		return &External{
+			Config: grpcplugin.Config{
+				RestartDelay: config.Duration(10 * time.Second),
+				StartTimeout: config.Duration(10 * time.Second),
+			},
+			Timeout: config.Duration(5 * time.Second),
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package external
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/shim"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+var externalPlugin = flag.Bool("external-plugin", false, "if true, act like an external plugin instead of test")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *externalPlugin {
+		runExternalPlugin()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestWrite(t *testing.T) {
+	plugin := newTestPlugin(t)
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	metrics := []telegraf.Metric{
+		metric.New("cpu", nil, map[string]interface{}{"value": 42}, time.Unix(0, 0), telegraf.Counter),
+		metric.New("mem", nil, map[string]interface{}{"value": 23}, time.Unix(0, 0), telegraf.Gauge),
+	}
+	require.NoError(t, plugin.Write(metrics))
+}
+
+func TestWritePartial(t *testing.T) {
+	plugin := newTestPlugin(t)
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	// The plugin rejects metrics named "reject" and requires metrics to be
+	// typed to check the value type is kept
+	metrics := []telegraf.Metric{
+		metric.New("cpu", nil, map[string]interface{}{"value": 42}, time.Unix(0, 0), telegraf.Counter),
+		metric.New("reject", nil, map[string]interface{}{"value": 1}, time.Unix(0, 0), telegraf.Gauge),
+		metric.New("untyped", nil, map[string]interface{}{"value": 1}, time.Unix(0, 0)),
+	}
+	err := plugin.Write(metrics)
+	require.ErrorContains(t, err, "2 metrics rejected")
+
+	var writeErr *internal.PartialWriteError
+	require.ErrorAs(t, err, &writeErr)
+	require.Equal(t, []int{0}, writeErr.MetricsAccept)
+	require.Equal(t, []int{1, 2}, writeErr.MetricsReject)
+}
+
+func TestWriteFailure(t *testing.T) {
+	plugin := newTestPlugin(t)
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	metrics := []telegraf.Metric{
+		metric.New("fail", nil, map[string]interface{}{"value": 1}, time.Unix(0, 0), telegraf.Gauge),
+	}
+	err := plugin.Write(metrics)
+	require.ErrorContains(t, err, "write failed")
+
+	var writeErr *internal.PartialWriteError
+	require.NotErrorAs(t, err, &writeErr)
+}
+
+func newTestPlugin(t *testing.T) *External {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	return &External{
+		Config: grpcplugin.Config{
+			Command:      []string{exe, "-external-plugin"},
+			RestartDelay: config.Duration(100 * time.Millisecond),
+			StartTimeout: config.Duration(5 * time.Second),
+		},
+		Timeout: config.Duration(5 * time.Second),
+		Log:     testutil.Logger{},
+	}
+}
+
+func runExternalPlugin() {
+	s := shim.New()
+	err := s.AddOutput(&testOutput{})
+	if err == nil {
+		err = s.Run(shim.PollIntervalDisabled)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // error code is important for this "test"
+		os.Exit(1)
+	}
+}
+
+type testOutput struct{}
+
+func (*testOutput) SampleConfig() string {
+	return ""
+}
+
+func (*testOutput) Connect() error {
+	return nil
+}
+
+func (*testOutput) Close() error {
+	return nil
+}
+
+func (*testOutput) Write(metrics []telegraf.Metric) error {
+	var accepted, rejected []int
+	for i, m := range metrics {
+		switch {
+		case m.Name() == "fail":
+			return errors.New("write failed")
+		case m.Name() == "reject", m.Type() == telegraf.Untyped:
+			rejected = append(rejected, i)
+		default:
+			accepted = append(accepted, i)
+		}
+	}
+	if len(rejected) == 0 {
+		return nil
+	}
+	return &internal.PartialWriteError{
+		Err:           fmt.Errorf("%d metrics rejected", len(rejected)),
+		MetricsAccept: accepted,
+		MetricsReject: rejected,
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/internal/rotate"
+	"github.com/influxdata/telegraf/plugins/common/partition"
 	"github.com/influxdata/telegraf/plugins/outputs"
 )
 
@@ -26,12 +27,26 @@ type File struct {
 	UseBatchFormat       bool            `toml:"use_batch_format"`
 	CompressionAlgorithm string          `toml:"compression_algorithm"`
 	CompressionLevel     int             `toml:"compression_level"`
+	MaxOpenFiles         int             `toml:"max_open_files"`
 	Log                  telegraf.Logger `toml:"-"`
 
 	encoder    internal.ContentEncoder
 	writer     io.Writer
 	closers    []io.Closer
 	serializer telegraf.Serializer
+
+	files      []string
+	templates  []*partition.Template
+	partitions *partition.Pool[nopCloser]
+}
+
+// nopCloser passes the serialized metrics through to the partition file
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
 }
 
 func (*File) SampleConfig() string {
@@ -48,6 +63,26 @@ func (f *File) Init() error {
 		f.Files = []string{"stdout"}
 	}
 
+	// Files containing template actions are partitioned per metric
+	f.files = make([]string, 0, len(f.Files))
+	for _, file := range f.Files {
+		if !partition.IsTemplate(file) {
+			f.files = append(f.files, file)
+			continue
+		}
+		tmpl, err := partition.NewTemplate(file)
+		if err != nil {
+			return fmt.Errorf("invalid file template %q: %w", file, err)
+		}
+		f.templates = append(f.templates, tmpl)
+	}
+	if len(f.templates) > 0 && f.RotationMaxArchives != 0 {
+		f.Log.Warn("Setting 'rotation_max_archives' has no effect on templated files")
+	}
+	if f.MaxOpenFiles < 0 {
+		return fmt.Errorf("invalid 'max_open_files' %d", f.MaxOpenFiles)
+	}
+
 	var options []internal.EncodingOption
 	if f.CompressionAlgorithm == "" {
 		f.CompressionAlgorithm = "identity"
@@ -64,7 +99,7 @@ func (f *File) Init() error {
 func (f *File) Connect() error {
 	var writers []io.Writer
 
-	for _, file := range f.Files {
+	for _, file := range f.files {
 		if file == "stdout" {
 			writers = append(writers, os.Stdout)
 		} else {
@@ -79,6 +114,13 @@ func (f *File) Connect() error {
 		}
 	}
 	f.writer = io.MultiWriter(writers...)
+
+	f.partitions = &partition.Pool[nopCloser]{
+		MaxOpen:  f.MaxOpenFiles,
+		Interval: time.Duration(f.RotationInterval),
+		MaxSize:  int64(f.RotationMaxSize),
+		Append:   true,
+	}
 	return nil
 }
 
@@ -90,10 +132,63 @@ func (f *File) Close() error {
 			err = errClose
 		}
 	}
+	if f.partitions != nil {
+		if errClose := f.partitions.Close(); errClose != nil {
+			err = errClose
+		}
+	}
 	return err
 }
 
 func (f *File) Write(metrics []telegraf.Metric) error {
+	if len(f.files) > 0 {
+		if err := f.write(f.writer, metrics); err != nil {
+			return err
+		}
+	}
+	return f.writePartitions(metrics)
+}
+
+// writePartitions writes the metrics to the files generated by the templates
+// for each metric
+func (f *File) writePartitions(metrics []telegraf.Metric) error {
+	if len(f.templates) == 0 {
+		return nil
+	}
+
+	// Group the metrics by partition keeping the order of the metrics
+	var paths []string
+	groups := make(map[string][]telegraf.Metric)
+	for _, tmpl := range f.templates {
+		for _, m := range metrics {
+			path, err := tmpl.Path(m)
+			if err != nil {
+				f.Log.Errorf("Could not determine file for metric %q: %v", m.Name(), err)
+				continue
+			}
+			if _, found := groups[path]; !found {
+				paths = append(paths, path)
+			}
+			groups[path] = append(groups[path], m)
+		}
+	}
+
+	open := func(w io.Writer) (nopCloser, error) {
+		return nopCloser{w}, nil
+	}
+	for _, path := range paths {
+		w, err := f.partitions.Get(path, open)
+		if err != nil {
+			return err
+		}
+		if err := f.write(w, groups[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *File) write(writer io.Writer, metrics []telegraf.Metric) error {
 	var writeErr error
 
 	if f.UseBatchFormat {
@@ -107,7 +202,7 @@ func (f *File) Write(metrics []telegraf.Metric) error {
 			f.Log.Errorf("Could not compress metrics: %v", err)
 		}
 
-		_, err = f.writer.Write(octets)
+		_, err = writer.Write(octets)
 		if err != nil {
 			f.Log.Errorf("Error writing to file: %v", err)
 		}
@@ -123,7 +218,7 @@ func (f *File) Write(metrics []telegraf.Metric) error {
 				f.Log.Errorf("Could not compress metrics: %v", err)
 			}
 
-			_, err = f.writer.Write(b)
+			_, err = writer.Write(b)
 			if err != nil {
 				writeErr = fmt.Errorf("failed to write message: %w", err)
 			}
@@ -137,6 +232,7 @@ func init() {
 	outputs.Add("file", func() telegraf.Output {
 		return &File{
 			CompressionLevel: -1,
+			MaxOpenFiles:     16,
 		}
 	})
 }
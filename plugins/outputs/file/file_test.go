@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/serializers/influx"
 	"github.com/influxdata/telegraf/testutil"
 )
@@ -344,3 +349,38 @@ func validateZlibCompressedFile(t *testing.T, fileName, expS string) {
 	require.NoError(t, err)
 	require.Equal(t, expS, string(buf))
 }
+
+func TestFileTemplate(t *testing.T) {
+	s := &influx.Serializer{}
+	require.NoError(t, s.Init())
+
+	dir := t.TempDir()
+	f := File{
+		Files:            []string{filepath.Join(dir, `{{.Name}}`, `host={{.Tag "host"}}`, "part-*.influx")},
+		RotationMaxSize:  config.Size(60),
+		serializer:       s,
+		CompressionLevel: -1,
+		MaxOpenFiles:     2,
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, f.Init())
+	require.NoError(t, f.Connect())
+
+	metrics := []telegraf.Metric{
+		metric.New("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1}, time.Unix(1, 0)),
+		metric.New("cpu", map[string]string{"host": "b"}, map[string]interface{}{"value": 2}, time.Unix(1, 0)),
+		metric.New("mem", map[string]string{"host": "a"}, map[string]interface{}{"value": 3}, time.Unix(1, 0)),
+		metric.New("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 4}, time.Unix(2, 0)),
+	}
+	require.NoError(t, f.Write(metrics))
+
+	// Exceeding the size limit starts a new part
+	require.NoError(t, f.Write(metrics[:1]))
+	require.NoError(t, f.Close())
+
+	validateFile(t, filepath.Join(dir, "cpu", "host=a", "part-00000.influx"),
+		"cpu,host=a value=1i 1000000000\ncpu,host=a value=4i 2000000000\n")
+	validateFile(t, filepath.Join(dir, "cpu", "host=a", "part-00001.influx"), "cpu,host=a value=1i 1000000000\n")
+	validateFile(t, filepath.Join(dir, "cpu", "host=b", "part-00000.influx"), "cpu,host=b value=2i 1000000000\n")
+	validateFile(t, filepath.Join(dir, "mem", "host=a", "part-00000.influx"), "mem,host=a value=3i 1000000000\n")
+}
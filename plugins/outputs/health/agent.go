@@ -0,0 +1,148 @@
+package health
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+// AgentChecks defines conditions on the internal state of Telegraf taken from
+// the statistics of the running plugins. Unset thresholds are not checked.
+type AgentChecks struct {
+	Inputs                       []string `toml:"inputs"`
+	Outputs                      []string `toml:"outputs"`
+	MaxBufferFullness            *float64 `toml:"max_buffer_fullness"`
+	MaxConsecutiveWriteErrors    *int64   `toml:"max_consecutive_write_errors"`
+	MaxConsecutiveGatherErrors   *int64   `toml:"max_consecutive_gather_errors"`
+	MaxConsecutiveGatherTimeouts *int64   `toml:"max_consecutive_gather_timeouts"`
+	FailOnStartupPending         bool     `toml:"fail_on_startup_pending"`
+
+	inputFilter  filter.Filter
+	outputFilter filter.Filter
+}
+
+// failure describes a failed check in the probe response
+type failure struct {
+	Check   string `json:"check"`
+	Plugin  string `json:"plugin,omitempty"`
+	Message string `json:"message"`
+}
+
+func (c *AgentChecks) init() error {
+	var err error
+	if c.inputFilter, err = filter.Compile(c.Inputs); err != nil {
+		return fmt.Errorf("creating input filter failed: %w", err)
+	}
+	if c.outputFilter, err = filter.Compile(c.Outputs); err != nil {
+		return fmt.Errorf("creating output filter failed: %w", err)
+	}
+	return nil
+}
+
+// check evaluates the conditions on the current statistics and returns the
+// failed checks
+func (c *AgentChecks) check() []failure {
+	var failures []failure
+
+	for _, m := range selfstat.Snapshot("write") {
+		plugin, selected := pluginName(m, "outputs", "output", c.outputFilter)
+		if !selected {
+			continue
+		}
+
+		if c.MaxBufferFullness != nil {
+			size, limit := stat(m, "buffer_size"), stat(m, "buffer_limit")
+			if limit > 0 {
+				fullness := 100 * float64(size) / float64(limit)
+				if fullness > *c.MaxBufferFullness {
+					failures = append(failures, failure{
+						Check:   "buffer_fullness",
+						Plugin:  plugin,
+						Message: fmt.Sprintf("buffer %.1f%% full exceeding %.1f%%", fullness, *c.MaxBufferFullness),
+					})
+				}
+			}
+		}
+		if c.MaxConsecutiveWriteErrors != nil {
+			if n := stat(m, "consecutive_write_errors"); n > *c.MaxConsecutiveWriteErrors {
+				failures = append(failures, failure{
+					Check:   "consecutive_write_errors",
+					Plugin:  plugin,
+					Message: fmt.Sprintf("%d consecutive failed writes exceeding %d", n, *c.MaxConsecutiveWriteErrors),
+				})
+			}
+		}
+		if c.FailOnStartupPending && stat(m, "startup_pending") > 0 {
+			failures = append(failures, failure{
+				Check:   "startup_pending",
+				Plugin:  plugin,
+				Message: "connecting failed and is retried",
+			})
+		}
+	}
+
+	for _, m := range selfstat.Snapshot("gather") {
+		plugin, selected := pluginName(m, "inputs", "input", c.inputFilter)
+		if !selected {
+			continue
+		}
+
+		if c.MaxConsecutiveGatherErrors != nil {
+			if n := stat(m, "consecutive_gather_errors"); n > *c.MaxConsecutiveGatherErrors {
+				failures = append(failures, failure{
+					Check:   "consecutive_gather_errors",
+					Plugin:  plugin,
+					Message: fmt.Sprintf("%d consecutive failed collections exceeding %d", n, *c.MaxConsecutiveGatherErrors),
+				})
+			}
+		}
+		if c.MaxConsecutiveGatherTimeouts != nil {
+			if n := stat(m, "consecutive_gather_timeouts"); n > *c.MaxConsecutiveGatherTimeouts {
+				failures = append(failures, failure{
+					Check:   "consecutive_gather_timeouts",
+					Plugin:  plugin,
+					Message: fmt.Sprintf("%d consecutive collection timeouts exceeding %d", n, *c.MaxConsecutiveGatherTimeouts),
+				})
+			}
+		}
+		if c.FailOnStartupPending && stat(m, "startup_pending") > 0 {
+			failures = append(failures, failure{
+				Check:   "startup_pending",
+				Plugin:  plugin,
+				Message: "starting failed and is retried",
+			})
+		}
+	}
+
+	// The statistics are not ordered so sort the failures for a stable output
+	sort.SliceStable(failures, func(i, j int) bool {
+		if failures[i].Plugin != failures[j].Plugin {
+			return failures[i].Plugin < failures[j].Plugin
+		}
+		return failures[i].Check < failures[j].Check
+	})
+
+	return failures
+}
+
+// pluginName returns the log-name of the plugin the statistics belong to and
+// if the plugin is selected by the filter on either the name or alias
+func pluginName(m telegraf.Metric, pluginType, key string, f filter.Filter) (string, bool) {
+	name, _ := m.GetTag(key)
+	alias, _ := m.GetTag("alias")
+
+	selected := f == nil || f.Match(name) || (alias != "" && f.Match(alias))
+	if alias == "" {
+		return pluginType + "." + name, selected
+	}
+	return pluginType + "." + name + "::" + alias, selected
+}
+
+func stat(m telegraf.Metric, field string) int64 {
+	v, _ := m.GetField(field)
+	n, _ := v.(int64)
+	return n
+}
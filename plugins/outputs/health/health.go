@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/tls"
 	_ "embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -27,6 +28,8 @@ const (
 	defaultServiceAddress = "tcp://:8080"
 	defaultReadTimeout    = 5 * time.Second
 	defaultWriteTimeout   = 5 * time.Second
+	defaultLivePath       = "/live"
+	defaultReadyPath      = "/ready"
 )
 
 type Checker interface {
@@ -44,6 +47,10 @@ type Health struct {
 	Contains              []*Contains     `toml:"contains"`
 	MaxTimeBetweenMetrics config.Duration `toml:"max_time_between_metrics"`
 	DefaultStatus         int             `toml:"default_status"`
+	LivePath              string          `toml:"live_path"`
+	ReadyPath             string          `toml:"ready_path"`
+	Live                  *AgentChecks    `toml:"live"`
+	Ready                 *AgentChecks    `toml:"ready"`
 	Log                   telegraf.Logger `toml:"-"`
 	common_tls.ServerConfig
 
@@ -99,6 +106,24 @@ func (h *Health) Init() error {
 		return err
 	}
 
+	if h.LivePath == "" {
+		h.LivePath = defaultLivePath
+	}
+	if h.ReadyPath == "" {
+		h.ReadyPath = defaultReadyPath
+	}
+	if h.LivePath == h.ReadyPath {
+		return errors.New("live_path and ready_path must differ")
+	}
+	for _, checks := range []*AgentChecks{h.Live, h.Ready} {
+		if checks == nil {
+			continue
+		}
+		if err := checks.init(); err != nil {
+			return err
+		}
+	}
+
 	h.checkers = make([]Checker, 0)
 	for i := range h.Compares {
 		h.checkers = append(h.checkers, h.Compares[i])
@@ -156,32 +181,88 @@ func (h *Health) listen() (net.Listener, error) {
 	return net.Listen(h.network, h.address)
 }
 
-func (h *Health) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Server", internal.ProductToken())
+
+	switch r.URL.Path {
+	case h.LivePath:
+		h.serveProbe(w, h.Live, false)
+	case h.ReadyPath:
+		h.serveProbe(w, h.Ready, true)
+	default:
+		status, _ := h.metricsStatus()
+		http.Error(w, http.StatusText(status), status)
+	}
+}
+
+// serveProbe responds with the result of the agent checks and optionally the
+// metric based checks including the failures in the JSON body
+func (h *Health) serveProbe(w http.ResponseWriter, checks *AgentChecks, includeMetrics bool) {
+	status := http.StatusOK
+	failures := make([]failure, 0)
+	if includeMetrics {
+		var f *failure
+		status, f = h.metricsStatus()
+		if f != nil {
+			failures = append(failures, *f)
+		}
+	}
+	if checks != nil {
+		failures = append(failures, checks.check()...)
+	}
+	if len(failures) > 0 && status < http.StatusBadRequest {
+		status = http.StatusServiceUnavailable
+	}
+
+	result := "pass"
+	if status >= http.StatusBadRequest {
+		result = "fail"
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"status":   result,
+		"failures": failures,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		h.Log.Debugf("Writing response failed: %v", err)
+	}
+}
+
+// metricsStatus returns the HTTP status based on the written metrics and the
+// reason for unhealthy states
+func (h *Health) metricsStatus() (int, *failure) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	w.Header().Set("Server", internal.ProductToken())
-
 	// Check the timeout independent of the available metrics
 	if h.MaxTimeBetweenMetrics > 0 && time.Since(h.lastMetricTime) >= time.Duration(h.MaxTimeBetweenMetrics) {
-		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
-		return
+		return http.StatusServiceUnavailable, &failure{
+			Check:   "max_time_between_metrics",
+			Message: fmt.Sprintf("no metrics written within %s", time.Duration(h.MaxTimeBetweenMetrics)),
+		}
 	}
 
 	// Return the default status if we have no metrics to check
 	if !h.metricsAvailable {
-		http.Error(w, http.StatusText(h.DefaultStatus), h.DefaultStatus)
-		return
+		if h.DefaultStatus >= http.StatusBadRequest {
+			return h.DefaultStatus, &failure{Check: "default_status", Message: "no metrics written yet"}
+		}
+		return h.DefaultStatus, nil
 	}
 
 	// Check the health conditions and return 503 - Service Unavailable for
 	// unhealthy states
 	if !h.healthy {
-		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
-		return
+		return http.StatusServiceUnavailable, &failure{Check: "metrics", Message: "metrics do not meet the configured checks"}
 	}
 
-	http.Error(w, http.StatusText(http.StatusOK), http.StatusOK)
+	return http.StatusOK, nil
 }
 
 // Write runs all checks over the metric batch and adjust health state.
@@ -253,6 +334,8 @@ func NewHealth() *Health {
 		ServiceAddress: defaultServiceAddress,
 		ReadTimeout:    config.Duration(defaultReadTimeout),
 		WriteTimeout:   config.Duration(defaultWriteTimeout),
+		LivePath:       defaultLivePath,
+		ReadyPath:      defaultReadyPath,
 	}
 }
 
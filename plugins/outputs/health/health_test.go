@@ -1,6 +1,7 @@
 package health_test
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/outputs/health"
+	"github.com/influxdata/telegraf/selfstat"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -430,3 +432,202 @@ func TestDefaultStatusHealthy(t *testing.T) {
 		})
 	}
 }
+
+func TestProbes(t *testing.T) {
+	type stat struct {
+		measurement string
+		field       string
+		tags        map[string]string
+		value       int64
+	}
+
+	limit := func(v int64) *int64 { return &v }
+	fullness := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name             string
+		path             string
+		live             *health.AgentChecks
+		ready            *health.AgentChecks
+		stats            []stat
+		timeout          time.Duration
+		expectedCode     int
+		expectedFailures []map[string]string
+	}{
+		{
+			name:         "live without checks",
+			path:         "/live",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "buffer fullness",
+			path: "/ready",
+			ready: &health.AgentChecks{
+				Outputs:           []string{"probe_*"},
+				MaxBufferFullness: fullness(80),
+			},
+			stats: []stat{
+				{"write", "buffer_size", map[string]string{"output": "probe_full"}, 90},
+				{"write", "buffer_limit", map[string]string{"output": "probe_full"}, 100},
+				{"write", "buffer_size", map[string]string{"output": "probe_empty"}, 10},
+				{"write", "buffer_limit", map[string]string{"output": "probe_empty"}, 100},
+			},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedFailures: []map[string]string{
+				{
+					"check":   "buffer_fullness",
+					"plugin":  "outputs.probe_full",
+					"message": "buffer 90.0% full exceeding 80.0%",
+				},
+			},
+		},
+		{
+			name: "consecutive write errors of selected output",
+			path: "/live",
+			live: &health.AgentChecks{
+				Outputs:                   []string{"probe_alias"},
+				MaxConsecutiveWriteErrors: limit(2),
+			},
+			stats: []stat{
+				{"write", "consecutive_write_errors", map[string]string{"output": "probe_a", "alias": "probe_alias"}, 3},
+				{"write", "consecutive_write_errors", map[string]string{"output": "probe_b"}, 5},
+			},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedFailures: []map[string]string{
+				{
+					"check":   "consecutive_write_errors",
+					"plugin":  "outputs.probe_a::probe_alias",
+					"message": "3 consecutive failed writes exceeding 2",
+				},
+			},
+		},
+		{
+			name: "consecutive write errors within limit",
+			path: "/live",
+			live: &health.AgentChecks{
+				Outputs:                   []string{"probe_*"},
+				MaxConsecutiveWriteErrors: limit(2),
+			},
+			stats: []stat{
+				{"write", "consecutive_write_errors", map[string]string{"output": "probe_a"}, 2},
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "input errors and timeouts",
+			path: "/live",
+			live: &health.AgentChecks{
+				Inputs:                       []string{"probe"},
+				MaxConsecutiveGatherErrors:   limit(0),
+				MaxConsecutiveGatherTimeouts: limit(1),
+			},
+			stats: []stat{
+				{"gather", "consecutive_gather_errors", map[string]string{"input": "probe"}, 1},
+				{"gather", "consecutive_gather_timeouts", map[string]string{"input": "probe"}, 2},
+			},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedFailures: []map[string]string{
+				{
+					"check":   "consecutive_gather_errors",
+					"plugin":  "inputs.probe",
+					"message": "1 consecutive failed collections exceeding 0",
+				},
+				{
+					"check":   "consecutive_gather_timeouts",
+					"plugin":  "inputs.probe",
+					"message": "2 consecutive collection timeouts exceeding 1",
+				},
+			},
+		},
+		{
+			name: "startup pending",
+			path: "/ready",
+			ready: &health.AgentChecks{
+				Inputs:               []string{"probe"},
+				Outputs:              []string{"probe"},
+				FailOnStartupPending: true,
+			},
+			stats: []stat{
+				{"gather", "startup_pending", map[string]string{"input": "probe"}, 1},
+				{"write", "startup_pending", map[string]string{"output": "probe"}, 0},
+			},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedFailures: []map[string]string{
+				{
+					"check":   "startup_pending",
+					"plugin":  "inputs.probe",
+					"message": "starting failed and is retried",
+				},
+			},
+		},
+		{
+			name: "ready checks not used for liveness",
+			path: "/live",
+			ready: &health.AgentChecks{
+				Inputs:               []string{"probe"},
+				FailOnStartupPending: true,
+			},
+			stats: []stat{
+				{"gather", "startup_pending", map[string]string{"input": "probe"}, 1},
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "ready includes metric checks",
+			path:         "/ready",
+			timeout:      time.Nanosecond,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedFailures: []map[string]string{
+				{
+					"check":   "max_time_between_metrics",
+					"message": "no metrics written within 1ns",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range tt.stats {
+				selfstat.Register(s.measurement, s.field, s.tags).Set(s.value)
+				defer selfstat.Unregister(s.measurement, s.field, s.tags)
+			}
+
+			plugin := health.NewHealth()
+			plugin.ServiceAddress = "tcp://127.0.0.1:0"
+			plugin.Live = tt.live
+			plugin.Ready = tt.ready
+			plugin.MaxTimeBetweenMetrics = config.Duration(tt.timeout)
+			plugin.Log = testutil.Logger{}
+			require.NoError(t, plugin.Init())
+			require.NoError(t, plugin.Connect())
+			defer plugin.Close()
+
+			resp, err := http.Get(plugin.Origin() + tt.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tt.expectedCode, resp.StatusCode)
+			require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			var body struct {
+				Status   string              `json:"status"`
+				Failures []map[string]string `json:"failures"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			if tt.expectedCode == http.StatusOK {
+				require.Equal(t, "pass", body.Status)
+				require.Empty(t, body.Failures)
+			} else {
+				require.Equal(t, "fail", body.Status)
+				require.Equal(t, tt.expectedFailures, body.Failures)
+			}
+		})
+	}
+}
+
+func TestProbePathsInvalid(t *testing.T) {
+	plugin := health.NewHealth()
+	plugin.LivePath = "/health"
+	plugin.ReadyPath = "/health"
+	require.ErrorContains(t, plugin.Init(), "must differ")
+}
@@ -206,6 +206,11 @@ func (h *HTTP) writeMetric(reqBody []byte) error {
 
 	req.Header.Set("User-Agent", internal.ProductToken())
 	req.Header.Set("Content-Type", defaultContentType)
+	if s, ok := h.serializer.(telegraf.HeaderSerializer); ok {
+		for k, v := range s.Headers() {
+			req.Header.Set(k, v)
+		}
+	}
 	if h.ContentEncoding == "gzip" {
 		req.Header.Set("Content-Encoding", "gzip")
 	}
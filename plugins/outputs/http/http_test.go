@@ -23,6 +23,7 @@ import (
 	"github.com/influxdata/telegraf/plugins/common/oauth"
 	"github.com/influxdata/telegraf/plugins/serializers/influx"
 	"github.com/influxdata/telegraf/plugins/serializers/json"
+	"github.com/influxdata/telegraf/plugins/serializers/prometheusremotewrite"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -344,6 +345,64 @@ func TestContentType(t *testing.T) {
 	}
 }
 
+func TestSerializerHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	u, err := url.Parse("http://" + ts.Listener.Addr().String())
+	require.NoError(t, err)
+
+	versionSecret := config.NewSecret([]byte("2.0.1"))
+	tests := []struct {
+		name     string
+		plugin   *HTTP
+		expected map[string]string
+	}{
+		{
+			name:   "serializer headers",
+			plugin: &HTTP{URL: u.String()},
+			expected: map[string]string{
+				"Content-Type":                      "application/x-protobuf;proto=io.prometheus.write.v2.Request",
+				"Content-Encoding":                  "snappy",
+				"X-Prometheus-Remote-Write-Version": "2.0.0",
+			},
+		},
+		{
+			name: "overwrite serializer headers",
+			plugin: &HTTP{
+				URL:     u.String(),
+				Headers: map[string]*config.Secret{"X-Prometheus-Remote-Write-Version": &versionSecret},
+			},
+			expected: map[string]string{
+				"Content-Type":                      "application/x-protobuf;proto=io.prometheus.write.v2.Request",
+				"Content-Encoding":                  "snappy",
+				"X-Prometheus-Remote-Write-Version": "2.0.1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.expected {
+					if actual := r.Header.Get(k); actual != v {
+						w.WriteHeader(http.StatusInternalServerError)
+						t.Errorf("Header %q not equal, expected: %q, actual: %q", k, v, actual)
+						return
+					}
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+
+			serializer := &prometheusremotewrite.Serializer{Version: "v2", Log: testutil.Logger{}}
+			require.NoError(t, serializer.Init())
+			tt.plugin.SetSerializer(serializer)
+			require.NoError(t, tt.plugin.Connect())
+			require.NoError(t, tt.plugin.Write([]telegraf.Metric{getMetric()}))
+		})
+	}
+}
+
 func TestContentEncodingGzip(t *testing.T) {
 	ts := httptest.NewServer(http.NotFoundHandler())
 	defer ts.Close()
@@ -5,7 +5,9 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/IBM/sarama"
@@ -24,16 +26,20 @@ var sampleConfig string
 var zeroTime = time.Unix(0, 0)
 
 type Kafka struct {
-	Brokers           []string        `toml:"brokers"`
-	Topic             string          `toml:"topic"`
-	TopicTag          string          `toml:"topic_tag"`
-	ExcludeTopicTag   bool            `toml:"exclude_topic_tag"`
-	TopicSuffix       TopicSuffix     `toml:"topic_suffix"`
-	RoutingTag        string          `toml:"routing_tag"`
-	RoutingKey        string          `toml:"routing_key"`
-	ProducerTimestamp string          `toml:"producer_timestamp"`
-	MetricNameHeader  string          `toml:"metric_name_header"`
-	Log               telegraf.Logger `toml:"-"`
+	Brokers           []string          `toml:"brokers"`
+	Topic             string            `toml:"topic"`
+	TopicTag          string            `toml:"topic_tag"`
+	ExcludeTopicTag   bool              `toml:"exclude_topic_tag"`
+	TopicSuffix       TopicSuffix       `toml:"topic_suffix"`
+	RoutingTag        string            `toml:"routing_tag"`
+	RoutingKey        string            `toml:"routing_key"`
+	ProducerTimestamp string            `toml:"producer_timestamp"`
+	MetricNameHeader  string            `toml:"metric_name_header"`
+	TopicTemplate     string            `toml:"topic_template"`
+	KeyTemplate       string            `toml:"key_template"`
+	Headers           map[string]string `toml:"headers"`
+	TransactionalID   string            `toml:"transactional_id"`
+	Log               telegraf.Logger   `toml:"-"`
 	proxy.Socks5ProxyConfig
 	kafka.WriteConfig
 
@@ -46,6 +52,10 @@ type Kafka struct {
 	producerFunc func(addrs []string, config *sarama.Config) (sarama.SyncProducer, error)
 	producer     sarama.SyncProducer
 
+	topicTemplate *template.Template
+	keyTemplate   *template.Template
+	headers       []messageHeader
+
 	serializer telegraf.Serializer
 }
 
@@ -74,11 +84,46 @@ func (k *Kafka) Init() error {
 		return fmt.Errorf("unknown topic suffix method provided: %s", k.TopicSuffix.Method)
 	}
 
+	// Parse the templates for the message parts
+	var err error
+	if k.TopicTemplate != "" {
+		if k.topicTemplate, err = newTemplate("topic", k.TopicTemplate); err != nil {
+			return err
+		}
+	}
+	if k.KeyTemplate != "" {
+		if k.keyTemplate, err = newTemplate("key", k.KeyTemplate); err != nil {
+			return err
+		}
+	}
+	k.headers = make([]messageHeader, 0, len(k.Headers))
+	for key, text := range k.Headers {
+		tmpl, err := newTemplate("header "+key, text)
+		if err != nil {
+			return err
+		}
+		k.headers = append(k.headers, messageHeader{key: key, value: tmpl})
+	}
+	sort.Slice(k.headers, func(i, j int) bool { return k.headers[i].key < k.headers[j].key })
+
 	config := sarama.NewConfig()
 	if err := k.SetConfig(config, k.Log); err != nil {
 		return err
 	}
 
+	// Transactions require an idempotent producer waiting for all replicas
+	if k.TransactionalID != "" {
+		if k.RequiredAcks != int(sarama.WaitForAll) {
+			return errors.New("transactions require 'required_acks = -1'")
+		}
+		if k.MaxRetry < 1 {
+			return errors.New("transactions require 'max_retry' of at least one")
+		}
+		config.Producer.Transaction.ID = k.TransactionalID
+		config.Producer.Idempotent = true
+		config.Net.MaxOpenRequests = 1
+	}
+
 	// Legacy support ssl config
 	if k.Certificate != "" {
 		k.TLSCert = k.Certificate
@@ -125,11 +170,28 @@ func (k *Kafka) Close() error {
 }
 
 func (k *Kafka) Write(metrics []telegraf.Metric) error {
+	// Recreate the producer after fatal transaction errors
+	if k.producer == nil {
+		if err := k.Connect(); err != nil {
+			return err
+		}
+	}
+
 	msgs := make([]*sarama.ProducerMessage, 0, len(metrics))
+metrics:
 	for _, metric := range metrics {
-		metric, topic := k.getTopicName(metric)
+		metric, topic, err := k.topic(metric)
+		if err != nil {
+			k.Log.Errorf("Could not generate topic: %v", err)
+			continue
+		}
 
-		buf, err := k.serializer.Serialize(metric)
+		var buf []byte
+		if s, ok := k.serializer.(telegraf.TopicSerializer); ok {
+			buf, err = s.SerializeTopic(metric, topic)
+		} else {
+			buf, err = k.serializer.Serialize(metric)
+		}
 		if err != nil {
 			k.Log.Debugf("Could not serialize metric: %v", err)
 			continue
@@ -148,6 +210,14 @@ func (k *Kafka) Write(metrics []telegraf.Metric) error {
 				},
 			}
 		}
+		for _, header := range k.headers {
+			value, err := render(header.value, metric)
+			if err != nil {
+				k.Log.Errorf("Could not generate header %q: %v", header.key, err)
+				continue metrics
+			}
+			m.Headers = append(m.Headers, sarama.RecordHeader{Key: []byte(header.key), Value: []byte(value)})
+		}
 
 		// Negative timestamps are not allowed by the Kafka protocol.
 		if k.ProducerTimestamp == "metric" && !metric.Time().Before(zeroTime) {
@@ -165,6 +235,80 @@ func (k *Kafka) Write(metrics []telegraf.Metric) error {
 		msgs = append(msgs, m)
 	}
 
+	if k.TransactionalID != "" {
+		return k.sendTransaction(msgs)
+	}
+	return k.send(msgs)
+}
+
+// sendTransaction writes the messages within a transaction which is only
+// committed if all messages were written successfully. Messages which can
+// never be written, e.g. due to their size, would fail all transactions so
+// the transaction is aborted and the remaining messages are sent again.
+func (k *Kafka) sendTransaction(msgs []*sarama.ProducerMessage) error {
+	for len(msgs) > 0 {
+		if err := k.producer.BeginTxn(); err != nil {
+			k.resetOnFatalError()
+			return fmt.Errorf("beginning transaction failed: %w", err)
+		}
+
+		err := k.producer.SendMessages(msgs)
+		if err == nil {
+			if err := k.producer.CommitTxn(); err != nil {
+				k.abort()
+				return fmt.Errorf("committing transaction failed: %w", err)
+			}
+			return nil
+		}
+		k.abort()
+
+		var errs sarama.ProducerErrors
+		if !errors.As(err, &errs) || len(errs) == 0 {
+			return err
+		}
+		invalid := make(map[*sarama.ProducerMessage]bool, len(errs))
+		for _, e := range errs {
+			if errors.Is(e.Err, sarama.ErrMessageSizeTooLarge) || errors.Is(e.Err, sarama.ErrInvalidTimestamp) {
+				invalid[e.Msg] = true
+			}
+		}
+		if len(invalid) == 0 || k.producer == nil {
+			return errs[0]
+		}
+		k.Log.Errorf("Dropping %d messages from transaction being too large or out of the acceptable timestamp range", len(invalid))
+
+		remaining := make([]*sarama.ProducerMessage, 0, len(msgs)-len(invalid))
+		for _, m := range msgs {
+			if !invalid[m] {
+				remaining = append(remaining, m)
+			}
+		}
+		msgs = remaining
+	}
+	return nil
+}
+
+func (k *Kafka) abort() {
+	if err := k.producer.AbortTxn(); err != nil {
+		k.Log.Errorf("Aborting transaction failed: %v", err)
+	}
+	k.resetOnFatalError()
+}
+
+// resetOnFatalError closes the producer if it cannot be used for further
+// transactions so it is recreated on the next write
+func (k *Kafka) resetOnFatalError() {
+	if k.producer.TxnStatus()&sarama.ProducerTxnFlagFatalError == 0 {
+		return
+	}
+	k.Log.Warn("Producer is in fatal state, recreating it")
+	if err := k.producer.Close(); err != nil {
+		k.Log.Errorf("Closing producer failed: %v", err)
+	}
+	k.producer = nil
+}
+
+func (k *Kafka) send(msgs []*sarama.ProducerMessage) error {
 	if err := k.producer.SendMessages(msgs); err != nil {
 		// We could have many errors, return only the first encountered.
 		var errs sarama.ProducerErrors
@@ -190,6 +334,21 @@ func (k *Kafka) Write(metrics []telegraf.Metric) error {
 	return nil
 }
 
+// topic returns the topic of the metric using the template if configured
+func (k *Kafka) topic(metric telegraf.Metric) (telegraf.Metric, string, error) {
+	if k.topicTemplate != nil {
+		topic, err := render(k.topicTemplate, metric)
+		if err != nil {
+			return metric, "", err
+		}
+		if topic != "" {
+			return metric, topic, nil
+		}
+	}
+	metric, topic := k.getTopicName(metric)
+	return metric, topic, nil
+}
+
 func (k *Kafka) getTopicName(metric telegraf.Metric) (telegraf.Metric, string) {
 	topic := k.Topic
 	if k.TopicTag != "" {
@@ -226,6 +385,16 @@ func (k *Kafka) getTopicName(metric telegraf.Metric) (telegraf.Metric, string) {
 }
 
 func (k *Kafka) routingKey(metric telegraf.Metric) (string, error) {
+	if k.keyTemplate != nil {
+		key, err := render(k.keyTemplate, metric)
+		if err != nil {
+			return "", err
+		}
+		if key != "" {
+			return key, nil
+		}
+	}
+
 	if k.RoutingTag != "" {
 		key, ok := metric.GetTag(k.RoutingTag)
 		if ok {
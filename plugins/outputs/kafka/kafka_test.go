@@ -268,10 +268,303 @@ func TestTopicTag(t *testing.T) {
 	}
 }
 
+func TestTemplates(t *testing.T) {
+	input := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{
+				"host": "server01",
+				"dc":   "east",
+			},
+			map[string]interface{}{
+				"time_idle": 42.0,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"mem",
+			map[string]string{},
+			map[string]interface{}{
+				"used": 23,
+			},
+			time.Unix(0, 0),
+		),
+	}
+
+	s := &influx.Serializer{}
+	require.NoError(t, s.Init())
+
+	plugin := &Kafka{
+		Brokers:       []string{"127.0.0.1"},
+		Topic:         "telegraf",
+		TopicTemplate: `{{ with .Tag "dc" }}{{ . }}-{{ end }}{{ .Name }}`,
+		KeyTemplate:   `{{ .Tag "host" }}`,
+		RoutingKey:    "static",
+		Headers: map[string]string{
+			"source": `{{ .Tag "host" | default "unknown" }}`,
+			"name":   `{{ .Name | upper }}`,
+		},
+		Log:          testutil.Logger{},
+		producerFunc: newMockProducer,
+	}
+	plugin.SetSerializer(s)
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	require.NoError(t, plugin.Write(input))
+
+	producer, ok := plugin.producer.(*mockProducer)
+	require.True(t, ok, "invalid producer type")
+	require.Len(t, producer.sent, 2)
+
+	first := producer.sent[0]
+	require.Equal(t, "east-cpu", first.Topic)
+	require.Equal(t, sarama.StringEncoder("server01"), first.Key)
+	require.Equal(t, []sarama.RecordHeader{
+		{Key: []byte("name"), Value: []byte("CPU")},
+		{Key: []byte("source"), Value: []byte("server01")},
+	}, first.Headers)
+
+	// Empty template results fall back to the static settings
+	second := producer.sent[1]
+	require.Equal(t, "mem", second.Topic)
+	require.Equal(t, sarama.StringEncoder("static"), second.Key)
+	require.Equal(t, []sarama.RecordHeader{
+		{Key: []byte("name"), Value: []byte("MEM")},
+		{Key: []byte("source"), Value: []byte("unknown")},
+	}, second.Headers)
+}
+
+func TestTopicSerializer(t *testing.T) {
+	plugin := &Kafka{
+		Brokers:       []string{"127.0.0.1"},
+		Topic:         "telegraf",
+		TopicTemplate: `{{ .Name }}`,
+		Log:           testutil.Logger{},
+		producerFunc:  newMockProducer,
+	}
+	plugin.SetSerializer(&topicSerializer{})
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+
+	m := metric.New("cpu", map[string]string{}, map[string]interface{}{"value": 42.0}, time.Unix(0, 0))
+	require.NoError(t, plugin.Write([]telegraf.Metric{m}))
+
+	producer, ok := plugin.producer.(*mockProducer)
+	require.True(t, ok, "invalid producer type")
+	require.Len(t, producer.sent, 1)
+	require.Equal(t, sarama.ByteEncoder("cpu"), producer.sent[0].Value)
+}
+
+// topicSerializer serializes metrics to the name of the topic
+type topicSerializer struct {
+	influx.Serializer
+}
+
+func (*topicSerializer) SerializeTopic(_ telegraf.Metric, topic string) ([]byte, error) {
+	return []byte(topic), nil
+}
+
+func TestInvalidTemplate(t *testing.T) {
+	plugin := &Kafka{
+		KeyTemplate: `{{ .Tag "host" `,
+		Log:         testutil.Logger{},
+	}
+	require.ErrorContains(t, plugin.Init(), "parsing key template failed")
+}
+
+func TestTransactionRequiresAcks(t *testing.T) {
+	plugin := &Kafka{
+		TransactionalID: "telegraf",
+		Log:             testutil.Logger{},
+	}
+	plugin.RequiredAcks = 1
+	plugin.MaxRetry = 3
+	require.ErrorContains(t, plugin.Init(), "transactions require 'required_acks = -1'")
+}
+
+func TestTransaction(t *testing.T) {
+	input := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{},
+			map[string]interface{}{
+				"time_idle": 42.0,
+			},
+			time.Unix(0, 0),
+		),
+	}
+
+	tests := []struct {
+		name        string
+		sendErr     error
+		commitErr   error
+		fatal       bool
+		expectedErr string
+		committed   int
+		aborted     int
+	}{
+		{
+			name:      "commit on success",
+			committed: 1,
+		},
+		{
+			name:        "abort on send failure",
+			sendErr:     sarama.ErrOutOfBrokers,
+			expectedErr: "client has run out of available brokers",
+			aborted:     1,
+		},
+		{
+			name:        "abort on commit failure",
+			commitErr:   sarama.ErrTransactionNotReady,
+			expectedErr: "committing transaction failed",
+			aborted:     1,
+		},
+		{
+			name:        "recreate producer on fatal error",
+			commitErr:   sarama.ErrTransitionNotAllowed,
+			fatal:       true,
+			expectedErr: "committing transaction failed",
+			aborted:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &influx.Serializer{}
+			require.NoError(t, s.Init())
+
+			plugin := &Kafka{
+				Brokers:         []string{"127.0.0.1"},
+				Topic:           "telegraf",
+				TransactionalID: "telegraf",
+				Log:             testutil.Logger{},
+				producerFunc:    newMockProducer,
+			}
+			plugin.RequiredAcks = -1
+			plugin.MaxRetry = 3
+			plugin.SetSerializer(s)
+			require.NoError(t, plugin.Init())
+			require.Equal(t, "telegraf", plugin.saramaConfig.Producer.Transaction.ID)
+			require.True(t, plugin.saramaConfig.Producer.Idempotent)
+			require.NoError(t, plugin.Connect())
+
+			producer, ok := plugin.producer.(*mockProducer)
+			require.True(t, ok, "invalid producer type")
+			producer.sendErr = tt.sendErr
+			producer.commitErr = tt.commitErr
+			producer.fatal = tt.fatal
+
+			err := plugin.Write(input)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, 1, producer.begun)
+			require.Equal(t, tt.committed, producer.committed)
+			require.Equal(t, tt.aborted, producer.aborted)
+
+			// A producer in fatal state must be replaced on the next write
+			if tt.fatal {
+				require.Nil(t, plugin.producer)
+				require.True(t, producer.closed)
+				require.NoError(t, plugin.Write(input))
+				require.NotSame(t, producer, plugin.producer)
+			}
+		})
+	}
+}
+
+func TestTransactionDropInvalidMessages(t *testing.T) {
+	input := []telegraf.Metric{
+		metric.New("cpu", map[string]string{}, map[string]interface{}{"value": 1.0}, time.Unix(0, 0)),
+		metric.New("large", map[string]string{}, map[string]interface{}{"value": 2.0}, time.Unix(0, 0)),
+		metric.New("mem", map[string]string{}, map[string]interface{}{"value": 3.0}, time.Unix(0, 0)),
+	}
+
+	s := &influx.Serializer{}
+	require.NoError(t, s.Init())
+
+	plugin := &Kafka{
+		Brokers:         []string{"127.0.0.1"},
+		Topic:           "telegraf",
+		TopicTemplate:   `{{ .Name }}`,
+		TransactionalID: "telegraf",
+		Log:             testutil.Logger{},
+		producerFunc:    newMockProducer,
+	}
+	plugin.RequiredAcks = -1
+	plugin.MaxRetry = 3
+	plugin.SetSerializer(s)
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+
+	producer, ok := plugin.producer.(*mockProducer)
+	require.True(t, ok, "invalid producer type")
+	producer.reject = func(msg *sarama.ProducerMessage) error {
+		if msg.Topic == "large" {
+			return sarama.ErrMessageSizeTooLarge
+		}
+		return nil
+	}
+
+	// The transaction is aborted and the valid messages are sent again
+	require.NoError(t, plugin.Write(input))
+	require.Equal(t, 2, producer.begun)
+	require.Equal(t, 1, producer.aborted)
+	require.Equal(t, 1, producer.committed)
+	require.Len(t, producer.sent, 2)
+	require.Equal(t, "cpu", producer.sent[0].Topic)
+	require.Equal(t, "mem", producer.sent[1].Topic)
+}
+
+func TestHeaderTemplateFailure(t *testing.T) {
+	input := []telegraf.Metric{
+		metric.New("cpu", map[string]string{"host": "server01"}, map[string]interface{}{"value": 1.0}, time.Unix(0, 0)),
+		metric.New("mem", map[string]string{}, map[string]interface{}{"value": 2.0}, time.Unix(0, 0)),
+	}
+
+	s := &influx.Serializer{}
+	require.NoError(t, s.Init())
+
+	plugin := &Kafka{
+		Brokers: []string{"127.0.0.1"},
+		Topic:   "telegraf",
+		Headers: map[string]string{
+			"source": `{{ with .Tag "host" }}{{ . }}{{ else }}{{ fail "missing host" }}{{ end }}`,
+		},
+		Log:          testutil.Logger{},
+		producerFunc: newMockProducer,
+	}
+	plugin.SetSerializer(s)
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+
+	// Metrics failing to render the header are skipped
+	require.NoError(t, plugin.Write(input))
+	producer, ok := plugin.producer.(*mockProducer)
+	require.True(t, ok, "invalid producer type")
+	require.Len(t, producer.sent, 1)
+	require.Equal(t, []sarama.RecordHeader{
+		{Key: []byte("source"), Value: []byte("server01")},
+	}, producer.sent[0].Headers)
+}
+
 type mockProducer struct {
 	sent []*sarama.ProducerMessage
 	sarama.SyncProducer
 	sync.Mutex
+
+	sendErr   error
+	commitErr error
+	fatal     bool
+	reject    func(*sarama.ProducerMessage) error
+	txnStart  int
+
+	begun     int
+	committed int
+	aborted   int
+	closed    bool
 }
 
 func newMockProducer(_ []string, _ *sarama.Config) (sarama.SyncProducer, error) {
@@ -288,10 +581,58 @@ func (p *mockProducer) SendMessage(msg *sarama.ProducerMessage) (partition int32
 func (p *mockProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
 	p.Lock()
 	defer p.Unlock()
-	p.sent = append(p.sent, msgs...)
+	if p.sendErr != nil {
+		errs := make(sarama.ProducerErrors, 0, len(msgs))
+		for _, msg := range msgs {
+			errs = append(errs, &sarama.ProducerError{Msg: msg, Err: p.sendErr})
+		}
+		return errs
+	}
+
+	var errs sarama.ProducerErrors
+	for _, msg := range msgs {
+		if p.reject != nil {
+			if err := p.reject(msg); err != nil {
+				errs = append(errs, &sarama.ProducerError{Msg: msg, Err: err})
+				continue
+			}
+		}
+		p.sent = append(p.sent, msg)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (p *mockProducer) BeginTxn() error {
+	p.begun++
+	p.txnStart = len(p.sent)
+	return nil
+}
+
+func (p *mockProducer) CommitTxn() error {
+	if p.commitErr != nil {
+		return p.commitErr
+	}
+	p.committed++
 	return nil
 }
 
-func (*mockProducer) Close() error {
+func (p *mockProducer) AbortTxn() error {
+	p.aborted++
+	p.sent = p.sent[:p.txnStart]
+	return nil
+}
+
+func (p *mockProducer) TxnStatus() sarama.ProducerTxnStatusFlag {
+	if p.fatal {
+		return sarama.ProducerTxnFlagFatalError
+	}
+	return sarama.ProducerTxnFlagReady
+}
+
+func (p *mockProducer) Close() error {
+	p.closed = true
 	return nil
 }
@@ -0,0 +1,39 @@
+package kafka
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+
+	"github.com/influxdata/telegraf"
+)
+
+// messageHeader is a Kafka header with its value rendered from a template
+type messageHeader struct {
+	key   string
+	value *template.Template
+}
+
+func newTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(sprig.TxtFuncMap()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s template failed: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// render executes the template with access to the metric's name, tags, fields
+// and time
+func render(tmpl *template.Template, m telegraf.Metric) (string, error) {
+	if wm, ok := m.(telegraf.UnwrappableMetric); ok {
+		m = wm.Unwrap()
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, m.(telegraf.TemplateMetric)); err != nil {
+		return "", fmt.Errorf("executing %s template failed: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
@@ -33,11 +33,14 @@ type message struct {
 }
 
 type MQTT struct {
-	Topic           string          `toml:"topic"`
-	Layout          string          `toml:"layout"`
-	HomieDeviceName string          `toml:"homie_device_name"`
-	HomieNodeID     string          `toml:"homie_node_id"`
-	Log             telegraf.Logger `toml:"-"`
+	Topic                string          `toml:"topic"`
+	Layout               string          `toml:"layout"`
+	HomieDeviceName      string          `toml:"homie_device_name"`
+	HomieNodeID          string          `toml:"homie_node_id"`
+	SparkplugBGroupID    string          `toml:"sparkplugb_group_id"`
+	SparkplugBEdgeNodeID string          `toml:"sparkplugb_edge_node_id"`
+	SparkplugBDeviceID   string          `toml:"sparkplugb_device_id"`
+	Log                  telegraf.Logger `toml:"-"`
 	mqtt.MqttConfig
 
 	client     mqtt.Client
@@ -48,6 +51,9 @@ type MQTT struct {
 	homieNodeIDGenerator     *template.Template
 	homieSeen                map[string]map[string]bool
 
+	sparkplugDeviceIDGenerator *template.Template
+	sparkplug                  *sparkplugSession
+
 	sync.Mutex
 }
 
@@ -106,6 +112,32 @@ func (m *MQTT) Init() error {
 		if err != nil {
 			return fmt.Errorf("creating node ID name generator failed: %w", err)
 		}
+	case "sparkplugb":
+		if m.SparkplugBGroupID == "" {
+			return errors.New("missing 'sparkplugb_group_id' option")
+		}
+		if m.SparkplugBEdgeNodeID == "" {
+			return errors.New("missing 'sparkplugb_edge_node_id' option")
+		}
+		// Rebirth requests and new sessions on reconnect are only supported
+		// by the MQTT 3.1.1 client
+		if m.Protocol != "" && m.Protocol != "3.1.1" {
+			return fmt.Errorf("layout 'sparkplugb' is not supported with protocol %q", m.Protocol)
+		}
+		for _, id := range []string{m.SparkplugBGroupID, m.SparkplugBEdgeNodeID} {
+			if strings.ContainsAny(id, "/+#") {
+				return fmt.Errorf("found forbidden character in Sparkplug B ID %q", id)
+			}
+		}
+
+		if m.SparkplugBDeviceID == "" {
+			m.SparkplugBDeviceID = "{{ .Name }}"
+		}
+		m.SparkplugBDeviceID = pluginNameRe.ReplaceAllString(m.SparkplugBDeviceID, `$1.Name$2`)
+		m.sparkplugDeviceIDGenerator, err = template.New("device_id").Funcs(sprig.TxtFuncMap()).Parse(m.SparkplugBDeviceID)
+		if err != nil {
+			return fmt.Errorf("creating device ID generator failed: %w", err)
+		}
 	default:
 		return fmt.Errorf("invalid layout %q", m.Layout)
 	}
@@ -120,6 +152,11 @@ func (m *MQTT) Connect() error {
 	defer m.Unlock()
 
 	m.homieSeen = make(map[string]map[string]bool)
+	if m.Layout == "sparkplugb" {
+		if err := m.prepareSparkplugB(); err != nil {
+			return err
+		}
+	}
 
 	client, err := mqtt.NewClient(&m.MqttConfig)
 	if err != nil {
@@ -148,6 +185,18 @@ func (m *MQTT) Close() error {
 		// Give the messages some time to settle
 		time.Sleep(100 * time.Millisecond)
 	}
+
+	// Publish the death certificate as the broker does not send the "will"
+	// message on a regular disconnect
+	if m.sparkplug != nil {
+		death, err := m.sparkplugDeath(m.sparkplug)
+		if err == nil {
+			err = m.client.Publish(death.topic, death.payload)
+		}
+		if err != nil {
+			m.Log.Errorf("Publishing death certificate failed: %v", err)
+		}
+	}
 	return m.client.Close()
 }
 
@@ -169,6 +218,8 @@ func (m *MQTT) Write(metrics []telegraf.Metric) error {
 		topicMessages = m.collectField(metrics)
 	case "homie-v4":
 		topicMessages = m.collectHomieV4(metrics)
+	case "sparkplugb":
+		topicMessages = m.collectSparkplugB(metrics)
 	default:
 		return fmt.Errorf("unknown layout %q", m.Layout)
 	}
@@ -178,6 +229,10 @@ func (m *MQTT) Write(metrics []telegraf.Metric) error {
 			// We do receive a timeout error if the remote broker is down,
 			// so let's retry the metrics in this case and drop them otherwise.
 			if errors.Is(err, internal.ErrTimeout) {
+				// Birth certificates might be lost so issue them again
+				if m.sparkplug != nil {
+					m.sparkplug.rebirth.Store(true)
+				}
 				return fmt.Errorf("could not publish message to MQTT server: %w", err)
 			}
 			m.Log.Warnf("Could not publish message to MQTT server: %v", err)
@@ -0,0 +1,296 @@
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	mqttv3 "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/common/mqtt"
+	"github.com/influxdata/telegraf/plugins/common/sparkplug"
+)
+
+const sparkplugRebirthMetric = "Node Control/Rebirth"
+
+type sparkplugValue struct {
+	alias     uint64
+	datatype  sparkplug.DataType
+	value     interface{}
+	timestamp uint64
+}
+
+// sparkplugDevice holds the metrics announced in the birth certificate of a
+// device and their latest values
+type sparkplugDevice struct {
+	id      string
+	metrics map[string]*sparkplugValue
+	order   []string
+}
+
+// sparkplugSession holds the state of the edge node session
+type sparkplugSession struct {
+	bdSeq     atomic.Uint64
+	seq       uint64
+	nextAlias uint64
+	devices   map[string]*sparkplugDevice
+	order     []string
+	rebirth   atomic.Bool
+}
+
+func (s *sparkplugSession) nextSeq() *uint64 {
+	s.seq = (s.seq + 1) % 256
+	seq := s.seq
+	return &seq
+}
+
+func (m *MQTT) sparkplugTopic(messageType, deviceID string) string {
+	t := &sparkplug.Topic{
+		GroupID:     m.SparkplugBGroupID,
+		MessageType: messageType,
+		EdgeNodeID:  m.SparkplugBEdgeNodeID,
+		DeviceID:    deviceID,
+	}
+	return t.String()
+}
+
+// prepareSparkplugB starts a new session and sets up the death certificate
+// and the rebirth handling before connecting to the broker
+func (m *MQTT) prepareSparkplugB() error {
+	var bdSeq uint64
+	if m.sparkplug != nil {
+		bdSeq = (m.sparkplug.bdSeq.Load() + 1) % 256
+	}
+	m.sparkplug = &sparkplugSession{
+		nextAlias: 1,
+		devices:   make(map[string]*sparkplugDevice),
+	}
+	session := m.sparkplug
+	session.bdSeq.Store(bdSeq)
+
+	death, err := m.sparkplugDeath(session)
+	if err != nil {
+		return err
+	}
+	m.MqttConfig.Will = &mqtt.WillMessage{Topic: death.topic, Payload: death.payload, QoS: 1}
+
+	// Every connect must use a new bdSeq, so increment it before each automatic
+	// reconnect and update the death certificate accordingly
+	m.MqttConfig.OnReconnecting = func() {
+		session.bdSeq.Store((session.bdSeq.Load() + 1) % 256)
+		death, err := m.sparkplugDeath(session)
+		if err != nil {
+			m.Log.Errorf("Updating death certificate failed: %v", err)
+			return
+		}
+		m.MqttConfig.Will = &mqtt.WillMessage{Topic: death.topic, Payload: death.payload, QoS: 1}
+	}
+
+	// Request a birth on every (re-)connect and subscribe to the node commands
+	// to handle rebirth requests of host applications
+	m.MqttConfig.OnConnect = func() {
+		session.rebirth.Store(true)
+		topic := m.sparkplugTopic(sparkplug.NodeCommand, "")
+		err := m.client.SubscribeMultiple(map[string]byte{topic: 1}, func(_ mqttv3.Client, msg mqttv3.Message) {
+			var payload sparkplug.Payload
+			if err := payload.Unmarshal(msg.Payload()); err != nil {
+				m.Log.Errorf("Decoding node command failed: %v", err)
+				return
+			}
+			for _, metric := range payload.Metrics {
+				if v, ok := metric.Value.(bool); ok && v && metric.Name == sparkplugRebirthMetric {
+					m.Log.Debug("Rebirth requested")
+					session.rebirth.Store(true)
+				}
+			}
+		})
+		if err != nil {
+			m.Log.Errorf("Subscribing to node commands failed: %v", err)
+		}
+	}
+	return nil
+}
+
+func (m *MQTT) sparkplugDeath(session *sparkplugSession) (message, error) {
+	payload := &sparkplug.Payload{
+		Timestamp: uint64(time.Now().UnixMilli()),
+		Metrics: []*sparkplug.Metric{
+			{Name: "bdSeq", DataType: sparkplug.UInt64, Value: session.bdSeq.Load()},
+		},
+	}
+	buf, err := payload.Marshal()
+	if err != nil {
+		return message{}, fmt.Errorf("encoding death certificate failed: %w", err)
+	}
+	return message{m.sparkplugTopic(sparkplug.NodeDeath, ""), buf}, nil
+}
+
+func (m *MQTT) sparkplugNodeBirth(session *sparkplugSession) (message, error) {
+	now := uint64(time.Now().UnixMilli())
+	seq := uint64(0)
+	session.seq = 0
+	payload := &sparkplug.Payload{
+		Timestamp: now,
+		Seq:       &seq,
+		Metrics: []*sparkplug.Metric{
+			{Name: "bdSeq", Timestamp: now, DataType: sparkplug.UInt64, Value: session.bdSeq.Load()},
+			{Name: sparkplugRebirthMetric, Timestamp: now, DataType: sparkplug.Boolean, Value: false},
+		},
+	}
+	buf, err := payload.Marshal()
+	if err != nil {
+		return message{}, fmt.Errorf("encoding node birth failed: %w", err)
+	}
+	return message{m.sparkplugTopic(sparkplug.NodeBirth, ""), buf}, nil
+}
+
+func (m *MQTT) sparkplugDeviceBirth(session *sparkplugSession, device *sparkplugDevice) (message, error) {
+	payload := &sparkplug.Payload{
+		Timestamp: uint64(time.Now().UnixMilli()),
+		Seq:       session.nextSeq(),
+		Metrics:   make([]*sparkplug.Metric, 0, len(device.order)),
+	}
+	for _, name := range device.order {
+		v := device.metrics[name]
+		payload.Metrics = append(payload.Metrics, &sparkplug.Metric{
+			Name:      name,
+			Alias:     v.alias,
+			HasAlias:  true,
+			Timestamp: v.timestamp,
+			DataType:  v.datatype,
+			Value:     v.value,
+		})
+	}
+	buf, err := payload.Marshal()
+	if err != nil {
+		return message{}, fmt.Errorf("encoding birth of device %q failed: %w", device.id, err)
+	}
+	return message{m.sparkplugTopic(sparkplug.DeviceBirth, device.id), buf}, nil
+}
+
+// collectSparkplugB converts the metrics to data messages of the devices
+// determined by the device ID template. Birth certificates are issued
+// before the data if the node (re-)connected, a rebirth was requested or the
+// metrics of a device changed.
+func (m *MQTT) collectSparkplugB(metrics []telegraf.Metric) []message {
+	session := m.sparkplug
+
+	// Group the values by device and update the device metrics
+	var order []string
+	seen := make(map[string]bool)
+	data := make(map[string][]*sparkplug.Metric)
+	changed := make(map[string]bool)
+	for _, metric := range metrics {
+		deviceID, err := m.generateSparkplugDeviceID(metric)
+		if err != nil {
+			m.Log.Warnf("Generating device ID failed: %v", err)
+			m.Log.Debugf("metric was: %v", metric)
+			continue
+		}
+
+		device, found := session.devices[deviceID]
+		if !found {
+			device = &sparkplugDevice{id: deviceID, metrics: make(map[string]*sparkplugValue)}
+			session.devices[deviceID] = device
+			session.order = append(session.order, deviceID)
+		}
+		if !seen[deviceID] {
+			seen[deviceID] = true
+			order = append(order, deviceID)
+		}
+
+		ts := uint64(metric.Time().UnixMilli())
+		for _, field := range metric.FieldList() {
+			var sm sparkplug.Metric
+			if err := sm.SetValue(field.Value); err != nil {
+				m.Log.Warnf("Could not serialize field %q of device %q: %v", field.Key, deviceID, err)
+				continue
+			}
+
+			v, found := device.metrics[field.Key]
+			if !found {
+				v = &sparkplugValue{alias: session.nextAlias}
+				session.nextAlias++
+				device.metrics[field.Key] = v
+				device.order = append(device.order, field.Key)
+			}
+			if !found || v.datatype != sm.DataType {
+				changed[deviceID] = true
+			}
+			v.datatype, v.value, v.timestamp = sm.DataType, sm.Value, ts
+
+			data[deviceID] = append(data[deviceID], &sparkplug.Metric{
+				Alias:     v.alias,
+				HasAlias:  true,
+				Timestamp: ts,
+				Value:     sm.Value,
+			})
+		}
+	}
+
+	var collection []message
+	if session.rebirth.Swap(false) {
+		msg, err := m.sparkplugNodeBirth(session)
+		if err != nil {
+			m.Log.Error(err)
+			session.rebirth.Store(true)
+			return nil
+		}
+		collection = append(collection, msg)
+		for _, id := range session.order {
+			changed[id] = true
+		}
+	}
+
+	// Issue birth certificates of new or changed devices
+	for _, id := range session.order {
+		device := session.devices[id]
+		if !changed[id] || len(device.order) == 0 {
+			continue
+		}
+		msg, err := m.sparkplugDeviceBirth(session, device)
+		if err != nil {
+			m.Log.Warn(err)
+			continue
+		}
+		collection = append(collection, msg)
+	}
+
+	for _, id := range order {
+		if len(data[id]) == 0 {
+			continue
+		}
+		payload := &sparkplug.Payload{
+			Timestamp: uint64(time.Now().UnixMilli()),
+			Seq:       session.nextSeq(),
+			Metrics:   data[id],
+		}
+		buf, err := payload.Marshal()
+		if err != nil {
+			m.Log.Warnf("Could not serialize data of device %q: %v", id, err)
+			continue
+		}
+		collection = append(collection, message{m.sparkplugTopic(sparkplug.DeviceData, id), buf})
+	}
+
+	return collection
+}
+
+func (m *MQTT) generateSparkplugDeviceID(metric telegraf.Metric) (string, error) {
+	var b strings.Builder
+	if err := m.sparkplugDeviceIDGenerator.Execute(&b, metric.(telegraf.TemplateMetric)); err != nil {
+		return "", err
+	}
+
+	id := b.String()
+	if id == "" {
+		return "", errors.New("empty device ID")
+	}
+	if strings.ContainsAny(id, "/+#") {
+		return "", fmt.Errorf("device ID %q contains forbidden characters", id)
+	}
+	return id, nil
+}
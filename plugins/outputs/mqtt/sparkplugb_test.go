@@ -0,0 +1,143 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/mqtt"
+	"github.com/influxdata/telegraf/plugins/common/sparkplug"
+	"github.com/influxdata/telegraf/plugins/parsers/sparkplug_b"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestSparkplugBInitInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *MQTT
+		expected string
+	}{
+		{
+			name:     "missing group",
+			plugin:   &MQTT{SparkplugBEdgeNodeID: "edge1"},
+			expected: "missing 'sparkplugb_group_id' option",
+		},
+		{
+			name:     "missing edge node",
+			plugin:   &MQTT{SparkplugBGroupID: "plant"},
+			expected: "missing 'sparkplugb_edge_node_id' option",
+		},
+		{
+			name:     "invalid group",
+			plugin:   &MQTT{SparkplugBGroupID: "plant/a", SparkplugBEdgeNodeID: "edge1"},
+			expected: `found forbidden character in Sparkplug B ID "plant/a"`,
+		},
+		{
+			name: "unsupported protocol",
+			plugin: &MQTT{
+				SparkplugBGroupID:    "plant",
+				SparkplugBEdgeNodeID: "edge1",
+				MqttConfig:           mqtt.MqttConfig{Protocol: "5"},
+			},
+			expected: `layout 'sparkplugb' is not supported with protocol "5"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.plugin.Layout = "sparkplugb"
+			tt.plugin.Servers = []string{"tcp://localhost:1883"}
+			tt.plugin.Log = testutil.Logger{}
+			require.ErrorContains(t, tt.plugin.Init(), tt.expected)
+		})
+	}
+}
+
+func TestSparkplugBMessages(t *testing.T) {
+	plugin := &MQTT{
+		Layout:               "sparkplugb",
+		SparkplugBGroupID:    "plant",
+		SparkplugBEdgeNodeID: "edge1",
+		SparkplugBDeviceID:   `{{ .Name }}-{{ .Tag "id" }}`,
+		MqttConfig:           mqtt.MqttConfig{Servers: []string{"tcp://localhost:1883"}},
+		Log:                  testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.prepareSparkplugB())
+	require.NotNil(t, plugin.Will)
+	require.Equal(t, "spBv1.0/plant/NDEATH/edge1", plugin.Will.Topic)
+
+	// Decode the published messages to verify the session handling
+	parser := &sparkplug_b.Parser{MetricName: "sparkplug", Log: testutil.Logger{}}
+	require.NoError(t, parser.Init())
+	decode := func(msgs []message) ([]string, []telegraf.Metric) {
+		topics := make([]string, 0, len(msgs))
+		var metrics []telegraf.Metric
+		for _, msg := range msgs {
+			topics = append(topics, msg.topic)
+			ms, err := parser.ParseTopic(msg.topic, msg.payload)
+			require.NoError(t, err)
+			metrics = append(metrics, ms...)
+		}
+		return topics, metrics
+	}
+
+	ts := time.Unix(1700000000, 0)
+	plugin.sparkplug.rebirth.Store(true)
+	topics, actual := decode(plugin.collectSparkplugB([]telegraf.Metric{
+		metric.New("tank", map[string]string{"id": "1"}, map[string]interface{}{"level": int64(-3)}, ts),
+	}))
+	require.Equal(t, []string{
+		"spBv1.0/plant/NBIRTH/edge1",
+		"spBv1.0/plant/DBIRTH/edge1/tank-1",
+		"spBv1.0/plant/DDATA/edge1/tank-1",
+	}, topics)
+	require.Len(t, actual, 3)
+	require.Equal(t, map[string]interface{}{"bdSeq": uint64(0), "Node Control/Rebirth": false}, actual[0].Fields())
+	require.Equal(t, map[string]interface{}{"level": int64(-3)}, actual[1].Fields())
+
+	// Known metrics are published by alias only
+	topics, actual = decode(plugin.collectSparkplugB([]telegraf.Metric{
+		metric.New("tank", map[string]string{"id": "1"}, map[string]interface{}{"level": int64(5)}, ts.Add(time.Second)),
+	}))
+	require.Equal(t, []string{"spBv1.0/plant/DDATA/edge1/tank-1"}, topics)
+	expected := []telegraf.Metric{
+		metric.New(
+			"sparkplug",
+			map[string]string{"group_id": "plant", "edge_node_id": "edge1", "device_id": "tank-1", "message_type": "DDATA"},
+			map[string]interface{}{"level": int64(5)},
+			ts.Add(time.Second),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	// New fields require a rebirth of the device
+	topics, actual = decode(plugin.collectSparkplugB([]telegraf.Metric{
+		metric.New("tank", map[string]string{"id": "1"}, map[string]interface{}{"level": int64(6), "state": "full"}, ts.Add(2*time.Second)),
+	}))
+	require.Equal(t, []string{"spBv1.0/plant/DBIRTH/edge1/tank-1", "spBv1.0/plant/DDATA/edge1/tank-1"}, topics)
+	require.Equal(t, map[string]interface{}{"level": int64(6), "state": "full"}, actual[0].Fields())
+	require.Equal(t, map[string]interface{}{"level": int64(6), "state": "full"}, actual[1].Fields())
+
+	// The death certificate must reference the session
+	death, err := plugin.sparkplugDeath(plugin.sparkplug)
+	require.NoError(t, err)
+	var payload sparkplug.Payload
+	require.NoError(t, payload.Unmarshal(death.payload))
+	require.Nil(t, payload.Seq)
+	require.Len(t, payload.Metrics, 1)
+	require.Equal(t, "bdSeq", payload.Metrics[0].Name)
+	require.Equal(t, uint64(0), payload.Metrics[0].Value)
+
+	// Reconnecting must start a new session with updated death certificate
+	plugin.OnReconnecting()
+	require.NoError(t, payload.Unmarshal(plugin.Will.Payload))
+	require.Equal(t, uint64(1), payload.Metrics[0].Value)
+	plugin.sparkplug.rebirth.Store(true)
+	_, actual = decode(plugin.collectSparkplugB([]telegraf.Metric{
+		metric.New("tank", map[string]string{"id": "1"}, map[string]interface{}{"level": int64(7)}, ts.Add(3*time.Second)),
+	}))
+	require.Equal(t, map[string]interface{}{"bdSeq": uint64(1), "Node Control/Rebirth": false}, actual[0].Fields())
+}
@@ -0,0 +1,179 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchutil"
+
+	"github.com/influxdata/telegraf/internal"
+)
+
+// document is a metric prepared for indexing
+type document struct {
+	index string
+	body  []byte
+}
+
+// bulkResult is the outcome of indexing a single document. Documents without
+// result, e.g. due to a failing request, are not done.
+type bulkResult struct {
+	done   bool
+	status int
+	reason string
+}
+
+func (r *bulkResult) succeeded() bool {
+	return r.done && r.reason == "" && r.status < http.StatusMultipleChoices
+}
+
+// isRetryable returns true for statuses of bulk items indicating a temporary
+// failure where the document should be sent again
+func isRetryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+func (o *Opensearch) onSuccess(result *bulkResult) func(context.Context, opensearchutil.BulkIndexerItem, opensearchutil.BulkIndexerResponseItem) {
+	return func(_ context.Context, _ opensearchutil.BulkIndexerItem, res opensearchutil.BulkIndexerResponseItem) {
+		o.Log.Debugf("Indexed to OpenSearch with status- [%d] Result- %s DocumentID- %s ", res.Status, res.Result, res.DocumentID)
+		result.done = true
+		result.status = res.Status
+	}
+}
+
+func (o *Opensearch) onFailure(result *bulkResult) func(context.Context, opensearchutil.BulkIndexerItem, opensearchutil.BulkIndexerResponseItem, error) {
+	return func(_ context.Context, _ opensearchutil.BulkIndexerItem, res opensearchutil.BulkIndexerResponseItem, err error) {
+		if err != nil {
+			o.Log.Errorf("error while OpenSearch bulkIndexing: %v", err)
+			return
+		}
+		o.Log.Debugf("error while OpenSearch bulkIndexing with status %d: %s: %s", res.Status, res.Error.Type, res.Error.Reason)
+		result.done = true
+		result.status = res.Status
+		result.reason = res.Error.Type + ": " + res.Error.Reason
+		if res.Error.Type == "" {
+			result.reason = http.StatusText(res.Status)
+		}
+	}
+}
+
+// handleBulkFailures evaluates the result of each document of the bulk
+// requests. Successfully indexed documents are accepted, documents failing
+// temporarily are kept for retrying them in the next write and all other
+// documents, e.g. failing due to mapping conflicts, are rejected. Rejected
+// documents are written to the reject index if configured. When creating
+// documents, version conflicts indicate that the document already exists,
+// e.g. due to a retried write, so those documents are accepted.
+func (o *Opensearch) handleBulkFailures(ctx context.Context, docs []document, results []bulkResult) error {
+	writeErr := &internal.PartialWriteError{
+		MetricsAccept: make([]int, 0, len(docs)),
+	}
+
+	var retry int
+	var rejected []int
+	for i := range results {
+		switch {
+		case results[i].succeeded():
+			writeErr.MetricsAccept = append(writeErr.MetricsAccept, i)
+		case results[i].done && results[i].status == http.StatusConflict && o.action() == "create":
+			o.Log.Debugf("Document already exists in %q: %s", docs[i].index, results[i].reason)
+			writeErr.MetricsAccept = append(writeErr.MetricsAccept, i)
+		case !results[i].done || isRetryable(results[i].status):
+			retry++
+		default:
+			rejected = append(rejected, i)
+		}
+	}
+
+	// Try to store the rejected documents in the reject index and accept them
+	// if this succeeded. If the reject index is not available, keep the
+	// documents to avoid losing them.
+	var delivered []bool
+	if o.RejectIndex != "" && len(rejected) > 0 {
+		var err error
+		delivered, err = o.writeRejects(ctx, docs, results, rejected)
+		if err != nil {
+			o.Log.Errorf("Writing rejected documents to %q failed: %v", o.RejectIndex, err)
+			retry += len(rejected)
+			rejected = nil
+		}
+	}
+
+	var nrejected int
+	var last *bulkResult
+	for j, i := range rejected {
+		if delivered != nil && delivered[j] {
+			writeErr.MetricsAccept = append(writeErr.MetricsAccept, i)
+			continue
+		}
+		nrejected++
+		last = &results[i]
+		writeErr.MetricsReject = append(writeErr.MetricsReject, i)
+		writeErr.MetricsRejectErrors = append(writeErr.MetricsRejectErrors, errors.New(results[i].reason))
+	}
+
+	if last != nil {
+		o.Log.Errorf("OpenSearch rejected %d documents, last error status: %d, error: %s", nrejected, last.status, last.reason)
+	}
+
+	if retry == 0 && nrejected == 0 {
+		return nil
+	}
+	writeErr.Err = fmt.Errorf("failed to index %d documents, %d rejected and %d kept for retry", retry+nrejected, nrejected, retry)
+	return writeErr
+}
+
+// writeRejects writes the rejected documents to the reject index and returns
+// which of the documents were stored successfully
+func (o *Opensearch) writeRejects(ctx context.Context, docs []document, results []bulkResult, rejected []int) ([]bool, error) {
+	indexer, err := createBulkIndexer(o, "")
+	if err != nil {
+		return nil, err
+	}
+
+	rejectResults := make([]bulkResult, len(rejected))
+	for j, i := range rejected {
+		var original map[string]interface{}
+		if err := json.Unmarshal(docs[i].body, &original); err != nil {
+			return nil, fmt.Errorf("decoding document failed: %w", err)
+		}
+		doc := map[string]interface{}{
+			"@timestamp":       original["@timestamp"],
+			"measurement_name": original["measurement_name"],
+			"index":            docs[i].index,
+			"error": map[string]interface{}{
+				"status": results[i].status,
+				"reason": results[i].reason,
+			},
+			"document": string(docs[i].body),
+		}
+		body, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("encoding reject document failed: %w", err)
+		}
+
+		item := opensearchutil.BulkIndexerItem{
+			Action:    o.action(),
+			Index:     o.RejectIndex,
+			Body:      bytes.NewReader(body),
+			OnSuccess: o.onSuccess(&rejectResults[j]),
+			OnFailure: o.onFailure(&rejectResults[j]),
+		}
+		if err := indexer.Add(ctx, item); err != nil {
+			return nil, err
+		}
+	}
+	if err := indexer.Close(ctx); err != nil {
+		return nil, err
+	}
+
+	delivered := make([]bool, len(rejected))
+	for j := range rejectResults {
+		delivered[j] = rejectResults[j].succeeded()
+	}
+	return delivered, nil
+}
@@ -43,6 +43,8 @@ type Opensearch struct {
 	TemplateName        string          `toml:"template_name"`
 	ManageTemplate      bool            `toml:"manage_template"`
 	OverwriteTemplate   bool            `toml:"overwrite_template"`
+	DataStream          bool            `toml:"data_stream"`
+	RejectIndex         string          `toml:"reject_index"`
 	DefaultPipeline     string          `toml:"default_pipeline"`
 	UsePipeline         string          `toml:"use_pipeline"`
 	Timeout             config.Duration `toml:"timeout"`
@@ -54,8 +56,6 @@ type Opensearch struct {
 
 	indexTmpl    *template.Template
 	pipelineTmpl *template.Template
-	onSucc       func(context.Context, opensearchutil.BulkIndexerItem, opensearchutil.BulkIndexerResponseItem)
-	onFail       func(context.Context, opensearchutil.BulkIndexerItem, opensearchutil.BulkIndexerResponseItem, error)
 	osClient     *opensearch.Client
 }
 
@@ -64,6 +64,7 @@ var indexTemplate string
 
 type templatePart struct {
 	TemplatePattern string
+	DataStream      bool
 }
 
 func (*Opensearch) SampleConfig() string {
@@ -97,17 +98,6 @@ func (o *Opensearch) Init() error {
 	}
 	o.pipelineTmpl = pipelineTmpl
 
-	o.onSucc = func(_ context.Context, _ opensearchutil.BulkIndexerItem, res opensearchutil.BulkIndexerResponseItem) {
-		o.Log.Debugf("Indexed to OpenSearch with status- [%d] Result- %s DocumentID- %s ", res.Status, res.Result, res.DocumentID)
-	}
-	o.onFail = func(_ context.Context, _ opensearchutil.BulkIndexerItem, res opensearchutil.BulkIndexerResponseItem, err error) {
-		if err != nil {
-			o.Log.Errorf("error while OpenSearch bulkIndexing: %v", err)
-		} else {
-			o.Log.Errorf("error while OpenSearch bulkIndexing: %s: %s", res.Error.Type, res.Error.Reason)
-		}
-	}
-
 	if o.TemplateName == "" {
 		return errors.New("template_name configuration not defined")
 	}
@@ -220,7 +210,9 @@ func (o *Opensearch) Write(metrics []telegraf.Metric) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(o.Timeout))
 	defer cancel()
 
-	for _, metric := range metrics {
+	docs := make([]document, len(metrics))
+	results := make([]bulkResult, len(metrics))
+	for i, metric := range metrics {
 		var name = metric.Name()
 
 		// index name has to be re-evaluated each time for telegraf
@@ -261,12 +253,14 @@ func (o *Opensearch) Write(metrics []telegraf.Metric) error {
 			return fmt.Errorf("failed to marshal body: %w", err)
 		}
 
+		docs[i] = document{index: indexName, body: body}
+
 		bulkIndxrItem := opensearchutil.BulkIndexerItem{
-			Action:    "index",
+			Action:    o.action(),
 			Index:     indexName,
-			Body:      strings.NewReader(string(body)),
-			OnSuccess: o.onSucc,
-			OnFailure: o.onFail,
+			Body:      bytes.NewReader(body),
+			OnSuccess: o.onSuccess(&results[i]),
+			OnFailure: o.onFailure(&results[i]),
 		}
 		if o.ForceDocumentID {
 			bulkIndxrItem.DocumentID = getPointID(metric)
@@ -293,6 +287,7 @@ func (o *Opensearch) Write(metrics []telegraf.Metric) error {
 		}
 	}
 
+	var failed uint64
 	for _, bulkIndxr := range indexers {
 		if err := bulkIndxr.Close(ctx); err != nil {
 			return fmt.Errorf("error sending bulk request to OpenSearch: %w", err)
@@ -300,16 +295,27 @@ func (o *Opensearch) Write(metrics []telegraf.Metric) error {
 
 		// Report the indexer statistics
 		stats := bulkIndxr.Stats()
-		if stats.NumFailed > 0 {
-			return fmt.Errorf("failed to index [%d] documents", stats.NumFailed)
-		}
+		failed += stats.NumFailed
+
+		o.Log.Debugf("Successfully indexed [%d] documents", stats.NumFlushed)
+	}
 
-		o.Log.Debugf("Successfully indexed [%d] documents", stats.NumAdded)
+	if failed > 0 {
+		return o.handleBulkFailures(ctx, docs, results)
 	}
 
 	return nil
 }
 
+// action returns the bulk action for indexing documents as data streams only
+// accept the "create" action
+func (o *Opensearch) action() string {
+	if o.DataStream {
+		return "create"
+	}
+	return "index"
+}
+
 // BulkIndexer supports pipeline at config level so separate indexer instance for each unique pipeline
 func getTargetIndexers(metrics []telegraf.Metric, osInst *Opensearch) map[string]opensearchutil.BulkIndexer {
 	var indexers = make(map[string]opensearchutil.BulkIndexer)
@@ -405,6 +411,16 @@ func (o *Opensearch) manageTemplate(ctx context.Context) error {
 	}
 
 	templateExists := resp.Body != http.NoBody
+	if o.DataStream {
+		// Data streams require composable index templates
+		existsReq := opensearchapi.IndicesExistsIndexTemplateRequest{Name: o.TemplateName}
+		existsResp, err := existsReq.Do(ctx, o.osClient.Transport)
+		if err != nil {
+			return fmt.Errorf("template check failed, template name: %s, error: %w", o.TemplateName, err)
+		}
+		existsResp.Body.Close()
+		templateExists = existsResp.StatusCode == http.StatusOK
+	}
 	templatePattern := o.IndexName
 
 	if strings.Contains(templatePattern, "{{") {
@@ -418,6 +434,7 @@ func (o *Opensearch) manageTemplate(ctx context.Context) error {
 	if o.OverwriteTemplate || !templateExists || templatePattern != "" {
 		tp := templatePart{
 			TemplatePattern: templatePattern + "*",
+			DataStream:      o.DataStream,
 		}
 
 		t := template.Must(template.New("template").Parse(indexTemplate))
@@ -427,10 +444,16 @@ func (o *Opensearch) manageTemplate(ctx context.Context) error {
 			return err
 		}
 
-		indexTempReq := opensearchapi.IndicesPutTemplateRequest{
+		var indexTempReq opensearchapi.Request = opensearchapi.IndicesPutTemplateRequest{
 			Name: o.TemplateName,
 			Body: strings.NewReader(tmpl.String()),
 		}
+		if o.DataStream {
+			indexTempReq = opensearchapi.IndicesPutIndexTemplateRequest{
+				Name: o.TemplateName,
+				Body: strings.NewReader(tmpl.String()),
+			}
+		}
 		indexTempResp, err := indexTempReq.Do(ctx, o.osClient.Transport)
 
 		if err != nil || indexTempResp.StatusCode != 200 {
@@ -1,8 +1,15 @@
 package opensearch
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"text/template"
 	"time"
@@ -10,7 +17,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go/wait"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -358,3 +367,224 @@ func TestDisconnectedServerOnWrite(t *testing.T) {
 	err = e.Write(testutil.MockMetrics())
 	require.Error(t, err)
 }
+
+func TestDataStreamTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("template").Parse(indexTemplate))
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, templatePart{TemplatePattern: "telegraf*", DataStream: true}))
+
+	var actual struct {
+		IndexPatterns []string               `json:"index_patterns"`
+		DataStream    map[string]interface{} `json:"data_stream"`
+		Template      map[string]interface{} `json:"template"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+	require.Equal(t, []string{"telegraf*"}, actual.IndexPatterns)
+	require.NotNil(t, actual.DataStream)
+	require.Contains(t, actual.Template, "settings")
+	require.Contains(t, actual.Template, "mappings")
+}
+
+func TestBulkFailures(t *testing.T) {
+	// The status of each document is determined by its measurement name
+	statuses := map[string]string{
+		"ok":          `{"status": 201}`,
+		"throttled":   `{"status": 429, "error": {"type": "es_rejected_execution_exception", "reason": "queue full"}}`,
+		"conflict":    `{"status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed to parse field [value]"}}`,
+		"unavailable": `{"status": 503, "error": {"type": "unavailable_shards_exception", "reason": "primary shard is not active"}}`,
+	}
+
+	tests := []struct {
+		name           string
+		rejectIndex    string
+		rejectStatus   string
+		expectedAccept []int
+		expectedReject []int
+	}{
+		{
+			name:           "without reject index",
+			expectedAccept: []int{0},
+			expectedReject: []int{2},
+		},
+		{
+			name:           "with reject index",
+			rejectIndex:    "rejects",
+			rejectStatus:   `{"status": 201}`,
+			expectedAccept: []int{0, 2},
+		},
+		{
+			name:           "reject index failure",
+			rejectIndex:    "rejects",
+			rejectStatus:   `{"status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed"}}`,
+			expectedAccept: []int{0},
+			expectedReject: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var rejected []string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/_bulk" {
+					if _, err := w.Write([]byte(`{"version": {"number": "2.8.0"}}`)); err != nil {
+						t.Error(err)
+					}
+					return
+				}
+
+				// Respond to each action and document pair of the request
+				var items []string
+				scanner := bufio.NewScanner(r.Body)
+				for scanner.Scan() {
+					meta := scanner.Text()
+					if !scanner.Scan() {
+						break
+					}
+					var doc struct {
+						Name string `json:"measurement_name"`
+					}
+					if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+						w.WriteHeader(http.StatusInternalServerError)
+						t.Error(err)
+						return
+					}
+					status := statuses[doc.Name]
+					if strings.Contains(meta, `"_index":"rejects"`) {
+						mu.Lock()
+						rejected = append(rejected, scanner.Text())
+						mu.Unlock()
+						status = tt.rejectStatus
+					}
+					items = append(items, `{"index": `+status+`}`)
+				}
+				resp := fmt.Sprintf(`{"took": 1, "errors": true, "items": [%s]}`, strings.Join(items, ","))
+				if _, err := w.Write([]byte(resp)); err != nil {
+					t.Error(err)
+				}
+			}))
+			defer ts.Close()
+
+			e := &Opensearch{
+				URLs:         []string{"http://" + ts.Listener.Addr().String()},
+				IndexName:    "test",
+				TemplateName: "telegraf",
+				RejectIndex:  tt.rejectIndex,
+				Timeout:      config.Duration(time.Second * 5),
+				Log:          testutil.Logger{},
+			}
+			require.NoError(t, e.Init())
+			require.NoError(t, e.Connect())
+
+			metrics := []telegraf.Metric{
+				testutil.TestMetric(1.0, "ok"),
+				testutil.TestMetric(2.0, "throttled"),
+				testutil.TestMetric(3.0, "conflict"),
+				testutil.TestMetric(4.0, "unavailable"),
+			}
+			err := e.Write(metrics)
+
+			var writeErr *internal.PartialWriteError
+			require.True(t, errors.As(err, &writeErr))
+			require.Equal(t, tt.expectedAccept, writeErr.MetricsAccept)
+			require.Equal(t, tt.expectedReject, writeErr.MetricsReject)
+			require.Len(t, writeErr.MetricsRejectErrors, len(tt.expectedReject))
+
+			if tt.rejectIndex == "" {
+				require.Empty(t, rejected)
+				return
+			}
+			require.Len(t, rejected, 1)
+			require.Contains(t, rejected[0], `"index":"test"`)
+			require.Contains(t, rejected[0], `"reason":"mapper_parsing_exception: failed to parse field [value]"`)
+			require.Contains(t, rejected[0], `"document":"{`)
+		})
+	}
+}
+
+func TestBulkConflictCreate(t *testing.T) {
+	// The status of each document is determined by its measurement name
+	statuses := map[string]string{
+		"ok":     `{"status": 201}`,
+		"exists": `{"status": 409, "error": {"type": "version_conflict_engine_exception", "reason": "document already exists"}}`,
+	}
+
+	tests := []struct {
+		name           string
+		dataStream     bool
+		expectedAccept []int
+		expectedReject []int
+	}{
+		{
+			name:           "index action",
+			expectedAccept: []int{0},
+			expectedReject: []int{1},
+		},
+		{
+			name:       "create action",
+			dataStream: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/_bulk" {
+					if _, err := w.Write([]byte(`{"version": {"number": "2.8.0"}}`)); err != nil {
+						t.Error(err)
+					}
+					return
+				}
+
+				// Respond to each action and document pair of the request
+				var items []string
+				scanner := bufio.NewScanner(r.Body)
+				for scanner.Scan() {
+					if !scanner.Scan() {
+						break
+					}
+					var doc struct {
+						Name string `json:"measurement_name"`
+					}
+					if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+						w.WriteHeader(http.StatusInternalServerError)
+						t.Error(err)
+						return
+					}
+					items = append(items, `{"create": `+statuses[doc.Name]+`}`)
+				}
+				resp := fmt.Sprintf(`{"took": 1, "errors": true, "items": [%s]}`, strings.Join(items, ","))
+				if _, err := w.Write([]byte(resp)); err != nil {
+					t.Error(err)
+				}
+			}))
+			defer ts.Close()
+
+			e := &Opensearch{
+				URLs:         []string{"http://" + ts.Listener.Addr().String()},
+				IndexName:    "test",
+				TemplateName: "telegraf",
+				DataStream:   tt.dataStream,
+				Timeout:      config.Duration(time.Second * 5),
+				Log:          testutil.Logger{},
+			}
+			require.NoError(t, e.Init())
+			require.NoError(t, e.Connect())
+
+			metrics := []telegraf.Metric{
+				testutil.TestMetric(1.0, "ok"),
+				testutil.TestMetric(2.0, "exists"),
+			}
+			err := e.Write(metrics)
+			if len(tt.expectedReject) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var writeErr *internal.PartialWriteError
+			require.True(t, errors.As(err, &writeErr))
+			require.Equal(t, tt.expectedAccept, writeErr.MetricsAccept)
+			require.Equal(t, tt.expectedReject, writeErr.MetricsReject)
+		})
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/influxdata/influxdb-observability/common"
 	"github.com/influxdata/influxdb-observability/influx2otel"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 	_ "google.golang.org/grpc/encoding/gzip" // Blank import to allow gzip encoding
@@ -18,6 +17,7 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/internal"
+	common_otel "github.com/influxdata/telegraf/plugins/common/opentelemetry"
 	"github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/outputs"
 )
@@ -70,7 +70,7 @@ func (*OpenTelemetry) SampleConfig() string {
 }
 
 func (o *OpenTelemetry) Connect() error {
-	logger := &otelLogger{o.Log}
+	logger := &common_otel.Logger{Logger: o.Log}
 	if o.ServiceAddress == "" {
 		o.ServiceAddress = defaultServiceAddress
 	}
@@ -160,32 +160,7 @@ func (o *OpenTelemetry) Write(metrics []telegraf.Metric) error {
 }
 
 func (o *OpenTelemetry) sendBatch(metrics []telegraf.Metric) error {
-	batch := o.metricsConverter.NewBatch()
-	for _, metric := range metrics {
-		var vType common.InfluxMetricValueType
-		switch metric.Type() {
-		case telegraf.Gauge:
-			vType = common.InfluxMetricValueTypeGauge
-		case telegraf.Untyped:
-			vType = common.InfluxMetricValueTypeUntyped
-		case telegraf.Counter:
-			vType = common.InfluxMetricValueTypeSum
-		case telegraf.Histogram:
-			vType = common.InfluxMetricValueTypeHistogram
-		case telegraf.Summary:
-			vType = common.InfluxMetricValueTypeSummary
-		default:
-			o.Log.Warnf("Unrecognized metric type %v", metric.Type())
-			continue
-		}
-		err := batch.AddPoint(metric.Name(), metric.Tags(), metric.Fields(), metric.Time(), vType)
-		if err != nil {
-			o.Log.Warnf("Failed to add point: %v", err)
-			continue
-		}
-	}
-
-	md := pmetricotlp.NewExportRequestFromMetrics(batch.GetMetrics())
+	md := pmetricotlp.NewExportRequestFromMetrics(common_otel.EncodeMetrics(o.metricsConverter, metrics, o.Log))
 	if md.Metrics().ResourceMetrics().Len() == 0 {
 		return nil
 	}
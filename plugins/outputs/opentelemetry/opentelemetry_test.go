@@ -87,6 +87,72 @@ func TestOpenTelemetry(t *testing.T) {
 	require.JSONEq(t, string(expectJSON), string(gotJSON))
 }
 
+func TestOpenTelemetryExponentialHistogram(t *testing.T) {
+	expect := pmetric.NewMetrics()
+	{
+		rm := expect.ResourceMetrics().AppendEmpty()
+		ilm := rm.ScopeMetrics().AppendEmpty()
+		m := ilm.Metrics().AppendEmpty()
+		m.SetName("latency")
+		eh := m.SetEmptyExponentialHistogram()
+		eh.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		dp := eh.DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("method", "GET")
+		dp.SetTimestamp(pcommon.Timestamp(1622848686000000000))
+		dp.SetScale(1)
+		dp.SetCount(6)
+		dp.SetSum(12.5)
+		dp.SetZeroCount(1)
+		dp.SetZeroThreshold(0.001)
+		dp.Positive().SetOffset(-1)
+		dp.Positive().BucketCounts().FromRaw([]uint64{2, 0, 3})
+		dp.Negative().SetOffset(-1)
+	}
+	m := newMockOtelService(t)
+	t.Cleanup(m.Cleanup)
+
+	metricsConverter, err := influx2otel.NewLineProtocolToOtelMetrics(common.NoopLogger{})
+	require.NoError(t, err)
+	plugin := &OpenTelemetry{
+		ServiceAddress:   m.Address(),
+		Timeout:          config.Duration(time.Second),
+		Headers:          map[string]string{"test": "header1"},
+		metricsConverter: metricsConverter,
+		otlpMetricClient: &gRPCClient{
+			grpcClientConn:       m.GrpcClient(),
+			metricsServiceClient: pmetricotlp.NewGRPCClient(m.GrpcClient()),
+		},
+		Log: testutil.Logger{},
+	}
+
+	h := &metric.ExponentialHistogram{
+		Schema:        1,
+		ZeroThreshold: 0.001,
+		ZeroCount:     1,
+		Count:         6,
+		Sum:           12.5,
+	}
+	h.SetPositiveBucketCounts(0, []float64{2, 0, 3})
+	input := metric.New(
+		"latency",
+		map[string]string{"method": "GET"},
+		h.Fields(),
+		time.Unix(0, 1622848686000000000),
+		telegraf.Histogram,
+	)
+
+	require.NoError(t, plugin.Write([]telegraf.Metric{input}))
+
+	marshaller := pmetric.JSONMarshaler{}
+	expectJSON, err := marshaller.MarshalMetrics(expect)
+	require.NoError(t, err)
+
+	gotJSON, err := marshaller.MarshalMetrics(m.GotMetrics())
+	require.NoError(t, err)
+
+	require.JSONEq(t, string(expectJSON), string(gotJSON))
+}
+
 func TestOpenTelemetryHTTPProtobuf(t *testing.T) {
 	expect := pmetric.NewMetrics()
 	{
@@ -5,18 +5,20 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
-	"github.com/apache/arrow-go/v18/arrow/array"
-	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/apache/arrow-go/v18/parquet"
 	"github.com/apache/arrow-go/v18/parquet/pqarrow"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/columnar"
+	"github.com/influxdata/telegraf/plugins/common/partition"
 	"github.com/influxdata/telegraf/plugins/outputs"
 )
 
@@ -27,18 +29,30 @@ var defaultTimestampFieldName = "timestamp"
 
 type metricGroup struct {
 	filename string
-	builder  *array.RecordBuilder
 	schema   *arrow.Schema
 	writer   *pqarrow.FileWriter
 }
 
+// partitionWriter writes the metrics of a partition using the schema derived
+// from the first metrics written to the file
+type partitionWriter struct {
+	*pqarrow.FileWriter
+	schema *arrow.Schema
+}
+
 type Parquet struct {
 	Directory          string          `toml:"directory"`
+	PathTemplate       string          `toml:"path_template"`
 	RotationInterval   config.Duration `toml:"rotation_interval"`
+	RotationMaxSize    config.Size     `toml:"rotation_max_size"`
+	MaxOpenFiles       int             `toml:"max_open_files"`
 	TimestampFieldName string          `toml:"timestamp_field_name"`
 	Log                telegraf.Logger `toml:"-"`
 
+	converter    *columnar.Converter
 	metricGroups map[string]*metricGroup
+	template     *partition.Template
+	partitions   *partition.Pool[*partitionWriter]
 }
 
 func (*Parquet) SampleConfig() string {
@@ -59,8 +73,26 @@ func (p *Parquet) Init() error {
 		return fmt.Errorf("provided directory %q is not a directory", p.Directory)
 	}
 
+	p.converter = &columnar.Converter{TimestampColumn: p.TimestampFieldName, Strict: true}
 	p.metricGroups = make(map[string]*metricGroup)
 
+	if p.PathTemplate != "" {
+		if p.MaxOpenFiles < 0 {
+			return fmt.Errorf("invalid 'max_open_files' %d", p.MaxOpenFiles)
+		}
+		p.template, err = partition.NewTemplate(p.PathTemplate)
+		if err != nil {
+			return err
+		}
+		p.partitions = &partition.Pool[*partitionWriter]{
+			MaxOpen:  p.MaxOpenFiles,
+			Interval: time.Duration(p.RotationInterval),
+			MaxSize:  int64(p.RotationMaxSize),
+		}
+	} else if p.RotationMaxSize != 0 {
+		p.Log.Warn("Setting 'rotation_max_size' has no effect without 'path_template'")
+	}
+
 	return nil
 }
 
@@ -78,6 +110,13 @@ func (p *Parquet) Close() error {
 		}
 	}
 
+	if p.partitions != nil {
+		if err := p.partitions.Close(); err != nil {
+			p.Log.Errorf("failed to close files: %v", err)
+			errorOccurred = true
+		}
+	}
+
 	if errorOccurred {
 		return errors.New("failed closing one or more parquet files")
 	}
@@ -86,6 +125,10 @@ func (p *Parquet) Close() error {
 }
 
 func (p *Parquet) Write(metrics []telegraf.Metric) error {
+	if p.template != nil {
+		return p.writePartitions(metrics)
+	}
+
 	groupedMetrics := make(map[string][]telegraf.Metric)
 	for _, metric := range metrics {
 		groupedMetrics[metric.Name()] = append(groupedMetrics[metric.Name()], metric)
@@ -95,7 +138,7 @@ func (p *Parquet) Write(metrics []telegraf.Metric) error {
 	for name, metrics := range groupedMetrics {
 		if _, ok := p.metricGroups[name]; !ok {
 			filename := fmt.Sprintf("%s/%s-%s-%s.parquet", p.Directory, name, now.Format("2006-01-02"), strconv.FormatInt(now.Unix(), 10))
-			schema, err := p.createSchema(metrics)
+			schema, err := p.converter.Schema(metrics)
 			if err != nil {
 				return fmt.Errorf("failed to create schema for file %q: %w", name, err)
 			}
@@ -104,7 +147,6 @@ func (p *Parquet) Write(metrics []telegraf.Metric) error {
 				return fmt.Errorf("failed to create writer for file %q: %w", name, err)
 			}
 			p.metricGroups[name] = &metricGroup{
-				builder:  array.NewRecordBuilder(memory.DefaultAllocator, schema),
 				filename: filename,
 				schema:   schema,
 				writer:   writer,
@@ -117,7 +159,8 @@ func (p *Parquet) Write(metrics []telegraf.Metric) error {
 			}
 		}
 
-		record, err := p.createRecordBatch(metrics, p.metricGroups[name].builder, p.metricGroups[name].schema)
+		metrics = p.matching(metrics, p.metricGroups[name].schema, p.metricGroups[name].filename)
+		record, err := p.converter.RecordBatch(metrics, p.metricGroups[name].schema)
 		if err != nil {
 			return fmt.Errorf("failed to create record for file %q: %w", p.metricGroups[name].filename, err)
 		}
@@ -130,6 +173,73 @@ func (p *Parquet) Write(metrics []telegraf.Metric) error {
 	return nil
 }
 
+// writePartitions writes the metrics to the files generated by the path
+// template for each metric. Each write creates a new row group so the size of
+// the files is known for rolling over.
+func (p *Parquet) writePartitions(metrics []telegraf.Metric) error {
+	// Group the metrics by partition keeping the order of the metrics
+	var paths []string
+	groups := make(map[string][]telegraf.Metric)
+	for _, m := range metrics {
+		path, err := p.template.Path(m)
+		if err != nil {
+			p.Log.Errorf("Could not determine file for metric %q: %v", m.Name(), err)
+			continue
+		}
+		path = filepath.Join(p.Directory, path)
+		if _, found := groups[path]; !found {
+			paths = append(paths, path)
+		}
+		groups[path] = append(groups[path], m)
+	}
+
+	for _, path := range paths {
+		metrics := groups[path]
+		writer, err := p.partitions.Get(path, func(w io.Writer) (*partitionWriter, error) {
+			schema, err := p.converter.Schema(metrics)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create schema: %w", err)
+			}
+			fw, err := pqarrow.NewFileWriter(schema, w, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+			if err != nil {
+				return nil, fmt.Errorf("failed to create parquet writer: %w", err)
+			}
+			return &partitionWriter{FileWriter: fw, schema: schema}, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open file for %q: %w", path, err)
+		}
+		filename := p.partitions.Filename(path)
+
+		metrics = p.matching(metrics, writer.schema, filename)
+		record, err := p.converter.RecordBatch(metrics, writer.schema)
+		if err != nil {
+			return fmt.Errorf("failed to create record for file %q: %w", filename, err)
+		}
+		err = writer.Write(record)
+		record.Release()
+		if err != nil {
+			return fmt.Errorf("failed to write to file %q: %w", filename, err)
+		}
+	}
+
+	return nil
+}
+
+// matching returns the metrics matching the schema of the file and drops all
+// others as they cannot be written to the file
+func (p *Parquet) matching(metrics []telegraf.Metric, schema *arrow.Schema, filename string) []telegraf.Metric {
+	valid := make([]telegraf.Metric, 0, len(metrics))
+	for _, m := range metrics {
+		if err := p.converter.Validate(m, schema); err != nil {
+			p.Log.Errorf("Dropping metric %q not matching the schema of file %q: %v", m.Name(), filename, err)
+			continue
+		}
+		valid = append(valid, m)
+	}
+	return valid
+}
+
 func (p *Parquet) rotateIfNeeded(name string) error {
 	fileInfo, err := os.Stat(p.metricGroups[name].filename)
 	if err != nil {
@@ -154,128 +264,6 @@ func (p *Parquet) rotateIfNeeded(name string) error {
 	return nil
 }
 
-func (p *Parquet) createRecordBatch(metrics []telegraf.Metric, builder *array.RecordBuilder, schema *arrow.Schema) (arrow.RecordBatch, error) {
-	for index, col := range schema.Fields() {
-		for _, m := range metrics {
-			if p.TimestampFieldName != "" && col.Name == p.TimestampFieldName {
-				builder.Field(index).(*array.Int64Builder).Append(m.Time().UnixNano())
-				continue
-			}
-
-			// Try to get the value from a field first, then from a tag.
-			var value any
-			var ok bool
-			value, ok = m.GetField(col.Name)
-			if !ok {
-				value, ok = m.GetTag(col.Name)
-			}
-
-			// if neither field nor tag exists, append a null value
-			if !ok {
-				switch col.Type {
-				case arrow.PrimitiveTypes.Int8:
-					builder.Field(index).(*array.Int8Builder).AppendNull()
-				case arrow.PrimitiveTypes.Int16:
-					builder.Field(index).(*array.Int16Builder).AppendNull()
-				case arrow.PrimitiveTypes.Int32:
-					builder.Field(index).(*array.Int32Builder).AppendNull()
-				case arrow.PrimitiveTypes.Int64:
-					builder.Field(index).(*array.Int64Builder).AppendNull()
-				case arrow.PrimitiveTypes.Uint8:
-					builder.Field(index).(*array.Uint8Builder).AppendNull()
-				case arrow.PrimitiveTypes.Uint16:
-					builder.Field(index).(*array.Uint16Builder).AppendNull()
-				case arrow.PrimitiveTypes.Uint32:
-					builder.Field(index).(*array.Uint32Builder).AppendNull()
-				case arrow.PrimitiveTypes.Uint64:
-					builder.Field(index).(*array.Uint64Builder).AppendNull()
-				case arrow.PrimitiveTypes.Float32:
-					builder.Field(index).(*array.Float32Builder).AppendNull()
-				case arrow.PrimitiveTypes.Float64:
-					builder.Field(index).(*array.Float64Builder).AppendNull()
-				case arrow.BinaryTypes.String:
-					builder.Field(index).(*array.StringBuilder).AppendNull()
-				case arrow.FixedWidthTypes.Boolean:
-					builder.Field(index).(*array.BooleanBuilder).AppendNull()
-				default:
-					return nil, fmt.Errorf("unsupported type: %T", value)
-				}
-
-				continue
-			}
-
-			switch col.Type {
-			case arrow.PrimitiveTypes.Int8:
-				builder.Field(index).(*array.Int8Builder).Append(value.(int8))
-			case arrow.PrimitiveTypes.Int16:
-				builder.Field(index).(*array.Int16Builder).Append(value.(int16))
-			case arrow.PrimitiveTypes.Int32:
-				builder.Field(index).(*array.Int32Builder).Append(value.(int32))
-			case arrow.PrimitiveTypes.Int64:
-				builder.Field(index).(*array.Int64Builder).Append(value.(int64))
-			case arrow.PrimitiveTypes.Uint8:
-				builder.Field(index).(*array.Uint8Builder).Append(value.(uint8))
-			case arrow.PrimitiveTypes.Uint16:
-				builder.Field(index).(*array.Uint16Builder).Append(value.(uint16))
-			case arrow.PrimitiveTypes.Uint32:
-				builder.Field(index).(*array.Uint32Builder).Append(value.(uint32))
-			case arrow.PrimitiveTypes.Uint64:
-				builder.Field(index).(*array.Uint64Builder).Append(value.(uint64))
-			case arrow.PrimitiveTypes.Float32:
-				builder.Field(index).(*array.Float32Builder).Append(value.(float32))
-			case arrow.PrimitiveTypes.Float64:
-				builder.Field(index).(*array.Float64Builder).Append(value.(float64))
-			case arrow.BinaryTypes.String:
-				builder.Field(index).(*array.StringBuilder).Append(value.(string))
-			case arrow.FixedWidthTypes.Boolean:
-				builder.Field(index).(*array.BooleanBuilder).Append(value.(bool))
-			default:
-				return nil, fmt.Errorf("unsupported type: %T", value)
-			}
-		}
-	}
-
-	record := builder.NewRecordBatch()
-	return record, nil
-}
-
-func (p *Parquet) createSchema(metrics []telegraf.Metric) (*arrow.Schema, error) {
-	rawFields := make(map[string]arrow.DataType, 0)
-	for _, metric := range metrics {
-		for _, field := range metric.FieldList() {
-			if _, ok := rawFields[field.Key]; !ok {
-				arrowType, err := goToArrowType(field.Value)
-				if err != nil {
-					return nil, fmt.Errorf("error converting '%s=%s' field to arrow type: %w", field.Key, field.Value, err)
-				}
-				rawFields[field.Key] = arrowType
-			}
-		}
-		for _, tag := range metric.TagList() {
-			if _, ok := rawFields[tag.Key]; !ok {
-				rawFields[tag.Key] = arrow.BinaryTypes.String
-			}
-		}
-	}
-
-	fields := make([]arrow.Field, 0)
-	for key, value := range rawFields {
-		fields = append(fields, arrow.Field{
-			Name: key,
-			Type: value,
-		})
-	}
-
-	if p.TimestampFieldName != "" {
-		fields = append(fields, arrow.Field{
-			Name: p.TimestampFieldName,
-			Type: arrow.PrimitiveTypes.Int64,
-		})
-	}
-
-	return arrow.NewSchema(fields, nil), nil
-}
-
 func (p *Parquet) createWriter(name, filename string, schema *arrow.Schema) (*pqarrow.FileWriter, error) {
 	if _, err := os.Stat(filename); err == nil {
 		now := time.Now()
@@ -297,41 +285,11 @@ func (p *Parquet) createWriter(name, filename string, schema *arrow.Schema) (*pq
 	return writer, nil
 }
 
-func goToArrowType(value interface{}) (arrow.DataType, error) {
-	switch value.(type) {
-	case int8:
-		return arrow.PrimitiveTypes.Int8, nil
-	case int16:
-		return arrow.PrimitiveTypes.Int16, nil
-	case int32:
-		return arrow.PrimitiveTypes.Int32, nil
-	case int64, int:
-		return arrow.PrimitiveTypes.Int64, nil
-	case uint8:
-		return arrow.PrimitiveTypes.Uint8, nil
-	case uint16:
-		return arrow.PrimitiveTypes.Uint16, nil
-	case uint32:
-		return arrow.PrimitiveTypes.Uint32, nil
-	case uint64, uint:
-		return arrow.PrimitiveTypes.Uint64, nil
-	case float32:
-		return arrow.PrimitiveTypes.Float32, nil
-	case float64:
-		return arrow.PrimitiveTypes.Float64, nil
-	case string:
-		return arrow.BinaryTypes.String, nil
-	case bool:
-		return arrow.FixedWidthTypes.Boolean, nil
-	default:
-		return nil, fmt.Errorf("unsupported type: %T", value)
-	}
-}
-
 func init() {
 	outputs.Add("parquet", func() telegraf.Output {
 		return &Parquet{
 			TimestampFieldName: defaultTimestampFieldName,
+			MaxOpenFiles:       16,
 		}
 	})
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
 )
 
 func TestCases(t *testing.T) {
@@ -245,3 +246,77 @@ func TestTimestampDifferentName(t *testing.T) {
 	require.Equal(t, 1, int(metadata.NumRows))
 	require.Equal(t, 2, metadata.Schema.NumColumns())
 }
+
+func TestPathTemplate(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	metrics := []telegraf.Metric{
+		metric.New("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.0}, ts),
+		metric.New("cpu", map[string]string{"host": "b"}, map[string]interface{}{"value": 2.0}, ts),
+		metric.New("mem", map[string]string{"host": "a"}, map[string]interface{}{"used": 3.0}, ts),
+		metric.New("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 4.0}, ts),
+	}
+
+	testDir := t.TempDir()
+	plugin := &Parquet{
+		Directory:          testDir,
+		PathTemplate:       `{{.Name}}/date={{.Time.Format "2006-01-02"}}/host={{.Tag "host"}}/part-*.parquet`,
+		RotationMaxSize:    config.Size(1),
+		MaxOpenFiles:       2,
+		TimestampFieldName: defaultTimestampFieldName,
+		Log:                testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	require.NoError(t, plugin.Write(metrics))
+
+	// Exceeding the size limit starts a new part
+	require.NoError(t, plugin.Write(metrics[:1]))
+	require.NoError(t, plugin.Close())
+
+	expected := map[string]int64{
+		filepath.Join("cpu", "date=2024-03-01", "host=a", "part-00000.parquet"): 2,
+		filepath.Join("cpu", "date=2024-03-01", "host=a", "part-00001.parquet"): 1,
+		filepath.Join("cpu", "date=2024-03-01", "host=b", "part-00000.parquet"): 1,
+		filepath.Join("mem", "date=2024-03-01", "host=a", "part-00000.parquet"): 1,
+	}
+	for name, rows := range expected {
+		reader, err := file.OpenParquetFile(filepath.Join(testDir, name), false)
+		require.NoError(t, err, name)
+		require.Equal(t, rows, reader.MetaData().NumRows, name)
+		reader.Close()
+	}
+}
+
+func TestSchemaMismatch(t *testing.T) {
+	testDir := t.TempDir()
+	plugin := &Parquet{
+		Directory: testDir,
+		Log:       testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+
+	// The schema is determined by the first write and columns are sorted by
+	// name. Metrics not matching the column types are dropped.
+	require.NoError(t, plugin.Write([]telegraf.Metric{
+		metric.New("test", map[string]string{"host": "a"}, map[string]interface{}{"value": 1.0}, time.Unix(0, 0)),
+	}))
+	require.NoError(t, plugin.Write([]telegraf.Metric{
+		metric.New("test", map[string]string{"host": "b"}, map[string]interface{}{"value": "invalid"}, time.Unix(1, 0)),
+		metric.New("test", map[string]string{"host": "c"}, map[string]interface{}{"value": 2.0}, time.Unix(2, 0)),
+	}))
+	require.NoError(t, plugin.Close())
+
+	files, err := os.ReadDir(testDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	reader, err := file.OpenParquetFile(filepath.Join(testDir, files[0].Name()), false)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	metadata := reader.MetaData()
+	require.Equal(t, 2, int(metadata.NumRows))
+	require.Equal(t, 2, metadata.Schema.NumColumns())
+	require.Equal(t, "host", metadata.Schema.Column(0).Name())
+	require.Equal(t, "value", metadata.Schema.Column(1).Name())
+}
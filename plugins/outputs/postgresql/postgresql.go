@@ -54,6 +54,7 @@ type Postgresql struct {
 	TagCacheSize               int                     `toml:"tag_cache_size"`
 	ColumnNameLenLimit         int                     `toml:"column_name_length_limit"`
 	LogLevel                   string                  `toml:"log_level"`
+	TimescaleDB                timescaleDB             `toml:"timescaledb"`
 	Logger                     telegraf.Logger         `toml:"-"`
 
 	dbContext       context.Context
@@ -150,7 +151,7 @@ func (p *Postgresql) Init() error {
 		return errors.New("invalid uint64_type")
 	}
 
-	return nil
+	return p.TimescaleDB.init(p.TagsAsForeignKeys)
 }
 
 // Connect establishes a connection to the target database and prepares the cache
@@ -177,6 +178,19 @@ func (p *Postgresql) Connect() error {
 		}
 	}
 
+	// Check if we can create hypertables
+	version, err := p.TimescaleDB.detect(p.dbContext, db)
+	if err != nil {
+		db.Close()
+		p.dbContextCancel()
+		return err
+	}
+	if p.TimescaleDB.enabled {
+		p.Logger.Debugf("Using TimescaleDB %s", version)
+	} else if p.TimescaleDB.Mode == timescaleAuto {
+		p.Logger.Info("TimescaleDB extension not installed, creating plain tables")
+	}
+
 	p.db = db
 	p.tableManager = NewTableManager(p)
 
@@ -506,6 +520,9 @@ func newPostgresql() *Postgresql {
 		RetryMaxBackoff:            config.Duration(time.Second * 15),
 		Logger:                     logger.New("outputs", "postgresql", ""),
 		LogLevel:                   "warn",
+		TimescaleDB: timescaleDB{
+			ChunkTimeInterval: config.Duration(7 * 24 * time.Hour),
+		},
 	}
 
 	p.CreateTemplates[0].UnmarshalText([]byte(`CREATE TABLE {{ .table }} ({{ .columns }})`))
@@ -231,6 +231,10 @@ type PostgresqlTest struct {
 }
 
 func newPostgresqlTest(tb testing.TB) (*PostgresqlTest, error) {
+	return newPostgresqlTestImage(tb, "postgres:alpine")
+}
+
+func newPostgresqlTestImage(tb testing.TB, image string) (*PostgresqlTest, error) {
 	if testing.Short() {
 		tb.Skip("Skipping integration test in short mode")
 	}
@@ -241,7 +245,7 @@ func newPostgresqlTest(tb testing.TB) (*PostgresqlTest, error) {
 	testDatabaseName := "telegraf_test"
 
 	container := testutil.Container{
-		Image:        "postgres:alpine",
+		Image:        image,
 		ExposedPorts: []string{servicePort},
 		Env: map[string]string{
 			"POSTGRES_USER":     username,
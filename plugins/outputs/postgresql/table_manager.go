@@ -284,7 +284,8 @@ func (tm *TableManager) EnsureStructure(
 
 	// write_db
 	var tmpls []*sqltemplate.Template
-	if len(currCols) == 0 {
+	created := len(currCols) == 0
+	if created {
 		tmpls = createTemplates
 	} else {
 		tmpls = addColumnsTemplates
@@ -293,6 +294,15 @@ func (tm *TableManager) EnsureStructure(
 		return append(addColumns, invalidColumns...), err
 	}
 
+	// Turn new metric tables into hypertables
+	if created && tbl == metricsTable && tm.TimescaleDB.enabled {
+		for _, stmt := range tm.TimescaleDB.statements(tm.Schema, tbl.name, tm.timeColumn.Name, addColumns) {
+			if _, err := tx.Exec(ctx, stmt); err != nil {
+				return append(addColumns, invalidColumns...), fmt.Errorf("executing %q: %w", stmt, err)
+			}
+		}
+	}
+
 	if currCols, err = tm.getColumns(ctx, tx, tbl.name); err != nil {
 		return append(addColumns, invalidColumns...), err
 	}
@@ -0,0 +1,203 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/outputs/postgresql/sqltemplate"
+	"github.com/influxdata/telegraf/plugins/outputs/postgresql/utils"
+)
+
+// TimescaleDB modes
+const (
+	timescaleDisabled = "disabled"
+	timescaleAuto     = "auto"
+	timescaleEnabled  = "enabled"
+)
+
+// continuousAggregate is a materialized view aggregating the metrics of a
+// hypertable into time buckets
+type continuousAggregate struct {
+	Name               string          `toml:"name"`
+	Measurement        string          `toml:"measurement"`
+	BucketWidth        config.Duration `toml:"bucket_width"`
+	Aggregates         []string        `toml:"aggregates"`
+	GroupBy            []string        `toml:"group_by"`
+	RefreshInterval    config.Duration `toml:"refresh_interval"`
+	RefreshStartOffset config.Duration `toml:"refresh_start_offset"`
+	RefreshEndOffset   config.Duration `toml:"refresh_end_offset"`
+}
+
+// timescaleDB holds the settings for creating metric tables as TimescaleDB
+// hypertables including their compression and retention policies
+type timescaleDB struct {
+	Mode                 string                 `toml:"mode"`
+	ChunkTimeInterval    config.Duration        `toml:"chunk_time_interval"`
+	CompressAfter        config.Duration        `toml:"compress_after"`
+	CompressSegmentBy    []string               `toml:"compress_segment_by"`
+	RetentionPeriod      config.Duration        `toml:"retention_period"`
+	ContinuousAggregates []*continuousAggregate `toml:"continuous_aggregate"`
+
+	// enabled is set if the extension is available in the database
+	enabled bool
+}
+
+func (t *timescaleDB) init(tagsAsForeignKeys bool) error {
+	switch t.Mode {
+	case "":
+		t.Mode = timescaleDisabled
+	case timescaleDisabled, timescaleAuto, timescaleEnabled:
+	default:
+		return fmt.Errorf("invalid timescaledb mode %q", t.Mode)
+	}
+	if t.Mode == timescaleDisabled {
+		return nil
+	}
+
+	if time.Duration(t.ChunkTimeInterval) < time.Second {
+		return errors.New("timescaledb chunk_time_interval must be at least one second")
+	}
+	if t.CompressSegmentBy == nil && tagsAsForeignKeys {
+		t.CompressSegmentBy = []string{"tag_id"}
+	}
+
+	names := make(map[string]bool, len(t.ContinuousAggregates))
+	for i, cagg := range t.ContinuousAggregates {
+		if cagg.Name == "" {
+			return fmt.Errorf("continuous aggregate %d: name required", i+1)
+		}
+		if names[cagg.Name] {
+			return fmt.Errorf("continuous aggregate %q: duplicate name", cagg.Name)
+		}
+		names[cagg.Name] = true
+		if cagg.Measurement == "" {
+			return fmt.Errorf("continuous aggregate %q: measurement required", cagg.Name)
+		}
+		if time.Duration(cagg.BucketWidth) < time.Second {
+			return fmt.Errorf("continuous aggregate %q: bucket_width must be at least one second", cagg.Name)
+		}
+		if len(cagg.Aggregates) == 0 {
+			return fmt.Errorf("continuous aggregate %q: aggregates required", cagg.Name)
+		}
+	}
+
+	return nil
+}
+
+// detect checks for the TimescaleDB extension in the database
+func (t *timescaleDB) detect(ctx context.Context, db dbh) (string, error) {
+	t.enabled = false
+	if t.Mode == timescaleDisabled {
+		return "", nil
+	}
+
+	rows, err := db.Query(ctx, "SELECT extversion FROM pg_extension WHERE extname = 'timescaledb'")
+	if err != nil {
+		return "", fmt.Errorf("detecting timescaledb extension: %w", err)
+	}
+	version, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[string])
+	if errors.Is(err, pgx.ErrNoRows) {
+		if t.Mode == timescaleEnabled {
+			return "", errors.New("timescaledb extension is not installed")
+		}
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("detecting timescaledb extension: %w", err)
+	}
+
+	t.enabled = true
+	return version, nil
+}
+
+// statements returns the SQL statements converting the newly created metric
+// table into a hypertable and setting up the configured policies and
+// continuous aggregates
+func (t *timescaleDB) statements(schema, table, timeColumn string, columns []utils.Column) []string {
+	ident := pgx.Identifier{schema, table}.Sanitize()
+	regclass := sqltemplate.QuoteLiteral(ident)
+	timeIdent := sqltemplate.QuoteIdentifier(timeColumn)
+
+	stmts := []string{
+		fmt.Sprintf("SELECT create_hypertable(%s, %s, chunk_time_interval => %s, if_not_exists => true)",
+			regclass, sqltemplate.QuoteLiteral(timeColumn), interval(t.ChunkTimeInterval)),
+	}
+
+	if t.CompressAfter > 0 {
+		settings := "timescaledb.compress, timescaledb.compress_orderby = " + sqltemplate.QuoteLiteral(timeIdent+" DESC")
+		// Only segment by columns existing in the table as the statement fails
+		// otherwise
+		segmentBy := make([]string, 0, len(t.CompressSegmentBy))
+		for _, name := range t.CompressSegmentBy {
+			if slices.ContainsFunc(columns, func(c utils.Column) bool { return c.Name == name }) {
+				segmentBy = append(segmentBy, sqltemplate.QuoteIdentifier(name))
+			}
+		}
+		if len(segmentBy) > 0 {
+			settings += ", timescaledb.compress_segmentby = " + sqltemplate.QuoteLiteral(strings.Join(segmentBy, ", "))
+		}
+		stmts = append(stmts,
+			fmt.Sprintf("ALTER TABLE %s SET (%s)", ident, settings),
+			fmt.Sprintf("SELECT add_compression_policy(%s, %s, if_not_exists => true)", regclass, interval(t.CompressAfter)),
+		)
+	}
+
+	if t.RetentionPeriod > 0 {
+		stmts = append(stmts,
+			fmt.Sprintf("SELECT add_retention_policy(%s, %s, if_not_exists => true)", regclass, interval(t.RetentionPeriod)),
+		)
+	}
+
+	for _, cagg := range t.ContinuousAggregates {
+		if cagg.Measurement != table {
+			continue
+		}
+
+		// Group by the tags, i.e. the tag ID when storing tags as foreign keys,
+		// if not specified explicitly
+		groupBy := make([]string, 0, len(cagg.GroupBy))
+		for _, name := range cagg.GroupBy {
+			groupBy = append(groupBy, sqltemplate.QuoteIdentifier(name))
+		}
+		if len(cagg.GroupBy) == 0 {
+			for _, c := range columns {
+				if c.Role == utils.TagColType || c.Role == utils.TagsIDColType {
+					groupBy = append(groupBy, sqltemplate.QuoteIdentifier(c.Name))
+				}
+			}
+		}
+
+		bucket := fmt.Sprintf("time_bucket(%s, %s)", interval(cagg.BucketWidth), timeIdent)
+		selectors := append([]string{bucket + " AS " + timeIdent}, groupBy...)
+		selectors = append(selectors, cagg.Aggregates...)
+		view := pgx.Identifier{schema, cagg.Name}.Sanitize()
+		stmts = append(stmts, fmt.Sprintf(
+			"CREATE MATERIALIZED VIEW IF NOT EXISTS %s WITH (timescaledb.continuous) AS SELECT %s FROM %s GROUP BY %s WITH NO DATA",
+			view, strings.Join(selectors, ", "), ident, strings.Join(append([]string{bucket}, groupBy...), ", "),
+		))
+
+		if cagg.RefreshInterval > 0 {
+			stmts = append(stmts, fmt.Sprintf(
+				"SELECT add_continuous_aggregate_policy(%s, start_offset => %s, end_offset => %s, schedule_interval => %s, if_not_exists => true)",
+				sqltemplate.QuoteLiteral(view), interval(cagg.RefreshStartOffset), interval(cagg.RefreshEndOffset), interval(cagg.RefreshInterval),
+			))
+		}
+	}
+
+	return stmts
+}
+
+// interval returns the duration as SQL interval with zero resulting in NULL
+func interval(d config.Duration) string {
+	if d <= 0 {
+		return "NULL"
+	}
+	return fmt.Sprintf("INTERVAL '%d seconds'", int64(time.Duration(d)/time.Second))
+}
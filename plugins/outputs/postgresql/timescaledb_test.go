@@ -0,0 +1,154 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/outputs/postgresql/utils"
+)
+
+func TestTimescaleDBInit(t *testing.T) {
+	tests := []struct {
+		name   string
+		ts     timescaleDB
+		errmsg string
+	}{
+		{
+			name:   "invalid mode",
+			ts:     timescaleDB{Mode: "foo"},
+			errmsg: `invalid timescaledb mode "foo"`,
+		},
+		{
+			name:   "chunk interval too small",
+			ts:     timescaleDB{Mode: timescaleAuto},
+			errmsg: "chunk_time_interval must be at least one second",
+		},
+		{
+			name: "aggregate without measurement",
+			ts: timescaleDB{
+				Mode:                 timescaleAuto,
+				ChunkTimeInterval:    config.Duration(time.Hour),
+				ContinuousAggregates: []*continuousAggregate{{Name: "cpu_hourly"}},
+			},
+			errmsg: `continuous aggregate "cpu_hourly": measurement required`,
+		},
+		{
+			name: "aggregate without aggregates",
+			ts: timescaleDB{
+				Mode:              timescaleAuto,
+				ChunkTimeInterval: config.Duration(time.Hour),
+				ContinuousAggregates: []*continuousAggregate{
+					{Name: "cpu_hourly", Measurement: "cpu", BucketWidth: config.Duration(time.Hour)},
+				},
+			},
+			errmsg: `continuous aggregate "cpu_hourly": aggregates required`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.ts.init(false), tt.errmsg)
+		})
+	}
+}
+
+func TestTimescaleDBStatements(t *testing.T) {
+	ts := timescaleDB{
+		Mode:              timescaleEnabled,
+		ChunkTimeInterval: config.Duration(24 * time.Hour),
+		CompressAfter:     config.Duration(2 * time.Hour),
+		RetentionPeriod:   config.Duration(30 * 24 * time.Hour),
+		ContinuousAggregates: []*continuousAggregate{
+			{
+				Name:             "cpu_hourly",
+				Measurement:      "cpu",
+				BucketWidth:      config.Duration(time.Hour),
+				Aggregates:       []string{`avg(usage) AS usage`},
+				RefreshInterval:  config.Duration(time.Hour),
+				RefreshEndOffset: config.Duration(time.Hour),
+			},
+			{
+				Name:        "mem_hourly",
+				Measurement: "mem",
+				BucketWidth: config.Duration(time.Hour),
+				Aggregates:  []string{`max(used) AS used`},
+			},
+		},
+	}
+	require.NoError(t, ts.init(true))
+	require.Equal(t, []string{"tag_id"}, ts.CompressSegmentBy)
+
+	columns := []utils.Column{
+		{Name: "time", Type: PgTimestampWithoutTimeZone, Role: utils.TimeColType},
+		{Name: "tag_id", Type: PgBigInt, Role: utils.TagsIDColType},
+		{Name: "usage", Type: PgDoublePrecision, Role: utils.FieldColType},
+	}
+	expected := []string{
+		`SELECT create_hypertable('"public"."cpu"', 'time', chunk_time_interval => INTERVAL '86400 seconds', if_not_exists => true)`,
+		`ALTER TABLE "public"."cpu" SET (timescaledb.compress, timescaledb.compress_orderby = '"time" DESC', ` +
+			`timescaledb.compress_segmentby = '"tag_id"')`,
+		`SELECT add_compression_policy('"public"."cpu"', INTERVAL '7200 seconds', if_not_exists => true)`,
+		`SELECT add_retention_policy('"public"."cpu"', INTERVAL '2592000 seconds', if_not_exists => true)`,
+		`CREATE MATERIALIZED VIEW IF NOT EXISTS "public"."cpu_hourly" WITH (timescaledb.continuous) AS ` +
+			`SELECT time_bucket(INTERVAL '3600 seconds', "time") AS "time", "tag_id", avg(usage) AS usage FROM "public"."cpu" ` +
+			`GROUP BY time_bucket(INTERVAL '3600 seconds', "time"), "tag_id" WITH NO DATA`,
+		`SELECT add_continuous_aggregate_policy('"public"."cpu_hourly"', start_offset => NULL, ` +
+			`end_offset => INTERVAL '3600 seconds', schedule_interval => INTERVAL '3600 seconds', if_not_exists => true)`,
+	}
+	require.Equal(t, expected, ts.statements("public", "cpu", "time", columns))
+}
+
+func TestTimescaleDBIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	p, err := newPostgresqlTestImage(t, "timescale/timescaledb:latest-pg16")
+	require.NoError(t, err)
+	p.TagsAsForeignKeys = true
+	p.TimescaleDB = timescaleDB{
+		Mode:              timescaleEnabled,
+		ChunkTimeInterval: config.Duration(24 * time.Hour),
+		CompressAfter:     config.Duration(2 * time.Hour),
+		RetentionPeriod:   config.Duration(30 * 24 * time.Hour),
+		ContinuousAggregates: []*continuousAggregate{
+			{
+				Name:            t.Name() + "_hourly",
+				Measurement:     t.Name(),
+				BucketWidth:     config.Duration(time.Hour),
+				Aggregates:      []string{`avg(v) AS v`},
+				RefreshInterval: config.Duration(time.Hour),
+			},
+		},
+	}
+	require.NoError(t, p.Init())
+	require.NoError(t, p.Connect())
+	require.True(t, p.TimescaleDB.enabled)
+
+	metrics := []telegraf.Metric{
+		newMetric(t, "", MSS{"host": "a"}, MSI{"v": 1.0}),
+		newMetric(t, "", MSS{"host": "b"}, MSI{"v": 2.0}),
+	}
+	require.NoError(t, p.Write(metrics))
+	require.Len(t, dbTableDump(t, p.db, ""), 2)
+
+	rows, err := p.db.Query(ctx,
+		"SELECT compression_enabled FROM timescaledb_information.hypertables WHERE hypertable_name = $1", t.Name())
+	require.NoError(t, err)
+	compressed, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[bool])
+	require.NoError(t, err)
+	require.True(t, compressed)
+
+	rows, err = p.db.Query(ctx, "SELECT proc_name FROM timescaledb_information.jobs WHERE proc_name LIKE 'policy_%'")
+	require.NoError(t, err)
+	jobs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	require.NoError(t, err)
+	require.Contains(t, jobs, "policy_compression")
+	require.Contains(t, jobs, "policy_retention")
+	require.Contains(t, jobs, "policy_refresh_continuous_aggregate")
+}
@@ -0,0 +1,227 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Default templates for inserting or updating rows identified by the key
+// columns, i.e. the timestamp and the tag columns
+var defaultUpsertTemplates = map[string]string{
+	"pgx":    "INSERT INTO {TABLE}({COLUMNS}) VALUES {VALUES} ON CONFLICT ({KEY_COLUMNS}) DO UPDATE SET {UPDATE_EXCLUDED}",
+	"sqlite": "INSERT INTO {TABLE}({COLUMNS}) VALUES {VALUES} ON CONFLICT ({KEY_COLUMNS}) DO UPDATE SET {UPDATE_EXCLUDED}",
+	"mysql":  "INSERT INTO {TABLE}({COLUMNS}) VALUES {VALUES} ON DUPLICATE KEY UPDATE {UPDATE_VALUES}",
+	"mssql": "MERGE INTO {TABLE} AS t USING (VALUES {VALUES}) AS s({COLUMNS}) ON {KEY_MATCH} " +
+		"WHEN MATCHED THEN UPDATE SET {UPDATE_SOURCE} " +
+		"WHEN NOT MATCHED THEN INSERT ({COLUMNS}) VALUES ({SOURCE_COLUMNS});",
+	"snowflake": "MERGE INTO {TABLE} AS t USING (SELECT * FROM (VALUES {VALUES}) AS v({COLUMNS})) AS s ON {KEY_MATCH} " +
+		"WHEN MATCHED THEN UPDATE SET {UPDATE_SOURCE} " +
+		"WHEN NOT MATCHED THEN INSERT ({COLUMNS}) VALUES ({SOURCE_COLUMNS})",
+}
+
+// Maximum number of placeholders in a single statement for the drivers
+// limiting the number of parameters
+var maxPlaceholders = map[string]int{
+	"mssql":  2000,
+	"sqlite": 999,
+	"mysql":  65535,
+	"pgx":    65535,
+}
+
+// rowGroup collects the rows of a table sharing the same columns for inserting
+// them in bulk. The first keys columns identify a row.
+type rowGroup struct {
+	table   string
+	columns []string
+	keys    int
+	rows    [][]interface{}
+}
+
+// placeholders returns the parameter placeholders for a row of the given
+// number of columns starting at the given offset
+func (p *SQL) placeholders(columns, offset int) string {
+	placeholders := make([]string, 0, columns)
+	for i := 0; i < columns; i++ {
+		if p.Driver == "pgx" {
+			// Postgres uses $1 $2 $3 as placeholders
+			placeholders = append(placeholders, fmt.Sprintf("$%d", offset+i+1))
+		} else {
+			// Everything else uses ? ? ? as placeholders
+			placeholders = append(placeholders, "?")
+		}
+	}
+	return "(" + strings.Join(placeholders, ",") + ")"
+}
+
+func (p *SQL) generateValues(columns, rows int) string {
+	values := make([]string, 0, rows)
+	for i := 0; i < rows; i++ {
+		values = append(values, p.placeholders(columns, i*columns))
+	}
+	return strings.Join(values, ",")
+}
+
+func (p *SQL) generateMultiInsert(tablename string, columns []string, rows int) string {
+	quotedColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, quoteIdent(column))
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+		quoteIdent(tablename),
+		strings.Join(quotedColumns, ","),
+		p.generateValues(len(columns), rows))
+}
+
+// generateUpsert fills the upsert template for the given number of rows where
+// the first keys columns identify the row
+func (p *SQL) generateUpsert(tablename string, columns []string, keys, rows int) (string, error) {
+	if keys == 0 {
+		return "", fmt.Errorf("no key columns for upserting into table %q", tablename)
+	}
+
+	quotedColumns := make([]string, 0, len(columns))
+	sourceColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, quoteIdent(column))
+		sourceColumns = append(sourceColumns, "s."+quoteIdent(column))
+	}
+
+	keyMatch := make([]string, 0, keys)
+	for _, column := range quotedColumns[:keys] {
+		keyMatch = append(keyMatch, "t."+column+"=s."+column)
+	}
+
+	updateExcluded := make([]string, 0, len(columns)-keys)
+	updateValues := make([]string, 0, len(columns)-keys)
+	updateSource := make([]string, 0, len(columns)-keys)
+	for _, column := range quotedColumns[keys:] {
+		updateExcluded = append(updateExcluded, column+"=excluded."+column)
+		updateValues = append(updateValues, column+"=VALUES("+column+")")
+		updateSource = append(updateSource, column+"=s."+column)
+	}
+
+	query := p.UpsertTemplate
+	query = strings.ReplaceAll(query, "{TABLE}", quoteIdent(tablename))
+	query = strings.ReplaceAll(query, "{COLUMNS}", strings.Join(quotedColumns, ","))
+	query = strings.ReplaceAll(query, "{VALUES}", p.generateValues(len(columns), rows))
+	query = strings.ReplaceAll(query, "{KEY_COLUMNS}", strings.Join(quotedColumns[:keys], ","))
+	query = strings.ReplaceAll(query, "{KEY_MATCH}", strings.Join(keyMatch, " AND "))
+	query = strings.ReplaceAll(query, "{SOURCE_COLUMNS}", strings.Join(sourceColumns, ","))
+	query = strings.ReplaceAll(query, "{UPDATE_EXCLUDED}", strings.Join(updateExcluded, ","))
+	query = strings.ReplaceAll(query, "{UPDATE_VALUES}", strings.Join(updateValues, ","))
+	query = strings.ReplaceAll(query, "{UPDATE_SOURCE}", strings.Join(updateSource, ","))
+
+	return query, nil
+}
+
+// deduplicate keeps the last row for each key as upserting the same row twice
+// within one statement fails for some databases
+func (g *rowGroup) deduplicate() {
+	index := make(map[string]int, len(g.rows))
+	rows := make([][]interface{}, 0, len(g.rows))
+	for _, row := range g.rows {
+		key := rowKey(row[:g.keys])
+		if i, found := index[key]; found {
+			rows[i] = row
+			continue
+		}
+		index[key] = len(rows)
+		rows = append(rows, row)
+	}
+	g.rows = rows
+}
+
+// rowKey returns a unique key for the given values by prefixing each value
+// with its type and length to avoid collisions like ("ab", "c") and ("a", "bc")
+func rowKey(values []interface{}) string {
+	var b strings.Builder
+	for _, v := range values {
+		s := fmt.Sprint(v)
+		fmt.Fprintf(&b, "%T:%d:%s", v, len(s), s)
+	}
+	return b.String()
+}
+
+// sendBulk writes the rows of the group using the most efficient way
+// available for the driver
+func (p *SQL) sendBulk(g *rowGroup) error {
+	if p.Upsert {
+		g.deduplicate()
+	}
+
+	switch {
+	case p.Driver == "clickhouse":
+		// ClickHouse batches all rows of a prepared statement in a transaction
+		// natively
+		return p.sendBatch(p.generateInsert(g.table, g.columns), g.rows)
+	case p.Driver == "pgx" && !p.Upsert:
+		return p.copyFrom(g)
+	}
+
+	// Insert multiple rows per statement while staying within the limits of
+	// the database for the number of parameters
+	size := len(g.rows)
+	if limit, found := maxPlaceholders[p.Driver]; found {
+		size = max(min(size, limit/len(g.columns)), 1)
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin failed: %w", err)
+	}
+	for start := 0; start < len(g.rows); start += size {
+		rows := g.rows[start:min(start+size, len(g.rows))]
+
+		var query string
+		if p.Upsert {
+			query, err = p.generateUpsert(g.table, g.columns, g.keys, len(rows))
+		} else {
+			query = p.generateMultiInsert(g.table, g.columns, len(rows))
+		}
+		if err == nil {
+			args := make([]interface{}, 0, len(rows)*len(g.columns))
+			for _, row := range rows {
+				args = append(args, row...)
+			}
+			_, err = tx.Exec(query, args...)
+		}
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				return fmt.Errorf("execution failed: %w, unable to rollback: %w", err, errRollback)
+			}
+			return fmt.Errorf("execution failed: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// copyFrom uses the COPY protocol of Postgres for inserting the rows
+func (p *SQL) copyFrom(g *rowGroup) error {
+	ctx := context.Background()
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("getting connection failed: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("connection does not support COPY")
+		}
+		if _, err := c.Conn().CopyFrom(ctx, pgx.Identifier{g.table}, g.columns, pgx.CopyFromRows(g.rows)); err != nil {
+			return fmt.Errorf("copy failed: %w", err)
+		}
+		return nil
+	})
+}
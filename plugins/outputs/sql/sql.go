@@ -5,6 +5,7 @@ import (
 	"cmp"
 	gosql "database/sql"
 	_ "embed"
+	"errors"
 	"fmt"
 	"iter"
 	"net/url"
@@ -59,6 +60,9 @@ type SQL struct {
 	TableUpdateTemplate   string          `toml:"table_update_template"`
 	InitSQL               string          `toml:"init_sql"`
 	BatchTx               bool            `toml:"batch_transactions"`
+	InsertMode            string          `toml:"insert_mode"`
+	Upsert                bool            `toml:"upsert"`
+	UpsertTemplate        string          `toml:"upsert_template"`
 	Convert               ConvertStruct   `toml:"convert"`
 	ConnectionMaxIdleTime config.Duration `toml:"connection_max_idle_time"`
 	ConnectionMaxLifetime config.Duration `toml:"connection_max_lifetime"`
@@ -83,13 +87,32 @@ func (p *SQL) Init() error {
 	}
 
 	if p.TableTemplate == "" {
-		if p.Driver == "clickhouse" {
+		switch {
+		case p.Driver == "clickhouse":
 			p.TableTemplate = "CREATE TABLE {TABLE}({COLUMNS}) ORDER BY ({TAG_COLUMN_NAMES}, {TIMESTAMP_COLUMN_NAME})"
-		} else {
+		case p.Upsert:
+			// Upserting requires a unique key for identifying the rows
+			p.TableTemplate = "CREATE TABLE {TABLE}({COLUMNS}, PRIMARY KEY ({KEY_COLUMN_NAMES}))"
+		default:
 			p.TableTemplate = "CREATE TABLE {TABLE}({COLUMNS})"
 		}
 	}
 
+	switch p.InsertMode {
+	case "":
+		p.InsertMode = "row"
+	case "row", "bulk":
+	default:
+		return fmt.Errorf("invalid insert_mode %q", p.InsertMode)
+	}
+
+	if p.Upsert && p.UpsertTemplate == "" {
+		if p.Driver == "clickhouse" {
+			return errors.New("upsert is not supported for clickhouse, use a ReplacingMergeTree table engine instead")
+		}
+		p.UpsertTemplate = defaultUpsertTemplates[p.Driver]
+	}
+
 	p.tableListColumnsTemplate = "SELECT column_name FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_NAME={TABLE}"
 	if p.Driver == "sqlite" {
 		p.tableListColumnsTemplate = "SELECT name AS column_name FROM pragma_table_info({TABLE})"
@@ -205,14 +228,17 @@ func (p *SQL) deriveDatatype(value interface{}) string {
 func (p *SQL) generateCreateTable(metric telegraf.Metric) string {
 	columns := make([]string, 0, len(metric.TagList())+len(metric.FieldList())+1)
 	tagColumnNames := make([]string, 0, len(metric.TagList()))
+	keyColumnNames := make([]string, 0, len(metric.TagList())+1)
 
 	if p.TimestampColumn != "" {
 		columns = append(columns, fmt.Sprintf("%s %s", quoteIdent(p.TimestampColumn), p.Convert.Timestamp))
+		keyColumnNames = append(keyColumnNames, quoteIdent(p.TimestampColumn))
 	}
 
 	for _, tag := range metric.TagList() {
 		columns = append(columns, fmt.Sprintf("%s %s", quoteIdent(tag.Key), p.Convert.Text))
 		tagColumnNames = append(tagColumnNames, quoteIdent(tag.Key))
+		keyColumnNames = append(keyColumnNames, quoteIdent(tag.Key))
 	}
 
 	var datatype string
@@ -227,6 +253,7 @@ func (p *SQL) generateCreateTable(metric telegraf.Metric) string {
 	query = strings.ReplaceAll(query, "{COLUMNS}", strings.Join(columns, ","))
 	query = strings.ReplaceAll(query, "{TAG_COLUMN_NAMES}", strings.Join(tagColumnNames, ","))
 	query = strings.ReplaceAll(query, "{TIMESTAMP_COLUMN_NAME}", quoteIdent(p.TimestampColumn))
+	query = strings.ReplaceAll(query, "{KEY_COLUMN_NAMES}", strings.Join(keyColumnNames, ","))
 
 	return query
 }
@@ -240,27 +267,15 @@ func (p *SQL) generateAddColumn(tablename, column, columnType string) string {
 }
 
 func (p *SQL) generateInsert(tablename string, columns []string) string {
-	placeholders := make([]string, 0, len(columns))
 	quotedColumns := make([]string, 0, len(columns))
 	for _, column := range columns {
 		quotedColumns = append(quotedColumns, quoteIdent(column))
 	}
-	if p.Driver == "pgx" {
-		// Postgres uses $1 $2 $3 as placeholders
-		for i := 0; i < len(columns); i++ {
-			placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
-		}
-	} else {
-		// Everything else uses ? ? ? as placeholders
-		for i := 0; i < len(columns); i++ {
-			placeholders = append(placeholders, "?")
-		}
-	}
 
-	return fmt.Sprintf("INSERT INTO %s (%s) VALUES(%s)",
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES%s",
 		quoteIdent(tablename),
 		strings.Join(quotedColumns, ","),
-		strings.Join(placeholders, ","))
+		p.placeholders(len(columns), 0))
 }
 
 func (p *SQL) createTable(metric telegraf.Metric) error {
@@ -445,6 +460,8 @@ func (p *SQL) sendBatch(sql string, values [][]interface{}) error {
 
 func (p *SQL) Write(metrics []telegraf.Metric) error {
 	batchedQueries := make(map[string][][]interface{})
+	bulkGroups := make(map[string]*rowGroup)
+	var bulkOrder []*rowGroup
 
 	for _, metric := range metrics {
 		tablename := metric.Name()
@@ -455,11 +472,6 @@ func (p *SQL) Write(metrics []telegraf.Metric) error {
 			}
 		}
 		cacheKey, columns, values := p.processMetric(metric)
-		sql, found := p.queryCache[cacheKey]
-		if !found {
-			sql = p.generateInsert(tablename, columns)
-			p.queryCache[cacheKey] = sql
-		}
 		// Modifying the table schema is opt-in
 		if p.TableUpdateTemplate != "" {
 			for i := range len(columns) {
@@ -468,6 +480,40 @@ func (p *SQL) Write(metrics []telegraf.Metric) error {
 				}
 			}
 		}
+
+		// The timestamp and tag columns identify the row when upserting
+		keys := len(metric.TagList())
+		if p.TimestampColumn != "" {
+			keys++
+		}
+		if p.Upsert {
+			cacheKey += "\n" + strconv.Itoa(keys)
+		}
+
+		// Collect the rows with the same columns for inserting them at once
+		if p.InsertMode == "bulk" {
+			g, found := bulkGroups[cacheKey]
+			if !found {
+				g = &rowGroup{table: tablename, columns: columns, keys: keys}
+				bulkGroups[cacheKey] = g
+				bulkOrder = append(bulkOrder, g)
+			}
+			g.rows = append(g.rows, values)
+			continue
+		}
+
+		sql, found := p.queryCache[cacheKey]
+		if !found {
+			if p.Upsert {
+				var err error
+				if sql, err = p.generateUpsert(tablename, columns, keys, 1); err != nil {
+					return err
+				}
+			} else {
+				sql = p.generateInsert(tablename, columns)
+			}
+			p.queryCache[cacheKey] = sql
+		}
 		// Using BatchTx is opt-in
 		if p.BatchTx {
 			batchedQueries[sql] = append(batchedQueries[sql], values)
@@ -486,6 +532,12 @@ func (p *SQL) Write(metrics []telegraf.Metric) error {
 		}
 	}
 
+	for _, g := range bulkOrder {
+		if err := p.sendBulk(g); err != nil {
+			return fmt.Errorf("failed to send bulk insert into table %q: %w", g.table, err)
+		}
+	}
+
 	return nil
 }
 
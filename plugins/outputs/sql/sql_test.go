@@ -644,6 +644,86 @@ func TestPostgresIntegrationSendBatch(t *testing.T) {
 	}, 5*time.Second, 500*time.Millisecond)
 }
 
+func TestPostgresIntegrationBulk(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	initdb, err := filepath.Abs("testdata/postgres/initdb/init.sql")
+	require.NoError(t, err)
+
+	// initdb/init.sql creates this database
+	const dbname = "foo"
+
+	// default username for postgres is postgres
+	const username = "postgres"
+
+	password := testutil.GetRandomString(32)
+	outDir := t.TempDir()
+
+	servicePort := "5432"
+	container := testutil.Container{
+		Image: "postgres",
+		Env: map[string]string{
+			"POSTGRES_PASSWORD": password,
+		},
+		Files: map[string]string{
+			"/docker-entrypoint-initdb.d/script.sql": initdb,
+			"/out":                                   outDir,
+		},
+		ExposedPorts: []string{servicePort},
+		WaitingFor: wait.ForAll(
+			wait.ForListeningPort(servicePort),
+			wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+		),
+	}
+	require.NoError(t, container.Start(), "failed to start container")
+	defer container.Terminate()
+
+	// use the plugin to write to the database
+	// host, port, username, password, dbname
+	address := config.NewSecret([]byte(fmt.Sprintf("postgres://%v:%v@%v:%v/%v",
+		username, password, container.Address, container.Ports[servicePort], dbname,
+	)))
+	p := &SQL{
+		Driver:            "pgx",
+		DataSourceName:    address,
+		Convert:           defaultConvert,
+		TimestampColumn:   "timestamp",
+		ConnectionMaxIdle: 2,
+		Log:               testutil.Logger{},
+		InsertMode:        "bulk",
+	}
+	p.Convert.Real = "double precision"
+	p.Convert.Unsigned = "bigint"
+	p.Convert.ConversionStyle = "literal"
+	require.NoError(t, p.Init())
+
+	require.NoError(t, p.Connect())
+	defer p.Close()
+	require.NoError(t, p.Write(testMetrics))
+	require.NoError(t, p.Close())
+
+	expected, err := os.ReadFile("./testdata/postgres/expected.sql")
+	require.NoError(t, err)
+	expected = sanitize(expected, removalPostgres)
+
+	require.Eventually(t, func() bool {
+		rc, out, err := container.Exec([]string{
+			"bash",
+			"-c",
+			"pg_dump" +
+				" --username=" + username +
+				" --no-comments" +
+				" " + dbname,
+		})
+		require.NoError(t, err)
+		require.Zero(t, rc)
+
+		return dumpEquals(t, expected, out, removalPostgres)
+	}, 5*time.Second, 500*time.Millisecond)
+}
+
 func TestClickHouseIntegration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -1268,3 +1348,79 @@ func sanitize(in []byte, remove []*regexp.Regexp) []byte {
 	}
 	return out.Bytes()
 }
+
+func TestGenerateUpsert(t *testing.T) {
+	tests := []struct {
+		driver   string
+		expected string
+	}{
+		{
+			driver: "pgx",
+			expected: `INSERT INTO "cpu"("timestamp","host","usage") VALUES ($1,$2,$3),($4,$5,$6) ` +
+				`ON CONFLICT ("timestamp","host") DO UPDATE SET "usage"=excluded."usage"`,
+		},
+		{
+			driver: "mysql",
+			expected: `INSERT INTO "cpu"("timestamp","host","usage") VALUES (?,?,?),(?,?,?) ` +
+				`ON DUPLICATE KEY UPDATE "usage"=VALUES("usage")`,
+		},
+		{
+			driver: "mssql",
+			expected: `MERGE INTO "cpu" AS t USING (VALUES (?,?,?),(?,?,?)) AS s("timestamp","host","usage") ` +
+				`ON t."timestamp"=s."timestamp" AND t."host"=s."host" ` +
+				`WHEN MATCHED THEN UPDATE SET "usage"=s."usage" ` +
+				`WHEN NOT MATCHED THEN INSERT ("timestamp","host","usage") VALUES (s."timestamp",s."host",s."usage");`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			p := &SQL{
+				Driver:          tt.driver,
+				Upsert:          true,
+				TimestampColumn: "timestamp",
+				Log:             testutil.Logger{},
+			}
+			require.NoError(t, p.Init())
+
+			actual, err := p.generateUpsert("cpu", []string{"timestamp", "host", "usage"}, 2, 2)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	ts := time.Unix(0, 0)
+	g := &rowGroup{
+		table:   "cpu",
+		columns: []string{"timestamp", "a", "b", "usage"},
+		keys:    3,
+		rows: [][]interface{}{
+			{ts, "ab", "c", 1.0},
+			{ts, "a", "bc", 2.0},
+			{ts, "1", "2", 3.0},
+			{ts, int64(1), "2", 4.0},
+			{ts, "ab", "c", 5.0},
+		},
+	}
+	g.deduplicate()
+
+	// Only rows with identical keys are merged keeping the last row
+	expected := [][]interface{}{
+		{ts, "ab", "c", 5.0},
+		{ts, "a", "bc", 2.0},
+		{ts, "1", "2", 3.0},
+		{ts, int64(1), "2", 4.0},
+	}
+	require.Equal(t, expected, g.rows)
+}
+
+func TestUpsertClickHouseUnsupported(t *testing.T) {
+	p := &SQL{
+		Driver: "clickhouse",
+		Upsert: true,
+		Log:    testutil.Logger{},
+	}
+	require.ErrorContains(t, p.Init(), "upsert is not supported for clickhouse")
+}
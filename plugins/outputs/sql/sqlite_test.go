@@ -11,7 +11,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -410,3 +412,100 @@ func TestSqliteSendBatch(t *testing.T) {
 	require.Equal(t, "string2", k)
 	require.False(t, rows4.Next())
 }
+
+func TestSqliteBulk(t *testing.T) {
+	address := filepath.Join(t.TempDir(), "db")
+	p := &SQL{
+		Driver:            "sqlite",
+		DataSourceName:    config.NewSecret([]byte(address)),
+		Convert:           defaultConvert,
+		TimestampColumn:   "timestamp",
+		InsertMode:        "bulk",
+		ConnectionMaxIdle: 2,
+		Log:               testutil.Logger{},
+	}
+	require.NoError(t, p.Init())
+	require.NoError(t, p.Connect())
+	defer p.Close()
+
+	// Exceed the placeholder limit of sqlite to split the rows into multiple
+	// statements
+	metrics := make([]telegraf.Metric, 0, 1000)
+	for i := range 1000 {
+		metrics = append(metrics, metric.New(
+			"bulk",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"value": int64(i)},
+			ts.Add(time.Duration(i)*time.Second),
+		))
+	}
+	require.NoError(t, p.Write(metrics))
+
+	db, err := gosql.Open("sqlite", address)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var count, sum int
+	require.NoError(t, db.QueryRow("select count(*), sum(value) from bulk").Scan(&count, &sum))
+	require.Equal(t, 1000, count)
+	require.Equal(t, 999*1000/2, sum)
+}
+
+func TestSqliteUpsert(t *testing.T) {
+	for _, mode := range []string{"row", "bulk"} {
+		t.Run(mode, func(t *testing.T) {
+			address := filepath.Join(t.TempDir(), "db")
+			p := &SQL{
+				Driver:            "sqlite",
+				DataSourceName:    config.NewSecret([]byte(address)),
+				Convert:           defaultConvert,
+				TimestampColumn:   "timestamp",
+				InsertMode:        mode,
+				Upsert:            true,
+				ConnectionMaxIdle: 2,
+				Log:               testutil.Logger{},
+			}
+			require.NoError(t, p.Init())
+			require.NoError(t, p.Connect())
+			defer p.Close()
+
+			metrics := []telegraf.Metric{
+				metric.New("upsert", map[string]string{"host": "a"}, map[string]interface{}{"value": int64(1)}, ts),
+				metric.New("upsert", map[string]string{"host": "b"}, map[string]interface{}{"value": int64(2)}, ts),
+			}
+			require.NoError(t, p.Write(metrics))
+
+			// Retrying the batch must not create duplicates but update the rows
+			updated := []telegraf.Metric{
+				metric.New("upsert", map[string]string{"host": "a"}, map[string]interface{}{"value": int64(3)}, ts),
+				metric.New("upsert", map[string]string{"host": "b"}, map[string]interface{}{"value": int64(4)}, ts),
+				metric.New("upsert", map[string]string{"host": "b"}, map[string]interface{}{"value": int64(5)}, ts),
+			}
+			require.NoError(t, p.Write(updated))
+
+			db, err := gosql.Open("sqlite", address)
+			require.NoError(t, err)
+			defer db.Close()
+
+			var sql string
+			require.NoError(t, db.QueryRow("select sql from sqlite_master where name = 'upsert'").Scan(&sql))
+			require.Equal(t,
+				`CREATE TABLE "upsert"("timestamp" TIMESTAMP,"host" TEXT,"value" INT, PRIMARY KEY ("timestamp","host"))`,
+				sql,
+			)
+
+			rows, err := db.Query("select host, value from upsert order by host")
+			require.NoError(t, err)
+			defer rows.Close()
+			actual := make(map[string]int64)
+			for rows.Next() {
+				var host string
+				var value int64
+				require.NoError(t, rows.Scan(&host, &value))
+				actual[host] = value
+			}
+			require.NoError(t, rows.Err())
+			require.Equal(t, map[string]int64{"a": 3, "b": 5}, actual)
+		})
+	}
+}
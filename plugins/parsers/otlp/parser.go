@@ -0,0 +1,157 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb-observability/common"
+	"github.com/influxdata/influxdb-observability/otel2influx"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	common_otel "github.com/influxdata/telegraf/plugins/common/opentelemetry"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+type Parser struct {
+	Encoding      string            `toml:"otlp_encoding"`
+	MetricsSchema string            `toml:"otlp_metrics_schema"`
+	DefaultTags   map[string]string `toml:"-"`
+	Log           telegraf.Logger   `toml:"-"`
+
+	schema common.MetricsSchema
+}
+
+func (p *Parser) Init() error {
+	switch p.Encoding {
+	case "":
+		p.Encoding = "protobuf"
+	case "protobuf", "json":
+	default:
+		return fmt.Errorf("invalid encoding %q", p.Encoding)
+	}
+
+	if p.MetricsSchema == "" {
+		p.MetricsSchema = "prometheus-v1"
+	}
+	schema, found := common_otel.MetricsSchemata[p.MetricsSchema]
+	if !found {
+		return fmt.Errorf("invalid metric schema %q", p.MetricsSchema)
+	}
+	p.schema = schema
+
+	return nil
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	req := pmetricotlp.NewExportRequest()
+	var err error
+	if p.Encoding == "json" {
+		err = req.UnmarshalJSON(buf)
+	} else {
+		err = req.UnmarshalProto(buf)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling request failed: %w", err)
+	}
+
+	// Exponential histograms are not supported by the conversion library
+	metrics := common_otel.ExtractExponentialHistograms(req.Metrics(), p.schema, p.Log)
+
+	// Use a separate converter for each call as the collected metrics are
+	// state of the call and Parse might be called concurrently
+	c := &collector{metrics: metrics}
+	cfg := otel2influx.DefaultOtelMetricsToLineProtocolConfig()
+	cfg.Logger = &common_otel.Logger{Logger: p.Log}
+	cfg.Writer = c
+	cfg.Schema = p.schema
+	converter, err := otel2influx.NewOtelMetricsToLineProtocol(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating converter failed: %w", err)
+	}
+	if err := converter.WriteMetrics(context.Background(), req.Metrics()); err != nil {
+		return nil, fmt.Errorf("converting metrics failed: %w", err)
+	}
+	metrics = c.metrics
+
+	for _, m := range metrics {
+		for k, v := range p.DefaultTags {
+			if !m.HasTag(k) {
+				m.AddTag(k, v)
+			}
+		}
+	}
+
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, errors.New("no metrics in line")
+	}
+
+	if len(metrics) > 1 {
+		return nil, errors.New("more than one metric in line")
+	}
+
+	return metrics[0], nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+// collector gathers the metrics produced by the conversion library
+type collector struct {
+	metrics []telegraf.Metric
+}
+
+func (c *collector) NewBatch() otel2influx.InfluxWriterBatch {
+	return c
+}
+
+func (c *collector) EnqueuePoint(
+	_ context.Context,
+	measurement string,
+	tags map[string]string,
+	fields map[string]interface{},
+	ts time.Time,
+	vType common.InfluxMetricValueType,
+) error {
+	var tp telegraf.ValueType
+	switch vType {
+	case common.InfluxMetricValueTypeUntyped:
+		tp = telegraf.Untyped
+	case common.InfluxMetricValueTypeGauge:
+		tp = telegraf.Gauge
+	case common.InfluxMetricValueTypeSum:
+		tp = telegraf.Counter
+	case common.InfluxMetricValueTypeHistogram:
+		tp = telegraf.Histogram
+	case common.InfluxMetricValueTypeSummary:
+		tp = telegraf.Summary
+	default:
+		return fmt.Errorf("unrecognized InfluxMetricValueType %q", vType)
+	}
+	c.metrics = append(c.metrics, metric.New(measurement, tags, fields, ts, tp))
+	return nil
+}
+
+func (*collector) WriteBatch(context.Context) error {
+	return nil
+}
+
+func init() {
+	parsers.Add("otlp",
+		func(string) telegraf.Parser {
+			return &Parser{}
+		})
+}
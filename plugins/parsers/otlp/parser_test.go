@@ -0,0 +1,169 @@
+package otlp
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestInitInvalid(t *testing.T) {
+	parser := &Parser{Encoding: "xml"}
+	require.ErrorContains(t, parser.Init(), `invalid encoding "xml"`)
+
+	parser = &Parser{MetricsSchema: "foo"}
+	require.ErrorContains(t, parser.Init(), `invalid metric schema "foo"`)
+}
+
+func TestParse(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	sm := rm.ScopeMetrics().AppendEmpty()
+
+	m := sm.Metrics().AppendEmpty()
+	m.SetName("queue_length")
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.Attributes().PutStr("queue", "orders")
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	dp.SetIntValue(42)
+
+	m = sm.Metrics().AppendEmpty()
+	m.SetName("requests_total")
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp = sum.DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	dp.SetDoubleValue(1234)
+
+	m = sm.Metrics().AppendEmpty()
+	m.SetName("latency")
+	eh := m.SetEmptyExponentialHistogram()
+	eh.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	edp := eh.DataPoints().AppendEmpty()
+	edp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	edp.SetCount(3)
+	edp.SetSum(3.5)
+	edp.Positive().SetOffset(-1)
+	edp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"latency",
+			map[string]string{"service.name": "checkout", "host": "localhost"},
+			map[string]interface{}{
+				"counter_reset_hint":     uint64(0),
+				"schema":                 int64(0),
+				"zero_threshold":         float64(0),
+				"zero_count":             float64(0),
+				"count":                  float64(3),
+				"sum":                    3.5,
+				"positive_span_0_offset": int64(0),
+				"positive_span_0_length": uint64(2),
+				"positive_bucket_0":      float64(1),
+				"positive_bucket_1":      float64(2),
+			},
+			ts,
+			telegraf.Histogram,
+		),
+		metric.New(
+			"queue_length",
+			map[string]string{"service.name": "checkout", "queue": "orders", "host": "localhost"},
+			map[string]interface{}{"gauge": int64(42)},
+			ts,
+			telegraf.Gauge,
+		),
+		metric.New(
+			"requests_total",
+			map[string]string{"service.name": "checkout", "host": "localhost"},
+			map[string]interface{}{"counter": float64(1234)},
+			ts,
+			telegraf.Counter,
+		),
+	}
+
+	for _, encoding := range []string{"protobuf", "json"} {
+		t.Run(encoding, func(t *testing.T) {
+			req := pmetricotlp.NewExportRequest()
+			md.CopyTo(req.Metrics())
+
+			var buf []byte
+			var err error
+			if encoding == "json" {
+				buf, err = req.MarshalJSON()
+			} else {
+				buf, err = req.MarshalProto()
+			}
+			require.NoError(t, err)
+
+			parser := &Parser{Encoding: encoding, Log: testutil.Logger{}}
+			require.NoError(t, parser.Init())
+			parser.SetDefaultTags(map[string]string{"host": "localhost"})
+
+			actual, err := parser.Parse(buf)
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, expected, actual, testutil.SortMetrics())
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	parser := &Parser{Log: testutil.Logger{}}
+	require.NoError(t, parser.Init())
+
+	_, err := parser.Parse([]byte("not a protobuf message"))
+	require.ErrorContains(t, err, "unmarshalling request failed")
+}
+
+func TestParseConcurrent(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+
+	parser := &Parser{Log: testutil.Logger{}}
+	require.NoError(t, parser.Init())
+
+	// Each request contains a single metric with a distinct name
+	requests := make([][]byte, 0, 8)
+	for i := range 8 {
+		req := pmetricotlp.NewExportRequest()
+		m := req.Metrics().ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName(fmt.Sprintf("metric_%d", i))
+		dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		dp.SetIntValue(int64(i))
+		buf, err := req.MarshalProto()
+		require.NoError(t, err)
+		requests = append(requests, buf)
+	}
+
+	var wg sync.WaitGroup
+	for i, buf := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				metrics, err := parser.Parse(buf)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if len(metrics) != 1 || metrics[0].Name() != fmt.Sprintf("metric_%d", i) {
+					t.Errorf("unexpected metrics for request %d: %v", i, metrics)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
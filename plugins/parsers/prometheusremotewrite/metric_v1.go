@@ -6,13 +6,13 @@ import (
 	"time"
 
 	"github.com/prometheus/common/model"
-	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/model/histogram"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/metric"
 )
 
-func (p *Parser) extractMetricsV1(ts *prompb.TimeSeries) ([]telegraf.Metric, error) {
+func (p *Parser) extractMetricsV1(ts *timeSeries) ([]telegraf.Metric, error) {
 	t := time.Now()
 
 	// Convert each prometheus metrics to the corresponding telegraf metrics.
@@ -25,13 +25,13 @@ func (p *Parser) extractMetricsV1(ts *prompb.TimeSeries) ([]telegraf.Metric, err
 	// write requests, so we won't try to aggregate them here.
 	// However, for Native Histogram, you will get one telegraf metric with
 	// multiple fields.
-	metrics := make([]telegraf.Metric, 0, len(ts.Samples)+len(ts.Histograms))
+	metrics := make([]telegraf.Metric, 0, len(ts.samples)+len(ts.histograms))
 
-	tags := make(map[string]string, len(p.DefaultTags)+len(ts.Labels))
+	tags := make(map[string]string, len(p.DefaultTags)+len(ts.labels))
 	for key, value := range p.DefaultTags {
 		tags[key] = value
 	}
-	for _, l := range ts.Labels {
+	for _, l := range ts.labels {
 		tags[l.Name] = l.Value
 	}
 
@@ -41,66 +41,67 @@ func (p *Parser) extractMetricsV1(ts *prompb.TimeSeries) ([]telegraf.Metric, err
 	}
 	delete(tags, model.MetricNameLabel)
 
-	for _, s := range ts.Samples {
+	for i, s := range ts.samples {
 		if math.IsNaN(s.Value) {
 			continue
 		}
 		// In prometheus remote write,
 		// You won't know if it's a counter or gauge or a sub-counter in a histogram
 		fields := map[string]interface{}{"value": s.Value}
+		if i < len(ts.created) && ts.created[i] > 0 {
+			fields["created"] = float64(ts.created[i]) / 1000
+		}
 		if s.Timestamp > 0 {
 			t = time.Unix(0, s.Timestamp*1000000)
 		}
-		m := metric.New(metricName, tags, fields, t)
+		m := metric.New(metricName, tags, fields, t, ts.valueType)
 		metrics = append(metrics, m)
 	}
 
-	for _, hp := range ts.Histograms {
+	for _, hp := range ts.histograms {
 		h := hp.ToFloatHistogram()
 
 		if hp.Timestamp > 0 {
 			t = time.Unix(0, hp.Timestamp*1000000)
 		}
 
-		fields := map[string]any{
-			"counter_reset_hint": uint64(h.CounterResetHint),
-			"schema":             int64(h.Schema),
-			"zero_threshold":     h.ZeroThreshold,
-			"zero_count":         h.ZeroCount,
-			"count":              h.Count,
-			"sum":                h.Sum,
+		eh := fromFloatHistogram(h)
+		if err := eh.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid histogram %q: %w", metricName, err)
 		}
+		m := metric.NewExponentialHistogram(metricName, tags, eh, t)
 
 		count := 0.0
 		iter := h.AllBucketIterator()
 		for iter.Next() {
 			bucket := iter.At()
 			count = count + bucket.Count
-			fields[fmt.Sprintf("%g", bucket.Upper)] = count
-		}
-
-		// expand positiveSpans and negativeSpans into fields
-		for i, span := range h.PositiveSpans {
-			fields[fmt.Sprintf("positive_span_%d_offset", i)] = int64(span.Offset)
-			fields[fmt.Sprintf("positive_span_%d_length", i)] = uint64(span.Length)
+			m.AddField(fmt.Sprintf("%g", bucket.Upper), count)
 		}
 
-		for i, span := range h.NegativeSpans {
-			fields[fmt.Sprintf("negative_span_%d_offset", i)] = int64(span.Offset)
-			fields[fmt.Sprintf("negative_span_%d_length", i)] = uint64(span.Length)
-		}
-		// expand positiveBuckets and negativeBuckets into fields
-		for i, bucket := range h.PositiveBuckets {
-			fields[fmt.Sprintf("positive_bucket_%d", i)] = bucket
-		}
-
-		for i, bucket := range h.NegativeBuckets {
-			fields[fmt.Sprintf("negative_bucket_%d", i)] = bucket
-		}
-
-		m := metric.New(metricName, tags, fields, t, telegraf.Histogram)
 		metrics = append(metrics, m)
 	}
 
 	return metrics, nil
 }
+
+func fromFloatHistogram(h *histogram.FloatHistogram) *metric.ExponentialHistogram {
+	eh := &metric.ExponentialHistogram{
+		Schema:           h.Schema,
+		CounterResetHint: uint8(h.CounterResetHint),
+		ZeroThreshold:    h.ZeroThreshold,
+		ZeroCount:        h.ZeroCount,
+		Count:            h.Count,
+		Sum:              h.Sum,
+		PositiveBuckets:  h.PositiveBuckets,
+		NegativeBuckets:  h.NegativeBuckets,
+		CustomValues:     h.CustomValues,
+	}
+	for _, span := range h.PositiveSpans {
+		eh.PositiveSpans = append(eh.PositiveSpans, metric.BucketSpan{Offset: span.Offset, Length: span.Length})
+	}
+	for _, span := range h.NegativeSpans {
+		eh.NegativeSpans = append(eh.NegativeSpans, metric.BucketSpan{Offset: span.Offset, Length: span.Length})
+	}
+	return eh
+}
@@ -6,13 +6,12 @@ import (
 	"time"
 
 	"github.com/prometheus/common/model"
-	"github.com/prometheus/prometheus/prompb"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/metric"
 )
 
-func (p *Parser) extractMetricsV2(ts *prompb.TimeSeries) ([]telegraf.Metric, error) {
+func (p *Parser) extractMetricsV2(ts *timeSeries) ([]telegraf.Metric, error) {
 	t := time.Now()
 
 	// Convert each prometheus metric to a corresponding telegraf metric
@@ -20,11 +19,11 @@ func (p *Parser) extractMetricsV2(ts *prompb.TimeSeries) ([]telegraf.Metric, err
 	// the corresponding metrics.
 	metrics := make([]telegraf.Metric, 0)
 
-	tags := make(map[string]string, len(p.DefaultTags)+len(ts.Labels))
+	tags := make(map[string]string, len(p.DefaultTags)+len(ts.labels))
 	for key, value := range p.DefaultTags {
 		tags[key] = value
 	}
-	for _, l := range ts.Labels {
+	for _, l := range ts.labels {
 		tags[l.Name] = l.Value
 	}
 
@@ -34,20 +33,23 @@ func (p *Parser) extractMetricsV2(ts *prompb.TimeSeries) ([]telegraf.Metric, err
 	}
 	delete(tags, model.MetricNameLabel)
 
-	for _, s := range ts.Samples {
+	for i, s := range ts.samples {
 		if math.IsNaN(s.Value) {
 			continue
 		}
 		// converting to telegraf metric
 		fields := map[string]interface{}{metricName: s.Value}
+		if i < len(ts.created) && ts.created[i] > 0 {
+			fields[metricName+"_created"] = float64(ts.created[i]) / 1000
+		}
 		if s.Timestamp > 0 {
 			t = time.Unix(0, s.Timestamp*1000000)
 		}
-		m := metric.New("prometheus_remote_write", tags, fields, t)
+		m := metric.New("prometheus_remote_write", tags, fields, t, ts.valueType)
 		metrics = append(metrics, m)
 	}
 
-	for _, hp := range ts.Histograms {
+	for _, hp := range ts.histograms {
 		h := hp.ToFloatHistogram()
 
 		if hp.Timestamp > 0 {
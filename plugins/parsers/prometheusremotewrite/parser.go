@@ -4,33 +4,55 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/prometheus/prometheus/prompb"
-
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/plugins/parsers"
 )
 
 type Parser struct {
-	MetricVersion int `toml:"prometheus_metric_version"`
+	MetricVersion int    `toml:"prometheus_metric_version"`
+	Version       string `toml:"prometheus_remote_write_version"`
 	DefaultTags   map[string]string
 }
 
+func (p *Parser) Init() error {
+	switch p.Version {
+	case "":
+		p.Version = "auto"
+	case "auto", "v1", "v2":
+	default:
+		return fmt.Errorf("invalid remote write version %q", p.Version)
+	}
+	return nil
+}
+
 func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
-	var err error
-	var metrics []telegraf.Metric
-	var req prompb.WriteRequest
+	version := p.Version
+	if version == "" || version == "auto" {
+		version = detectVersion(buf)
+	}
 
-	if err := req.Unmarshal(buf); err != nil {
-		return nil, fmt.Errorf("unable to unmarshal request body: %w", err)
+	var series []timeSeries
+	var err error
+	switch version {
+	case "v1":
+		series, err = decodeV1(buf)
+	case "v2":
+		series, err = decodeV2(buf)
+	default:
+		return nil, fmt.Errorf("unknown remote write version %q", version)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	for _, ts := range req.Timeseries {
+	var metrics []telegraf.Metric
+	for i := range series {
 		var metricsFromTS []telegraf.Metric
 		switch p.MetricVersion {
 		case 0, 2:
-			metricsFromTS, err = p.extractMetricsV2(&ts)
+			metricsFromTS, err = p.extractMetricsV2(&series[i])
 		case 1:
-			metricsFromTS, err = p.extractMetricsV1(&ts)
+			metricsFromTS, err = p.extractMetricsV1(&series[i])
 		default:
 			return nil, fmt.Errorf("unknown prometheus metric version %d", p.MetricVersion)
 		}
@@ -40,7 +62,7 @@ func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 		metrics = append(metrics, metricsFromTS...)
 	}
 
-	return metrics, err
+	return metrics, nil
 }
 
 func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
@@ -3,6 +3,7 @@ package prometheusremotewrite
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/influxdata/telegraf"
@@ -52,38 +54,63 @@ func TestCases(t *testing.T) {
 		var writeRequest prompb.WriteRequest
 		err = jsonpb.Unmarshal(bytes.NewReader(buf), &writeRequest)
 		require.NoError(t, err)
-		inputBytes, err := writeRequest.Marshal()
+		inputV1, err := writeRequest.Marshal()
+		require.NoError(t, err)
+		inputV2, err := toRequestV2(&writeRequest).Marshal()
 		require.NoError(t, err)
 
+		inputs := map[string][]byte{"rw1": inputV1, "rw2": inputV2}
 		versions := []int{1, 2}
-		for _, version := range versions {
-			t.Run(fmt.Sprintf("%s_v%d", fname, version), func(t *testing.T) {
-				// Load parser
-				configFilePath := filepath.Join(testdataPath, configFilename)
-				cfg := config.NewConfig()
-				require.NoError(t, cfg.LoadConfig(configFilePath))
-				require.Len(t, cfg.Inputs, 1)
-				plugin := cfg.Inputs[0].Input.(*test.Plugin)
-				parser := plugin.Parser.(*models.RunningParser).Parser.(*Parser)
-				parser.MetricVersion = version
-
-				// Load expected output
-				expectedFilePath := filepath.Join(testdataPath, fmt.Sprintf(expectedFilename, version))
-				var expected []telegraf.Metric
-				influxParser := &influx.Parser{}
-				require.NoError(t, influxParser.Init())
-				expected, err := testutil.ParseMetricsFromFile(expectedFilePath, influxParser)
-				require.NoError(t, err)
-
-				// Act and assert
-				parsed, err := parser.Parse(inputBytes)
-				require.NoError(t, err)
-				require.Len(t, parsed, len(expected))
-				// Ignore type when comparing, because expected metrics are parsed from influx lines and thus always untyped
-				testutil.RequireMetricsEqual(t, expected, parsed, testutil.SortMetrics(), testutil.IgnoreType())
-			})
+		for protocol, inputBytes := range inputs {
+			for _, version := range versions {
+				t.Run(fmt.Sprintf("%s_v%d_%s", fname, version, protocol), func(t *testing.T) {
+					// Load parser
+					configFilePath := filepath.Join(testdataPath, configFilename)
+					cfg := config.NewConfig()
+					require.NoError(t, cfg.LoadConfig(configFilePath))
+					require.Len(t, cfg.Inputs, 1)
+					plugin := cfg.Inputs[0].Input.(*test.Plugin)
+					parser := plugin.Parser.(*models.RunningParser).Parser.(*Parser)
+					parser.MetricVersion = version
+
+					// Load expected output
+					expectedFilePath := filepath.Join(testdataPath, fmt.Sprintf(expectedFilename, version))
+					var expected []telegraf.Metric
+					influxParser := &influx.Parser{}
+					require.NoError(t, influxParser.Init())
+					expected, err := testutil.ParseMetricsFromFile(expectedFilePath, influxParser)
+					require.NoError(t, err)
+
+					// Act and assert
+					parsed, err := parser.Parse(inputBytes)
+					require.NoError(t, err)
+					require.Len(t, parsed, len(expected))
+					// Ignore type when comparing, because expected metrics are parsed from influx lines and thus always untyped
+					testutil.RequireMetricsEqual(t, expected, parsed, testutil.SortMetrics(), testutil.IgnoreType())
+				})
+			}
+		}
+	}
+}
+
+// toRequestV2 converts the given request to a remote write 2.0 request
+func toRequestV2(req *prompb.WriteRequest) *writev2.Request {
+	symbols := writev2.NewSymbolTable()
+	series := make([]writev2.TimeSeries, 0, len(req.Timeseries))
+	for _, ts := range req.Timeseries {
+		s := writev2.TimeSeries{}
+		for _, l := range ts.Labels {
+			s.LabelsRefs = append(s.LabelsRefs, symbols.Symbolize(l.Name), symbols.Symbolize(l.Value))
+		}
+		for _, sample := range ts.Samples {
+			s.Samples = append(s.Samples, writev2.Sample{Value: sample.Value, Timestamp: sample.Timestamp})
 		}
+		for _, h := range ts.Histograms {
+			s.Histograms = append(s.Histograms, writev2.FromFloatHistogram(h.Timestamp, h.ToFloatHistogram()))
+		}
+		series = append(series, s)
 	}
+	return &writev2.Request{Symbols: symbols.Symbols(), Timeseries: series}
 }
 
 func BenchmarkParsingMetricVersion1(b *testing.B) {
@@ -285,6 +312,28 @@ func TestHistograms(t *testing.T) {
 	testutil.RequireMetricsSubset(t, expected, metrics, testutil.IgnoreTime(), testutil.SortMetrics())
 }
 
+func TestHistogramInvalidBucketRange(t *testing.T) {
+	h := generateTestFloatHistogram(1)
+	h.PositiveSpans[1].Offset = math.MaxInt32
+
+	prompbInput := prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "test_metric_seconds"},
+				},
+				Histograms: []prompb.Histogram{prompb.FromFloatHistogram(0, h)},
+			},
+		},
+	}
+	buf, err := prompbInput.Marshal()
+	require.NoError(t, err)
+
+	parser := Parser{MetricVersion: 1}
+	_, err = parser.Parse(buf)
+	require.ErrorContains(t, err, "spans cover more than 65536 buckets")
+}
+
 func TestDefaultTags(t *testing.T) {
 	prompbInput := prompb.WriteRequest{
 		Timeseries: []prompb.TimeSeries{
@@ -447,3 +496,101 @@ func BenchmarkParsing(b *testing.B) {
 		plugin.Parse(benchmarkData)
 	}
 }
+
+func TestParseV2(t *testing.T) {
+	symbols := writev2.NewSymbolTable()
+	req := &writev2.Request{
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: []uint32{symbols.Symbolize("__name__"), symbols.Symbolize("http_requests_total"), symbols.Symbolize("job"), symbols.Symbolize("api")},
+				Samples:    []writev2.Sample{{Value: 42, Timestamp: 1700000000000, StartTimestamp: 1699999000000}},
+				Metadata: writev2.Metadata{
+					Type:    writev2.Metadata_METRIC_TYPE_COUNTER,
+					HelpRef: symbols.Symbolize("Number of requests"),
+				},
+			},
+			{
+				LabelsRefs: []uint32{symbols.Symbolize("__name__"), symbols.Symbolize("temperature"), symbols.Symbolize("job"), symbols.Symbolize("api")},
+				Samples:    []writev2.Sample{{Value: 21.5, Timestamp: 1700000000000}},
+				Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+			},
+		},
+	}
+	req.Symbols = symbols.Symbols()
+	buf, err := req.Marshal()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		version  int
+		expected []telegraf.Metric
+	}{
+		{
+			name:    "metric version 1",
+			version: 1,
+			expected: []telegraf.Metric{
+				metric.New(
+					"http_requests_total",
+					map[string]string{"job": "api"},
+					map[string]interface{}{"value": float64(42), "created": float64(1699999000)},
+					time.UnixMilli(1700000000000),
+					telegraf.Counter,
+				),
+				metric.New(
+					"temperature",
+					map[string]string{"job": "api"},
+					map[string]interface{}{"value": 21.5},
+					time.UnixMilli(1700000000000),
+					telegraf.Gauge,
+				),
+			},
+		},
+		{
+			name:    "metric version 2",
+			version: 2,
+			expected: []telegraf.Metric{
+				metric.New(
+					"prometheus_remote_write",
+					map[string]string{"job": "api"},
+					map[string]interface{}{"http_requests_total": float64(42), "http_requests_total_created": float64(1699999000)},
+					time.UnixMilli(1700000000000),
+					telegraf.Counter,
+				),
+				metric.New(
+					"prometheus_remote_write",
+					map[string]string{"job": "api"},
+					map[string]interface{}{"temperature": 21.5},
+					time.UnixMilli(1700000000000),
+					telegraf.Gauge,
+				),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{MetricVersion: tt.version}
+			require.NoError(t, parser.Init())
+			metrics, err := parser.Parse(buf)
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, metrics)
+		})
+	}
+}
+
+func TestParseVersionInvalid(t *testing.T) {
+	parser := &Parser{Version: "v3"}
+	require.ErrorContains(t, parser.Init(), `invalid remote write version "v3"`)
+
+	// Symbol references must be in range
+	req := &writev2.Request{
+		Symbols:    []string{"", "__name__"},
+		Timeseries: []writev2.TimeSeries{{LabelsRefs: []uint32{1, 2}}},
+	}
+	buf, err := req.Marshal()
+	require.NoError(t, err)
+
+	parser = &Parser{Version: "v2"}
+	require.NoError(t, parser.Init())
+	_, err = parser.Parse(buf)
+	require.ErrorContains(t, err, "symbol reference 2 out of range")
+}
@@ -0,0 +1,125 @@
+package prometheusremotewrite
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/influxdata/telegraf"
+)
+
+// timeSeries is the protocol version independent representation of a series
+type timeSeries struct {
+	labels     []prompb.Label
+	samples    []prompb.Sample
+	histograms []prompb.Histogram
+
+	// Created timestamps of the samples in milliseconds, zero if unknown.
+	// This information is only available in remote write 2.0 requests.
+	created   []int64
+	valueType telegraf.ValueType
+}
+
+// detectVersion determines the protocol version of the request by the fields
+// present as version 1.0 (prometheus.WriteRequest) uses field numbers 1 and 3
+// while version 2.0 (io.prometheus.write.v2.Request) uses 4 and 5.
+func detectVersion(buf []byte) string {
+	for len(buf) > 0 {
+		num, typ, n := protowire.ConsumeTag(buf)
+		if n < 0 {
+			break
+		}
+		switch num {
+		case 1, 3:
+			return "v1"
+		case 4, 5:
+			return "v2"
+		}
+		buf = buf[n:]
+		if n = protowire.ConsumeFieldValue(num, typ, buf); n < 0 {
+			break
+		}
+		buf = buf[n:]
+	}
+	return "v1"
+}
+
+func decodeV1(buf []byte) ([]timeSeries, error) {
+	var req prompb.WriteRequest
+	if err := req.Unmarshal(buf); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal request body: %w", err)
+	}
+
+	series := make([]timeSeries, 0, len(req.Timeseries))
+	for _, ts := range req.Timeseries {
+		series = append(series, timeSeries{
+			labels:     ts.Labels,
+			samples:    ts.Samples,
+			histograms: ts.Histograms,
+			valueType:  telegraf.Untyped,
+		})
+	}
+	return series, nil
+}
+
+func decodeV2(buf []byte) ([]timeSeries, error) {
+	var req writev2.Request
+	if err := req.Unmarshal(buf); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal request body: %w", err)
+	}
+
+	// Resolve the interned strings
+	symbol := func(ref uint32) (string, error) {
+		if int(ref) >= len(req.Symbols) {
+			return "", fmt.Errorf("symbol reference %d out of range", ref)
+		}
+		return req.Symbols[ref], nil
+	}
+
+	series := make([]timeSeries, 0, len(req.Timeseries))
+	for i, ts := range req.Timeseries {
+		if len(ts.LabelsRefs)%2 != 0 {
+			return nil, fmt.Errorf("odd number of label references in series %d", i)
+		}
+		labels := make([]prompb.Label, 0, len(ts.LabelsRefs)/2)
+		for j := 0; j < len(ts.LabelsRefs); j += 2 {
+			name, err := symbol(ts.LabelsRefs[j])
+			if err != nil {
+				return nil, fmt.Errorf("resolving label name of series %d failed: %w", i, err)
+			}
+			value, err := symbol(ts.LabelsRefs[j+1])
+			if err != nil {
+				return nil, fmt.Errorf("resolving label value of series %d failed: %w", i, err)
+			}
+			labels = append(labels, prompb.Label{Name: name, Value: value})
+		}
+
+		s := timeSeries{
+			labels:     labels,
+			samples:    make([]prompb.Sample, 0, len(ts.Samples)),
+			created:    make([]int64, 0, len(ts.Samples)),
+			histograms: make([]prompb.Histogram, 0, len(ts.Histograms)),
+			valueType:  telegraf.Untyped,
+		}
+		for _, sample := range ts.Samples {
+			s.samples = append(s.samples, prompb.Sample{Value: sample.Value, Timestamp: sample.Timestamp})
+			s.created = append(s.created, sample.StartTimestamp)
+		}
+		for _, h := range ts.Histograms {
+			s.histograms = append(s.histograms, prompb.FromFloatHistogram(h.Timestamp, h.ToFloatHistogram()))
+		}
+
+		// Only counters and gauges map to Telegraf's value types as other types
+		// are split into multiple series
+		switch ts.Metadata.Type {
+		case writev2.Metadata_METRIC_TYPE_COUNTER:
+			s.valueType = telegraf.Counter
+		case writev2.Metadata_METRIC_TYPE_GAUGE:
+			s.valueType = telegraf.Gauge
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
@@ -0,0 +1,464 @@
+package protobuf
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+// If SchemaRegistry is set, we assume that our input will be in
+// Confluent Wire Format
+// (https://docs.confluent.io/platform/current/schema-registry/fundamentals/serdes-develop/index.html#wire-format)
+// and we will load the schema from the registry using the schema ID and
+// determine the message type from the message indexes.
+
+// Otherwise, the input is a bare protocol-buffer message of the configured
+// message type defined in either the descriptor set or the given files.
+
+type Parser struct {
+	MetricName       string            `toml:"metric_name"`
+	DescriptorSet    string            `toml:"protobuf_descriptor_set"`
+	Files            []string          `toml:"protobuf_files"`
+	ImportPaths      []string          `toml:"protobuf_import_paths"`
+	MessageType      string            `toml:"protobuf_message_type"`
+	SchemaRegistry   string            `toml:"protobuf_schema_registry"`
+	CaCertPath       string            `toml:"protobuf_schema_registry_cert"`
+	SkipBytes        int64             `toml:"protobuf_skip_bytes"`
+	Measurement      string            `toml:"protobuf_measurement"`
+	MeasurementField string            `toml:"protobuf_measurement_field"`
+	Tags             []string          `toml:"protobuf_tags"`
+	Fields           []string          `toml:"protobuf_fields"`
+	Timestamp        string            `toml:"protobuf_timestamp"`
+	TimestampFormat  string            `toml:"protobuf_timestamp_format"`
+	FieldSeparator   string            `toml:"protobuf_field_separator"`
+	MetricsField     string            `toml:"protobuf_metrics_field"`
+	DefaultTags      map[string]string `toml:"tags"`
+	Log              telegraf.Logger   `toml:"-"`
+
+	registryObj  *schemaRegistry
+	msgDesc      protoreflect.MessageDescriptor
+	unmarshaller proto.UnmarshalOptions
+	tagFilter    filter.Filter
+	fieldFilter  filter.Filter
+}
+
+func (p *Parser) Init() error {
+	var sources int
+	for _, set := range []bool{p.DescriptorSet != "", len(p.Files) > 0, p.SchemaRegistry != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return errors.New("exactly one of 'protobuf_descriptor_set', 'protobuf_files' or 'protobuf_schema_registry' must be specified")
+	}
+
+	if p.TimestampFormat == "" {
+		p.TimestampFormat = "unix"
+	}
+	if p.FieldSeparator == "" {
+		p.FieldSeparator = "_"
+	}
+	if p.SkipBytes < 0 {
+		return fmt.Errorf("invalid number of bytes to skip %d", p.SkipBytes)
+	}
+
+	var err error
+	if p.tagFilter, err = filter.Compile(p.Tags); err != nil {
+		return fmt.Errorf("creating tag filter failed: %w", err)
+	}
+	if p.fieldFilter, err = filter.Compile(p.Fields); err != nil {
+		return fmt.Errorf("creating field filter failed: %w", err)
+	}
+
+	if p.SchemaRegistry != "" {
+		registry, err := newSchemaRegistry(p.SchemaRegistry, p.CaCertPath)
+		if err != nil {
+			return fmt.Errorf("error connecting to the schema registry %q: %w", p.SchemaRegistry, err)
+		}
+		p.registryObj = registry
+		p.unmarshaller = proto.UnmarshalOptions{RecursionLimit: protowire.DefaultRecursionLimit}
+		return nil
+	}
+
+	// Load the file descriptors from the given definition
+	if p.MessageType == "" {
+		return errors.New("'protobuf_message_type' not set")
+	}
+	var files *protoregistry.Files
+	if p.DescriptorSet != "" {
+		files, err = loadDescriptorSet(p.DescriptorSet)
+	} else {
+		files, err = compileFiles(p.Files, p.ImportPaths)
+	}
+	if err != nil {
+		return err
+	}
+
+	p.unmarshaller = proto.UnmarshalOptions{
+		RecursionLimit: protowire.DefaultRecursionLimit,
+		Resolver:       dynamicpb.NewTypes(files),
+	}
+
+	// Lookup given type in the loaded file descriptors
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(p.MessageType))
+	if err != nil {
+		return fmt.Errorf("looking up message type %q failed: %w", p.MessageType, err)
+	}
+	msgDesc, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return fmt.Errorf("%q is not a message descriptor (%T)", p.MessageType, descriptor)
+	}
+	p.msgDesc = msgDesc
+
+	return nil
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	if int64(len(buf)) < p.SkipBytes {
+		return nil, fmt.Errorf("message shorter than the %d bytes to skip", p.SkipBytes)
+	}
+	message := buf[p.SkipBytes:]
+
+	msgDesc := p.msgDesc
+	if p.registryObj != nil {
+		// The input must be Confluent Wire Format
+		schemaID, indexes, data, err := decodeWireFormat(message)
+		if err != nil {
+			return nil, err
+		}
+		fd, err := p.registryObj.getFileDescriptor(schemaID)
+		if err != nil {
+			return nil, err
+		}
+		if msgDesc, err = messageByIndexes(fd, indexes); err != nil {
+			return nil, fmt.Errorf("resolving message type in schema %d failed: %w", schemaID, err)
+		}
+		message = data
+	}
+
+	msg := dynamicpb.NewMessage(msgDesc)
+	if err := p.unmarshaller.Unmarshal(message, msg); err != nil {
+		p.Log.Debugf("raw data (hex): %q", hex.EncodeToString(buf))
+		return nil, fmt.Errorf("unmarshalling message failed: %w", err)
+	}
+
+	// Without a metrics field, each message results in a single metric
+	if p.MetricsField == "" {
+		data := make(map[string]interface{})
+		p.flattenMessage("", msg, nil, data)
+		m, err := p.createMetric(data, msgDesc)
+		if err != nil {
+			return nil, err
+		}
+		return []telegraf.Metric{m}, nil
+	}
+
+	// Otherwise, create one metric for each element of the repeated field and
+	// add the remaining fields of the message to all of them
+	fd := msgDesc.Fields().ByName(protoreflect.Name(p.MetricsField))
+	if fd == nil {
+		return nil, fmt.Errorf("message %q has no field %q", msgDesc.FullName(), p.MetricsField)
+	}
+	if !fd.IsList() || fd.Message() == nil {
+		return nil, fmt.Errorf("field %q is not a repeated message field", p.MetricsField)
+	}
+
+	common := make(map[string]interface{})
+	p.flattenMessage("", msg, fd, common)
+
+	list := msg.Get(fd).List()
+	metrics := make([]telegraf.Metric, 0, list.Len())
+	for i := range list.Len() {
+		data := make(map[string]interface{}, len(common))
+		for k, v := range common {
+			data[k] = v
+		}
+		p.flattenMessage("", list.Get(i).Message(), nil, data)
+		m, err := p.createMetric(data, fd.Message())
+		if err != nil {
+			p.Log.Warnf("Skipping element %d of field %q due to error during metric creation: %v", i, p.MetricsField, err)
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) != 1 {
+		return nil, errors.New("line contains multiple metrics")
+	}
+
+	return metrics[0], nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+func (p *Parser) createMetric(data map[string]interface{}, desc protoreflect.MessageDescriptor) (telegraf.Metric, error) {
+	// Determine the measurement name and timestamp and do not include the
+	// corresponding fields into fields or tags
+	var name string
+	if p.MeasurementField != "" {
+		if v, found := data[p.MeasurementField]; found {
+			sMetric, err := internal.ToString(v)
+			if err != nil {
+				p.Log.Warnf("Could not convert %v to string for metric name %q: %v", v, p.MeasurementField, err)
+			} else {
+				name = sMetric
+			}
+			delete(data, p.MeasurementField)
+		}
+	}
+	if name == "" {
+		name = p.Measurement
+	}
+	if name == "" {
+		name = p.MetricName
+	}
+	if name == "" {
+		name = string(desc.FullName())
+	}
+	if name == "" {
+		return nil, errors.New("could not determine measurement name")
+	}
+
+	timestamp := time.Now()
+	if p.Timestamp != "" {
+		v, found := data[p.Timestamp]
+		if !found {
+			return nil, fmt.Errorf("timestamp field %q not found", p.Timestamp)
+		}
+		if t, ok := v.(time.Time); ok {
+			timestamp = t
+		} else {
+			var err error
+			timestamp, err = internal.ParseTimestamp(p.TimestampFormat, v, nil)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse %v to %q: %w", v, p.TimestampFormat, err)
+			}
+		}
+		delete(data, p.Timestamp)
+	}
+
+	tags := make(map[string]string, len(p.DefaultTags))
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+
+	fields := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if t, ok := v.(time.Time); ok {
+			v = t.UnixNano()
+		}
+		if p.tagFilter != nil && p.tagFilter.Match(k) {
+			sTag, err := internal.ToString(v)
+			if err != nil {
+				p.Log.Warnf("Could not convert %v to string for tag %q: %v", v, k, err)
+				continue
+			}
+			tags[k] = sTag
+			continue
+		}
+		if p.fieldFilter == nil || p.fieldFilter.Match(k) {
+			fields[k] = v
+		}
+	}
+	if len(fields) == 0 {
+		// A telegraf metric needs at least one field.
+		return nil, errors.New("number of fields is 0; unable to create metric")
+	}
+
+	return metric.New(name, tags, fields, timestamp), nil
+}
+
+// flattenMessage adds all fields of the message to the data using the given
+// prefix. Fields without presence information are added even if they carry
+// the default value to be consistent across messages.
+func (p *Parser) flattenMessage(prefix string, msg protoreflect.Message, skip protoreflect.FieldDescriptor, data map[string]interface{}) {
+	fields := msg.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if fd == skip || (fd.HasPresence() && !msg.Has(fd)) {
+			continue
+		}
+
+		name := p.join(prefix, string(fd.Name()))
+		v := msg.Get(fd)
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for j := range list.Len() {
+				p.flattenValue(p.join(name, strconv.Itoa(j)), fd, list.Get(j), data)
+			}
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				p.flattenValue(p.join(name, k.String()), fd.MapValue(), mv, data)
+				return true
+			})
+		default:
+			p.flattenValue(name, fd, v, data)
+		}
+	}
+}
+
+func (p *Parser) flattenValue(name string, fd protoreflect.FieldDescriptor, v protoreflect.Value, data map[string]interface{}) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		data[name] = v.Bool()
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			data[name] = string(ev.Name())
+		} else {
+			data[name] = int64(v.Enum())
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		data[name] = v.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		data[name] = v.Uint()
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		data[name] = v.Float()
+	case protoreflect.StringKind:
+		data[name] = v.String()
+	case protoreflect.BytesKind:
+		data[name] = hex.EncodeToString(v.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		msg := v.Message()
+		if fd.Message().FullName() == "google.protobuf.Timestamp" {
+			fields := fd.Message().Fields()
+			seconds := msg.Get(fields.ByName("seconds")).Int()
+			nanos := msg.Get(fields.ByName("nanos")).Int()
+			data[name] = time.Unix(seconds, nanos)
+			return
+		}
+		p.flattenMessage(name, msg, nil, data)
+	}
+}
+
+func (p *Parser) join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + p.FieldSeparator + name
+}
+
+func loadDescriptorSet(filename string) (*protoregistry.Files, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading descriptor set failed: %w", err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(buf, &set); err != nil {
+		return nil, fmt.Errorf("unmarshalling descriptor set failed: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("loading descriptor set failed: %w", err)
+	}
+	return files, nil
+}
+
+func compileFiles(filenames, importPaths []string) (*protoregistry.Files, error) {
+	resolver := &protocompile.SourceResolver{ImportPaths: importPaths}
+	compiler := &protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(resolver),
+	}
+	compiled, err := compiler.Compile(context.Background(), filenames...)
+	if err != nil {
+		return nil, fmt.Errorf("parsing protocol-buffer definition failed: %w", err)
+	}
+
+	var files protoregistry.Files
+	for _, f := range compiled {
+		if err := files.RegisterFile(f); err != nil {
+			return nil, fmt.Errorf("adding file %q to registry failed: %w", f.Path(), err)
+		}
+	}
+	return &files, nil
+}
+
+// decodeWireFormat splits the Confluent wire format into schema ID, message
+// indexes and the actual message
+func decodeWireFormat(buf []byte) (int, []int, []byte, error) {
+	if len(buf) < 6 || buf[0] != 0 {
+		return 0, nil, nil, errors.New("first byte is not 0: not Confluent Wire Format")
+	}
+	schemaID := int(binary.BigEndian.Uint32(buf[1:5]))
+	buf = buf[5:]
+
+	count, n := binary.Varint(buf)
+	if n <= 0 || count < 0 || count > int64(len(buf)) {
+		return 0, nil, nil, errors.New("invalid message indexes")
+	}
+	buf = buf[n:]
+
+	// An empty list is a shortcut for the first message in the schema
+	if count == 0 {
+		return schemaID, []int{0}, buf, nil
+	}
+
+	indexes := make([]int, 0, count)
+	for range count {
+		idx, n := binary.Varint(buf)
+		if n <= 0 {
+			return 0, nil, nil, errors.New("invalid message index")
+		}
+		indexes = append(indexes, int(idx))
+		buf = buf[n:]
+	}
+	return schemaID, indexes, buf, nil
+}
+
+// messageByIndexes returns the message at the given index path where the
+// first index refers to the top-level messages of the file and all subsequent
+// indexes refer to nested messages
+func messageByIndexes(fd protoreflect.FileDescriptor, indexes []int) (protoreflect.MessageDescriptor, error) {
+	var desc protoreflect.MessageDescriptor
+	msgs := fd.Messages()
+	for _, idx := range indexes {
+		if idx < 0 || idx >= msgs.Len() {
+			return nil, fmt.Errorf("message index %d out of range", idx)
+		}
+		desc = msgs.Get(idx)
+		msgs = desc.Messages()
+	}
+	return desc, nil
+}
+
+func init() {
+	parsers.Add("protobuf",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{MetricName: defaultMetricName}
+		})
+}
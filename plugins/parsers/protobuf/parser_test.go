@@ -0,0 +1,316 @@
+package protobuf
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+const report = `{
+	"host": "server01",
+	"time": "2023-11-14T22:13:20Z",
+	"location": {"building": "north", "floor": 3},
+	"samples": [10, 20],
+	"readings": [
+		{"sensor": "temperature", "value": 21.5, "status": "OK"},
+		{"sensor": "humidity", "value": 48.0, "status": "FAILED"}
+	]
+}`
+
+func TestInitInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		expected string
+	}{
+		{
+			name:     "no source",
+			parser:   &Parser{},
+			expected: "exactly one of",
+		},
+		{
+			name: "multiple sources",
+			parser: &Parser{
+				Files:          []string{"testdata/sensors.proto"},
+				SchemaRegistry: "http://localhost:8081",
+			},
+			expected: "exactly one of",
+		},
+		{
+			name:     "no message type",
+			parser:   &Parser{Files: []string{"testdata/sensors.proto"}},
+			expected: "'protobuf_message_type' not set",
+		},
+		{
+			name: "unknown message type",
+			parser: &Parser{
+				Files:       []string{"testdata/sensors.proto"},
+				MessageType: "example.Foo",
+			},
+			expected: `looking up message type "example.Foo" failed`,
+		},
+		{
+			name: "enum as message type",
+			parser: &Parser{
+				Files:       []string{"testdata/sensors.proto"},
+				MessageType: "example.Status",
+			},
+			expected: "is not a message descriptor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.Log = testutil.Logger{}
+			require.ErrorContains(t, tt.parser.Init(), tt.expected)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	parser := &Parser{
+		Files:       []string{"testdata/sensors.proto"},
+		MessageType: "example.Report",
+		Tags:        []string{"host", "location_*"},
+		Timestamp:   "time",
+		Log:         testutil.Logger{},
+	}
+	require.NoError(t, parser.Init())
+
+	buf := marshalMessage(t, "example.Report", report)
+	actual, err := parser.Parse(buf)
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"example.Report",
+			map[string]string{
+				"host":              "server01",
+				"location_building": "north",
+				"location_floor":    "3",
+			},
+			map[string]interface{}{
+				"samples_0":         int64(10),
+				"samples_1":         int64(20),
+				"readings_0_sensor": "temperature",
+				"readings_0_value":  21.5,
+				"readings_0_status": "OK",
+				"readings_1_sensor": "humidity",
+				"readings_1_value":  48.0,
+				"readings_1_status": "FAILED",
+			},
+			time.Unix(1700000000, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestParseDefaultMetricName(t *testing.T) {
+	parser := &Parser{
+		MetricName:  "kafka_consumer",
+		Files:       []string{"testdata/sensors.proto"},
+		MessageType: "example.Report",
+		Fields:      []string{"samples_*"},
+		Timestamp:   "time",
+		Log:         testutil.Logger{},
+	}
+	require.NoError(t, parser.Init())
+
+	buf := marshalMessage(t, "example.Report", report)
+	actual, err := parser.Parse(buf)
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"kafka_consumer",
+			map[string]string{},
+			map[string]interface{}{
+				"samples_0": int64(10),
+				"samples_1": int64(20),
+			},
+			time.Unix(1700000000, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestParseMetricsField(t *testing.T) {
+	parser := &Parser{
+		Files:            []string{"testdata/sensors.proto"},
+		MessageType:      "example.Report",
+		MeasurementField: "sensor",
+		Tags:             []string{"host", "status"},
+		Fields:           []string{"value"},
+		Timestamp:        "time",
+		MetricsField:     "readings",
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, parser.Init())
+
+	buf := marshalMessage(t, "example.Report", report)
+	actual, err := parser.Parse(buf)
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"temperature",
+			map[string]string{"host": "server01", "status": "OK"},
+			map[string]interface{}{"value": 21.5},
+			time.Unix(1700000000, 0),
+		),
+		metric.New(
+			"humidity",
+			map[string]string{"host": "server01", "status": "FAILED"},
+			map[string]interface{}{"value": 48.0},
+			time.Unix(1700000000, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestParseDescriptorSet(t *testing.T) {
+	// Create a descriptor set including all dependencies as produced by
+	// "protoc --include_imports --descriptor_set_out"
+	files, err := (&protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{}),
+	}).Compile(context.Background(), "testdata/sensors.proto")
+	require.NoError(t, err)
+	fd := files[0]
+
+	var set descriptorpb.FileDescriptorSet
+	imports := fd.Imports()
+	for i := range imports.Len() {
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(imports.Get(i).FileDescriptor))
+	}
+	set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	buf, err := proto.Marshal(&set)
+	require.NoError(t, err)
+
+	filename := filepath.Join(t.TempDir(), "sensors.binpb")
+	require.NoError(t, os.WriteFile(filename, buf, 0600))
+
+	parser := &Parser{
+		DescriptorSet: filename,
+		MessageType:   "example.Report.Location",
+		Measurement:   "location",
+		Log:           testutil.Logger{},
+	}
+	require.NoError(t, parser.Init())
+
+	actual, err := parser.ParseLine(string(marshalMessage(t, "example.Report.Location", `{"building": "south"}`)))
+	require.NoError(t, err)
+
+	expected := metric.New(
+		"location",
+		map[string]string{},
+		map[string]interface{}{"building": "south", "floor": uint64(0)},
+		time.Unix(0, 0),
+	)
+	testutil.RequireMetricEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestParseSchemaRegistry(t *testing.T) {
+	schema, err := os.ReadFile("testdata/sensors.proto")
+	require.NoError(t, err)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/schemas/ids/42" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		response := map[string]string{"schemaType": "PROTOBUF", "schema": string(schema)}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	parser := &Parser{
+		SchemaRegistry:   "http://user:secret@" + server.Listener.Addr().String(),
+		MeasurementField: "sensor",
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, parser.Init())
+
+	// Schema ID 42 and message indexes [1, 0] pointing to "Report.Location"
+	header := []byte{0x00, 0x00, 0x00, 0x00, 0x2a, 0x04, 0x02, 0x00}
+	location := marshalMessage(t, "example.Report.Location", `{"building": "south", "floor": 1}`)
+	actual, err := parser.Parse(append(header, location...))
+	require.NoError(t, err)
+	expected := []telegraf.Metric{
+		metric.New(
+			"example.Report.Location",
+			map[string]string{},
+			map[string]interface{}{"building": "south", "floor": uint64(1)},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	// A single zero index refers to the first message, i.e. "Reading"
+	header = []byte{0x00, 0x00, 0x00, 0x00, 0x2a, 0x00}
+	reading := marshalMessage(t, "example.Reading", `{"sensor": "temperature", "value": 21.5}`)
+	actual, err = parser.Parse(append(header, reading...))
+	require.NoError(t, err)
+	expected = []telegraf.Metric{
+		metric.New(
+			"temperature",
+			map[string]string{},
+			map[string]interface{}{"value": 21.5, "status": "UNKNOWN"},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	// The schema must only be fetched once
+	require.Equal(t, 1, requests)
+
+	// Unknown schemas and invalid messages should error
+	_, err = parser.Parse([]byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x00})
+	require.ErrorContains(t, err, "404 Not Found")
+	_, err = parser.Parse(reading)
+	require.ErrorContains(t, err, "not Confluent Wire Format")
+	_, err = parser.Parse([]byte{0x00, 0x00, 0x00, 0x00, 0x2a, 0x02, 0x0a})
+	require.ErrorContains(t, err, "message index 5 out of range")
+}
+
+func marshalMessage(t *testing.T, msgType, data string) []byte {
+	t.Helper()
+
+	files, err := compileFiles([]string{"testdata/sensors.proto"}, nil)
+	require.NoError(t, err)
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(msgType))
+	require.NoError(t, err)
+
+	msg := dynamicpb.NewMessage(desc.(protoreflect.MessageDescriptor))
+	require.NoError(t, protojson.Unmarshal([]byte(data), msg))
+	buf, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return buf
+}
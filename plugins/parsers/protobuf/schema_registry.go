@@ -0,0 +1,93 @@
+package protobuf
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/influxdata/telegraf/plugins/common/schemaregistry"
+)
+
+type schemaRegistry struct {
+	client *schemaregistry.Client
+	cache  map[int]protoreflect.FileDescriptor
+	mu     sync.RWMutex
+}
+
+func newSchemaRegistry(addr, caCertPath string) (*schemaRegistry, error) {
+	client, err := schemaregistry.NewClient(addr, caCertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemaRegistry{
+		client: client,
+		cache:  make(map[int]protoreflect.FileDescriptor),
+	}, nil
+}
+
+func (sr *schemaRegistry) getFileDescriptor(id int) (protoreflect.FileDescriptor, error) {
+	sr.mu.RLock()
+	fd, found := sr.cache[id]
+	sr.mu.RUnlock()
+	if found {
+		return fd, nil
+	}
+
+	// Collect the schema and all (transitive) references as sources for
+	// the compiler
+	schema, err := sr.client.SchemaByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type != "" && schema.Type != "PROTOBUF" {
+		return nil, fmt.Errorf("schema %d is of type %q and not a protocol-buffer schema", id, schema.Type)
+	}
+
+	filename := "schema_" + strconv.Itoa(id) + ".proto"
+	sources := map[string]string{filename: schema.Schema}
+	if err := sr.resolveReferences(schema.References, sources); err != nil {
+		return nil, err
+	}
+
+	compiler := &protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), filename)
+	if err != nil {
+		return nil, fmt.Errorf("compiling schema %d failed: %w", id, err)
+	}
+	if len(files) < 1 {
+		return nil, fmt.Errorf("schema %d does not contain a file descriptor", id)
+	}
+	fd = files[0]
+
+	// Lock the cache map before update.
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.cache[id] = fd
+	return fd, nil
+}
+
+func (sr *schemaRegistry) resolveReferences(refs []schemaregistry.Reference, sources map[string]string) error {
+	for _, ref := range refs {
+		if _, found := sources[ref.Name]; found {
+			continue
+		}
+		schema, err := sr.client.SchemaBySubject(ref.Subject, ref.Version)
+		if err != nil {
+			return fmt.Errorf("fetching reference %q failed: %w", ref.Name, err)
+		}
+		sources[ref.Name] = schema.Schema
+		if err := sr.resolveReferences(schema.References, sources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
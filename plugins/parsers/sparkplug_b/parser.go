@@ -0,0 +1,245 @@
+package sparkplug_b
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/sparkplug"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+// birthCertificate holds the metric names and data types announced in a
+// birth message of an edge node or device
+type birthCertificate struct {
+	names map[uint64]string
+	types map[string]sparkplug.DataType
+}
+
+func newBirthCertificate(metrics []*sparkplug.Metric) *birthCertificate {
+	b := &birthCertificate{
+		names: make(map[uint64]string, len(metrics)),
+		types: make(map[string]sparkplug.DataType, len(metrics)),
+	}
+	for _, m := range metrics {
+		if m.Name == "" {
+			continue
+		}
+		if m.HasAlias {
+			b.names[m.Alias] = m.Name
+		}
+		b.types[m.Name] = m.DataType
+	}
+	return b
+}
+
+// edgeNode holds the session state of an edge node
+type edgeNode struct {
+	birth   *birthCertificate
+	devices map[string]*birthCertificate
+	bdSeq   *uint64
+	seq     uint64
+}
+
+type Parser struct {
+	MetricName  string            `toml:"-"`
+	DefaultTags map[string]string `toml:"-"`
+	Log         telegraf.Logger   `toml:"-"`
+
+	nodes map[string]*edgeNode
+	// Edge nodes already warned about for sending data before their birth
+	unknown map[string]bool
+	sync.Mutex
+}
+
+func (p *Parser) Init() error {
+	p.nodes = make(map[string]*edgeNode)
+	p.unknown = make(map[string]bool)
+	return nil
+}
+
+// Parse decodes the given payload without any topic information. Only metrics
+// containing a name can be decoded as aliases cannot be resolved.
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	var payload sparkplug.Payload
+	if err := payload.Unmarshal(buf); err != nil {
+		return nil, fmt.Errorf("decoding payload failed: %w", err)
+	}
+	return p.convert(&payload, nil, nil), nil
+}
+
+// ParseTopic decodes the given payload received on the given topic and
+// tracks the edge node and device sessions to resolve metric aliases
+func (p *Parser) ParseTopic(topic string, buf []byte) ([]telegraf.Metric, error) {
+	t, err := sparkplug.ParseTopic(topic)
+	if err != nil {
+		return nil, err
+	}
+	// State messages of host applications are JSON encoded and not relevant
+	if t.MessageType == sparkplug.State {
+		return nil, nil
+	}
+
+	var payload sparkplug.Payload
+	if err := payload.Unmarshal(buf); err != nil {
+		return nil, fmt.Errorf("decoding payload on topic %q failed: %w", topic, err)
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	key := t.GroupID + "/" + t.EdgeNodeID
+	node := p.nodes[key]
+
+	switch t.MessageType {
+	case sparkplug.NodeBirth:
+		node = &edgeNode{
+			birth:   newBirthCertificate(payload.Metrics),
+			devices: make(map[string]*birthCertificate),
+		}
+		for _, m := range payload.Metrics {
+			if m.Name == "bdSeq" {
+				if v, ok := m.Value.(uint64); ok {
+					node.bdSeq = &v
+				}
+			}
+		}
+		if payload.Seq != nil {
+			node.seq = *payload.Seq
+		}
+		p.nodes[key] = node
+		delete(p.unknown, key)
+	case sparkplug.NodeDeath:
+		// Ignore stale death certificates of previous sessions
+		if node != nil && node.bdSeq != nil {
+			for _, m := range payload.Metrics {
+				if v, ok := m.Value.(uint64); ok && m.Name == "bdSeq" && v != *node.bdSeq {
+					p.Log.Debugf("Ignoring death certificate of previous session of %q", key)
+					return nil, nil
+				}
+			}
+		}
+		delete(p.nodes, key)
+		node = nil
+	case sparkplug.DeviceBirth, sparkplug.DeviceDeath, sparkplug.NodeData, sparkplug.DeviceData:
+		if node == nil {
+			if !p.unknown[key] {
+				p.Log.Warnf("Received %s for unknown edge node %q, waiting for birth", t.MessageType, key)
+				p.unknown[key] = true
+			}
+		} else if payload.Seq != nil {
+			expected := (node.seq + 1) % 256
+			if *payload.Seq != expected {
+				p.Log.Warnf("Sequence number of edge node %q is %d but expected %d, messages might be lost",
+					key, *payload.Seq, expected)
+			}
+			node.seq = *payload.Seq
+		}
+		if node != nil {
+			switch t.MessageType {
+			case sparkplug.DeviceBirth:
+				node.devices[t.DeviceID] = newBirthCertificate(payload.Metrics)
+			case sparkplug.DeviceDeath:
+				delete(node.devices, t.DeviceID)
+			}
+		}
+	}
+
+	// Determine the birth certificate to resolve aliases and data types
+	var birth *birthCertificate
+	if node != nil {
+		if t.DeviceID == "" {
+			birth = node.birth
+		} else {
+			birth = node.devices[t.DeviceID]
+		}
+	}
+
+	tags := map[string]string{
+		"group_id":     t.GroupID,
+		"message_type": t.MessageType,
+		"edge_node_id": t.EdgeNodeID,
+	}
+	if t.DeviceID != "" {
+		tags["device_id"] = t.DeviceID
+	}
+
+	return p.convert(&payload, birth, tags), nil
+}
+
+func (p *Parser) convert(payload *sparkplug.Payload, birth *birthCertificate, tags map[string]string) []telegraf.Metric {
+	now := time.Now()
+
+	// Group the values by timestamp
+	var timestamps []uint64
+	fields := make(map[uint64]map[string]interface{})
+	for _, m := range payload.Metrics {
+		if m.IsNull {
+			continue
+		}
+
+		name := m.Name
+		if name == "" && m.HasAlias && birth != nil {
+			name = birth.names[m.Alias]
+		}
+		if name == "" {
+			p.Log.Warnf("Cannot resolve name of metric with alias %d", m.Alias)
+			continue
+		}
+
+		dt := m.DataType
+		if dt == sparkplug.Unknown && birth != nil {
+			dt = birth.types[name]
+		}
+		value, err := m.Convert(dt)
+		if err != nil {
+			p.Log.Debugf("Skipping metric %q: %v", name, err)
+			continue
+		}
+
+		ts := m.Timestamp
+		if ts == 0 {
+			ts = payload.Timestamp
+		}
+		if _, found := fields[ts]; !found {
+			fields[ts] = make(map[string]interface{})
+			timestamps = append(timestamps, ts)
+		}
+		fields[ts][name] = value
+	}
+
+	metrics := make([]telegraf.Metric, 0, len(timestamps))
+	for _, ts := range timestamps {
+		t := now
+		if ts > 0 {
+			t = time.UnixMilli(int64(ts))
+		}
+		m := metric.New(p.MetricName, tags, fields[ts], t)
+		for k, v := range p.DefaultTags {
+			if !m.HasTag(k) {
+				m.AddTag(k, v)
+			}
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+func (*Parser) ParseLine(string) (telegraf.Metric, error) {
+	return nil, errors.New("parsing line is not supported by sparkplug_b parser")
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+func init() {
+	parsers.Add("sparkplug_b",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{MetricName: defaultMetricName}
+		},
+	)
+}
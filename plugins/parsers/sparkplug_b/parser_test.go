@@ -0,0 +1,201 @@
+package sparkplug_b
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/sparkplug"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func encode(t *testing.T, seq *uint64, ts uint64, metrics ...*sparkplug.Metric) []byte {
+	t.Helper()
+	payload := &sparkplug.Payload{Timestamp: ts, Seq: seq, Metrics: metrics}
+	buf, err := payload.Marshal()
+	require.NoError(t, err)
+	return buf
+}
+
+func seq(v uint64) *uint64 {
+	return &v
+}
+
+func TestParseSession(t *testing.T) {
+	parser := &Parser{MetricName: "sparkplug", Log: testutil.Logger{}}
+	require.NoError(t, parser.Init())
+
+	// Node birth announcing the metrics with their aliases
+	birth := encode(t, seq(0), 1700000000000,
+		&sparkplug.Metric{Name: "bdSeq", DataType: sparkplug.UInt64, Value: uint64(3)},
+		&sparkplug.Metric{Name: "Node Control/Rebirth", DataType: sparkplug.Boolean, Value: false},
+		&sparkplug.Metric{Name: "temperature", Alias: 1, HasAlias: true, DataType: sparkplug.Double, Value: 21.5},
+	)
+	actual, err := parser.ParseTopic("spBv1.0/plant/NBIRTH/edge1", birth)
+	require.NoError(t, err)
+
+	// Device birth and data using aliases only
+	dbirth := encode(t, seq(1), 1700000001000,
+		&sparkplug.Metric{Name: "level", Alias: 1, HasAlias: true, DataType: sparkplug.Int8, Value: uint32(0xff)},
+		&sparkplug.Metric{Name: "state", Alias: 2, HasAlias: true, DataType: sparkplug.String, Value: "idle"},
+	)
+	metrics, err := parser.ParseTopic("spBv1.0/plant/DBIRTH/edge1/tank", dbirth)
+	require.NoError(t, err)
+	actual = append(actual, metrics...)
+
+	ndata := encode(t, seq(2), 1700000002000,
+		&sparkplug.Metric{Alias: 1, HasAlias: true, Value: 22.0},
+	)
+	metrics, err = parser.ParseTopic("spBv1.0/plant/NDATA/edge1", ndata)
+	require.NoError(t, err)
+	actual = append(actual, metrics...)
+
+	ddata := encode(t, seq(3), 1700000003000,
+		&sparkplug.Metric{Alias: 1, HasAlias: true, Value: uint32(0xfe)},
+		&sparkplug.Metric{Alias: 2, HasAlias: true, Timestamp: 1700000002500, IsHistorical: true, Value: "filling"},
+	)
+	metrics, err = parser.ParseTopic("spBv1.0/plant/DDATA/edge1/tank", ddata)
+	require.NoError(t, err)
+	actual = append(actual, metrics...)
+
+	node := map[string]string{"group_id": "plant", "edge_node_id": "edge1"}
+	device := map[string]string{"group_id": "plant", "edge_node_id": "edge1", "device_id": "tank"}
+	withType := func(tags map[string]string, mt string) map[string]string {
+		result := map[string]string{"message_type": mt}
+		for k, v := range tags {
+			result[k] = v
+		}
+		return result
+	}
+	expected := []telegraf.Metric{
+		metric.New("sparkplug", withType(node, "NBIRTH"),
+			map[string]interface{}{"bdSeq": uint64(3), "Node Control/Rebirth": false, "temperature": 21.5},
+			time.UnixMilli(1700000000000),
+		),
+		metric.New("sparkplug", withType(device, "DBIRTH"),
+			map[string]interface{}{"level": int64(-1), "state": "idle"},
+			time.UnixMilli(1700000001000),
+		),
+		metric.New("sparkplug", withType(node, "NDATA"),
+			map[string]interface{}{"temperature": 22.0},
+			time.UnixMilli(1700000002000),
+		),
+		metric.New("sparkplug", withType(device, "DDATA"),
+			map[string]interface{}{"level": int64(-2)},
+			time.UnixMilli(1700000003000),
+		),
+		metric.New("sparkplug", withType(device, "DDATA"),
+			map[string]interface{}{"state": "filling"},
+			time.UnixMilli(1700000002500),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestParseSequenceGap(t *testing.T) {
+	logger := &testutil.CaptureLogger{}
+	parser := &Parser{MetricName: "sparkplug", Log: logger}
+	require.NoError(t, parser.Init())
+
+	birth := encode(t, seq(0), 1700000000000,
+		&sparkplug.Metric{Name: "temperature", Alias: 1, HasAlias: true, DataType: sparkplug.Float, Value: float32(1.5)},
+	)
+	_, err := parser.ParseTopic("spBv1.0/plant/NBIRTH/edge1", birth)
+	require.NoError(t, err)
+
+	data := encode(t, seq(5), 1700000001000, &sparkplug.Metric{Alias: 1, HasAlias: true, Value: float32(2.5)})
+	actual, err := parser.ParseTopic("spBv1.0/plant/NDATA/edge1", data)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, map[string]interface{}{"temperature": 2.5}, actual[0].Fields())
+	require.Contains(t, logger.Warnings(), `W! [] Sequence number of edge node "plant/edge1" is 5 but expected 1, messages might be lost`)
+}
+
+func TestParseDeath(t *testing.T) {
+	logger := &testutil.CaptureLogger{}
+	parser := &Parser{MetricName: "sparkplug", Log: logger}
+	require.NoError(t, parser.Init())
+
+	birth := encode(t, seq(0), 1700000000000,
+		&sparkplug.Metric{Name: "bdSeq", DataType: sparkplug.UInt64, Value: uint64(1)},
+		&sparkplug.Metric{Name: "temperature", Alias: 1, HasAlias: true, DataType: sparkplug.Double, Value: 21.5},
+	)
+	_, err := parser.ParseTopic("spBv1.0/plant/NBIRTH/edge1", birth)
+	require.NoError(t, err)
+
+	// Stale death certificates of previous sessions must be ignored
+	death := encode(t, nil, 1700000001000, &sparkplug.Metric{Name: "bdSeq", DataType: sparkplug.UInt64, Value: uint64(0)})
+	actual, err := parser.ParseTopic("spBv1.0/plant/NDEATH/edge1", death)
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	data := encode(t, seq(1), 1700000002000, &sparkplug.Metric{Alias: 1, HasAlias: true, Value: 22.0})
+	actual, err = parser.ParseTopic("spBv1.0/plant/NDATA/edge1", data)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+
+	death = encode(t, nil, 1700000003000, &sparkplug.Metric{Name: "bdSeq", DataType: sparkplug.UInt64, Value: uint64(1)})
+	actual, err = parser.ParseTopic("spBv1.0/plant/NDEATH/edge1", death)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, "NDEATH", actual[0].Tags()["message_type"])
+
+	// Aliases cannot be resolved after the death of the node
+	data = encode(t, seq(2), 1700000004000, &sparkplug.Metric{Alias: 1, HasAlias: true, Value: 23.0})
+	actual, err = parser.ParseTopic("spBv1.0/plant/NDATA/edge1", data)
+	require.NoError(t, err)
+	require.Empty(t, actual)
+	require.Contains(t, logger.Warnings(), `W! [] Received NDATA for unknown edge node "plant/edge1", waiting for birth`)
+	require.Contains(t, logger.Warnings(), "W! [] Cannot resolve name of metric with alias 1")
+
+	// Unknown nodes must only be reported once
+	data = encode(t, seq(3), 1700000005000, &sparkplug.Metric{Alias: 1, HasAlias: true, Value: 24.0})
+	_, err = parser.ParseTopic("spBv1.0/plant/NDATA/edge1", data)
+	require.NoError(t, err)
+	var count int
+	for _, msg := range logger.Warnings() {
+		if strings.Contains(msg, "unknown edge node") {
+			count++
+		}
+	}
+	require.Equal(t, 1, count)
+}
+
+func TestParseWithoutTopic(t *testing.T) {
+	parser := &Parser{MetricName: "sparkplug", Log: testutil.Logger{}}
+	require.NoError(t, parser.Init())
+
+	buf := encode(t, seq(4), 1700000000000,
+		&sparkplug.Metric{Name: "counter", DataType: sparkplug.UInt32, Value: uint32(42)},
+		&sparkplug.Metric{Name: "ignored", DataType: sparkplug.Double, IsNull: true},
+	)
+	actual, err := parser.Parse(buf)
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		metric.New("sparkplug", map[string]string{}, map[string]interface{}{"counter": uint64(42)}, time.UnixMilli(1700000000000)),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestParseInvalid(t *testing.T) {
+	parser := &Parser{MetricName: "sparkplug", Log: testutil.Logger{}}
+	require.NoError(t, parser.Init())
+
+	_, err := parser.ParseTopic("foo/plant/NDATA/edge1", nil)
+	require.ErrorContains(t, err, `invalid topic "foo/plant/NDATA/edge1"`)
+
+	_, err = parser.ParseTopic("spBv1.0/plant/DDATA/edge1", nil)
+	require.ErrorContains(t, err, "invalid device topic")
+
+	_, err = parser.ParseTopic("spBv1.0/plant/NDATA/edge1", []byte{0x12, 0xff})
+	require.ErrorContains(t, err, "decoding payload")
+
+	actual, err := parser.ParseTopic("spBv1.0/STATE/scada", []byte(`{"online":true}`))
+	require.NoError(t, err)
+	require.Empty(t, actual)
+}
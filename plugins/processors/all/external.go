@@ -0,0 +1,5 @@
+//go:build !custom || processors || processors.external
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/external" // register plugin
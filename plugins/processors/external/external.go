@@ -0,0 +1,127 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package external
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin/pluginv1"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type External struct {
+	grpcplugin.Config
+	Timeout config.Duration `toml:"timeout"`
+	Log     telegraf.Logger `toml:"-"`
+
+	client    *grpcplugin.Client
+	processor pluginv1.ProcessorClient
+}
+
+func (*External) SampleConfig() string {
+	return sampleConfig
+}
+
+func (e *External) Init() error {
+	if len(e.Command) == 0 {
+		return errors.New("no command specified")
+	}
+	return nil
+}
+
+func (e *External) Start(telegraf.Accumulator) error {
+	client, err := e.Config.StartClient(pluginv1.PluginType_PLUGIN_TYPE_PROCESSOR, e.Log)
+	if err != nil {
+		return err
+	}
+	processor := pluginv1.NewProcessorClient(client.Conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+	if _, err := processor.Start(ctx, &pluginv1.StartRequest{}); err != nil {
+		client.Stop()
+		return &internal.StartupError{Err: err, Retry: true}
+	}
+	e.client = client
+	e.processor = processor
+	return nil
+}
+
+func (e *External) Add(m telegraf.Metric, acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	req := &pluginv1.ApplyRequest{Metrics: []*pluginv1.Metric{grpcplugin.ToProto(m)}}
+	resp, err := e.processor.Apply(ctx, req)
+	if err != nil {
+		// Pass on the metric unmodified to avoid losing data and start the
+		// processor again in case the process was restarted
+		e.Log.Errorf("Processing metric failed: %v", err)
+		acc.AddMetric(m)
+		e.restart()
+		return nil
+	}
+
+	// Modify the original metric in-place to keep the tracking information
+	// and create new metrics for all others
+	var kept bool
+	for _, pm := range resp.GetMetrics() {
+		if pm.Source != nil && pm.GetSource() == 0 && !kept {
+			grpcplugin.UpdateMetric(m, pm.GetMetric())
+			acc.AddMetric(m)
+			kept = true
+			continue
+		}
+		acc.AddMetric(grpcplugin.FromProto(pm.GetMetric()))
+	}
+	if !kept {
+		m.Drop()
+	}
+
+	return nil
+}
+
+// restart starts the processor again using a new context as the one of the
+// failed call might have expired already
+func (e *External) restart() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+	if _, err := e.processor.Start(ctx, &pluginv1.StartRequest{}); err != nil {
+		e.Log.Debugf("Starting processor failed: %v", err)
+	}
+}
+
+func (e *External) Stop() {
+	if e.client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+	if _, err := e.processor.Stop(ctx, &pluginv1.StopRequest{}); err != nil {
+		e.Log.Errorf("Stopping plugin failed: %v", err)
+	}
+
+	e.client.Stop()
+}
+
+func init() {
+	processors.AddStreaming("external", func() telegraf.StreamingProcessor {
+		return &External{
+			Config: grpcplugin.Config{
+				RestartDelay: config.Duration(10 * time.Second),
+				StartTimeout: config.Duration(10 * time.Second),
+			},
+			Timeout: config.Duration(5 * time.Second),
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package external
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/shim"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+var externalPlugin = flag.Bool("external-plugin", false, "if true, act like an external plugin instead of test")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *externalPlugin {
+		runExternalPlugin()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestProcessing(t *testing.T) {
+	plugin := newTestPlugin(t)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	input := []telegraf.Metric{
+		metric.New("cpu", map[string]string{"host": "a"}, map[string]interface{}{"value": 42}, time.Unix(0, 0), telegraf.Gauge),
+		metric.New("drop", map[string]string{}, map[string]interface{}{"value": 1}, time.Unix(0, 0)),
+	}
+	for _, m := range input {
+		require.NoError(t, plugin.Add(m, &acc))
+	}
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{"host": "a", "processed": "true"},
+			map[string]interface{}{"value": int64(42)},
+			time.Unix(0, 0),
+			telegraf.Gauge,
+		),
+		metric.New(
+			"cpu_copy",
+			map[string]string{"host": "a", "processed": "true"},
+			map[string]interface{}{"value": int64(42)},
+			time.Unix(0, 0),
+			telegraf.Gauge,
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestTracking(t *testing.T) {
+	plugin := newTestPlugin(t)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	var delivered []telegraf.DeliveryInfo
+	notify := func(di telegraf.DeliveryInfo) {
+		delivered = append(delivered, di)
+	}
+
+	// Metrics dropped by the plugin must be marked as delivered
+	m, _ := metric.WithTracking(metric.New("drop", nil, map[string]interface{}{"value": 1}, time.Unix(0, 0)), notify)
+	require.NoError(t, plugin.Add(m, &acc))
+	require.Len(t, delivered, 1)
+	require.True(t, delivered[0].Delivered())
+
+	// Modified metrics must keep their tracking information
+	m, _ = metric.WithTracking(metric.New("cpu", nil, map[string]interface{}{"value": 1}, time.Unix(0, 0)), notify)
+	require.NoError(t, plugin.Add(m, &acc))
+	require.Len(t, delivered, 1)
+	processed := acc.GetTelegrafMetrics()
+	require.Len(t, processed, 2)
+	processed[0].Accept()
+	require.Len(t, delivered, 2)
+}
+
+func newTestPlugin(t *testing.T) *External {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	return &External{
+		Config: grpcplugin.Config{
+			Command:      []string{exe, "-external-plugin"},
+			RestartDelay: config.Duration(100 * time.Millisecond),
+			StartTimeout: config.Duration(5 * time.Second),
+		},
+		Timeout: config.Duration(5 * time.Second),
+		Log:     testutil.Logger{},
+	}
+}
+
+func runExternalPlugin() {
+	s := shim.New()
+	err := s.AddProcessor(&testProcessor{})
+	if err == nil {
+		err = s.Run(shim.PollIntervalDisabled)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // error code is important for this "test"
+		os.Exit(1)
+	}
+}
+
+// testProcessor tags all metrics, adds a copy and drops metrics named "drop"
+type testProcessor struct{}
+
+func (*testProcessor) SampleConfig() string {
+	return ""
+}
+
+func (*testProcessor) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	out := make([]telegraf.Metric, 0, 2*len(in))
+	for _, m := range in {
+		if m.Name() == "drop" {
+			m.Drop()
+			continue
+		}
+		m.AddTag("processed", "true")
+		c := m.Copy()
+		c.SetName(m.Name() + "_copy")
+		out = append(out, m, c)
+	}
+	return out
+}
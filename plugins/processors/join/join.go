@@ -0,0 +1,213 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package join
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type Join struct {
+	Left            []string        `toml:"left"`
+	Right           []string        `toml:"right"`
+	JoinKeys        []string        `toml:"join_keys"`
+	Window          config.Duration `toml:"window"`
+	Mode            string          `toml:"mode"`
+	Tags            []string        `toml:"tags"`
+	Fields          []string        `toml:"fields"`
+	DropRight       bool            `toml:"drop_right"`
+	MaxCacheEntries int             `toml:"max_cache_entries"`
+	Log             telegraf.Logger `toml:"-"`
+
+	leftFilter  filter.Filter
+	rightFilter filter.Filter
+	tagFilter   filter.Filter
+	fieldFilter filter.Filter
+	cache       *lru.Cache[string, *record]
+}
+
+// record holds the data of the latest right-hand side metric for a join key
+type record struct {
+	timestamp time.Time
+	tags      []*telegraf.Tag
+	fields    []*telegraf.Field
+}
+
+func (*Join) SampleConfig() string {
+	return sampleConfig
+}
+
+func (j *Join) Init() error {
+	if len(j.Left) == 0 {
+		return errors.New("no left-hand side measurements specified")
+	}
+	if len(j.Right) == 0 {
+		return errors.New("no right-hand side measurements specified")
+	}
+	if len(j.JoinKeys) == 0 {
+		return errors.New("no join keys specified")
+	}
+	if len(j.Tags) == 0 && len(j.Fields) == 0 {
+		return errors.New("neither tags nor fields to copy specified")
+	}
+	if j.Window < 0 {
+		return errors.New("window must not be negative")
+	}
+
+	switch j.Mode {
+	case "":
+		j.Mode = "left"
+	case "inner", "left":
+	default:
+		return fmt.Errorf("invalid mode %q", j.Mode)
+	}
+
+	if j.MaxCacheEntries <= 0 {
+		return errors.New("max_cache_entries must be positive")
+	}
+
+	var err error
+	if j.leftFilter, err = filter.Compile(j.Left); err != nil {
+		return fmt.Errorf("creating left-hand side filter failed: %w", err)
+	}
+	if j.rightFilter, err = filter.Compile(j.Right); err != nil {
+		return fmt.Errorf("creating right-hand side filter failed: %w", err)
+	}
+	if j.tagFilter, err = filter.Compile(j.Tags); err != nil {
+		return fmt.Errorf("creating tag filter failed: %w", err)
+	}
+	if j.fieldFilter, err = filter.Compile(j.Fields); err != nil {
+		return fmt.Errorf("creating field filter failed: %w", err)
+	}
+
+	j.cache, err = lru.New[string, *record](j.MaxCacheEntries)
+	if err != nil {
+		return fmt.Errorf("creating cache failed: %w", err)
+	}
+
+	return nil
+}
+
+func (j *Join) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	out := make([]telegraf.Metric, 0, len(in))
+	for _, m := range in {
+		switch {
+		case j.rightFilter.Match(m.Name()):
+			j.store(m)
+			if j.DropRight {
+				m.Drop()
+				continue
+			}
+		case j.leftFilter.Match(m.Name()):
+			if !j.join(m) && j.Mode == "inner" {
+				m.Drop()
+				continue
+			}
+		}
+		out = append(out, m)
+	}
+
+	return out
+}
+
+// store remembers the tags and fields of the given right-hand side metric
+// to be copied to left-hand side metrics with the same join key
+func (j *Join) store(m telegraf.Metric) {
+	key, found := j.key(m)
+	if !found {
+		j.Log.Tracef("Metric %q is missing join keys, ignoring it", m.Name())
+		return
+	}
+
+	// Only keep the latest record for a join key
+	if r, found := j.cache.Peek(key); found && r.timestamp.After(m.Time()) {
+		return
+	}
+
+	r := &record{timestamp: m.Time()}
+	if j.tagFilter != nil {
+		for _, tag := range m.TagList() {
+			if j.tagFilter.Match(tag.Key) {
+				r.tags = append(r.tags, &telegraf.Tag{Key: tag.Key, Value: tag.Value})
+			}
+		}
+	}
+	if j.fieldFilter != nil {
+		for _, field := range m.FieldList() {
+			if j.fieldFilter.Match(field.Key) {
+				r.fields = append(r.fields, &telegraf.Field{Key: field.Key, Value: field.Value})
+			}
+		}
+	}
+	j.cache.Add(key, r)
+}
+
+// join copies the data of the matching right-hand side record to the given
+// metric and returns true if a matching record was found
+func (j *Join) join(m telegraf.Metric) bool {
+	key, found := j.key(m)
+	if !found {
+		return false
+	}
+
+	r, found := j.cache.Get(key)
+	if !found {
+		return false
+	}
+
+	if j.Window > 0 {
+		diff := m.Time().Sub(r.timestamp)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > time.Duration(j.Window) {
+			return false
+		}
+	}
+
+	for _, tag := range r.tags {
+		if !m.HasTag(tag.Key) {
+			m.AddTag(tag.Key, tag.Value)
+		}
+	}
+	for _, field := range r.fields {
+		if !m.HasField(field.Key) {
+			m.AddField(field.Key, field.Value)
+		}
+	}
+
+	return true
+}
+
+func (j *Join) key(m telegraf.Metric) (string, bool) {
+	values := make([]string, 0, len(j.JoinKeys))
+	for _, k := range j.JoinKeys {
+		v, found := m.GetTag(k)
+		if !found {
+			return "", false
+		}
+		values = append(values, v)
+	}
+	return strings.Join(values, "\x00"), true
+}
+
+func init() {
+	processors.Add("join", func() telegraf.Processor {
+		return &Join{
+			Window:          config.Duration(time.Minute),
+			MaxCacheEntries: 1000,
+		}
+	})
+}
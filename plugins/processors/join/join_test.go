@@ -0,0 +1,424 @@
+package join
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestInitFail(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *Join
+		expected string
+	}{
+		{
+			name:     "no left",
+			plugin:   &Join{Right: []string{"b"}, JoinKeys: []string{"k"}, Tags: []string{"*"}},
+			expected: "no left-hand side measurements specified",
+		},
+		{
+			name:     "no right",
+			plugin:   &Join{Left: []string{"a"}, JoinKeys: []string{"k"}, Tags: []string{"*"}},
+			expected: "no right-hand side measurements specified",
+		},
+		{
+			name:     "no keys",
+			plugin:   &Join{Left: []string{"a"}, Right: []string{"b"}, Tags: []string{"*"}},
+			expected: "no join keys specified",
+		},
+		{
+			name:     "nothing to copy",
+			plugin:   &Join{Left: []string{"a"}, Right: []string{"b"}, JoinKeys: []string{"k"}},
+			expected: "neither tags nor fields to copy specified",
+		},
+		{
+			name: "invalid mode",
+			plugin: &Join{
+				Left:            []string{"a"},
+				Right:           []string{"b"},
+				JoinKeys:        []string{"k"},
+				Tags:            []string{"*"},
+				Mode:            "outer",
+				MaxCacheEntries: 10,
+			},
+			expected: `invalid mode "outer"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.plugin.Init(), tt.expected)
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name     string
+		mode     string
+		window   time.Duration
+		input    []telegraf.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name: "left join",
+			mode: "left",
+			input: []telegraf.Metric{
+				metric.New("pod",
+					map[string]string{"pod_name": "web-1", "namespace": "prod", "other": "x"},
+					map[string]interface{}{"restarts": 3, "phase": "running"},
+					now,
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "web-1"},
+					map[string]interface{}{"usage": 12.5},
+					now.Add(10*time.Second),
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "db-1"},
+					map[string]interface{}{"usage": 42.0},
+					now.Add(10*time.Second),
+				),
+				metric.New("mem",
+					map[string]string{"pod_name": "web-1"},
+					map[string]interface{}{"used": 1024},
+					now.Add(10*time.Second),
+				),
+			},
+			expected: []telegraf.Metric{
+				metric.New("pod",
+					map[string]string{"pod_name": "web-1", "namespace": "prod", "other": "x"},
+					map[string]interface{}{"restarts": 3, "phase": "running"},
+					now,
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "web-1", "namespace": "prod"},
+					map[string]interface{}{"usage": 12.5, "restarts": 3},
+					now.Add(10*time.Second),
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "db-1"},
+					map[string]interface{}{"usage": 42.0},
+					now.Add(10*time.Second),
+				),
+				metric.New("mem",
+					map[string]string{"pod_name": "web-1"},
+					map[string]interface{}{"used": 1024},
+					now.Add(10*time.Second),
+				),
+			},
+		},
+		{
+			name: "inner join",
+			mode: "inner",
+			input: []telegraf.Metric{
+				metric.New("pod",
+					map[string]string{"pod_name": "web-1", "namespace": "prod"},
+					map[string]interface{}{"restarts": 3},
+					now,
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "web-1"},
+					map[string]interface{}{"usage": 12.5},
+					now.Add(10*time.Second),
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "db-1"},
+					map[string]interface{}{"usage": 42.0},
+					now.Add(10*time.Second),
+				),
+			},
+			expected: []telegraf.Metric{
+				metric.New("pod",
+					map[string]string{"pod_name": "web-1", "namespace": "prod"},
+					map[string]interface{}{"restarts": 3},
+					now,
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "web-1", "namespace": "prod"},
+					map[string]interface{}{"usage": 12.5, "restarts": 3},
+					now.Add(10*time.Second),
+				),
+			},
+		},
+		{
+			name:   "outside window",
+			mode:   "inner",
+			window: 5 * time.Second,
+			input: []telegraf.Metric{
+				metric.New("pod",
+					map[string]string{"pod_name": "web-1", "namespace": "prod"},
+					map[string]interface{}{"restarts": 3},
+					now,
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "web-1"},
+					map[string]interface{}{"usage": 12.5},
+					now.Add(10*time.Second),
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "web-1"},
+					map[string]interface{}{"usage": 10.0},
+					now.Add(-3*time.Second),
+				),
+			},
+			expected: []telegraf.Metric{
+				metric.New("pod",
+					map[string]string{"pod_name": "web-1", "namespace": "prod"},
+					map[string]interface{}{"restarts": 3},
+					now,
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "web-1", "namespace": "prod"},
+					map[string]interface{}{"usage": 10.0, "restarts": 3},
+					now.Add(-3*time.Second),
+				),
+			},
+		},
+		{
+			name: "latest right-hand side wins",
+			mode: "left",
+			input: []telegraf.Metric{
+				metric.New("pod",
+					map[string]string{"pod_name": "web-1", "namespace": "new"},
+					map[string]interface{}{"restarts": 4},
+					now.Add(time.Second),
+				),
+				metric.New("pod",
+					map[string]string{"pod_name": "web-1", "namespace": "old"},
+					map[string]interface{}{"restarts": 3},
+					now,
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "web-1", "namespace": "keep"},
+					map[string]interface{}{"usage": 12.5},
+					now.Add(10*time.Second),
+				),
+			},
+			expected: []telegraf.Metric{
+				metric.New("pod",
+					map[string]string{"pod_name": "web-1", "namespace": "new"},
+					map[string]interface{}{"restarts": 4},
+					now.Add(time.Second),
+				),
+				metric.New("pod",
+					map[string]string{"pod_name": "web-1", "namespace": "old"},
+					map[string]interface{}{"restarts": 3},
+					now,
+				),
+				metric.New("cpu",
+					map[string]string{"pod_name": "web-1", "namespace": "keep"},
+					map[string]interface{}{"usage": 12.5, "restarts": 4},
+					now.Add(10*time.Second),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Join{
+				Left:            []string{"cpu"},
+				Right:           []string{"pod"},
+				JoinKeys:        []string{"pod_name"},
+				Window:          config.Duration(tt.window),
+				Mode:            tt.mode,
+				Tags:            []string{"namespace"},
+				Fields:          []string{"restarts"},
+				MaxCacheEntries: 10,
+				Log:             &testutil.Logger{},
+			}
+			require.NoError(t, plugin.Init())
+
+			actual := plugin.Apply(tt.input...)
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestMultipleKeys(t *testing.T) {
+	now := time.Now()
+
+	plugin := &Join{
+		Left:            []string{"cpu"},
+		Right:           []string{"pod*"},
+		JoinKeys:        []string{"cluster", "pod_name"},
+		Mode:            "inner",
+		Tags:            []string{"node*"},
+		MaxCacheEntries: 10,
+		DropRight:       true,
+		Log:             &testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	input := []telegraf.Metric{
+		metric.New("pod_info",
+			map[string]string{"cluster": "a", "pod_name": "web-1", "node_name": "n1"},
+			map[string]interface{}{"value": 1},
+			now,
+		),
+		metric.New("pod_info",
+			map[string]string{"cluster": "b", "pod_name": "web-1", "node_name": "n2"},
+			map[string]interface{}{"value": 1},
+			now,
+		),
+		metric.New("pod_info",
+			map[string]string{"pod_name": "web-1", "node_name": "n3"},
+			map[string]interface{}{"value": 1},
+			now,
+		),
+		metric.New("cpu",
+			map[string]string{"cluster": "b", "pod_name": "web-1"},
+			map[string]interface{}{"usage": 1.0},
+			now,
+		),
+		metric.New("cpu",
+			map[string]string{"pod_name": "web-1"},
+			map[string]interface{}{"usage": 2.0},
+			now,
+		),
+	}
+
+	expected := []telegraf.Metric{
+		metric.New("cpu",
+			map[string]string{"cluster": "b", "pod_name": "web-1", "node_name": "n2"},
+			map[string]interface{}{"usage": 1.0},
+			now,
+		),
+	}
+
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestCacheEviction(t *testing.T) {
+	now := time.Now()
+
+	plugin := &Join{
+		Left:            []string{"cpu"},
+		Right:           []string{"pod"},
+		JoinKeys:        []string{"pod_name"},
+		Mode:            "inner",
+		Tags:            []string{"namespace"},
+		MaxCacheEntries: 1,
+		DropRight:       true,
+		Log:             &testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	input := []telegraf.Metric{
+		metric.New("pod",
+			map[string]string{"pod_name": "web-1", "namespace": "a"},
+			map[string]interface{}{"value": 1},
+			now,
+		),
+		metric.New("pod",
+			map[string]string{"pod_name": "web-2", "namespace": "b"},
+			map[string]interface{}{"value": 1},
+			now,
+		),
+		metric.New("cpu",
+			map[string]string{"pod_name": "web-1"},
+			map[string]interface{}{"usage": 1.0},
+			now,
+		),
+		metric.New("cpu",
+			map[string]string{"pod_name": "web-2"},
+			map[string]interface{}{"usage": 2.0},
+			now,
+		),
+	}
+
+	expected := []telegraf.Metric{
+		metric.New("cpu",
+			map[string]string{"pod_name": "web-2", "namespace": "b"},
+			map[string]interface{}{"usage": 2.0},
+			now,
+		),
+	}
+
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestTracking(t *testing.T) {
+	now := time.Now()
+
+	inputRaw := []telegraf.Metric{
+		metric.New("pod",
+			map[string]string{"pod_name": "web-1", "namespace": "prod"},
+			map[string]interface{}{"restarts": 3},
+			now,
+		),
+		metric.New("cpu",
+			map[string]string{"pod_name": "web-1"},
+			map[string]interface{}{"usage": 12.5},
+			now,
+		),
+		metric.New("cpu",
+			map[string]string{"pod_name": "db-1"},
+			map[string]interface{}{"usage": 42.0},
+			now,
+		),
+	}
+
+	var mu sync.Mutex
+	delivered := make([]telegraf.DeliveryInfo, 0, len(inputRaw))
+	notify := func(di telegraf.DeliveryInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, di)
+	}
+
+	input := make([]telegraf.Metric, 0, len(inputRaw))
+	for _, m := range inputRaw {
+		tm, _ := metric.WithTracking(m, notify)
+		input = append(input, tm)
+	}
+
+	expected := []telegraf.Metric{
+		metric.New("cpu",
+			map[string]string{"pod_name": "web-1", "namespace": "prod"},
+			map[string]interface{}{"usage": 12.5},
+			now,
+		),
+	}
+
+	plugin := &Join{
+		Left:            []string{"cpu"},
+		Right:           []string{"pod"},
+		JoinKeys:        []string{"pod_name"},
+		Mode:            "inner",
+		Tags:            []string{"namespace"},
+		DropRight:       true,
+		MaxCacheEntries: 10,
+		Log:             &testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	// Process expected metrics and compare with resulting metrics
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	// Simulate output acknowledging delivery
+	for _, m := range actual {
+		m.Accept()
+	}
+
+	// Check delivery
+	require.Eventuallyf(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(input) == len(delivered)
+	}, time.Second, 100*time.Millisecond, "%d delivered but %d expected", len(delivered), len(input))
+}
@@ -0,0 +1,280 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package rate
+
+import (
+	_ "embed"
+	"fmt"
+	"maps"
+	"math"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type Rate struct {
+	Fields         []string        `toml:"fields"`
+	CountersOnly   bool            `toml:"counters_only"`
+	Temporality    string          `toml:"temporality"`
+	CounterWrap    string          `toml:"counter_wrap"`
+	RateUnit       config.Duration `toml:"rate_unit"`
+	Suffix         string          `toml:"suffix"`
+	ExpiryInterval config.Duration `toml:"expiry_interval"`
+	Log            telegraf.Logger `toml:"-"`
+
+	accept filter.Filter
+	cache  map[uint64]*series
+}
+
+// series holds the last-seen values of a metric series. The exported fields
+// are persisted across restarts.
+type series struct {
+	Time   time.Time          `json:"time"`
+	Values map[string]float64 `json:"values"`
+
+	seen time.Time
+}
+
+// counter is the value of a field to be converted
+type counter struct {
+	key   string
+	value float64
+}
+
+func (*Rate) SampleConfig() string {
+	return sampleConfig
+}
+
+func (r *Rate) Init() error {
+	if len(r.Fields) == 0 {
+		r.Fields = []string{"*"}
+	}
+	f, err := filter.Compile(r.Fields)
+	if err != nil {
+		return fmt.Errorf("failed to create new field filter: %w", err)
+	}
+	r.accept = f
+
+	switch r.Temporality {
+	case "":
+		r.Temporality = "cumulative"
+	case "cumulative", "delta":
+	default:
+		return fmt.Errorf("invalid temporality %q", r.Temporality)
+	}
+
+	switch r.CounterWrap {
+	case "":
+		r.CounterWrap = "none"
+	case "none", "32bit", "64bit", "auto":
+	default:
+		return fmt.Errorf("invalid counter_wrap %q", r.CounterWrap)
+	}
+
+	if r.RateUnit <= 0 {
+		return fmt.Errorf("invalid rate_unit %v", r.RateUnit)
+	}
+
+	if r.cache == nil {
+		r.cache = make(map[uint64]*series)
+	}
+
+	return nil
+}
+
+func (r *Rate) GetState() interface{} {
+	return r.cache
+}
+
+func (r *Rate) SetState(state interface{}) error {
+	cache, ok := state.(map[uint64]*series)
+	if !ok {
+		return fmt.Errorf("state has wrong type %T", state)
+	}
+
+	now := time.Now()
+	r.cache = make(map[uint64]*series, len(cache))
+	for id, s := range cache {
+		if s == nil || s.Values == nil {
+			continue
+		}
+		s.seen = now
+		r.cache[id] = s
+	}
+	return nil
+}
+
+func (r *Rate) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	now := time.Now()
+
+	out := make([]telegraf.Metric, 0, len(in))
+	for _, m := range in {
+		if !r.convertible(m) {
+			out = append(out, m)
+			continue
+		}
+
+		id := m.HashID()
+		stored, ok := r.cache[id]
+		if !ok {
+			stored = &series{Values: make(map[string]float64)}
+			r.cache[id] = stored
+		}
+		stored.seen = now
+
+		// Samples not newer than the previous one cannot be used to compute
+		// a rate and would corrupt the stored state, so do not convert those
+		elapsed := m.Time().Sub(stored.Time)
+		if ok && elapsed <= 0 {
+			r.Log.Tracef("Not converting out-of-order metric %q at %v", m.Name(), m.Time())
+			if r.suppress(m, r.convertedFields(m)) {
+				out = append(out, m)
+			}
+			continue
+		}
+
+		// Collect the rates first as modifying the fields while iterating
+		// over them is not safe
+		converted := r.convertedFields(m)
+		rates := make(map[string]float64)
+		for _, c := range converted {
+			last, seen := stored.Values[c.key]
+			stored.Values[c.key] = c.value
+
+			// The first sample of a series is the reference for computing the
+			// rate and does not produce a rate itself
+			if !seen {
+				continue
+			}
+			rates[c.key] = r.increase(last, c.value) / elapsed.Seconds() * time.Duration(r.RateUnit).Seconds()
+		}
+
+		stored.Time = m.Time()
+
+		// Metrics without any rate, e.g. the first sample of a series, must
+		// not emit the counter values in place of the rate
+		if len(rates) == 0 {
+			if r.suppress(m, converted) {
+				out = append(out, m)
+			}
+			continue
+		}
+
+		for _, c := range converted {
+			if r.Suffix == "" {
+				m.RemoveField(c.key)
+			}
+			if rate, found := rates[c.key]; found {
+				m.AddField(c.key+r.Suffix, rate)
+			}
+		}
+
+		if r.Suffix == "" {
+			m.SetType(telegraf.Gauge)
+		}
+		out = append(out, m)
+	}
+
+	// Cleanup cache entries that are too old
+	if r.ExpiryInterval > 0 {
+		threshold := now.Add(-time.Duration(r.ExpiryInterval))
+		maps.DeleteFunc(r.cache, func(_ uint64, s *series) bool {
+			return s.seen.Before(threshold)
+		})
+	}
+
+	return out
+}
+
+// convertedFields returns the values of all fields of the metric to be
+// converted to a rate in the order of the fields
+func (r *Rate) convertedFields(m telegraf.Metric) []counter {
+	var converted []counter
+	for _, field := range m.FieldList() {
+		if !r.accept.Match(field.Key) {
+			continue
+		}
+
+		// Ignore all fields not convertible to float
+		value, err := internal.ToFloat64(field.Value)
+		if err != nil {
+			r.Log.Tracef("Skipping field %q with value %v (%T) as it is not convertible to float: %v", field.Key, field.Value, field.Value, err)
+			continue
+		}
+		converted = append(converted, counter{key: field.Key, value: value})
+	}
+	return converted
+}
+
+// suppress removes the given fields from a metric without rates if the rate
+// replaces the original field. Metrics without any remaining field are
+// dropped and false is returned.
+func (r *Rate) suppress(m telegraf.Metric, converted []counter) bool {
+	if r.Suffix != "" {
+		return true
+	}
+	for _, c := range converted {
+		m.RemoveField(c.key)
+	}
+	if len(m.FieldList()) == 0 {
+		m.Drop()
+		return false
+	}
+	return true
+}
+
+func (r *Rate) convertible(m telegraf.Metric) bool {
+	switch m.Type() {
+	case telegraf.Counter:
+		return true
+	case telegraf.Untyped:
+		return !r.CountersOnly
+	}
+	return false
+}
+
+// increase returns the increase of the counter between the last and the
+// current value accounting for counter resets and wrap-arounds
+func (r *Rate) increase(last, value float64) float64 {
+	if r.Temporality == "delta" {
+		return value
+	}
+	if value >= last {
+		return value - last
+	}
+
+	// The counter decreased so it either wrapped around or was reset
+	var limit float64
+	switch r.CounterWrap {
+	case "32bit":
+		limit = math.MaxUint32
+	case "64bit":
+		limit = math.MaxUint64
+	case "auto":
+		limit = math.MaxUint64
+		if last <= math.MaxUint32 {
+			limit = math.MaxUint32
+		}
+	}
+	if limit > 0 && last > limit/2 {
+		return limit - last + value + 1
+	}
+
+	// Counter reset, assume the counter restarted from zero
+	return value
+}
+
+func init() {
+	processors.Add("rate", func() telegraf.Processor {
+		return &Rate{
+			Suffix:   "_rate",
+			RateUnit: config.Duration(time.Second),
+		}
+	})
+}
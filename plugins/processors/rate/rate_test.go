@@ -0,0 +1,234 @@
+package rate
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestInitFail(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *Rate
+		expected string
+	}{
+		{
+			name:     "invalid temporality",
+			plugin:   &Rate{Temporality: "foo", RateUnit: config.Duration(time.Second)},
+			expected: `invalid temporality "foo"`,
+		},
+		{
+			name:     "invalid counter wrap",
+			plugin:   &Rate{CounterWrap: "16bit", RateUnit: config.Duration(time.Second)},
+			expected: `invalid counter_wrap "16bit"`,
+		},
+		{
+			name:     "invalid rate unit",
+			plugin:   &Rate{},
+			expected: "invalid rate_unit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.plugin.Init(), tt.expected)
+		})
+	}
+}
+
+func TestCases(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tests := []struct {
+		name     string
+		plugin   *Rate
+		input    []telegraf.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name:   "cumulative with reset",
+			plugin: &Rate{Suffix: "_rate"},
+			input: []telegraf.Metric{
+				metric.New("net", map[string]string{"host": "a"}, map[string]interface{}{"bytes": uint64(100)}, start, telegraf.Counter),
+				metric.New("net", map[string]string{"host": "a"}, map[string]interface{}{"bytes": uint64(300)}, start.Add(10*time.Second), telegraf.Counter),
+				metric.New("net", map[string]string{"host": "a"}, map[string]interface{}{"bytes": uint64(50)}, start.Add(20*time.Second), telegraf.Counter),
+			},
+			expected: []telegraf.Metric{
+				metric.New("net", map[string]string{"host": "a"}, map[string]interface{}{"bytes": uint64(100)}, start, telegraf.Counter),
+				metric.New("net", map[string]string{"host": "a"}, map[string]interface{}{"bytes": uint64(300), "bytes_rate": 20.0}, start.Add(10*time.Second), telegraf.Counter),
+				metric.New("net", map[string]string{"host": "a"}, map[string]interface{}{"bytes": uint64(50), "bytes_rate": 5.0}, start.Add(20*time.Second), telegraf.Counter),
+			},
+		},
+		{
+			name:   "32bit wrap-around",
+			plugin: &Rate{Suffix: "_rate", CounterWrap: "auto"},
+			input: []telegraf.Metric{
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": uint64(math.MaxUint32 - 9)}, start, telegraf.Counter),
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": uint64(10)}, start.Add(2*time.Second), telegraf.Counter),
+			},
+			expected: []telegraf.Metric{
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": uint64(math.MaxUint32 - 9)}, start, telegraf.Counter),
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": uint64(10), "bytes_rate": 10.0}, start.Add(2*time.Second), telegraf.Counter),
+			},
+		},
+		{
+			name:   "delta replacing fields",
+			plugin: &Rate{Temporality: "delta", RateUnit: config.Duration(time.Minute)},
+			input: []telegraf.Metric{
+				metric.New("requests", map[string]string{}, map[string]interface{}{"count": int64(10), "status": "ok"}, start),
+				metric.New("requests", map[string]string{}, map[string]interface{}{"count": int64(30), "status": "ok"}, start.Add(30*time.Second)),
+			},
+			expected: []telegraf.Metric{
+				metric.New("requests", map[string]string{}, map[string]interface{}{"status": "ok"}, start),
+				metric.New("requests", map[string]string{}, map[string]interface{}{"count": 60.0, "status": "ok"}, start.Add(30*time.Second), telegraf.Gauge),
+			},
+		},
+		{
+			name:   "counters only",
+			plugin: &Rate{Suffix: "_rate", CountersOnly: true},
+			input: []telegraf.Metric{
+				metric.New("mem", map[string]string{}, map[string]interface{}{"used": 1.0}, start),
+				metric.New("mem", map[string]string{}, map[string]interface{}{"used": 2.0}, start.Add(time.Second)),
+				metric.New("cpu", map[string]string{}, map[string]interface{}{"usage": 1.0}, start, telegraf.Gauge),
+				metric.New("cpu", map[string]string{}, map[string]interface{}{"usage": 2.0}, start.Add(time.Second), telegraf.Gauge),
+			},
+			expected: []telegraf.Metric{
+				metric.New("mem", map[string]string{}, map[string]interface{}{"used": 1.0}, start),
+				metric.New("mem", map[string]string{}, map[string]interface{}{"used": 2.0}, start.Add(time.Second)),
+				metric.New("cpu", map[string]string{}, map[string]interface{}{"usage": 1.0}, start, telegraf.Gauge),
+				metric.New("cpu", map[string]string{}, map[string]interface{}{"usage": 2.0}, start.Add(time.Second), telegraf.Gauge),
+			},
+		},
+		{
+			name:   "out of order",
+			plugin: &Rate{Suffix: "_rate"},
+			input: []telegraf.Metric{
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 10.0}, start.Add(time.Second)),
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 5.0}, start),
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 20.0}, start.Add(2*time.Second)),
+			},
+			expected: []telegraf.Metric{
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 10.0}, start.Add(time.Second)),
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 5.0}, start),
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 20.0, "bytes_rate": 10.0}, start.Add(2*time.Second)),
+			},
+		},
+		{
+			name:   "out of order replacing fields",
+			plugin: &Rate{},
+			input: []telegraf.Metric{
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 10.0}, start.Add(time.Second)),
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 5.0}, start),
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 20.0}, start.Add(2*time.Second)),
+			},
+			expected: []telegraf.Metric{
+				metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 10.0}, start.Add(2*time.Second), telegraf.Gauge),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.plugin.RateUnit == 0 {
+				tt.plugin.RateUnit = config.Duration(time.Second)
+			}
+			tt.plugin.Log = testutil.Logger{}
+			require.NoError(t, tt.plugin.Init())
+
+			var actual []telegraf.Metric
+			for _, m := range tt.input {
+				actual = append(actual, tt.plugin.Apply(m)...)
+			}
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestStatePersistence(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	// Process a first metric and persist the state
+	plugin := &Rate{
+		Suffix:   "_rate",
+		RateUnit: config.Duration(time.Second),
+		Log:      testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.Apply(metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 100.0}, start, telegraf.Counter))
+
+	var pi telegraf.StatefulPlugin = plugin
+	serialized, err := json.Marshal(pi.GetState())
+	require.NoError(t, err)
+
+	// Restore the state in a new instance as done by the persister
+	var state map[uint64]*series
+	require.NoError(t, json.Unmarshal(serialized, &state))
+
+	restored := &Rate{
+		Suffix:   "_rate",
+		RateUnit: config.Duration(time.Second),
+		Log:      testutil.Logger{},
+	}
+	require.NoError(t, restored.Init())
+	require.NoError(t, restored.SetState(state))
+
+	// The first metric after the restart must produce a rate
+	expected := []telegraf.Metric{
+		metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 150.0, "bytes_rate": 5.0}, start.Add(10*time.Second), telegraf.Counter),
+	}
+	actual := restored.Apply(metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 150.0}, start.Add(10*time.Second), telegraf.Counter))
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestTracking(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	inputRaw := []telegraf.Metric{
+		metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 10.0}, start),
+		metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 20.0}, start.Add(time.Second)),
+	}
+
+	var mu sync.Mutex
+	delivered := make([]telegraf.DeliveryInfo, 0, len(inputRaw))
+	notify := func(di telegraf.DeliveryInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, di)
+	}
+
+	input := make([]telegraf.Metric, 0, len(inputRaw))
+	for _, m := range inputRaw {
+		tm, _ := metric.WithTracking(m, notify)
+		input = append(input, tm)
+	}
+
+	// The first metric is dropped as it has no rate
+	plugin := &Rate{RateUnit: config.Duration(time.Second), Log: testutil.Logger{}}
+	require.NoError(t, plugin.Init())
+
+	var actual []telegraf.Metric
+	for _, m := range input {
+		actual = append(actual, plugin.Apply(m)...)
+	}
+	for _, m := range actual {
+		m.Accept()
+	}
+
+	expected := []telegraf.Metric{
+		metric.New("net", map[string]string{}, map[string]interface{}{"bytes": 10.0}, start.Add(time.Second), telegraf.Gauge),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	require.Eventuallyf(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(input) == len(delivered)
+	}, time.Second, 100*time.Millisecond, "%d delivered but %d expected", len(delivered), len(input))
+}
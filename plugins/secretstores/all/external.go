@@ -0,0 +1,5 @@
+//go:build !custom || secretstores || secretstores.external
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/secretstores/external" // register plugin
@@ -0,0 +1,120 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package external
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin/pluginv1"
+	"github.com/influxdata/telegraf/plugins/secretstores"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type External struct {
+	ID string `toml:"id"`
+	grpcplugin.Config
+	Timeout config.Duration `toml:"timeout"`
+	Log     telegraf.Logger `toml:"-"`
+
+	client *grpcplugin.Client
+	store  pluginv1.SecretStoreClient
+}
+
+func (*External) SampleConfig() string {
+	return sampleConfig
+}
+
+func (e *External) Init() error {
+	if e.ID == "" {
+		return errors.New("id missing")
+	}
+
+	client, err := e.Config.NewClient(pluginv1.PluginType_PLUGIN_TYPE_SECRETSTORE, e.Log)
+	if err != nil {
+		return err
+	}
+	e.client = client
+	e.store = pluginv1.NewSecretStoreClient(client.Conn)
+
+	// Secret-stores are not stopped by Telegraf so the process runs for the
+	// lifetime of Telegraf
+	return e.client.Start()
+}
+
+func (e *External) Get(key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	resp, err := e.store.Get(ctx, &pluginv1.GetRequest{Key: key})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetValue(), nil
+}
+
+func (e *External) Set(key, value string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	_, err := e.store.Set(ctx, &pluginv1.SetRequest{Key: key, Value: value})
+	return err
+}
+
+func (e *External) List() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	resp, err := e.store.List(ctx, &pluginv1.ListRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetKeys(), nil
+}
+
+func (e *External) GetResolver(key string) (telegraf.ResolveFunc, error) {
+	value, dynamic, err := e.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only query the plugin again for secrets changing over time
+	if !dynamic {
+		return func() ([]byte, bool, error) {
+			return value, false, nil
+		}, nil
+	}
+	return func() ([]byte, bool, error) {
+		return e.resolve(key)
+	}, nil
+}
+
+func (e *External) resolve(key string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.Timeout))
+	defer cancel()
+
+	resp, err := e.store.Resolve(ctx, &pluginv1.ResolveRequest{Key: key})
+	if err != nil {
+		return nil, false, err
+	}
+	return resp.GetValue(), resp.GetDynamic(), nil
+}
+
+func init() {
+	secretstores.Add("external", func(id string) telegraf.SecretStore {
+		return &External{
+			ID: id,
+			Config: grpcplugin.Config{
+				RestartDelay: config.Duration(10 * time.Second),
+				StartTimeout: config.Duration(10 * time.Second),
+			},
+			Timeout: config.Duration(5 * time.Second),
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package external
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/grpcplugin"
+	"github.com/influxdata/telegraf/plugins/common/shim"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+var externalPlugin = flag.Bool("external-plugin", false, "if true, act like an external plugin instead of test")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *externalPlugin {
+		runExternalPlugin()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestInitFail(t *testing.T) {
+	plugin := &External{}
+	require.ErrorContains(t, plugin.Init(), "id missing")
+}
+
+func TestSecrets(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	plugin := &External{
+		ID: "test",
+		Config: grpcplugin.Config{
+			Command:      []string{exe, "-external-plugin"},
+			RestartDelay: config.Duration(100 * time.Millisecond),
+			StartTimeout: config.Duration(5 * time.Second),
+		},
+		Timeout: config.Duration(5 * time.Second),
+		Log:     testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	defer plugin.client.Stop()
+
+	require.NoError(t, plugin.Set("user", "telegraf"))
+	require.NoError(t, plugin.Set("password", "secret"))
+
+	value, err := plugin.Get("user")
+	require.NoError(t, err)
+	require.Equal(t, "telegraf", string(value))
+
+	_, err = plugin.Get("unknown")
+	require.ErrorContains(t, err, "not found")
+
+	keys, err := plugin.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"counter", "password", "user"}, keys)
+
+	// Static secrets are resolved once
+	resolver, err := plugin.GetResolver("password")
+	require.NoError(t, err)
+	value, dynamic, err := resolver()
+	require.NoError(t, err)
+	require.False(t, dynamic)
+	require.Equal(t, "secret", string(value))
+
+	// Dynamic secrets are resolved on each call
+	resolver, err = plugin.GetResolver("counter")
+	require.NoError(t, err)
+	value, dynamic, err = resolver()
+	require.NoError(t, err)
+	require.True(t, dynamic)
+	require.Equal(t, "2", string(value))
+}
+
+func runExternalPlugin() {
+	s := shim.New()
+	err := s.AddSecretStore(&testStore{})
+	if err == nil {
+		err = s.Run(shim.PollIntervalDisabled)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERR %v\n", err)
+		//nolint:revive // error code is important for this "test"
+		os.Exit(1)
+	}
+}
+
+// testStore keeps the secrets in memory and provides a dynamic "counter"
+// secret incremented on each resolution
+type testStore struct {
+	secrets map[string]string
+	counter int
+}
+
+func (*testStore) SampleConfig() string {
+	return ""
+}
+
+func (s *testStore) Init() error {
+	s.secrets = make(map[string]string)
+	return nil
+}
+
+func (s *testStore) Get(key string) ([]byte, error) {
+	value, found := s.secrets[key]
+	if !found {
+		return nil, errors.New("not found")
+	}
+	return []byte(value), nil
+}
+
+func (s *testStore) Set(key, value string) error {
+	s.secrets[key] = value
+	return nil
+}
+
+func (s *testStore) List() ([]string, error) {
+	keys := []string{"counter"}
+	for k := range s.secrets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *testStore) GetResolver(key string) (telegraf.ResolveFunc, error) {
+	if key == "counter" {
+		return func() ([]byte, bool, error) {
+			s.counter++
+			return []byte(strconv.Itoa(s.counter)), true, nil
+		}, nil
+	}
+	return func() ([]byte, bool, error) {
+		v, err := s.Get(key)
+		return v, false, err
+	}, nil
+}
@@ -0,0 +1,7 @@
+//go:build !custom || serializers || serializers.protobuf
+
+package all
+
+import (
+	_ "github.com/influxdata/telegraf/plugins/serializers/protobuf" // register plugin
+)
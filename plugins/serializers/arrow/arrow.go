@@ -0,0 +1,96 @@
+package arrow
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/common/columnar"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+type Serializer struct {
+	Compression       string `toml:"arrow_compression"`
+	MeasurementColumn string `toml:"arrow_measurement_column"`
+	TimestampColumn   string `toml:"arrow_timestamp_column"`
+
+	converter *columnar.Converter
+	options   []ipc.Option
+}
+
+func (s *Serializer) Init() error {
+	switch s.Compression {
+	case "", "none":
+		s.Compression = "none"
+	case "lz4":
+		s.options = append(s.options, ipc.WithLZ4())
+	case "zstd":
+		s.options = append(s.options, ipc.WithZstd())
+	default:
+		return fmt.Errorf("invalid compression %q", s.Compression)
+	}
+
+	s.converter = &columnar.Converter{
+		NameColumn:      s.MeasurementColumn,
+		TimestampColumn: s.TimestampColumn,
+		Nullable:        true,
+	}
+
+	return nil
+}
+
+func (s *Serializer) Serialize(metric telegraf.Metric) ([]byte, error) {
+	return s.SerializeBatch([]telegraf.Metric{metric})
+}
+
+// SerializeBatch writes the metrics of each measurement as a single record
+// batch into a separate stream of the Arrow IPC streaming format using a
+// schema inferred from the metrics of the measurement. The streams are
+// concatenated in the order of the first occurrence of the measurements.
+func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, group := range columnar.GroupByName(metrics) {
+		if err := s.writeStream(&buf, group); err != nil {
+			return nil, fmt.Errorf("measurement %q: %w", group[0].Name(), err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Serializer) writeStream(w io.Writer, metrics []telegraf.Metric) error {
+	schema, err := s.converter.Schema(metrics)
+	if err != nil {
+		return fmt.Errorf("creating schema failed: %w", err)
+	}
+	metadata := arrow.NewMetadata([]string{"measurement"}, []string{metrics[0].Name()})
+	schema = arrow.NewSchema(schema.Fields(), &metadata)
+
+	record, err := s.converter.RecordBatch(metrics, schema)
+	if err != nil {
+		return fmt.Errorf("creating record failed: %w", err)
+	}
+	defer record.Release()
+
+	options := append([]ipc.Option{ipc.WithSchema(schema)}, s.options...)
+	writer := ipc.NewWriter(w, options...)
+	if err := writer.Write(record); err != nil {
+		writer.Close()
+		return fmt.Errorf("writing record failed: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("closing writer failed: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	serializers.Add("arrow",
+		func() telegraf.Serializer {
+			return &Serializer{TimestampColumn: "timestamp"}
+		},
+	)
+}
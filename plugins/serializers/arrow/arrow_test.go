@@ -0,0 +1,84 @@
+package arrow
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+func TestInitInvalid(t *testing.T) {
+	serializer := &Serializer{Compression: "snappy"}
+	require.ErrorContains(t, serializer.Init(), `invalid compression "snappy"`)
+}
+
+func TestSerializeBatch(t *testing.T) {
+	metrics := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{"host": "server01"},
+			map[string]interface{}{"usage": 42.5},
+			time.Unix(1700000000, 0),
+		),
+		metric.New(
+			"mem",
+			map[string]string{"host": "server01"},
+			map[string]interface{}{"used": uint64(1024)},
+			time.Unix(1700000010, 0),
+		),
+	}
+
+	for _, compression := range []string{"none", "lz4", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			serializer := &Serializer{
+				Compression:       compression,
+				MeasurementColumn: "measurement",
+				TimestampColumn:   "time",
+			}
+			require.NoError(t, serializer.Init())
+
+			buf, err := serializer.SerializeBatch(metrics)
+			require.NoError(t, err)
+
+			// Each measurement is written to a separate stream
+			r := bytes.NewReader(buf)
+			expected := []struct {
+				measurement string
+				columns     []string
+			}{
+				{measurement: "cpu", columns: []string{"measurement", "host", "usage", "time"}},
+				{measurement: "mem", columns: []string{"measurement", "host", "used", "time"}},
+			}
+			for _, e := range expected {
+				reader, err := ipc.NewReader(r)
+				require.NoError(t, err)
+
+				names := make([]string, 0, reader.Schema().NumFields())
+				for _, f := range reader.Schema().Fields() {
+					names = append(names, f.Name)
+				}
+				require.Equal(t, e.columns, names)
+				measurement, found := reader.Schema().Metadata().GetValue("measurement")
+				require.True(t, found)
+				require.Equal(t, e.measurement, measurement)
+
+				require.True(t, reader.Next())
+				record := reader.RecordBatch()
+				require.Equal(t, int64(1), record.NumRows())
+				require.Equal(t, e.measurement, record.Column(0).(*array.String).Value(0))
+				require.Equal(t, "server01", record.Column(1).(*array.String).Value(0))
+
+				require.False(t, reader.Next())
+				require.NoError(t, reader.Err())
+				reader.Release()
+			}
+			require.Zero(t, r.Len())
+		})
+	}
+}
@@ -0,0 +1,418 @@
+package avro
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/schemaregistry"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+type Serializer struct {
+	SchemaRegistry  string            `toml:"avro_schema_registry"`
+	CaCertPath      string            `toml:"avro_schema_registry_cert"`
+	Subject         string            `toml:"avro_schema_registry_subject"`
+	SubjectStrategy string            `toml:"avro_schema_registry_subject_strategy"`
+	Schemas         map[string]string `toml:"avro_schemas"`
+	FieldNames      map[string]string `toml:"avro_field_names"`
+	Namespace       string            `toml:"avro_namespace"`
+	Timestamp       string            `toml:"avro_timestamp"`
+	TimestampFormat string            `toml:"avro_timestamp_format"`
+
+	registry *schemaregistry.Client
+	static   map[string]*schema
+	derived  map[string]*schema
+	sync.Mutex
+}
+
+type schema struct {
+	definition string
+	fullname   string
+	fields     []schemaField
+	codec      *goavro.Codec
+}
+
+type schemaField struct {
+	name       string
+	avroType   interface{}
+	hasDefault bool
+}
+
+func (s *Serializer) Init() error {
+	switch s.TimestampFormat {
+	case "":
+		s.TimestampFormat = "unix_ms"
+	case "unix", "unix_ms", "unix_us", "unix_ns":
+		// Valid values
+	default:
+		return fmt.Errorf("invalid timestamp format %q", s.TimestampFormat)
+	}
+	if s.Timestamp == "" {
+		s.Timestamp = "timestamp"
+	}
+	for key, name := range s.FieldNames {
+		if name != schemaregistry.SanitizeName(name) {
+			return fmt.Errorf("invalid name %q for %q", name, key)
+		}
+	}
+	if err := schemaregistry.CheckSubjectStrategy(s.SubjectStrategy); err != nil {
+		return err
+	}
+
+	s.static = make(map[string]*schema, len(s.Schemas))
+	for name, definition := range s.Schemas {
+		sc, err := newSchema(definition)
+		if err != nil {
+			return fmt.Errorf("invalid schema for measurement %q: %w", name, err)
+		}
+		s.static[name] = sc
+	}
+	s.derived = make(map[string]*schema)
+
+	if s.SchemaRegistry != "" {
+		registry, err := schemaregistry.NewClient(s.SchemaRegistry, s.CaCertPath)
+		if err != nil {
+			return fmt.Errorf("creating schema registry client for %q failed: %w", s.SchemaRegistry, err)
+		}
+		s.registry = registry
+	}
+
+	return nil
+}
+
+func (s *Serializer) Serialize(metric telegraf.Metric) ([]byte, error) {
+	return s.appendMetric(nil, metric, "")
+}
+
+func (s *Serializer) SerializeTopic(metric telegraf.Metric, topic string) ([]byte, error) {
+	return s.appendMetric(nil, metric, topic)
+}
+
+func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
+	var buf []byte
+	for _, m := range metrics {
+		var err error
+		if buf, err = s.appendMetric(buf, m, ""); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func (s *Serializer) appendMetric(buf []byte, metric telegraf.Metric, topic string) ([]byte, error) {
+	sc, err := s.schemaFor(metric)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefix the message with the Confluent wire format header if using a
+	// registry and with nothing otherwise
+	if s.registry != nil {
+		id, err := s.registry.Register(s.subject(sc, topic), "AVRO", sc.definition)
+		if err != nil {
+			return nil, err
+		}
+		buf = schemaregistry.AppendHeader(buf, id)
+	}
+
+	// Lookup values by their sanitized names to match the schema fields
+	values := make(map[string]interface{}, len(metric.TagList())+len(metric.FieldList()))
+	for _, tag := range metric.TagList() {
+		values[s.name(tag.Key)] = tag.Value
+	}
+	for _, field := range metric.FieldList() {
+		values[s.name(field.Key)] = field.Value
+	}
+	values[s.Timestamp] = metric.Time()
+
+	record := make(map[string]interface{}, len(sc.fields))
+	for _, f := range sc.fields {
+		value, found := values[f.name]
+		if !found && f.hasDefault {
+			// Use the default value of the schema
+			continue
+		}
+
+		v, err := s.convert(f.avroType, value)
+		if err != nil {
+			return nil, fmt.Errorf("converting %q of metric %q failed: %w", f.name, metric.Name(), err)
+		}
+		record[f.name] = v
+	}
+
+	return sc.codec.BinaryFromNative(buf, record)
+}
+
+// subject returns the subject to register the schema under
+func (s *Serializer) subject(sc *schema, topic string) string {
+	if s.Subject != "" {
+		return s.Subject
+	}
+	return schemaregistry.Subject(s.SubjectStrategy, topic, sc.fullname)
+}
+
+// name returns the name of the tag or field with the given key in the schema
+func (s *Serializer) name(key string) string {
+	if name, found := s.FieldNames[key]; found {
+		return name
+	}
+	return schemaregistry.SanitizeName(key)
+}
+
+// schemaFor returns the configured schema for the measurement or derives a
+// schema from the tags and fields of the metric
+func (s *Serializer) schemaFor(metric telegraf.Metric) (*schema, error) {
+	if sc, found := s.static[metric.Name()]; found {
+		return sc, nil
+	}
+
+	definition, err := s.deriveSchema(metric)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if sc, found := s.derived[definition]; found {
+		return sc, nil
+	}
+	sc, err := newSchema(definition)
+	if err != nil {
+		return nil, fmt.Errorf("creating schema for metric %q failed: %w", metric.Name(), err)
+	}
+	s.derived[definition] = sc
+	return sc, nil
+}
+
+func (s *Serializer) deriveSchema(metric telegraf.Metric) (string, error) {
+	var timestampType interface{} = "long"
+	switch s.TimestampFormat {
+	case "unix_ms":
+		timestampType = map[string]string{"type": "long", "logicalType": "timestamp-millis"}
+	case "unix_us":
+		timestampType = map[string]string{"type": "long", "logicalType": "timestamp-micros"}
+	}
+	fields := []map[string]interface{}{{"name": s.Timestamp, "type": timestampType}}
+
+	// Different keys might result in the same name after sanitizing
+	keys := map[string]string{s.Timestamp: "timestamp"}
+	checkName := func(key string) (string, error) {
+		name := s.name(key)
+		if other, found := keys[name]; found {
+			return "", fmt.Errorf("%q and %q both map to name %q, use 'avro_field_names' to rename one of them", other, key, name)
+		}
+		keys[name] = key
+		return name, nil
+	}
+
+	// Tags and fields are nullable to allow for schema evolution
+	for _, tag := range metric.TagList() {
+		name, err := checkName(tag.Key)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, map[string]interface{}{
+			"name":    name,
+			"type":    []string{"null", "string"},
+			"default": nil,
+		})
+	}
+
+	metricFields := slices.Clone(metric.FieldList())
+	sort.Slice(metricFields, func(i, j int) bool { return metricFields[i].Key < metricFields[j].Key })
+	for _, field := range metricFields {
+		var fieldType string
+		switch field.Value.(type) {
+		case int64, uint64:
+			fieldType = "long"
+		case float64:
+			fieldType = "double"
+		case bool:
+			fieldType = "boolean"
+		case string:
+			fieldType = "string"
+		default:
+			return "", fmt.Errorf("unsupported type %T of field %q", field.Value, field.Key)
+		}
+		name, err := checkName(field.Key)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, map[string]interface{}{
+			"name":    name,
+			"type":    []string{"null", fieldType},
+			"default": nil,
+		})
+	}
+
+	record := map[string]interface{}{
+		"type":   "record",
+		"name":   schemaregistry.SanitizeName(metric.Name()),
+		"fields": fields,
+	}
+	if s.Namespace != "" {
+		record["namespace"] = s.Namespace
+	}
+	definition, err := json.Marshal(record)
+	return string(definition), err
+}
+
+// convert converts the value to the native representation of the given Avro
+// type as expected by the codec
+func (s *Serializer) convert(avroType, value interface{}) (interface{}, error) {
+	switch t := avroType.(type) {
+	case string:
+		return s.convertPrimitive(t, value)
+	case map[string]interface{}:
+		if _, found := t["logicalType"]; found {
+			if _, ok := value.(time.Time); ok {
+				// Timestamp logical types accept time values directly
+				return value, nil
+			}
+		}
+		return s.convert(t["type"], value)
+	case []interface{}:
+		if value == nil {
+			if slices.Contains(t, interface{}("null")) {
+				return nil, nil
+			}
+			return nil, errors.New("missing value for non-nullable type")
+		}
+		for _, member := range t {
+			if member == "null" {
+				continue
+			}
+			if v, err := s.convert(member, value); err == nil {
+				return goavro.Union(unionMemberName(member), v), nil
+			}
+		}
+		return nil, fmt.Errorf("no matching type in union for %T", value)
+	}
+	return nil, fmt.Errorf("unsupported type %v", avroType)
+}
+
+func (s *Serializer) convertPrimitive(avroType string, value interface{}) (interface{}, error) {
+	// Timestamps without logical type are stored as integers
+	if t, ok := value.(time.Time); ok {
+		switch s.TimestampFormat {
+		case "unix":
+			value = t.Unix()
+		case "unix_ms":
+			value = t.UnixMilli()
+		case "unix_us":
+			value = t.UnixMicro()
+		case "unix_ns":
+			value = t.UnixNano()
+		}
+	}
+
+	switch avroType {
+	case "null":
+		if value == nil {
+			return nil, nil
+		}
+	case "boolean":
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case "string":
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case "int":
+		if v, err := internal.ToInt64(value); err == nil && v >= math.MinInt32 && v <= math.MaxInt32 {
+			return int32(v), nil
+		}
+	case "long":
+		if v, err := internal.ToInt64(value); err == nil {
+			return v, nil
+		}
+	case "float":
+		if v, err := internal.ToFloat64(value); err == nil {
+			return float32(v), nil
+		}
+	case "double":
+		if v, err := internal.ToFloat64(value); err == nil {
+			return v, nil
+		}
+	case "bytes":
+		if v, ok := value.(string); ok {
+			return []byte(v), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot convert %T to %q", value, avroType)
+}
+
+func newSchema(definition string) (*schema, error) {
+	codec, err := goavro.NewCodec(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	var record struct {
+		Type      string                   `json:"type"`
+		Name      string                   `json:"name"`
+		Namespace string                   `json:"namespace"`
+		Fields    []map[string]interface{} `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(definition), &record); err != nil {
+		return nil, err
+	}
+	if record.Type != "record" {
+		return nil, fmt.Errorf("schema type %q is not a record", record.Type)
+	}
+
+	fullname := record.Name
+	if record.Namespace != "" {
+		fullname = record.Namespace + "." + record.Name
+	}
+
+	fields := make([]schemaField, 0, len(record.Fields))
+	for _, f := range record.Fields {
+		name, _ := f["name"].(string)
+		_, hasDefault := f["default"]
+		fields = append(fields, schemaField{name: name, avroType: f["type"], hasDefault: hasDefault})
+	}
+
+	return &schema{
+		definition: definition,
+		fullname:   fullname,
+		fields:     fields,
+		codec:      codec,
+	}, nil
+}
+
+func unionMemberName(member interface{}) string {
+	switch m := member.(type) {
+	case string:
+		return m
+	case map[string]interface{}:
+		name, _ := m["type"].(string)
+		if logical, ok := m["logicalType"].(string); ok {
+			return name + "." + logical
+		}
+		if n, ok := m["name"].(string); ok {
+			return n
+		}
+		return name
+	}
+	return ""
+}
+
+func init() {
+	serializers.Add("avro",
+		func() telegraf.Serializer {
+			return &Serializer{}
+		},
+	)
+}
@@ -0,0 +1,276 @@
+package avro
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers/avro"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestInitInvalid(t *testing.T) {
+	serializer := &Serializer{TimestampFormat: "RFC3339"}
+	require.ErrorContains(t, serializer.Init(), `invalid timestamp format "RFC3339"`)
+
+	serializer = &Serializer{Schemas: map[string]string{"cpu": `{"type": "string"}`}}
+	require.ErrorContains(t, serializer.Init(), `invalid schema for measurement "cpu"`)
+}
+
+func TestSerializeRoundtrip(t *testing.T) {
+	registry := &mockRegistry{t: t}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	serializer := &Serializer{
+		SchemaRegistry:  server.URL,
+		Namespace:       "telegraf",
+		TimestampFormat: "unix_ns",
+	}
+	require.NoError(t, serializer.Init())
+
+	parser := &avro.Parser{
+		SchemaRegistry:  server.URL,
+		Tags:            []string{"host"},
+		Fields:          []string{"usage_idle", "count", "online", "model_vendor"},
+		Timestamp:       "timestamp",
+		TimestampFormat: "unix_ns",
+		UnionMode:       "any",
+		Log:             testutil.Logger{},
+	}
+	require.NoError(t, parser.Init())
+
+	input := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{"host": "server01"},
+			map[string]interface{}{
+				"usage_idle":   98.5,
+				"count":        int64(4),
+				"online":       true,
+				"model.vendor": "intel",
+			},
+			time.Unix(1700000000, 0),
+		),
+		metric.New(
+			"cpu",
+			map[string]string{"host": "server02"},
+			map[string]interface{}{
+				"usage_idle":   12.5,
+				"count":        int64(8),
+				"online":       false,
+				"model.vendor": "amd",
+			},
+			time.Unix(1700000010, 0),
+		),
+	}
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"telegraf.cpu",
+			map[string]string{"host": "server01"},
+			map[string]interface{}{
+				"usage_idle":   98.5,
+				"count":        int64(4),
+				"online":       true,
+				"model_vendor": "intel",
+			},
+			time.Unix(1700000000, 0),
+		),
+		metric.New(
+			"telegraf.cpu",
+			map[string]string{"host": "server02"},
+			map[string]interface{}{
+				"usage_idle":   12.5,
+				"count":        int64(8),
+				"online":       false,
+				"model_vendor": "amd",
+			},
+			time.Unix(1700000010, 0),
+		),
+	}
+
+	var actual []telegraf.Metric
+	for _, m := range input {
+		buf, err := serializer.Serialize(m)
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x01}, buf[:5])
+
+		metrics, err := parser.Parse(buf)
+		require.NoError(t, err)
+		actual = append(actual, metrics...)
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	// Metrics sharing the same schema should be registered only once
+	require.Equal(t, 1, registry.registrations)
+	require.Contains(t, registry.subjects, "telegraf.cpu")
+}
+
+func TestSerializeStaticSchema(t *testing.T) {
+	schema := `{
+		"type": "record",
+		"name": "cpu",
+		"namespace": "com.example",
+		"fields": [
+			{"name": "time", "type": "long"},
+			{"name": "host", "type": "string"},
+			{"name": "usage_idle", "type": ["null", "float"]},
+			{"name": "cores", "type": "int", "default": 1}
+		]
+	}`
+
+	serializer := &Serializer{
+		Schemas:         map[string]string{"cpu": schema},
+		Timestamp:       "time",
+		TimestampFormat: "unix",
+	}
+	require.NoError(t, serializer.Init())
+
+	m := metric.New(
+		"cpu",
+		map[string]string{"host": "server01"},
+		map[string]interface{}{"usage_idle": 98.5},
+		time.Unix(1700000000, 0),
+	)
+	buf, err := serializer.SerializeBatch([]telegraf.Metric{m, m})
+	require.NoError(t, err)
+
+	// Without registry the messages are bare Avro binary
+	codec, err := goavro.NewCodec(schema)
+	require.NoError(t, err)
+	expected := map[string]interface{}{
+		"time":       int64(1700000000),
+		"host":       "server01",
+		"usage_idle": map[string]interface{}{"float": float32(98.5)},
+		"cores":      int32(1),
+	}
+	for range 2 {
+		var native interface{}
+		native, buf, err = codec.NativeFromBinary(buf)
+		require.NoError(t, err)
+		require.Equal(t, expected, native)
+	}
+	require.Empty(t, buf)
+
+	// Missing mandatory values should error
+	m = metric.New("cpu", map[string]string{}, map[string]interface{}{"usage_idle": 98.5}, time.Unix(0, 0))
+	_, err = serializer.Serialize(m)
+	require.ErrorContains(t, err, `converting "host" of metric "cpu" failed`)
+}
+
+func TestSerializeTopicSubject(t *testing.T) {
+	registry := &mockRegistry{t: t}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	m := metric.New("cpu", map[string]string{}, map[string]interface{}{"value": 42.0}, time.Unix(0, 0))
+	for _, strategy := range []string{"", "topic", "topic_record"} {
+		serializer := &Serializer{SchemaRegistry: server.URL, SubjectStrategy: strategy}
+		require.NoError(t, serializer.Init())
+		_, err := serializer.SerializeTopic(m, "telegraf")
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"cpu", "telegraf-value", "telegraf-cpu"}, registry.subjects)
+
+	serializer := &Serializer{SubjectStrategy: "foo"}
+	require.ErrorContains(t, serializer.Init(), `invalid subject strategy "foo"`)
+}
+
+func TestSerializeNameCollision(t *testing.T) {
+	m := metric.New(
+		"cpu",
+		map[string]string{},
+		map[string]interface{}{"model.vendor": "intel", "model_vendor": "amd"},
+		time.Unix(0, 0),
+	)
+
+	serializer := &Serializer{}
+	require.NoError(t, serializer.Init())
+	_, err := serializer.Serialize(m)
+	require.ErrorContains(t, err, `"model.vendor" and "model_vendor" both map to name "model_vendor"`)
+
+	// Renaming one of the fields resolves the collision
+	serializer = &Serializer{FieldNames: map[string]string{"model.vendor": "vendor"}}
+	require.NoError(t, serializer.Init())
+	_, err = serializer.Serialize(m)
+	require.NoError(t, err)
+	sc, err := serializer.schemaFor(m)
+	require.NoError(t, err)
+	require.Contains(t, sc.definition, `"name":"vendor"`)
+	require.Contains(t, sc.definition, `"name":"model_vendor"`)
+
+	serializer = &Serializer{FieldNames: map[string]string{"model.vendor": "model.vendor"}}
+	require.ErrorContains(t, serializer.Init(), `invalid name "model.vendor"`)
+}
+
+func TestSerializeRegistryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		if _, err := w.Write([]byte(`{"error_code": 409, "message": "Schema being registered is incompatible"}`)); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	serializer := &Serializer{SchemaRegistry: server.URL}
+	require.NoError(t, serializer.Init())
+
+	m := metric.New("cpu", map[string]string{}, map[string]interface{}{"value": 42.0}, time.Unix(0, 0))
+	_, err := serializer.Serialize(m)
+	require.ErrorContains(t, err, "Schema being registered is incompatible (409)")
+}
+
+type mockRegistry struct {
+	t             *testing.T
+	schemas       []string
+	subjects      []string
+	registrations int
+	sync.Mutex
+}
+
+func (r *mockRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+
+	var response interface{}
+	switch {
+	case req.Method == http.MethodPost && strings.HasPrefix(req.URL.Path, "/subjects/"):
+		var body struct {
+			Schema string `json:"schema"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.registrations++
+		r.schemas = append(r.schemas, body.Schema)
+		r.subjects = append(r.subjects, strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/subjects/"), "/versions"))
+		response = map[string]int{"id": len(r.schemas)}
+	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/schemas/ids/"):
+		id, err := strconv.Atoi(strings.TrimPrefix(req.URL.Path, "/schemas/ids/"))
+		if err != nil || id < 1 || id > len(r.schemas) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		response = map[string]string{"schema": r.schemas[id-1]}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		r.t.Error(err)
+	}
+}
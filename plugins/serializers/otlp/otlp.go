@@ -0,0 +1,79 @@
+package otlp
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/influxdata/influxdb-observability/influx2otel"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/influxdata/telegraf"
+	common_otel "github.com/influxdata/telegraf/plugins/common/opentelemetry"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+type Serializer struct {
+	Encoding string          `toml:"otlp_encoding"`
+	Log      telegraf.Logger `toml:"-"`
+
+	converter *influx2otel.LineProtocolToOtelMetrics
+}
+
+func (s *Serializer) Init() error {
+	switch s.Encoding {
+	case "":
+		s.Encoding = "protobuf"
+	case "protobuf", "json":
+	default:
+		return fmt.Errorf("invalid encoding %q", s.Encoding)
+	}
+
+	converter, err := influx2otel.NewLineProtocolToOtelMetrics(&common_otel.Logger{Logger: s.Log})
+	if err != nil {
+		return fmt.Errorf("creating converter failed: %w", err)
+	}
+	s.converter = converter
+
+	return nil
+}
+
+func (s *Serializer) Serialize(metric telegraf.Metric) ([]byte, error) {
+	return s.SerializeBatch([]telegraf.Metric{metric})
+}
+
+// SerializeBatch converts the metrics into a single OTLP metrics export
+// request
+func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
+	// Convert the metrics grouped by timestamp as done by the OpenTelemetry
+	// output as the conversion library merges metrics with the same name
+	groups := make(map[int64][]telegraf.Metric)
+	timestamps := make([]int64, 0, len(metrics))
+	for _, m := range metrics {
+		ts := m.Time().UnixNano()
+		if _, found := groups[ts]; !found {
+			timestamps = append(timestamps, ts)
+		}
+		groups[ts] = append(groups[ts], m)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	md := pmetric.NewMetrics()
+	for _, ts := range timestamps {
+		common_otel.EncodeMetrics(s.converter, groups[ts], s.Log).ResourceMetrics().MoveAndAppendTo(md.ResourceMetrics())
+	}
+
+	req := pmetricotlp.NewExportRequestFromMetrics(md)
+	if s.Encoding == "json" {
+		return req.MarshalJSON()
+	}
+	return req.MarshalProto()
+}
+
+func init() {
+	serializers.Add("otlp",
+		func() telegraf.Serializer {
+			return &Serializer{}
+		},
+	)
+}
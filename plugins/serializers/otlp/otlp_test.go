@@ -0,0 +1,92 @@
+package otlp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers/otlp"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestInitInvalid(t *testing.T) {
+	serializer := &Serializer{Encoding: "xml"}
+	require.ErrorContains(t, serializer.Init(), `invalid encoding "xml"`)
+}
+
+func TestSerializeBatch(t *testing.T) {
+	metrics := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"gauge": float64(42)},
+			time.Unix(1700000000, 0),
+			telegraf.Gauge,
+		),
+		metric.New(
+			"requests",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"counter": float64(100)},
+			time.Unix(1700000000, 0),
+			telegraf.Counter,
+		),
+		metric.New(
+			"cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"gauge": float64(23)},
+			time.Unix(1700000010, 0),
+			telegraf.Gauge,
+		),
+	}
+
+	for _, encoding := range []string{"protobuf", "json"} {
+		t.Run(encoding, func(t *testing.T) {
+			serializer := &Serializer{Encoding: encoding, Log: testutil.Logger{}}
+			require.NoError(t, serializer.Init())
+
+			buf, err := serializer.SerializeBatch(metrics)
+			require.NoError(t, err)
+
+			// Check the message structure
+			req := pmetricotlp.NewExportRequest()
+			if encoding == "json" {
+				require.NoError(t, req.UnmarshalJSON(buf))
+			} else {
+				require.NoError(t, req.UnmarshalProto(buf))
+			}
+			require.Equal(t, 3, req.Metrics().DataPointCount())
+
+			// Check the roundtrip
+			parser := &otlp.Parser{Encoding: encoding, Log: testutil.Logger{}}
+			require.NoError(t, parser.Init())
+			actual, err := parser.Parse(buf)
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, metrics, actual, testutil.SortMetrics())
+		})
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	m := metric.New(
+		"cpu",
+		map[string]string{"host": "a"},
+		map[string]interface{}{"gauge": float64(42)},
+		time.Unix(1700000000, 0),
+		telegraf.Gauge,
+	)
+
+	serializer := &Serializer{Log: testutil.Logger{}}
+	require.NoError(t, serializer.Init())
+
+	buf, err := serializer.Serialize(m)
+	require.NoError(t, err)
+
+	req := pmetricotlp.NewExportRequest()
+	require.NoError(t, req.UnmarshalProto(buf))
+	require.Equal(t, 1, req.Metrics().DataPointCount())
+	require.Equal(t, "cpu", req.Metrics().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+}
@@ -0,0 +1,106 @@
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/common/columnar"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+var compressionCodecs = map[string]compress.Compression{
+	"none":   compress.Codecs.Uncompressed,
+	"snappy": compress.Codecs.Snappy,
+	"gzip":   compress.Codecs.Gzip,
+	"brotli": compress.Codecs.Brotli,
+	"zstd":   compress.Codecs.Zstd,
+	"lz4":    compress.Codecs.Lz4Raw,
+}
+
+type Serializer struct {
+	Compression       string `toml:"parquet_compression"`
+	MeasurementColumn string `toml:"parquet_measurement_column"`
+	TimestampColumn   string `toml:"parquet_timestamp_column"`
+
+	converter *columnar.Converter
+	props     *parquet.WriterProperties
+}
+
+func (s *Serializer) Init() error {
+	if s.Compression == "" {
+		s.Compression = "snappy"
+	}
+	codec, found := compressionCodecs[s.Compression]
+	if !found {
+		return fmt.Errorf("invalid compression %q", s.Compression)
+	}
+	s.props = parquet.NewWriterProperties(parquet.WithCompression(codec))
+
+	s.converter = &columnar.Converter{
+		NameColumn:      s.MeasurementColumn,
+		TimestampColumn: s.TimestampColumn,
+		Nullable:        true,
+	}
+
+	return nil
+}
+
+func (s *Serializer) Serialize(metric telegraf.Metric) ([]byte, error) {
+	return s.SerializeBatch([]telegraf.Metric{metric})
+}
+
+// SerializeBatch writes the metrics into a single Parquet file using a schema
+// inferred from the metrics. As a file only has a single schema, batches of
+// multiple measurements use the union of all tags and fields of the
+// measurements as schema.
+func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
+	if len(metrics) == 0 {
+		return nil, nil
+	}
+
+	schema, err := s.converter.Schema(metrics)
+	if err != nil {
+		return nil, fmt.Errorf("creating schema failed: %w", err)
+	}
+	if groups := columnar.GroupByName(metrics); len(groups) == 1 {
+		metadata := arrow.NewMetadata([]string{"measurement"}, []string{metrics[0].Name()})
+		schema = arrow.NewSchema(schema.Fields(), &metadata)
+	}
+	record, err := s.converter.RecordBatch(metrics, schema)
+	if err != nil {
+		return nil, fmt.Errorf("creating record failed: %w", err)
+	}
+	defer record.Release()
+
+	var buf bytes.Buffer
+	writer, err := pqarrow.NewFileWriter(schema, &buf, s.props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, fmt.Errorf("creating writer failed: %w", err)
+	}
+	if err := writer.Write(record); err != nil {
+		writer.Close()
+		return nil, fmt.Errorf("writing record failed: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("closing writer failed: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func init() {
+	serializers.Add("parquet",
+		func() telegraf.Serializer {
+			return &Serializer{
+				MeasurementColumn: "measurement",
+				TimestampColumn:   "timestamp",
+			}
+		},
+	)
+}
@@ -0,0 +1,122 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+func TestInitInvalid(t *testing.T) {
+	serializer := &Serializer{Compression: "foo"}
+	require.ErrorContains(t, serializer.Init(), `invalid compression "foo"`)
+}
+
+func TestSerializeBatch(t *testing.T) {
+	metrics := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{"host": "server01"},
+			map[string]interface{}{"usage": 42.5},
+			time.Unix(1700000000, 0),
+		),
+		metric.New(
+			"cpu",
+			map[string]string{"host": "server02"},
+			map[string]interface{}{"usage": 12.5, "cores": int64(8)},
+			time.Unix(1700000010, 0),
+		),
+	}
+
+	for _, compression := range []string{"none", "snappy", "gzip", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			serializer := &Serializer{
+				Compression:       compression,
+				MeasurementColumn: "measurement",
+				TimestampColumn:   "timestamp",
+			}
+			require.NoError(t, serializer.Init())
+
+			buf, err := serializer.SerializeBatch(metrics)
+			require.NoError(t, err)
+
+			reader, err := file.NewParquetReader(bytes.NewReader(buf))
+			require.NoError(t, err)
+			defer reader.Close()
+			require.Equal(t, int64(2), reader.NumRows())
+			chunk, err := reader.MetaData().RowGroup(0).ColumnChunk(0)
+			require.NoError(t, err)
+			require.Equal(t, compressionCodecs[compression], chunk.Compression())
+			measurement := reader.MetaData().KeyValueMetadata().FindValue("measurement")
+			require.NotNil(t, measurement)
+			require.Equal(t, "cpu", *measurement)
+
+			fr, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+			require.NoError(t, err)
+			table, err := fr.ReadTable(context.Background())
+			require.NoError(t, err)
+			defer table.Release()
+
+			names := make([]string, 0, table.NumCols())
+			for _, f := range table.Schema().Fields() {
+				names = append(names, f.Name)
+			}
+			require.Equal(t, []string{"measurement", "cores", "host", "usage", "timestamp"}, names)
+
+			cores := table.Column(1).Data().Chunk(0).(*array.Int64)
+			require.True(t, cores.IsNull(0))
+			require.Equal(t, int64(8), cores.Value(1))
+			timestamps := table.Column(4).Data().Chunk(0).(*array.Int64)
+			require.Equal(t, []int64{1700000000000000000, 1700000010000000000}, timestamps.Int64Values())
+		})
+	}
+}
+
+func TestSerializeBatchMultipleMeasurements(t *testing.T) {
+	metrics := []telegraf.Metric{
+		metric.New("cpu", map[string]string{}, map[string]interface{}{"usage": 42.5}, time.Unix(0, 0)),
+		metric.New("mem", map[string]string{}, map[string]interface{}{"used": uint64(1024)}, time.Unix(0, 0)),
+	}
+
+	// Use the defaults of the registered serializer
+	serializer := &Serializer{
+		MeasurementColumn: "measurement",
+		TimestampColumn:   "timestamp",
+	}
+	require.NoError(t, serializer.Init())
+	buf, err := serializer.SerializeBatch(metrics)
+	require.NoError(t, err)
+
+	reader, err := file.NewParquetReader(bytes.NewReader(buf))
+	require.NoError(t, err)
+	defer reader.Close()
+	require.Nil(t, reader.MetaData().KeyValueMetadata().FindValue("measurement"))
+
+	fr, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	require.NoError(t, err)
+	table, err := fr.ReadTable(context.Background())
+	require.NoError(t, err)
+	defer table.Release()
+
+	names := make([]string, 0, table.NumCols())
+	for _, f := range table.Schema().Fields() {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"measurement", "usage", "used", "timestamp"}, names)
+
+	measurements := table.Column(0).Data().Chunk(0).(*array.String)
+	require.Equal(t, "cpu", measurements.Value(0))
+	require.Equal(t, "mem", measurements.Value(1))
+	usage := table.Column(1).Data().Chunk(0).(*array.Float64)
+	require.Equal(t, 42.5, usage.Value(0))
+	require.True(t, usage.IsNull(1))
+}
@@ -11,6 +11,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
 )
 
 const helpString = "Telegraf collected metric"
@@ -26,6 +27,7 @@ type promMetric struct {
 	addTime   time.Time
 	scaler    *scaler
 	histogram *histogram
+	native    *metric.ExponentialHistogram
 	summary   *summary
 }
 
@@ -191,6 +193,16 @@ func (c *Collection) createLabels(metric telegraf.Metric) []labelPair {
 // Add adds a metric to the collection. It will create a new entry if the metric is not already present.
 func (c *Collection) Add(m telegraf.Metric, now time.Time) {
 	labels := c.createLabels(m)
+
+	// Native histograms span multiple fields so they have to be handled
+	// before processing the individual fields.
+	// Custom bucket histograms cannot be represented in the exposition format
+	// and are exported field by field.
+	if h, ok := metric.ExponentialHistogramOf(m); ok && !h.IsCustomBuckets() {
+		c.addNativeHistogram(m, h, labels, now)
+		return
+	}
+
 	for _, field := range m.FieldList() {
 		metricName := MetricName(m.Name(), field.Key, m.Type())
 		metricName, ok := c.sanitizeMetricName(metricName)
@@ -348,6 +360,39 @@ func (c *Collection) Add(m telegraf.Metric, now time.Time) {
 	}
 }
 
+func (c *Collection) addNativeHistogram(m telegraf.Metric, h *metric.ExponentialHistogram, labels []labelPair, now time.Time) {
+	metricName, ok := c.sanitizeMetricName(m.Name())
+	if !ok {
+		return
+	}
+
+	family := metricFamily{
+		name: metricName,
+		typ:  telegraf.Histogram,
+	}
+
+	singleEntry, ok := c.entries[family]
+	if !ok {
+		singleEntry = entry{
+			family:  family,
+			metrics: make(map[metricKey]*promMetric),
+		}
+		c.entries[family] = singleEntry
+	}
+
+	key := makeMetricKey(labels)
+	if existingMetric, ok := singleEntry.metrics[key]; ok && m.Time().Before(existingMetric.time) {
+		return
+	}
+
+	singleEntry.metrics[key] = &promMetric{
+		labels:  labels,
+		time:    m.Time(),
+		addTime: now,
+		native:  h,
+	}
+}
+
 // Expire removes metrics that are older than the specified age.
 func (c *Collection) Expire(now time.Time, age time.Duration) {
 	expireTime := now.Add(-age)
@@ -458,6 +503,11 @@ func (c *Collection) GetProto() []*dto.MetricFamily {
 			case telegraf.Untyped:
 				m.Untyped = &dto.Untyped{Value: proto.Float64(metric.scaler.value)}
 			case telegraf.Histogram:
+				if metric.native != nil {
+					m.Histogram = nativeHistogram(metric.native)
+					break
+				}
+
 				buckets := make([]*dto.Bucket, 0, len(metric.histogram.buckets))
 				for _, bucket := range metric.histogram.buckets {
 					buckets = append(buckets, &dto.Bucket{
@@ -499,3 +549,28 @@ func (c *Collection) GetProto() []*dto.MetricFamily {
 
 	return result
 }
+
+func nativeHistogram(h *metric.ExponentialHistogram) *dto.Histogram {
+	return &dto.Histogram{
+		SampleCountFloat: proto.Float64(h.Count),
+		SampleSum:        proto.Float64(h.Sum),
+		Schema:           proto.Int32(h.Schema),
+		ZeroThreshold:    proto.Float64(h.ZeroThreshold),
+		ZeroCountFloat:   proto.Float64(h.ZeroCount),
+		PositiveSpan:     bucketSpans(h.PositiveSpans),
+		PositiveCount:    h.PositiveBuckets,
+		NegativeSpan:     bucketSpans(h.NegativeSpans),
+		NegativeCount:    h.NegativeBuckets,
+	}
+}
+
+func bucketSpans(spans []metric.BucketSpan) []*dto.BucketSpan {
+	result := make([]*dto.BucketSpan, 0, len(spans))
+	for _, span := range spans {
+		result = append(result, &dto.BucketSpan{
+			Offset: proto.Int32(span.Offset),
+			Length: proto.Uint32(span.Length),
+		})
+	}
+	return result
+}
@@ -989,3 +989,59 @@ func TestCollectionUTF8DropWhenFallbackBecomesEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectionNativeHistogram(t *testing.T) {
+	h := &metric.ExponentialHistogram{
+		Schema:        0,
+		ZeroThreshold: 0.001,
+		ZeroCount:     1,
+		Count:         5,
+		Sum:           9,
+	}
+	h.SetPositiveBucketCounts(0, []float64{1, 2, 0, 1})
+
+	c := NewCollection(FormatConfig{})
+	c.Add(
+		metric.New(
+			"request_duration",
+			map[string]string{"host": "example.org"},
+			h.Fields(),
+			time.Unix(0, 0),
+			telegraf.Histogram,
+		),
+		time.Unix(0, 0),
+	)
+
+	expected := []*dto.MetricFamily{
+		{
+			Name: proto.String("request_duration"),
+			Help: proto.String(helpString),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label: []*dto.LabelPair{
+						{
+							Name:  proto.String("host"),
+							Value: proto.String("example.org"),
+						},
+					},
+					Histogram: &dto.Histogram{
+						SampleCountFloat: proto.Float64(5),
+						SampleSum:        proto.Float64(9),
+						Schema:           proto.Int32(0),
+						ZeroThreshold:    proto.Float64(0.001),
+						ZeroCountFloat:   proto.Float64(1),
+						PositiveSpan: []*dto.BucketSpan{
+							{Offset: proto.Int32(0), Length: proto.Uint32(2)},
+							{Offset: proto.Int32(1), Length: proto.Uint32(1)},
+						},
+						PositiveCount: []float64{1, 2, 1},
+						NegativeSpan:  []*dto.BucketSpan{},
+					},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, expected, c.GetProto())
+}
@@ -12,8 +12,10 @@ import (
 	"github.com/golang/snappy"
 	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/serializers"
 	"github.com/influxdata/telegraf/plugins/serializers/prometheus"
 )
@@ -21,11 +23,40 @@ import (
 type Serializer struct {
 	SortMetrics   bool            `toml:"prometheus_sort_metrics"`
 	StringAsLabel bool            `toml:"prometheus_string_as_label"`
+	Version       string          `toml:"prometheus_remote_write_version"`
 	Log           telegraf.Logger `toml:"-"`
 }
 
 type metricKey uint64
 
+func (s *Serializer) Init() error {
+	switch s.Version {
+	case "":
+		s.Version = "v1"
+	case "v1", "v2":
+	default:
+		return fmt.Errorf("invalid remote write version %q", s.Version)
+	}
+	return nil
+}
+
+// Headers returns the HTTP headers required to send the serialized data
+// according to the remote write protocol version
+func (s *Serializer) Headers() map[string]string {
+	if s.Version == "v2" {
+		return map[string]string{
+			"Content-Type":                      "application/x-protobuf;proto=io.prometheus.write.v2.Request",
+			"Content-Encoding":                  "snappy",
+			"X-Prometheus-Remote-Write-Version": "2.0.0",
+		}
+	}
+	return map[string]string{
+		"Content-Type":                      "application/x-protobuf",
+		"Content-Encoding":                  "snappy",
+		"X-Prometheus-Remote-Write-Version": "0.1.0",
+	}
+}
+
 func (s *Serializer) Serialize(metric telegraf.Metric) ([]byte, error) {
 	return s.SerializeBatch([]telegraf.Metric{metric})
 }
@@ -41,6 +72,7 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 
 	var buf bytes.Buffer
 	var entries = make(map[metricKey]prompb.TimeSeries)
+	var types = make(map[metricKey]telegraf.ValueType)
 	var labels = make([]prompb.Label, 0)
 	for _, metric := range metrics {
 		labels = s.appendCommonLabels(labels[:0], metric)
@@ -61,6 +93,7 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 					}
 				}
 				entries[metrickey] = *data
+				types[metrickey] = metric.Type()
 				continue
 			}
 		}
@@ -93,10 +126,12 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 					metrickeysum, promtssum := getPromTS(metricName+"_sum", labels, float64(0), metric.Time())
 					if _, ok = entries[metrickeysum]; !ok {
 						entries[metrickeysum] = promtssum
+						types[metrickeysum] = metric.Type()
 					}
 					metrickeycount, promtscount := getPromTS(metricName+"_count", labels, float64(0), metric.Time())
 					if _, ok = entries[metrickeycount]; !ok {
 						entries[metrickeycount] = promtscount
+						types[metrickeycount] = metric.Type()
 					}
 					extraLabel := prompb.Label{
 						Name:  "le",
@@ -105,6 +140,7 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 					metrickeyinf, promtsinf := getPromTS(metricName+"_bucket", labels, float64(0), metric.Time(), extraLabel)
 					if _, ok = entries[metrickeyinf]; !ok {
 						entries[metrickeyinf] = promtsinf
+						types[metrickeyinf] = metric.Type()
 					}
 
 					le, ok := metric.GetTag("le")
@@ -151,6 +187,7 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 					metrickeyinf, promtsinf := getPromTS(metricName+"_bucket", labels, float64(count), metric.Time(), extraLabel)
 					if minf, ok := entries[metrickeyinf]; !ok || minf.Samples[0].Value == 0 {
 						entries[metrickeyinf] = promtsinf
+						types[metrickeyinf] = metric.Type()
 					}
 
 					metrickey, promts = getPromTS(metricName+"_count", labels, float64(count), metric.Time())
@@ -213,6 +250,7 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 				}
 			}
 			entries[metrickey] = promts
+			types[metrickey] = metric.Type()
 		}
 	}
 
@@ -253,8 +291,15 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 			return false
 		})
 	}
-	pb := &prompb.WriteRequest{Timeseries: promTS}
-	data, err := pb.Marshal()
+
+	var data []byte
+	var err error
+	if s.Version == "v2" {
+		data, err = toRequestV2(promTS, types).Marshal()
+	} else {
+		pb := &prompb.WriteRequest{Timeseries: promTS}
+		data, err = pb.Marshal()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to marshal protobuf: %w", err)
 	}
@@ -263,6 +308,50 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// toRequestV2 converts the series to a remote write 2.0 request interning
+// all label names and values and adding the metric type as metadata
+func toRequestV2(series []prompb.TimeSeries, types map[metricKey]telegraf.ValueType) *writev2.Request {
+	symbols := writev2.NewSymbolTable()
+	timeseries := make([]writev2.TimeSeries, 0, len(series))
+	for _, ts := range series {
+		refs := make([]uint32, 0, 2*len(ts.Labels))
+		for _, l := range ts.Labels {
+			refs = append(refs, symbols.Symbolize(l.Name), symbols.Symbolize(l.Value))
+		}
+
+		samples := make([]writev2.Sample, 0, len(ts.Samples))
+		for _, sample := range ts.Samples {
+			samples = append(samples, writev2.Sample{Value: sample.Value, Timestamp: sample.Timestamp})
+		}
+		histograms := make([]writev2.Histogram, 0, len(ts.Histograms))
+		for _, h := range ts.Histograms {
+			histograms = append(histograms, writev2.FromFloatHistogram(h.Timestamp, h.ToFloatHistogram()))
+		}
+
+		var mtype writev2.Metadata_MetricType
+		switch types[makeMetricKey(ts.Labels)] {
+		case telegraf.Counter:
+			mtype = writev2.Metadata_METRIC_TYPE_COUNTER
+		case telegraf.Gauge:
+			mtype = writev2.Metadata_METRIC_TYPE_GAUGE
+		case telegraf.Summary:
+			mtype = writev2.Metadata_METRIC_TYPE_SUMMARY
+		case telegraf.Histogram:
+			mtype = writev2.Metadata_METRIC_TYPE_HISTOGRAM
+		default:
+			mtype = writev2.Metadata_METRIC_TYPE_UNSPECIFIED
+		}
+
+		timeseries = append(timeseries, writev2.TimeSeries{
+			LabelsRefs: refs,
+			Samples:    samples,
+			Histograms: histograms,
+			Metadata:   writev2.Metadata{Type: mtype},
+		})
+	}
+	return &writev2.Request{Symbols: symbols.Symbols(), Timeseries: timeseries}
+}
+
 func hasLabel(name string, labels []prompb.Label) bool {
 	for _, label := range labels {
 		if name == label.Name {
@@ -358,155 +447,36 @@ func getPromTS(name string, labels []prompb.Label, value float64, ts time.Time,
 	return makeMetricKey(labelscopy), prompb.TimeSeries{Labels: labelscopy, Samples: sample}
 }
 
-func tryConvertToNativeHistogram(metric telegraf.Metric, labels []prompb.Label) (metricKey, *prompb.TimeSeries) {
-	fields := metric.Fields()
-
+func tryConvertToNativeHistogram(m telegraf.Metric, labels []prompb.Label) (metricKey, *prompb.TimeSeries) {
 	// Native histograms have count, sum, schema, counter_reset_hint, zero_threshold, zero_count
 	// If any of these are missing, we can't convert to a native histogram and short-circuit.
-	count, found := fields["count"]
-	if !found {
-		return 0, nil
-	}
-	countFloat, ok := count.(float64)
-	if !ok {
-		return 0, nil
-	}
-	sum, found := fields["sum"]
-	if !found {
-		return 0, nil
-	}
-	sumFloat, ok := sum.(float64)
-	if !ok {
-		return 0, nil
-	}
-	schema, found := fields["schema"]
-	if !found {
-		return 0, nil
-	}
-	schemaInt, ok := schema.(int64)
-	if !ok {
-		return 0, nil
-	}
-	counterResetHint, found := fields["counter_reset_hint"]
-	if !found {
-		return 0, nil
-	}
-	counterResetHintInt, ok := counterResetHint.(uint64)
-	if !ok {
-		return 0, nil
-	}
-	zeroThreshold, found := fields["zero_threshold"]
-	if !found {
-		return 0, nil
-	}
-	zeroThresholdFloat, ok := zeroThreshold.(float64)
-	if !ok {
-		return 0, nil
-	}
-	zeroCount, found := fields["zero_count"]
-	if !found {
-		return 0, nil
-	}
-	zeroCountFloat, ok := zeroCount.(float64)
+	h, ok := metric.ExponentialHistogramOf(m)
 	if !ok {
 		return 0, nil
 	}
 
 	floatHistogram := &histogram.FloatHistogram{
-		Count:            countFloat,
-		Sum:              sumFloat,
-		Schema:           int32(schemaInt),
-		CounterResetHint: histogram.CounterResetHint(counterResetHintInt),
-		ZeroThreshold:    zeroThresholdFloat,
-		ZeroCount:        zeroCountFloat,
-		PositiveSpans:    make([]histogram.Span, 0),
-		NegativeSpans:    make([]histogram.Span, 0),
-		PositiveBuckets:  make([]float64, 0),
-		NegativeBuckets:  make([]float64, 0),
-	}
-
-	// Span (offset, length pair) define bucket boundaries.
-	// A native histogram can have 0 or multiple positive spans.
-	// We do not know how many spans there are, so iterate from 0 until we break.
-	i := 0
-	for {
-		offset, offsetFound := fields[fmt.Sprintf("positive_span_%d_offset", i)]
-		length, lengthFound := fields[fmt.Sprintf("positive_span_%d_length", i)]
-		if !offsetFound || !lengthFound {
-			break
-		}
-		offsetInt, offsetOk := offset.(int64)
-		lengthInt, lengthOk := length.(uint64)
-		if !offsetOk || !lengthOk {
-			break
-		}
-		floatHistogram.PositiveSpans = append(floatHistogram.PositiveSpans,
-			histogram.Span{
-				Offset: int32(offsetInt),
-				Length: uint32(lengthInt),
-			},
-		)
-		i++
+		Count:            h.Count,
+		Sum:              h.Sum,
+		Schema:           h.Schema,
+		CounterResetHint: histogram.CounterResetHint(h.CounterResetHint),
+		ZeroThreshold:    h.ZeroThreshold,
+		ZeroCount:        h.ZeroCount,
+		PositiveSpans:    make([]histogram.Span, 0, len(h.PositiveSpans)),
+		NegativeSpans:    make([]histogram.Span, 0, len(h.NegativeSpans)),
+		PositiveBuckets:  append(make([]float64, 0, len(h.PositiveBuckets)), h.PositiveBuckets...),
+		NegativeBuckets:  append(make([]float64, 0, len(h.NegativeBuckets)), h.NegativeBuckets...),
+		CustomValues:     h.CustomValues,
 	}
-
-	// Do the same for negative spans
-	i = 0
-	for {
-		offset, offsetFound := fields[fmt.Sprintf("negative_span_%d_offset", i)]
-		length, lengthFound := fields[fmt.Sprintf("negative_span_%d_length", i)]
-		if !offsetFound || !lengthFound {
-			break
-		}
-		offsetInt, offsetOk := offset.(int64)
-		lengthInt, lengthOk := length.(uint64)
-		if !offsetOk || !lengthOk {
-			break
-		}
-		floatHistogram.NegativeSpans = append(floatHistogram.NegativeSpans,
-			histogram.Span{
-				Offset: int32(offsetInt),
-				Length: uint32(lengthInt),
-			},
-		)
-		i++
-	}
-
-	// Bucket defines count in each bucket.
-	// Similarly, there can be 0 or multiple positive bucket fields.
-	// Similarly, we do not know how many bucket fields there are, so iterate from 0 until we break.
-	// Note that length of bucket array can be more than the length of spans due to delta encoding of bucket boundaries.
-	i = 0
-	for {
-		bucket, found := fields[fmt.Sprintf("positive_bucket_%d", i)]
-		if !found {
-			break
-		}
-		bucketFloat, ok := bucket.(float64)
-		if !ok {
-			break
-		}
-		floatHistogram.PositiveBuckets = append(floatHistogram.PositiveBuckets, bucketFloat)
-		i++
+	for _, span := range h.PositiveSpans {
+		floatHistogram.PositiveSpans = append(floatHistogram.PositiveSpans, histogram.Span{Offset: span.Offset, Length: span.Length})
 	}
-
-	// Do the same for negative buckets
-	i = 0
-	for {
-		bucket, found := fields[fmt.Sprintf("negative_bucket_%d", i)]
-		if !found {
-			break
-		}
-		bucketFloat, ok := bucket.(float64)
-		if !ok {
-			break
-		}
-		floatHistogram.NegativeBuckets = append(floatHistogram.NegativeBuckets, bucketFloat)
-		i++
+	for _, span := range h.NegativeSpans {
+		floatHistogram.NegativeSpans = append(floatHistogram.NegativeSpans, histogram.Span{Offset: span.Offset, Length: span.Length})
 	}
 
 	// Validate the floatHistogram
-	err := floatHistogram.Validate()
-	if err != nil {
+	if err := floatHistogram.Validate(); err != nil {
 		return 0, nil
 	}
 
@@ -516,13 +486,13 @@ func tryConvertToNativeHistogram(metric telegraf.Metric, labels []prompb.Label)
 
 	histograms := []prompb.Histogram{
 		prompb.FromFloatHistogram(
-			metric.Time().UnixNano()/int64(time.Millisecond),
+			m.Time().UnixNano()/int64(time.Millisecond),
 			floatHistogram,
 		),
 	}
 	labelscopy = append(labelscopy, prompb.Label{
 		Name:  "__name__",
-		Value: metric.Name(),
+		Value: m.Name(),
 	})
 
 	// We sort the labels since Prometheus TSDB does not like out of order labels
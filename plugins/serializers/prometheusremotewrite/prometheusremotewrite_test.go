@@ -10,6 +10,7 @@ import (
 	"github.com/golang/snappy"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/influxdata/telegraf"
@@ -805,6 +806,25 @@ func TestRemoteWriteSerializeNativeHistogram(t *testing.T) {
 			),
 			expected: []byte(`
 rpc_duration_seconds{host="example.org", node="node1"} {count:20, sum:10, [-2,-1):6, [-1,-0.5):4, [-0.001,0.001]:2, (0.5,1]:3, (1,2]:5}
+`),
+		},
+		{
+			name: "custom buckets histogram",
+			metric: metric.NewExponentialHistogram(
+				"rpc_duration_seconds",
+				map[string]string{"host": "example.org"},
+				&metric.ExponentialHistogram{
+					Schema:          metric.CustomBucketsSchema,
+					Count:           6,
+					Sum:             12,
+					PositiveSpans:   []metric.BucketSpan{{Offset: 0, Length: 3}},
+					PositiveBuckets: []float64{1, 2, 3},
+					CustomValues:    []float64{0.5, 1},
+				},
+				time.Unix(0, 0),
+			),
+			expected: []byte(`
+rpc_duration_seconds{host="example.org"} {count:6, sum:12, [-Inf,0.5]:1, (0.5,1]:2, (1,+Inf]:3}
 `),
 		},
 	}
@@ -827,6 +847,98 @@ rpc_duration_seconds{host="example.org", node="node1"} {count:20, sum:10, [-2,-1
 	}
 }
 
+func TestRemoteWriteSerializeV2(t *testing.T) {
+	metrics := []telegraf.Metric{
+		metric.New(
+			"prometheus",
+			map[string]string{"code": "400", "method": "post"},
+			map[string]interface{}{"http_requests_total": 3.0},
+			time.Unix(0, 0),
+			telegraf.Counter,
+		),
+		metric.New(
+			"prometheus",
+			map[string]string{"code": "200", "method": "post"},
+			map[string]interface{}{"http_requests_total": 5.0},
+			time.Unix(0, 0),
+			telegraf.Counter,
+		),
+		metric.New(
+			"cpu",
+			map[string]string{"host": "example.org"},
+			map[string]interface{}{"time_idle": 42.0},
+			time.Unix(1, 0),
+			telegraf.Gauge,
+		),
+		metric.New(
+			"prometheus",
+			map[string]string{"quantile": "0.5"},
+			map[string]interface{}{"rpc_duration_seconds": 0.1},
+			time.Unix(2, 0),
+			telegraf.Summary,
+		),
+	}
+
+	s := &Serializer{
+		Log:         &testutil.CaptureLogger{},
+		SortMetrics: true,
+		Version:     "v2",
+	}
+	require.NoError(t, s.Init())
+	data, err := s.SerializeBatch(metrics)
+	require.NoError(t, err)
+
+	buf, err := snappy.Decode(nil, data)
+	require.NoError(t, err)
+	var req writev2.Request
+	require.NoError(t, req.Unmarshal(buf))
+
+	// Symbols are interned, so each string must only occur once
+	require.Equal(t, []string{
+		"", "__name__", "cpu_time_idle", "host", "example.org", "rpc_duration_seconds", "quantile", "0.5",
+		"http_requests_total", "code", "200", "method", "post", "400",
+	}, req.Symbols)
+
+	type series struct {
+		labels    map[string]string
+		value     float64
+		timestamp int64
+		mtype     writev2.Metadata_MetricType
+	}
+	actual := make([]series, 0, len(req.Timeseries))
+	for _, ts := range req.Timeseries {
+		require.Len(t, ts.Samples, 1)
+		labels := make(map[string]string)
+		for i := 0; i < len(ts.LabelsRefs); i += 2 {
+			labels[req.Symbols[ts.LabelsRefs[i]]] = req.Symbols[ts.LabelsRefs[i+1]]
+		}
+		actual = append(actual, series{labels, ts.Samples[0].Value, ts.Samples[0].Timestamp, ts.Metadata.Type})
+	}
+	expected := []series{
+		{map[string]string{"__name__": "cpu_time_idle", "host": "example.org"}, 42, 1000, writev2.Metadata_METRIC_TYPE_GAUGE},
+		{map[string]string{"__name__": "rpc_duration_seconds", "quantile": "0.5"}, 0.1, 2000, writev2.Metadata_METRIC_TYPE_SUMMARY},
+		{map[string]string{"__name__": "http_requests_total", "code": "200", "method": "post"}, 5, 0, writev2.Metadata_METRIC_TYPE_COUNTER},
+		{map[string]string{"__name__": "http_requests_total", "code": "400", "method": "post"}, 3, 0, writev2.Metadata_METRIC_TYPE_COUNTER},
+	}
+	require.Equal(t, expected, actual)
+}
+
+func TestRemoteWriteHeaders(t *testing.T) {
+	s := &Serializer{}
+	require.NoError(t, s.Init())
+	require.Equal(t, "application/x-protobuf", s.Headers()["Content-Type"])
+	require.Equal(t, "0.1.0", s.Headers()["X-Prometheus-Remote-Write-Version"])
+
+	s = &Serializer{Version: "v2"}
+	require.NoError(t, s.Init())
+	require.Equal(t, "application/x-protobuf;proto=io.prometheus.write.v2.Request", s.Headers()["Content-Type"])
+	require.Equal(t, "snappy", s.Headers()["Content-Encoding"])
+	require.Equal(t, "2.0.0", s.Headers()["X-Prometheus-Remote-Write-Version"])
+
+	s = &Serializer{Version: "v3"}
+	require.ErrorContains(t, s.Init(), `invalid remote write version "v3"`)
+}
+
 func prompbToText(data []byte) ([]byte, error) {
 	var buf = bytes.Buffer{}
 	protobuff, err := snappy.Decode(nil, data)
@@ -0,0 +1,280 @@
+package protobuf
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/common/schemaregistry"
+	"github.com/influxdata/telegraf/plugins/serializers"
+)
+
+// First field number available for tags and fields after the timestamp field
+// and the range reserved by the protobuf implementation
+const (
+	minFieldNumber      = 2
+	reservedFieldNumber = 19000
+	reservedFieldCount  = 1000
+)
+
+type Serializer struct {
+	SchemaRegistry  string            `toml:"protobuf_schema_registry"`
+	CaCertPath      string            `toml:"protobuf_schema_registry_cert"`
+	Subject         string            `toml:"protobuf_schema_registry_subject"`
+	SubjectStrategy string            `toml:"protobuf_schema_registry_subject_strategy"`
+	Package         string            `toml:"protobuf_package"`
+	Timestamp       string            `toml:"protobuf_timestamp"`
+	FieldNames      map[string]string `toml:"protobuf_field_names"`
+
+	registry *schemaregistry.Client
+	schemas  map[string]*schema
+	numbers  map[string]map[string]int
+	sync.Mutex
+}
+
+type schema struct {
+	definition string
+	desc       protoreflect.MessageDescriptor
+}
+
+func (s *Serializer) Init() error {
+	if s.Timestamp == "" {
+		s.Timestamp = "timestamp"
+	}
+	if s.Timestamp != schemaregistry.SanitizeName(s.Timestamp) {
+		return fmt.Errorf("invalid timestamp field name %q", s.Timestamp)
+	}
+	for key, name := range s.FieldNames {
+		if name != schemaregistry.SanitizeName(name) {
+			return fmt.Errorf("invalid name %q for %q", name, key)
+		}
+	}
+	if err := schemaregistry.CheckSubjectStrategy(s.SubjectStrategy); err != nil {
+		return err
+	}
+	s.schemas = make(map[string]*schema)
+	s.numbers = make(map[string]map[string]int)
+
+	if s.SchemaRegistry != "" {
+		registry, err := schemaregistry.NewClient(s.SchemaRegistry, s.CaCertPath)
+		if err != nil {
+			return fmt.Errorf("creating schema registry client for %q failed: %w", s.SchemaRegistry, err)
+		}
+		s.registry = registry
+	}
+
+	return nil
+}
+
+func (s *Serializer) Serialize(metric telegraf.Metric) ([]byte, error) {
+	return s.appendMetric(nil, metric, "")
+}
+
+func (s *Serializer) SerializeTopic(metric telegraf.Metric, topic string) ([]byte, error) {
+	return s.appendMetric(nil, metric, topic)
+}
+
+func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
+	var buf []byte
+	for _, m := range metrics {
+		var err error
+		if buf, err = s.appendMetric(buf, m, ""); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func (s *Serializer) appendMetric(buf []byte, metric telegraf.Metric, topic string) ([]byte, error) {
+	sc, err := s.schemaFor(metric)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefix the message with the Confluent wire format header if using a
+	// registry. The message index is always zero as the schema only contains
+	// a single message.
+	if s.registry != nil {
+		id, err := s.registry.Register(s.subject(sc, topic), "PROTOBUF", sc.definition)
+		if err != nil {
+			return nil, err
+		}
+		buf = schemaregistry.AppendHeader(buf, id)
+		buf = append(buf, 0)
+	}
+
+	msg := dynamicpb.NewMessage(sc.desc)
+	fields := sc.desc.Fields()
+
+	fd := fields.ByName(protoreflect.Name(s.Timestamp))
+	ts := dynamicpb.NewMessage(fd.Message())
+	ts.Set(fd.Message().Fields().ByName("seconds"), protoreflect.ValueOfInt64(metric.Time().Unix()))
+	ts.Set(fd.Message().Fields().ByName("nanos"), protoreflect.ValueOfInt32(int32(metric.Time().Nanosecond())))
+	msg.Set(fd, protoreflect.ValueOfMessage(ts))
+
+	for _, tag := range metric.TagList() {
+		msg.Set(fields.ByName(protoreflect.Name(s.name(tag.Key))), protoreflect.ValueOfString(tag.Value))
+	}
+	for _, field := range metric.FieldList() {
+		fd := fields.ByName(protoreflect.Name(s.name(field.Key)))
+		msg.Set(fd, protoreflect.ValueOf(field.Value))
+	}
+
+	return proto.MarshalOptions{Deterministic: true}.MarshalAppend(buf, msg)
+}
+
+// subject returns the subject to register the schema under
+func (s *Serializer) subject(sc *schema, topic string) string {
+	if s.Subject != "" {
+		return s.Subject
+	}
+	return schemaregistry.Subject(s.SubjectStrategy, topic, string(sc.desc.FullName()))
+}
+
+// name returns the name of the tag or field with the given key in the schema
+func (s *Serializer) name(key string) string {
+	if name, found := s.FieldNames[key]; found {
+		return name
+	}
+	return schemaregistry.SanitizeName(key)
+}
+
+// schemaFor returns the schema containing a single message derived from the
+// tags and fields of the metric. Schemas are only derived once for each
+// measurement and set of tags and fields.
+func (s *Serializer) schemaFor(metric telegraf.Metric) (*schema, error) {
+	fields := slices.Clone(metric.FieldList())
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+
+	var key strings.Builder
+	key.WriteString(metric.Name())
+	for _, tag := range metric.TagList() {
+		fmt.Fprintf(&key, "\x00%s", tag.Key)
+	}
+	for _, f := range fields {
+		fmt.Fprintf(&key, "\x00%s:%T", f.Key, f.Value)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if sc, found := s.schemas[key.String()]; found {
+		return sc, nil
+	}
+
+	// Tags and fields are optional to distinguish missing from default values
+	type entry struct {
+		name      string
+		fieldType string
+	}
+	entries := make([]entry, 0, len(metric.TagList())+len(fields))
+	names := map[string]string{s.Timestamp: s.Timestamp}
+	add := func(key, fieldType string) error {
+		name := s.name(key)
+		if other, found := names[name]; found {
+			return fmt.Errorf("%q and %q both map to name %q, use 'protobuf_field_names' to rename one of them", other, key, name)
+		}
+		names[name] = key
+		entries = append(entries, entry{name: name, fieldType: fieldType})
+		return nil
+	}
+	for _, tag := range metric.TagList() {
+		if err := add(tag.Key, "string"); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range fields {
+		var fieldType string
+		switch f.Value.(type) {
+		case int64:
+			fieldType = "int64"
+		case uint64:
+			fieldType = "uint64"
+		case float64:
+			fieldType = "double"
+		case bool:
+			fieldType = "bool"
+		case string:
+			fieldType = "string"
+		default:
+			return nil, fmt.Errorf("unsupported type %T of field %q", f.Value, f.Key)
+		}
+		if err := add(f.Key, fieldType); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	// Field numbers are assigned in name order when a name is first seen for
+	// the message and are kept afterwards, so adding tags or fields does not
+	// change the numbers of existing ones.
+	name := schemaregistry.SanitizeName(metric.Name())
+	numbers, found := s.numbers[name]
+	if !found {
+		numbers = make(map[string]int)
+		s.numbers[name] = numbers
+	}
+	for _, e := range entries {
+		if _, found := numbers[e.name]; !found {
+			numbers[e.name] = nextFieldNumber(len(numbers))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("syntax = \"proto3\";\n\n")
+	if s.Package != "" {
+		fmt.Fprintf(&b, "package %s;\n\n", s.Package)
+	}
+	b.WriteString("import \"google/protobuf/timestamp.proto\";\n\n")
+	fmt.Fprintf(&b, "message %s {\n", name)
+	fmt.Fprintf(&b, "  google.protobuf.Timestamp %s = 1;\n", s.Timestamp)
+	for _, e := range entries {
+		fmt.Fprintf(&b, "  optional %s %s = %d;\n", e.fieldType, e.name, numbers[e.name])
+	}
+	b.WriteString("}\n")
+	definition := b.String()
+
+	filename := name + ".proto"
+	compiler := &protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{filename: definition}),
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), filename)
+	if err != nil {
+		return nil, fmt.Errorf("compiling schema for metric %q failed: %w", metric.Name(), err)
+	}
+
+	sc := &schema{
+		definition: definition,
+		desc:       files[0].Messages().Get(0),
+	}
+	s.schemas[key.String()] = sc
+	return sc, nil
+}
+
+// nextFieldNumber returns the field number following the given count of
+// already assigned numbers skipping the reserved range
+func nextFieldNumber(assigned int) int {
+	n := minFieldNumber + assigned
+	if n >= reservedFieldNumber {
+		n += reservedFieldCount
+	}
+	return n
+}
+
+func init() {
+	serializers.Add("protobuf",
+		func() telegraf.Serializer {
+			return &Serializer{}
+		},
+	)
+}
@@ -0,0 +1,273 @@
+package protobuf
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers/protobuf"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestInitInvalid(t *testing.T) {
+	serializer := &Serializer{Timestamp: "my-time"}
+	require.ErrorContains(t, serializer.Init(), `invalid timestamp field name "my-time"`)
+}
+
+func TestSerializeRoundtrip(t *testing.T) {
+	registry := &mockRegistry{t: t}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	serializer := &Serializer{
+		SchemaRegistry: server.URL,
+		Package:        "telegraf",
+	}
+	require.NoError(t, serializer.Init())
+
+	parser := &protobuf.Parser{
+		SchemaRegistry: server.URL,
+		Tags:           []string{"host"},
+		Timestamp:      "timestamp",
+		Log:            testutil.Logger{},
+	}
+	require.NoError(t, parser.Init())
+
+	input := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{"host": "server01"},
+			map[string]interface{}{
+				"usage_idle":   98.5,
+				"count":        int64(4),
+				"errors":       uint64(0),
+				"online":       true,
+				"model.vendor": "intel",
+			},
+			time.Unix(1700000000, 123),
+		),
+		metric.New(
+			"mem",
+			map[string]string{},
+			map[string]interface{}{"used": int64(1024)},
+			time.Unix(1700000010, 0),
+		),
+	}
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"telegraf.cpu",
+			map[string]string{"host": "server01"},
+			map[string]interface{}{
+				"usage_idle":   98.5,
+				"count":        int64(4),
+				"errors":       uint64(0),
+				"online":       true,
+				"model_vendor": "intel",
+			},
+			time.Unix(1700000000, 123),
+		),
+		metric.New(
+			"telegraf.mem",
+			map[string]string{},
+			map[string]interface{}{"used": int64(1024)},
+			time.Unix(1700000010, 0),
+		),
+	}
+
+	buf, err := serializer.SerializeBatch(input[:1])
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x00}, buf[:6])
+	actual, err := parser.Parse(buf)
+	require.NoError(t, err)
+
+	buf, err = serializer.Serialize(input[1])
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x02, 0x00}, buf[:6])
+	metrics, err := parser.Parse(buf)
+	require.NoError(t, err)
+	actual = append(actual, metrics...)
+
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	// The schemas must only be registered once
+	_, err = serializer.Serialize(input[0])
+	require.NoError(t, err)
+	require.Equal(t, []string{"telegraf.cpu", "telegraf.mem"}, registry.subjects)
+}
+
+func TestSerializeWithoutRegistry(t *testing.T) {
+	serializer := &Serializer{}
+	require.NoError(t, serializer.Init())
+
+	m := metric.New(
+		"cpu",
+		map[string]string{"host": "server01"},
+		map[string]interface{}{"value": 42.0},
+		time.Unix(1700000000, 0),
+	)
+	buf, err := serializer.Serialize(m)
+	require.NoError(t, err)
+
+	// Without registry the message is not prefixed
+	sc, err := serializer.schemaFor(m)
+	require.NoError(t, err)
+	require.Equal(t, "cpu", string(sc.desc.FullName()))
+
+	msg := dynamicpb.NewMessage(sc.desc)
+	require.NoError(t, proto.Unmarshal(buf, msg))
+	fields := sc.desc.Fields()
+	require.Equal(t, "server01", msg.Get(fields.ByName("host")).String())
+	require.InDelta(t, 42.0, msg.Get(fields.ByName("value")).Float(), 0)
+	ts := msg.Get(fields.ByName("timestamp")).Message()
+	require.Equal(t, int64(1700000000), ts.Get(ts.Descriptor().Fields().ByName("seconds")).Int())
+}
+
+func TestSerializeTopicSubject(t *testing.T) {
+	registry := &mockRegistry{t: t}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	m := metric.New("cpu", map[string]string{}, map[string]interface{}{"value": 42.0}, time.Unix(0, 0))
+	for _, strategy := range []string{"", "topic", "topic_record"} {
+		serializer := &Serializer{SchemaRegistry: server.URL, SubjectStrategy: strategy}
+		require.NoError(t, serializer.Init())
+		_, err := serializer.SerializeTopic(m, "telegraf")
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"cpu", "telegraf-value", "telegraf-cpu"}, registry.subjects)
+
+	serializer := &Serializer{SubjectStrategy: "foo"}
+	require.ErrorContains(t, serializer.Init(), `invalid subject strategy "foo"`)
+}
+
+func TestFieldNumbersStable(t *testing.T) {
+	serializer := &Serializer{}
+	require.NoError(t, serializer.Init())
+
+	// Adding tags and fields must not change the numbers of existing fields
+	first, err := serializer.schemaFor(metric.New(
+		"cpu",
+		map[string]string{"host": "server01"},
+		map[string]interface{}{"usage_user": 1.0},
+		time.Unix(0, 0),
+	))
+	require.NoError(t, err)
+	second, err := serializer.schemaFor(metric.New(
+		"cpu",
+		map[string]string{"cpu": "cpu0", "host": "server01"},
+		map[string]interface{}{"usage_idle": 98.0, "usage_user": 1.0},
+		time.Unix(0, 0),
+	))
+	require.NoError(t, err)
+
+	for _, name := range []string{"timestamp", "host", "usage_user"} {
+		expected := first.desc.Fields().ByName(protoreflect.Name(name)).Number()
+		actual := second.desc.Fields().ByName(protoreflect.Name(name)).Number()
+		require.Equalf(t, expected, actual, "number of field %q changed", name)
+	}
+
+	// Numbers are assigned in name order starting after the timestamp
+	expected := map[string]protoreflect.FieldNumber{
+		"timestamp":  1,
+		"host":       2,
+		"usage_user": 3,
+		"cpu":        4,
+		"usage_idle": 5,
+	}
+	for name, number := range expected {
+		require.Equalf(t, number, second.desc.Fields().ByName(protoreflect.Name(name)).Number(), "number of field %q", name)
+	}
+
+	// The schema must only be derived once for the same set of tags and fields
+	third, err := serializer.schemaFor(metric.New(
+		"cpu",
+		map[string]string{"host": "server02"},
+		map[string]interface{}{"usage_user": 2.0},
+		time.Unix(1, 0),
+	))
+	require.NoError(t, err)
+	require.Same(t, first, third)
+}
+
+func TestSerializeNameCollision(t *testing.T) {
+	m := metric.New(
+		"cpu",
+		map[string]string{},
+		map[string]interface{}{"model.vendor": "intel", "model_vendor": "amd"},
+		time.Unix(0, 0),
+	)
+
+	serializer := &Serializer{}
+	require.NoError(t, serializer.Init())
+	_, err := serializer.Serialize(m)
+	require.ErrorContains(t, err, `"model.vendor" and "model_vendor" both map to name "model_vendor"`)
+
+	// Renaming one of the fields resolves the collision
+	serializer = &Serializer{FieldNames: map[string]string{"model.vendor": "vendor"}}
+	require.NoError(t, serializer.Init())
+	_, err = serializer.Serialize(m)
+	require.NoError(t, err)
+	sc, err := serializer.schemaFor(m)
+	require.NoError(t, err)
+	require.NotNil(t, sc.desc.Fields().ByName("vendor"))
+	require.NotNil(t, sc.desc.Fields().ByName("model_vendor"))
+
+	serializer = &Serializer{FieldNames: map[string]string{"model.vendor": "model.vendor"}}
+	require.ErrorContains(t, serializer.Init(), `invalid name "model.vendor"`)
+}
+
+type mockRegistry struct {
+	t        *testing.T
+	schemas  []string
+	subjects []string
+	sync.Mutex
+}
+
+func (r *mockRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+
+	var response interface{}
+	switch {
+	case req.Method == http.MethodPost && strings.HasPrefix(req.URL.Path, "/subjects/"):
+		var body struct {
+			Schema     string `json:"schema"`
+			SchemaType string `json:"schemaType"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.SchemaType != "PROTOBUF" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.schemas = append(r.schemas, body.Schema)
+		r.subjects = append(r.subjects, strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/subjects/"), "/versions"))
+		response = map[string]int{"id": len(r.schemas)}
+	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/schemas/ids/"):
+		id, err := strconv.Atoi(strings.TrimPrefix(req.URL.Path, "/schemas/ids/"))
+		if err != nil || id < 1 || id > len(r.schemas) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		response = map[string]string{"schemaType": "PROTOBUF", "schema": r.schemas[id-1]}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		r.t.Error(err)
+	}
+}
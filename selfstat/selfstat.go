@@ -108,6 +108,36 @@ func Metrics() []telegraf.Metric {
 	return metrics
 }
 
+// Snapshot returns the current values of the registered stats with the given
+// measurement as telegraf metrics. In contrast to Metrics(), timing stats are
+// skipped as reading them resets their average.
+func Snapshot(measurement string) []telegraf.Metric {
+	measurement = "internal_" + measurement
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	now := time.Now()
+	var metrics []telegraf.Metric
+	for _, stats := range registry.stats {
+		var tags map[string]string
+		fields := make(map[string]interface{}, len(stats))
+		for fieldname, stat := range stats {
+			if _, ok := stat.(*timingStat); ok || stat.Name() != measurement {
+				continue
+			}
+			if tags == nil {
+				tags = stat.Tags()
+			}
+			fields[fieldname] = stat.Get()
+		}
+		if len(fields) > 0 {
+			metrics = append(metrics, metric.New(measurement, tags, fields, now))
+		}
+	}
+	return metrics
+}
+
 type Registry struct {
 	stats map[uint64]map[string]Stat
 	mu    sync.Mutex
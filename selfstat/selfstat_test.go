@@ -2,9 +2,12 @@ package selfstat
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -197,3 +200,23 @@ func TestRegisterCopy(t *testing.T) {
 	tags["new"] = "value"
 	require.NotEqual(t, tags, stat.Tags())
 }
+
+func TestSnapshot(t *testing.T) {
+	defer testCleanup()
+
+	timing := RegisterTiming("test", "time_ns", map[string]string{"test": "foo"})
+	timing.Incr(10)
+	s1 := Register("test", "value", map[string]string{"test": "foo"})
+	s1.Set(23)
+	Register("test", "value", map[string]string{"test": "bar"})
+	Register("other", "value", map[string]string{"test": "foo"})
+
+	expected := []telegraf.Metric{
+		metric.New("internal_test", map[string]string{"test": "bar"}, map[string]interface{}{"value": int64(0)}, time.Unix(0, 0)),
+		metric.New("internal_test", map[string]string{"test": "foo"}, map[string]interface{}{"value": int64(23)}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, Snapshot("test"), testutil.IgnoreTime(), testutil.SortMetrics())
+
+	// Timing stats must not be reset by the snapshot
+	require.Equal(t, int64(10), timing.Get())
+}
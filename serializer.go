@@ -20,6 +20,22 @@ type Serializer interface {
 	SerializeBatch(metrics []Metric) ([]byte, error)
 }
 
+// HeaderSerializer is an interface for serializers producing data that
+// requires specific headers for transmission, e.g. protocol versions or
+// content encodings.
+type HeaderSerializer interface {
+	// Headers returns the headers required for sending the serialized data.
+	Headers() map[string]string
+}
+
+// TopicSerializer is an interface for serializers producing data depending on
+// the destination topic, e.g. to register schemas per topic.
+type TopicSerializer interface {
+	// SerializeTopic takes a single telegraf metric sent to the given topic
+	// and turns it into a byte buffer.
+	SerializeTopic(metric Metric, topic string) ([]byte, error)
+}
+
 // SerializerFunc is a function to create a new instance of a serializer
 type SerializerFunc func() (Serializer, error)
 